@@ -35,6 +35,19 @@ vi.mock("@/components/widgets/GridDialog", () => ({
 vi.mock("@/components/widgets/RatingDialog", () => ({
   RatingDialog: ({ input }: any) => `MOCK_RATING:${input?.title ?? ""}`,
 }));
+vi.mock("@/components/widgets/DiffDialog", () => ({
+  DiffDialog: ({ input }: any) => `MOCK_DIFF:${input?.title ?? ""}`,
+}));
+vi.mock("@/components/widgets/KeyValueDialog", () => ({
+  KeyValueCard: ({ input }: any) => `MOCK_KEYVALUE_CARD:${input?.title ?? ""}`,
+  KeyValueDialog: ({ input }: any) => `MOCK_KEYVALUE:${input?.title ?? ""}`,
+}));
+vi.mock("@/components/widgets/ChecklistDialog", () => ({
+  ChecklistDialog: ({ input }: any) => `MOCK_CHECKLIST:${input?.title ?? ""}`,
+}));
+vi.mock("@/components/widgets/DisplayDialog", () => ({
+  DisplayDialog: ({ input }: any) => `MOCK_DISPLAY_DIALOG:${input?.title ?? ""}`,
+}));
 vi.mock("@/components/widgets/DisplayWidget", () => ({
   DisplayWidget: ({ input }: any) =>
     `MOCK_DISPLAY:${input?.content ?? ""}:${input?.format ?? ""}`,
@@ -169,7 +182,59 @@ describe("WidgetRenderer script branch", () => {
       })
     );
     expect(html).toContain(
-      "ERROR: INVALID_SCRIPT_SECTIONS [exactly one interactive section is required]"
+      "ERROR: INVALID_SCRIPT_SECTIONS [at least one interactive section is required]"
+    );
+  });
+
+  it("renders several interactive sections with a single submit", () => {
+    const html = renderWithStore(
+      buildScriptRequest({
+        id: "req-render-multi-sections",
+        scriptView: {
+          widgetType: "sections",
+          stepId: "release",
+          sections: [
+            {
+              widgetType: "display",
+              input: { content: "Release 1.4", format: "text" },
+            },
+            {
+              id: "env",
+              widgetType: "select",
+              input: { title: "Environment", options: ["staging", "prod"] },
+            },
+            {
+              id: "confidence",
+              widgetType: "rating",
+              input: { title: "Confidence" },
+            },
+          ],
+        },
+      })
+    );
+    expect(html).toContain("MOCK_DISPLAY:Release 1.4:text");
+    expect(html).toContain("MOCK_SELECT:Environment");
+    expect(html).toContain("MOCK_RATING:Confidence");
+    expect(html).toContain("AWAITING_ANSWER");
+    expect(html).toContain("SUBMIT ALL (0/2)");
+  });
+
+  it("renders explicit error when multiple interactive sections lack ids", () => {
+    const html = renderWithStore(
+      buildScriptRequest({
+        id: "req-render-multi-sections-no-id",
+        scriptView: {
+          widgetType: "sections",
+          stepId: "release",
+          sections: [
+            { widgetType: "confirm", input: { title: "A" } },
+            { widgetType: "rating", input: { title: "B" } },
+          ],
+        },
+      })
+    );
+    expect(html).toContain(
+      "ERROR: INVALID_SCRIPT_SECTIONS [each interactive section needs an id]"
     );
   });
 
@@ -226,4 +291,79 @@ describe("WidgetRenderer script branch", () => {
     );
     expect(html).toContain("MOCK_RATING:How was this flow?");
   });
+
+  it("renders keyvalue sections read-only next to a diff review", () => {
+    const html = renderWithStore(
+      buildScriptRequest({
+        id: "req-render-review",
+        scriptView: {
+          widgetType: "diff",
+          input: { title: "Review changes" },
+          stepId: "review-step",
+          sections: [
+            {
+              widgetType: "keyvalue",
+              input: { title: "Release", items: [{ key: "Version", value: "1.4.0" }] },
+            },
+            {
+              widgetType: "diff",
+              input: { title: "Review changes", files: [] },
+            },
+          ],
+        },
+      })
+    );
+    expect(html).toContain("MOCK_KEYVALUE_CARD:Release");
+    expect(html).toContain("MOCK_DIFF:Review changes");
+    expect(html).not.toContain("INVALID_SCRIPT_SECTIONS");
+  });
+
+  it("renders checklist script views via ChecklistDialog mapping", () => {
+    const html = renderWithStore(
+      buildScriptRequest({
+        id: "req-render-checklist",
+        scriptView: {
+          widgetType: "checklist",
+          input: { title: "Before shipping", items: [] },
+          stepId: "checklist-step",
+        },
+      })
+    );
+    expect(html).toContain("MOCK_CHECKLIST:Before shipping");
+  });
+
+  it("renders standalone rating, grid, and display requests", () => {
+    const rating = renderWithStore(
+      buildScriptRequest({
+        id: "req-render-standalone-rating",
+        type: WidgetType.rating,
+        scriptInput: undefined,
+        scriptView: undefined,
+        ratingInput: { title: "Rate the deploy" },
+      })
+    );
+    expect(rating).toContain("MOCK_RATING:Rate the deploy");
+
+    const grid = renderWithStore(
+      buildScriptRequest({
+        id: "req-render-standalone-grid",
+        type: WidgetType.grid,
+        scriptInput: undefined,
+        scriptView: undefined,
+        gridInput: { title: "Pick a seat", rows: 1, cols: 1, cells: [{ value: "A1" }] },
+      })
+    );
+    expect(grid).toContain("MOCK_GRID:Pick a seat");
+
+    const display = renderWithStore(
+      buildScriptRequest({
+        id: "req-render-standalone-display",
+        type: WidgetType.display,
+        scriptInput: undefined,
+        scriptView: undefined,
+        displayInput: { title: "Release notes", content: "Shipped" },
+      })
+    );
+    expect(display).toContain("MOCK_DISPLAY_DIALOG:Release notes");
+  });
 });
@@ -1,7 +1,12 @@
 import React from "react";
 import { useSelector } from "react-redux";
 import { RootState } from "@/store/store";
-import { submitResponse, submitScriptEvent, touchRequest } from "@/services/websocket";
+import {
+  ScriptEventError,
+  submitResponse,
+  submitScriptEvent,
+  touchRequest,
+} from "@/services/websocket";
 import { ConfirmDialog } from "./widgets/ConfirmDialog";
 import { SelectDialog } from "./widgets/SelectDialog";
 import { TableDialog } from "./widgets/TableDialog";
@@ -10,17 +15,37 @@ import { UploadDialog } from "./widgets/UploadDialog";
 import { ImageDialog } from "./widgets/ImageDialog";
 import { GridDialog } from "./widgets/GridDialog";
 import { DisplayWidget } from "./widgets/DisplayWidget";
+import { DisplayDialog } from "./widgets/DisplayDialog";
 import { RatingDialog } from "./widgets/RatingDialog";
+import { DiffDialog } from "./widgets/DiffDialog";
+import { KeyValueCard, KeyValueDialog } from "./widgets/KeyValueDialog";
+import { ChecklistDialog } from "./widgets/ChecklistDialog";
 import { Loader2 } from "lucide-react";
 import { WidgetType } from "@/proto/generated/plz_confirm/v1/request";
 import { Button } from "@/components/ui/button";
 import { toast } from "sonner";
 
+// Script widgets that only show context; a sectioned view pairs them with
+// one or more interactive sections. Several interactive sections each carry
+// an id and are submitted together as data.sections.
+const READ_ONLY_SCRIPT_WIDGETS = new Set(["display", "keyvalue"]);
+
 export const WidgetRenderer: React.FC = () => {
   const { active, loading } = useSelector((state: RootState) => state.request);
   const lastTouchedId = React.useRef<string | null>(null);
   const lastToastKey = React.useRef<string>("");
   const [nowMs, setNowMs] = React.useState(() => Date.now());
+  const [sectionAnswers, setSectionAnswers] = React.useState<
+    Record<string, any>
+  >({});
+  const [sectionErrors, setSectionErrors] = React.useState<
+    Record<string, string>
+  >({});
+
+  React.useEffect(() => {
+    setSectionAnswers({});
+    setSectionErrors({});
+  }, [active?.id, active?.scriptView?.stepId]);
 
   React.useEffect(() => {
     setNowMs(Date.now());
@@ -166,6 +191,35 @@ export const WidgetRenderer: React.FC = () => {
 
   const handleScriptBack = async () => submitScriptWidgetEvent("back");
 
+  const handleSectionAnswer = (sectionId: string) => async (output: any) => {
+    setSectionAnswers(prev => ({ ...prev, [sectionId]: output }));
+    setSectionErrors(prev => {
+      const next = { ...prev };
+      delete next[sectionId];
+      return next;
+    });
+  };
+
+  const handleSubmitAllSections = async () => {
+    try {
+      await submitScriptEvent(active.id, {
+        type: "submit",
+        stepId: active.scriptView?.stepId,
+        data: { sections: sectionAnswers },
+      });
+    } catch (error) {
+      if (error instanceof ScriptEventError && error.sectionErrors.length > 0) {
+        setSectionErrors(
+          Object.fromEntries(
+            error.sectionErrors.map(e => [e.sectionId, e.message])
+          )
+        );
+        return;
+      }
+      console.error("Failed to submit script sections", error);
+    }
+  };
+
   const renderScriptView = () => {
     if (!active.scriptView) {
       return (
@@ -206,25 +260,33 @@ export const WidgetRenderer: React.FC = () => {
     const renderInteractiveScriptWidget = (
       widgetType: string,
       input: any,
-      renderKey: string
+      renderKey: string,
+      onSubmit: (output: any) => Promise<void> = handleScriptSubmit
     ) => {
+      const props = { ...scriptCommonProps, onSubmit };
       switch (widgetType) {
         case "confirm":
-          return <ConfirmDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <ConfirmDialog key={renderKey} {...props} input={input} />;
         case "select":
-          return <SelectDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <SelectDialog key={renderKey} {...props} input={input} />;
         case "table":
-          return <TableDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <TableDialog key={renderKey} {...props} input={input} />;
         case "form":
-          return <FormDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <FormDialog key={renderKey} {...props} input={input} />;
         case "upload":
-          return <UploadDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <UploadDialog key={renderKey} {...props} input={input} />;
         case "image":
-          return <ImageDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <ImageDialog key={renderKey} {...props} input={input} />;
         case "grid":
-          return <GridDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <GridDialog key={renderKey} {...props} input={input} />;
         case "rating":
-          return <RatingDialog key={renderKey} {...scriptCommonProps} input={input} />;
+          return <RatingDialog key={renderKey} {...props} input={input} />;
+        case "diff":
+          return <DiffDialog key={renderKey} {...props} input={input} />;
+        case "keyvalue":
+          return <KeyValueDialog key={renderKey} {...props} input={input} />;
+        case "checklist":
+          return <ChecklistDialog key={renderKey} {...props} input={input} />;
         default:
           return (
             <div className="p-8 border border-destructive/50 bg-destructive/10 text-destructive">
@@ -240,6 +302,7 @@ export const WidgetRenderer: React.FC = () => {
             .trim()
             .toLowerCase(),
           input: (section.input ?? {}) as any,
+          id: section.id ? String(section.id) : "",
         }))
       : [];
 
@@ -255,12 +318,69 @@ export const WidgetRenderer: React.FC = () => {
     }
 
     const interactiveSections = sections.filter(
-      section => section.widgetType !== "display"
+      section => !READ_ONLY_SCRIPT_WIDGETS.has(section.widgetType)
     );
-    if (interactiveSections.length !== 1) {
+    if (interactiveSections.length === 0) {
       return (
         <div className="p-8 border border-destructive/50 bg-destructive/10 text-destructive">
-          ERROR: INVALID_SCRIPT_SECTIONS [exactly one interactive section is required]
+          ERROR: INVALID_SCRIPT_SECTIONS [at least one interactive section is required]
+        </div>
+      );
+    }
+    if (interactiveSections.length > 1) {
+      if (interactiveSections.some(section => !section.id)) {
+        return (
+          <div className="p-8 border border-destructive/50 bg-destructive/10 text-destructive">
+            ERROR: INVALID_SCRIPT_SECTIONS [each interactive section needs an id]
+          </div>
+        );
+      }
+      const answeredCount = interactiveSections.filter(
+        section => section.id in sectionAnswers
+      ).length;
+      return wrapWithBackControl(
+        <div className="space-y-3">
+          {sections.map((section, idx) => {
+            if (section.widgetType === "display") {
+              return <DisplayWidget key={`display-${idx}`} input={section.input} />;
+            }
+            if (section.widgetType === "keyvalue") {
+              return <KeyValueCard key={`keyvalue-${idx}`} input={section.input} />;
+            }
+            const error = sectionErrors[section.id];
+            const answered = section.id in sectionAnswers;
+            return (
+              <div key={`section-${section.id}`} className="space-y-1">
+                {renderInteractiveScriptWidget(
+                  section.widgetType,
+                  section.input,
+                  `${active.scriptView?.stepId || "section-step"}-${section.id}`,
+                  handleSectionAnswer(section.id)
+                )}
+                <div
+                  className={`px-1 text-xs font-mono uppercase ${
+                    error
+                      ? "text-destructive"
+                      : answered
+                        ? "text-green-500"
+                        : "text-muted-foreground"
+                  }`}
+                >
+                  {error ? `ERROR: ${error}` : answered ? "ANSWERED" : "AWAITING_ANSWER"}
+                </div>
+              </div>
+            );
+          })}
+          <div className="flex justify-end">
+            <Button
+              type="button"
+              className="cyber-button"
+              onClick={() => void handleSubmitAllSections()}
+              disabled={loading || answeredCount < interactiveSections.length}
+            >
+              {`SUBMIT ALL (${answeredCount}/${interactiveSections.length})`}
+            </Button>
+          </div>
         </div>
       );
     }
@@ -271,6 +391,9 @@ export const WidgetRenderer: React.FC = () => {
           if (section.widgetType === "display") {
             return <DisplayWidget key={`display-${idx}`} input={section.input} />;
           }
+          if (section.widgetType === "keyvalue") {
+            return <KeyValueCard key={`keyvalue-${idx}`} input={section.input} />;
+          }
           return (
             <React.Fragment key={`interactive-${idx}`}>
               {renderInteractiveScriptWidget(
@@ -312,6 +435,18 @@ export const WidgetRenderer: React.FC = () => {
         return active.imageInput ? (
           <ImageDialog {...commonProps} input={active.imageInput} />
         ) : null;
+      case WidgetType.grid:
+        return active.gridInput ? (
+          <GridDialog {...commonProps} input={active.gridInput} />
+        ) : null;
+      case WidgetType.rating:
+        return active.ratingInput ? (
+          <RatingDialog {...commonProps} input={active.ratingInput} />
+        ) : null;
+      case WidgetType.display:
+        return active.displayInput ? (
+          <DisplayDialog {...commonProps} input={active.displayInput} />
+        ) : null;
       case WidgetType.script:
         return renderScriptView();
       default:
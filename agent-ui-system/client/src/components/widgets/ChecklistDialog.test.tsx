@@ -0,0 +1,46 @@
+import React from "react";
+import { describe, expect, it, vi } from "vitest";
+import { renderToStaticMarkup } from "react-dom/server";
+
+import { ChecklistDialog } from "@/components/widgets/ChecklistDialog";
+
+describe("ChecklistDialog", () => {
+  it("counts unchecked required items and marks optional ones", () => {
+    const onSubmit = vi.fn().mockResolvedValue(undefined);
+    const html = renderToStaticMarkup(
+      React.createElement(ChecklistDialog, {
+        requestId: "req-checklist-1",
+        onSubmit,
+        input: {
+          title: "Before shipping",
+          items: [
+            { id: "tests", label: "Tests pass" },
+            { id: "docs", label: "Docs updated", checked: true },
+            { id: "notes", label: "Release notes", required: false },
+          ],
+        },
+      })
+    );
+    expect(html).toContain("Before shipping");
+    expect(html).toContain("Tests pass");
+    expect(html).toContain("optional");
+    expect(html).toContain("1 REQUIRED_REMAINING");
+  });
+
+  it("allows submit once every required item starts checked", () => {
+    const onSubmit = vi.fn().mockResolvedValue(undefined);
+    const html = renderToStaticMarkup(
+      React.createElement(ChecklistDialog, {
+        requestId: "req-checklist-2",
+        onSubmit,
+        input: {
+          title: "Done",
+          submitText: "SHIP",
+          items: [{ id: "tests", label: "Tests pass", checked: true }],
+        },
+      })
+    );
+    expect(html).toContain("ALL_REQUIRED_CHECKED");
+    expect(html).toContain("SHIP");
+  });
+});
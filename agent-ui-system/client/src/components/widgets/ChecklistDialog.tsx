@@ -0,0 +1,131 @@
+import React from "react";
+import { Loader2 } from "lucide-react";
+
+import { Button } from "@/components/ui/button";
+import { Checkbox } from "@/components/ui/checkbox";
+import { OptionalComment, normalizeOptionalComment } from "./OptionalComment";
+import {
+  ChecklistInput,
+  ChecklistOutput,
+} from "@/proto/generated/plz_confirm/v1/widgets";
+import { cn } from "@/lib/utils";
+
+interface Props {
+  requestId: string;
+  input: ChecklistInput;
+  onSubmit: (output: ChecklistOutput) => Promise<void>;
+  loading?: boolean;
+}
+
+export const ChecklistDialog: React.FC<Props> = ({ input, onSubmit, loading }) => {
+  const items = Array.isArray(input.items) ? input.items : [];
+  const [submitting, setSubmitting] = React.useState(false);
+  const [comment, setComment] = React.useState("");
+  const [checked, setChecked] = React.useState<Set<string>>(
+    () => new Set(items.filter(item => item.checked).map(item => item.id))
+  );
+
+  // Items are required unless the script says otherwise.
+  const missing = items.filter(
+    item => item.required !== false && !checked.has(item.id)
+  ).length;
+
+  const toggle = (id: string) => {
+    setChecked(prev => {
+      const next = new Set(prev);
+      if (next.has(id)) next.delete(id);
+      else next.add(id);
+      return next;
+    });
+  };
+
+  const handleSubmit = async () => {
+    if (missing > 0) return;
+    setSubmitting(true);
+    try {
+      const c = normalizeOptionalComment(comment);
+      await onSubmit({
+        checked: items.filter(item => checked.has(item.id)).map(item => item.id),
+        ...(c ? { comment: c } : {}),
+      });
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  return (
+    <div className="bg-background p-6 md:p-8 min-h-[300px] flex flex-col relative">
+      <div className="space-y-4 mb-6">
+        <h2 className="text-2xl font-display font-bold tracking-tight text-primary uppercase">
+          {input.title}
+        </h2>
+        {input.message && (
+          <p className="text-sm text-muted-foreground font-mono">{input.message}</p>
+        )}
+        <div className="h-px w-full bg-border" />
+      </div>
+
+      <div className="flex-1 space-y-2">
+        {items.map(item => {
+          const isChecked = checked.has(item.id);
+          const required = item.required !== false;
+          return (
+            <label
+              key={item.id}
+              className={cn(
+                "flex items-start gap-3 rounded border p-3 cursor-pointer transition-colors",
+                isChecked
+                  ? "border-primary/60 bg-primary/10"
+                  : "border-border hover:border-primary/40 hover:bg-primary/5"
+              )}
+            >
+              <Checkbox
+                checked={isChecked}
+                onCheckedChange={() => toggle(item.id)}
+                disabled={loading || submitting}
+                className="mt-0.5"
+              />
+              <span className="space-y-1">
+                <span className="block text-sm font-mono">
+                  {item.label}
+                  {!required && (
+                    <span className="ml-2 text-[10px] uppercase text-muted-foreground">
+                      optional
+                    </span>
+                  )}
+                </span>
+                {item.description && (
+                  <span className="block text-xs text-muted-foreground">
+                    {item.description}
+                  </span>
+                )}
+              </span>
+            </label>
+          );
+        })}
+      </div>
+
+      <div className="mt-6 pt-4 border-t border-border space-y-3">
+        <OptionalComment
+          value={comment}
+          onChange={setComment}
+          disabled={loading || submitting}
+        />
+
+        <div className="flex items-center justify-between gap-4">
+          <span className="text-xs font-mono uppercase text-muted-foreground">
+            {missing > 0 ? `${missing} REQUIRED_REMAINING` : "ALL_REQUIRED_CHECKED"}
+          </span>
+          <Button
+            className="cyber-button min-w-[160px]"
+            onClick={handleSubmit}
+            disabled={loading || submitting || missing > 0}
+          >
+            {submitting ? <Loader2 className="mr-2 h-4 w-4 animate-spin" /> : null}
+            {input.submitText || "SUBMIT"}
+          </Button>
+        </div>
+      </div>
+    </div>
+  );
+};
@@ -0,0 +1,45 @@
+import React from "react";
+import { describe, expect, it, vi } from "vitest";
+import { renderToStaticMarkup } from "react-dom/server";
+
+import { DiffDialog, splitRows } from "@/components/widgets/DiffDialog";
+
+describe("DiffDialog", () => {
+  it("renders files, hunk headers, and per-hunk controls", () => {
+    const onSubmit = vi.fn().mockResolvedValue(undefined);
+    const html = renderToStaticMarkup(
+      React.createElement(DiffDialog, {
+        requestId: "req-diff-1",
+        onSubmit,
+        input: {
+          title: "Review changes",
+          files: [
+            {
+              path: "main.go",
+              oldPath: "old.go",
+              hunks: [
+                { id: "h1", header: "@@ -1,2 +1,2 @@", lines: [" package main", "-var x = 1", "+var x = 2"] },
+                { id: "h2", lines: ["+// trailing"] },
+              ],
+            },
+          ],
+        },
+      })
+    );
+    expect(html).toContain("Review changes");
+    expect(html).toContain("old.go → main.go");
+    expect(html).toContain("@@ -1,2 +1,2 @@");
+    expect(html).toContain("+var x = 2");
+    expect((html.match(/aria-pressed/g) || []).length).toBe(4);
+    expect(html).toContain("0/2 HUNKS_REVIEWED");
+  });
+
+  it("pairs removed and added lines for the split layout", () => {
+    expect(splitRows([" a", "-b", "-c", "+d", " e"])).toEqual([
+      [" a", " a"],
+      ["-b", "+d"],
+      ["-c", null],
+      [" e", " e"],
+    ]);
+  });
+});
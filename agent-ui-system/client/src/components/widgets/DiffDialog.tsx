@@ -0,0 +1,237 @@
+import React from "react";
+import { Check, Loader2, X } from "lucide-react";
+
+import { Button } from "@/components/ui/button";
+import { OptionalComment, normalizeOptionalComment } from "./OptionalComment";
+import {
+  DiffHunk,
+  DiffHunkDecision,
+  DiffInput,
+  DiffOutput,
+} from "@/proto/generated/plz_confirm/v1/widgets";
+import { cn } from "@/lib/utils";
+
+interface Props {
+  requestId: string;
+  input: DiffInput;
+  onSubmit: (output: DiffOutput) => Promise<void>;
+  loading?: boolean;
+}
+
+type Decision = "approve" | "reject";
+
+const lineClass = (line: string): string => {
+  if (line.startsWith("+")) return "bg-green-500/10 text-green-400";
+  if (line.startsWith("-")) return "bg-destructive/10 text-destructive";
+  return "text-muted-foreground";
+};
+
+// splitRows pairs removed and added lines of a hunk for the side-by-side
+// layout; context lines appear on both sides.
+export const splitRows = (lines: string[]): [string | null, string | null][] => {
+  const rows: [string | null, string | null][] = [];
+  let removed: string[] = [];
+  let added: string[] = [];
+  const flush = () => {
+    const n = Math.max(removed.length, added.length);
+    for (let i = 0; i < n; i++) {
+      rows.push([removed[i] ?? null, added[i] ?? null]);
+    }
+    removed = [];
+    added = [];
+  };
+  for (const line of lines) {
+    if (line.startsWith("-")) removed.push(line);
+    else if (line.startsWith("+")) added.push(line);
+    else {
+      flush();
+      rows.push([line, line]);
+    }
+  }
+  flush();
+  return rows;
+};
+
+const HunkLines: React.FC<{ hunk: DiffHunk; split: boolean }> = ({ hunk, split }) => {
+  const lines = Array.isArray(hunk.lines) ? hunk.lines : [];
+  if (!split) {
+    return (
+      <pre className="text-xs font-mono overflow-x-auto">
+        {lines.map((line, idx) => (
+          <div key={idx} className={cn("px-2 whitespace-pre", lineClass(line))}>
+            {line || " "}
+          </div>
+        ))}
+      </pre>
+    );
+  }
+  return (
+    <div className="grid grid-cols-2 text-xs font-mono overflow-x-auto">
+      {splitRows(lines).map(([left, right], idx) => (
+        <React.Fragment key={idx}>
+          <div className={cn("px-2 whitespace-pre border-r border-border/40", left ? lineClass(left) : "")}>
+            {left ?? " "}
+          </div>
+          <div className={cn("px-2 whitespace-pre", right ? lineClass(right) : "")}>
+            {right ?? " "}
+          </div>
+        </React.Fragment>
+      ))}
+    </div>
+  );
+};
+
+export const DiffDialog: React.FC<Props> = ({ input, onSubmit, loading }) => {
+  const files = Array.isArray(input.files) ? input.files : [];
+  const hunkIds = files.flatMap(file => (file.hunks ?? []).map(hunk => hunk.id));
+  const split = String(input.layout || "unified").toLowerCase() === "split";
+
+  const [submitting, setSubmitting] = React.useState(false);
+  const [comment, setComment] = React.useState("");
+  const [decisions, setDecisions] = React.useState<Record<string, Decision>>({});
+
+  const undecided = hunkIds.filter(id => !decisions[id]).length;
+  const blocked = Boolean(input.requireAll) && undecided > 0;
+
+  const decide = (id: string, decision: Decision) => {
+    setDecisions(prev => {
+      if (prev[id] === decision) {
+        const { [id]: _removed, ...rest } = prev;
+        return rest;
+      }
+      return { ...prev, [id]: decision };
+    });
+  };
+
+  const decideAll = (decision: Decision) => {
+    setDecisions(Object.fromEntries(hunkIds.map(id => [id, decision])));
+  };
+
+  const handleSubmit = async () => {
+    if (blocked) return;
+    setSubmitting(true);
+    try {
+      const out: DiffHunkDecision[] = hunkIds
+        .filter(id => decisions[id])
+        .map(id => ({ hunkId: id, decision: decisions[id] }));
+      const c = normalizeOptionalComment(comment);
+      await onSubmit({
+        decisions: out,
+        approved: out.every(d => d.decision === "approve"),
+        ...(c ? { comment: c } : {}),
+      });
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  const disabled = loading || submitting;
+
+  return (
+    <div className="bg-background p-6 md:p-8 flex flex-col relative">
+      <div className="space-y-4 mb-6">
+        <h2 className="text-2xl font-display font-bold tracking-tight text-primary uppercase">
+          {input.title}
+        </h2>
+        {input.message && (
+          <p className="text-sm text-muted-foreground font-mono">{input.message}</p>
+        )}
+        <div className="flex items-center justify-between gap-2">
+          <span className="text-xs font-mono uppercase text-muted-foreground">
+            {hunkIds.length - undecided}/{hunkIds.length} HUNKS_REVIEWED
+          </span>
+          <div className="flex gap-2">
+            <Button
+              type="button"
+              variant="outline"
+              className="h-8 px-3 text-xs font-mono"
+              onClick={() => decideAll("approve")}
+              disabled={disabled}
+            >
+              APPROVE_ALL
+            </Button>
+            <Button
+              type="button"
+              variant="outline"
+              className="h-8 px-3 text-xs font-mono"
+              onClick={() => decideAll("reject")}
+              disabled={disabled}
+            >
+              REJECT_ALL
+            </Button>
+          </div>
+        </div>
+      </div>
+
+      <div className="flex-1 space-y-4">
+        {files.map((file, fileIdx) => (
+          <div key={`${file.path}-${fileIdx}`} className="rounded border border-border/60">
+            <div className="px-3 py-2 border-b border-border/60 bg-muted/20 text-xs font-mono">
+              {file.oldPath && file.oldPath !== file.path
+                ? `${file.oldPath} → ${file.path}`
+                : file.path}
+            </div>
+            {(file.hunks ?? []).map(hunk => {
+              const decision = decisions[hunk.id];
+              return (
+                <div key={hunk.id} className="border-b border-border/40 last:border-b-0">
+                  <div className="flex items-center justify-between gap-2 px-3 py-1 bg-primary/5">
+                    <span className="text-[10px] font-mono text-primary/80 truncate">
+                      {hunk.header || hunk.id}
+                    </span>
+                    <div className="flex gap-1">
+                      <Button
+                        type="button"
+                        variant="outline"
+                        aria-pressed={decision === "approve"}
+                        className={cn(
+                          "h-7 px-2 text-[10px] font-mono",
+                          decision === "approve" && "border-green-500 text-green-500"
+                        )}
+                        onClick={() => decide(hunk.id, "approve")}
+                        disabled={disabled}
+                      >
+                        <Check className="mr-1 h-3 w-3" />
+                        APPROVE
+                      </Button>
+                      <Button
+                        type="button"
+                        variant="outline"
+                        aria-pressed={decision === "reject"}
+                        className={cn(
+                          "h-7 px-2 text-[10px] font-mono",
+                          decision === "reject" && "border-destructive text-destructive"
+                        )}
+                        onClick={() => decide(hunk.id, "reject")}
+                        disabled={disabled}
+                      >
+                        <X className="mr-1 h-3 w-3" />
+                        REJECT
+                      </Button>
+                    </div>
+                  </div>
+                  <HunkLines hunk={hunk} split={split} />
+                </div>
+              );
+            })}
+          </div>
+        ))}
+      </div>
+
+      <div className="mt-6 pt-4 border-t border-border space-y-3">
+        <OptionalComment value={comment} onChange={setComment} disabled={disabled} />
+
+        <div className="flex justify-end">
+          <Button
+            className="cyber-button min-w-[160px]"
+            onClick={handleSubmit}
+            disabled={disabled || blocked}
+          >
+            {submitting ? <Loader2 className="mr-2 h-4 w-4 animate-spin" /> : null}
+            SUBMIT_REVIEW
+          </Button>
+        </div>
+      </div>
+    </div>
+  );
+};
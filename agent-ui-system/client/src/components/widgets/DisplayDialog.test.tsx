@@ -0,0 +1,38 @@
+import React from "react";
+import { describe, expect, it, vi } from "vitest";
+import { renderToStaticMarkup } from "react-dom/server";
+
+import { DisplayDialog } from "@/components/widgets/DisplayDialog";
+
+describe("DisplayDialog", () => {
+  it("renders title, content, and the acknowledge button", () => {
+    const onSubmit = vi.fn().mockResolvedValue(undefined);
+    const html = renderToStaticMarkup(
+      React.createElement(DisplayDialog, {
+        requestId: "req-display-1",
+        onSubmit,
+        input: {
+          title: "Release notes",
+          content: "Version 1.4.0 ships today.",
+          format: "text",
+          acknowledgeText: "GOT_IT",
+        },
+      })
+    );
+    expect(html).toContain("Release notes");
+    expect(html).toContain("Version 1.4.0 ships today.");
+    expect(html).toContain("GOT_IT");
+  });
+
+  it("falls back to the default acknowledge label", () => {
+    const onSubmit = vi.fn().mockResolvedValue(undefined);
+    const html = renderToStaticMarkup(
+      React.createElement(DisplayDialog, {
+        requestId: "req-display-2",
+        onSubmit,
+        input: { content: "Hello" },
+      })
+    );
+    expect(html).toContain("ACKNOWLEDGE");
+  });
+});
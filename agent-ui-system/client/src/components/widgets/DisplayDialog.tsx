@@ -0,0 +1,76 @@
+import React from "react";
+import { Check, Loader2 } from "lucide-react";
+
+import { Button } from "@/components/ui/button";
+import { DisplayWidget } from "./DisplayWidget";
+import { OptionalComment, normalizeOptionalComment } from "./OptionalComment";
+import {
+  DisplayInput,
+  DisplayOutput,
+} from "@/proto/generated/plz_confirm/v1/widgets";
+
+interface Props {
+  requestId: string;
+  input: DisplayInput;
+  onSubmit: (output: DisplayOutput) => Promise<void>;
+  loading?: boolean;
+}
+
+export const DisplayDialog: React.FC<Props> = ({ input, onSubmit, loading }) => {
+  const [submitting, setSubmitting] = React.useState(false);
+  const [comment, setComment] = React.useState("");
+
+  const handleAcknowledge = async () => {
+    setSubmitting(true);
+    try {
+      const c = normalizeOptionalComment(comment);
+      await onSubmit({
+        acknowledged: true,
+        timestamp: new Date().toISOString(),
+        ...(c ? { comment: c } : {}),
+      });
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  return (
+    <div className="bg-background p-6 md:p-8 min-h-[300px] flex flex-col relative">
+      {input.title && (
+        <div className="space-y-4 mb-6">
+          <h2 className="text-2xl font-display font-bold tracking-tight text-primary uppercase">
+            {input.title}
+          </h2>
+          <div className="h-px w-full bg-border" />
+        </div>
+      )}
+
+      <div className="flex-1">
+        <DisplayWidget input={input} />
+      </div>
+
+      <div className="mt-6 pt-4 border-t border-border space-y-3">
+        <OptionalComment
+          value={comment}
+          onChange={setComment}
+          disabled={loading || submitting}
+        />
+
+        <div className="flex justify-end">
+          <Button
+            className="cyber-button min-w-[160px]"
+            onClick={handleAcknowledge}
+            disabled={loading || submitting}
+          >
+            {submitting ? (
+              <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+            ) : (
+              <Check className="mr-2 h-4 w-4" />
+            )}
+            {input.acknowledgeText || "ACKNOWLEDGE"}
+          </Button>
+        </div>
+      </div>
+    </div>
+  );
+};
@@ -0,0 +1,41 @@
+import React from "react";
+import { describe, expect, it, vi } from "vitest";
+import { renderToStaticMarkup } from "react-dom/server";
+
+import { KeyValueCard, KeyValueDialog } from "@/components/widgets/KeyValueDialog";
+
+describe("KeyValueDialog", () => {
+  it("renders keys and values without a submit button as a card", () => {
+    const html = renderToStaticMarkup(
+      React.createElement(KeyValueCard, {
+        input: {
+          title: "Release",
+          items: [
+            { key: "Version", value: "1.4.0", monospace: true },
+            { key: "Breaking", value: false as any, style: "danger" },
+          ],
+        },
+      })
+    );
+    expect(html).toContain("Release");
+    expect(html).toContain("1.4.0");
+    expect(html).toContain(">false</dd>");
+    expect(html).not.toContain("<button");
+  });
+
+  it("renders a continue button when shown on its own", () => {
+    const onSubmit = vi.fn().mockResolvedValue(undefined);
+    const html = renderToStaticMarkup(
+      React.createElement(KeyValueDialog, {
+        requestId: "req-kv-1",
+        onSubmit,
+        input: {
+          title: "Summary",
+          confirmText: "LOOKS_GOOD",
+          items: [{ key: "Env", value: "prod" }],
+        },
+      })
+    );
+    expect(html).toContain("LOOKS_GOOD");
+  });
+});
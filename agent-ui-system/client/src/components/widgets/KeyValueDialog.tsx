@@ -0,0 +1,115 @@
+import React from "react";
+import { Loader2 } from "lucide-react";
+
+import { Button } from "@/components/ui/button";
+import { OptionalComment, normalizeOptionalComment } from "./OptionalComment";
+import {
+  KeyValueInput,
+  KeyValueOutput,
+} from "@/proto/generated/plz_confirm/v1/widgets";
+import { cn } from "@/lib/utils";
+
+const styleClass = (raw: unknown): string => {
+  switch (String(raw || "default").toLowerCase()) {
+    case "muted":
+      return "text-muted-foreground";
+    case "success":
+      return "text-green-500";
+    case "warning":
+      return "text-yellow-500";
+    case "danger":
+      return "text-destructive";
+    default:
+      return "text-foreground";
+  }
+};
+
+interface CardProps {
+  input: KeyValueInput;
+}
+
+// KeyValueCard is the read-only summary; scripts use it as a section next to
+// the interactive widget.
+export const KeyValueCard: React.FC<CardProps> = ({ input }) => {
+  const items = Array.isArray(input.items) ? input.items : [];
+  return (
+    <div className="rounded border border-border/60 bg-muted/20 p-4 space-y-3">
+      {input.title && (
+        <h3 className="text-sm font-display font-bold tracking-widest text-primary uppercase">
+          {input.title}
+        </h3>
+      )}
+      {input.message && (
+        <p className="text-sm text-muted-foreground font-mono">{input.message}</p>
+      )}
+      <dl className="grid grid-cols-[minmax(0,1fr)_minmax(0,2fr)] gap-x-4 gap-y-2 text-sm">
+        {items.map((item, idx) => (
+          <React.Fragment key={`${item.key}-${idx}`}>
+            <dt className="font-mono text-xs uppercase text-muted-foreground truncate">
+              {item.key}
+            </dt>
+            <dd
+              className={cn(
+                "break-words",
+                item.monospace && "font-mono",
+                styleClass(item.style)
+              )}
+            >
+              {String(item.value ?? "")}
+            </dd>
+          </React.Fragment>
+        ))}
+      </dl>
+    </div>
+  );
+};
+
+interface Props {
+  requestId: string;
+  input: KeyValueInput;
+  onSubmit: (output: KeyValueOutput) => Promise<void>;
+  loading?: boolean;
+}
+
+export const KeyValueDialog: React.FC<Props> = ({ input, onSubmit, loading }) => {
+  const [submitting, setSubmitting] = React.useState(false);
+  const [comment, setComment] = React.useState("");
+
+  const handleSubmit = async () => {
+    setSubmitting(true);
+    try {
+      const c = normalizeOptionalComment(comment);
+      await onSubmit({
+        acknowledged: true,
+        ...(c ? { comment: c } : {}),
+      });
+    } finally {
+      setSubmitting(false);
+    }
+  };
+
+  return (
+    <div className="bg-background p-6 md:p-8 flex flex-col relative">
+      <KeyValueCard input={input} />
+
+      <div className="mt-6 pt-4 border-t border-border space-y-3">
+        <OptionalComment
+          value={comment}
+          onChange={setComment}
+          disabled={loading || submitting}
+        />
+
+        <div className="flex justify-end">
+          <Button
+            className="cyber-button min-w-[160px]"
+            onClick={handleSubmit}
+            disabled={loading || submitting}
+          >
+            {submitting ? <Loader2 className="mr-2 h-4 w-4 animate-spin" /> : null}
+            {input.confirmText || "CONTINUE"}
+          </Button>
+        </div>
+      </div>
+    </div>
+  );
+};
@@ -33,7 +33,10 @@ export default function Home() {
     req.formOutput?.comment ??
     req.uploadOutput?.comment ??
     req.tableOutput?.comment ??
-    req.imageOutput?.comment;
+    req.imageOutput?.comment ??
+    req.gridOutput?.comment ??
+    req.ratingOutput?.comment ??
+    req.displayOutput?.comment;
 
   // Simulate receiving a new request if none is active
   const simulateNewRequest = (type: WidgetType) => {
@@ -103,6 +106,8 @@ export default function Home() {
                       createdAt: new Date().toISOString(),
                       expiresAt: new Date(Date.now() + 300000).toISOString(),
                       scriptLogs: [],
+                      priority: 0,
+                      dependsOn: [],
                       formInput: {
                         title: "CONFIGURE_DATABASE",
                         schema: {
@@ -140,6 +145,8 @@ export default function Home() {
                       createdAt: new Date().toISOString(),
                       expiresAt: new Date(Date.now() + 300000).toISOString(),
                       scriptLogs: [],
+                      priority: 0,
+                      dependsOn: [],
                       uploadInput: {
                         title: "UPLOAD_LOGS",
                         accept: [".log", ".txt"],
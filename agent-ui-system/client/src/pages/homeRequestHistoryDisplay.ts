@@ -32,6 +32,9 @@ const resolveStandardTitle = (req: UIRequest): string =>
   req.uploadInput?.title ||
   req.tableInput?.title ||
   req.imageInput?.title ||
+  req.gridInput?.title ||
+  req.ratingInput?.title ||
+  req.displayInput?.title ||
   UNKNOWN_REQUEST;
 
 const resolveScriptCompletedMeta = (req: UIRequest): string | undefined => {
@@ -8,10 +8,16 @@
 import type {
   ConfirmInput,
   ConfirmOutput,
+  DisplayInput,
+  DisplayOutput,
   FormInput,
   FormOutput,
+  GridInput,
+  GridSelection,
   ImageInput,
   ImageOutput,
+  RatingInput,
+  RatingOutput,
   ScriptDescribe,
   ScriptInput,
   ScriptOutput,
@@ -37,6 +43,7 @@ export enum RequestStatus {
   completed = 2,
   timeout = 3,
   error = 4,
+  cancelled = 5,
   UNRECOGNIZED = -1,
 }
 
@@ -54,6 +61,20 @@ export enum WidgetType {
   table = 5,
   image = 6,
   script = 7,
+  grid = 8,
+  rating = 9,
+  display = 10,
+  UNRECOGNIZED = -1,
+}
+
+/** SessionStatus enum */
+export enum SessionStatus {
+  session_status_unspecified = 0,
+  active = 1,
+  /** archived - Hidden from default listings, still accepts requests */
+  archived = 2,
+  /** closed - Pending requests cancelled, new requests rejected */
+  closed = 3,
   UNRECOGNIZED = -1,
 }
 
@@ -72,6 +93,59 @@ export interface RequestMetadata {
   userAgent?: string | undefined;
 }
 
+/** SessionViewer describes one connected WebSocket client of a session. */
+export interface SessionViewer {
+  /** RFC3339Nano timestamp */
+  connectedAt: string;
+  /** RFC3339Nano timestamp */
+  lastActivityAt: string;
+}
+
+/** SessionPresence reports whether anyone is watching a session. */
+export interface SessionPresence {
+  sessionId: string;
+  connected: boolean;
+  viewerCount: number;
+  viewers: SessionViewer[];
+  /** RFC3339Nano timestamp (latest viewer activity, survives disconnects) */
+  lastActivityAt?: string | undefined;
+}
+
+export interface SessionMetadata {
+  agentName?: string | undefined;
+  project?: string | undefined;
+  labels: { [key: string]: string };
+}
+
+export interface SessionMetadata_LabelsEntry {
+  key: string;
+  value: string;
+}
+
+/** Session - first-class grouping for requests sharing a session_id */
+export interface Session {
+  id: string;
+  name: string;
+  status: SessionStatus;
+  metadata?:
+    | SessionMetadata
+    | undefined;
+  /** RFC3339Nano timestamp */
+  createdAt: string;
+  /** RFC3339Nano timestamp */
+  updatedAt: string;
+  archivedAt?: string | undefined;
+  closedAt?:
+    | string
+    | undefined;
+  /** Computed on read */
+  pendingCount: number;
+}
+
+export interface SessionList {
+  sessions: Session[];
+}
+
 /** UIRequest - main request/response envelope */
 export interface UIRequest {
   id: string;
@@ -84,6 +158,9 @@ export interface UIRequest {
   tableInput?: TableInput | undefined;
   imageInput?: ImageInput | undefined;
   scriptInput?: ScriptInput | undefined;
+  gridInput?: GridInput | undefined;
+  ratingInput?: RatingInput | undefined;
+  displayInput?: DisplayInput | undefined;
   confirmOutput?: ConfirmOutput | undefined;
   selectOutput?: SelectOutput | undefined;
   formOutput?: FormOutput | undefined;
@@ -91,6 +168,9 @@ export interface UIRequest {
   tableOutput?: TableOutput | undefined;
   imageOutput?: ImageOutput | undefined;
   scriptOutput?: ScriptOutput | undefined;
+  gridOutput?: GridSelection | undefined;
+  ratingOutput?: RatingOutput | undefined;
+  displayOutput?: DisplayOutput | undefined;
   status: RequestStatus;
   /** RFC3339Nano timestamp */
   createdAt: string;
@@ -113,4 +193,28 @@ export interface UIRequest {
   scriptView?: ScriptView | undefined;
   scriptDescribe?: ScriptDescribe | undefined;
   scriptLogs: string[];
+  /** Only populated on create responses */
+  presence?:
+    | SessionPresence
+    | undefined;
+  /** Higher values are shown first (default 0) */
+  priority: number;
+  /** If true, sooner expires_at sorts first within a priority */
+  deadlineAware?:
+    | boolean
+    | undefined;
+  /** Request IDs that must complete (and not be rejected) first */
+  dependsOn: string[];
+  /** Retries with the same key return the original request */
+  idempotencyKey?:
+    | string
+    | undefined;
+  /** ctx.locale the current script view was rendered for */
+  scriptLocale?: string | undefined;
+}
+
+/** PriorityUpdate - body of POST /api/requests/{id}/priority; unset fields stay unchanged */
+export interface PriorityUpdate {
+  priority?: number | undefined;
+  deadlineAware?: boolean | undefined;
 }
@@ -41,7 +41,7 @@ export interface SelectOutputMulti {
   values: string[];
 }
 
-/** Grid Widget (script view extension) */
+/** Grid Widget */
 export interface GridCell {
   value: string;
   style?: string | undefined;
@@ -62,9 +62,10 @@ export interface GridSelection {
   row: number;
   col: number;
   cellIndex: number;
+  comment?: string | undefined;
 }
 
-/** Rating Widget (script view extension) */
+/** Rating Widget */
 export interface RatingLabels {
   low?: string | undefined;
   high?: string | undefined;
@@ -180,12 +181,172 @@ export interface ImageOutputStrings {
   values: string[];
 }
 
+/** Diff Widget (script view extension) */
+export interface DiffHunk {
+  /** Unique within the view; echoed in DiffHunkDecision */
+  id: string;
+  /** e.g. "@@ -10,4 +10,6 @@" */
+  header?:
+    | string
+    | undefined;
+  /** Unified diff lines starting with " ", "+", or "-" */
+  lines: string[];
+}
+
+export interface DiffFile {
+  path: string;
+  /** Set for renames */
+  oldPath?:
+    | string
+    | undefined;
+  /** Syntax hint, e.g. "go" */
+  language?: string | undefined;
+  hunks: DiffHunk[];
+}
+
+export interface DiffInput {
+  title: string;
+  message?: string | undefined;
+  files: DiffFile[];
+  /** "unified" (default) | "split" */
+  layout?:
+    | string
+    | undefined;
+  /** Every hunk needs a decision before submit */
+  requireAll?: boolean | undefined;
+}
+
+export interface DiffHunkDecision {
+  hunkId: string;
+  /** "approve" | "reject" */
+  decision: string;
+  comment?: string | undefined;
+}
+
+export interface DiffOutput {
+  decisions: DiffHunkDecision[];
+  /** No hunk was rejected */
+  approved: boolean;
+  comment?: string | undefined;
+}
+
+/** Key-Value Widget (script view extension) */
+export interface KeyValueItem {
+  key: string;
+  value: string;
+  /** "default" | "muted" | "success" | "warning" | "danger" */
+  style?: string | undefined;
+  monospace?: boolean | undefined;
+}
+
+export interface KeyValueInput {
+  title: string;
+  message?: string | undefined;
+  items: KeyValueItem[];
+  /** Continue button label when shown on its own */
+  confirmText?: string | undefined;
+}
+
+export interface KeyValueOutput {
+  acknowledged: boolean;
+  comment?: string | undefined;
+}
+
+/** Checklist Widget (script view extension) */
+export interface ChecklistItem {
+  id: string;
+  label: string;
+  description?:
+    | string
+    | undefined;
+  /** Defaults to true: must be ticked before submit */
+  required?:
+    | boolean
+    | undefined;
+  /** Initially ticked */
+  checked?: boolean | undefined;
+}
+
+export interface ChecklistInput {
+  title: string;
+  message?: string | undefined;
+  items: ChecklistItem[];
+  submitText?: string | undefined;
+}
+
+export interface ChecklistOutput {
+  /** IDs of ticked items */
+  checked: string[];
+  comment?: string | undefined;
+}
+
 /** Script Widget */
 export interface ScriptInput {
   title: string;
   script: string;
   props?: { [key: string]: any } | undefined;
-  timeoutMs?: number | undefined;
+  timeoutMs?:
+    | number
+    | undefined;
+  /** Registered script to run instead of inline script */
+  scriptRef?:
+    | ScriptRef
+    | undefined;
+  /** "javascript" (default) | "typescript" */
+  language?: string | undefined;
+}
+
+export interface ScriptRef {
+  name: string;
+  /** Latest when unset; the server records the resolved version */
+  version?:
+    | number
+    | undefined;
+  /** Set by the server to the digest of the resolved source */
+  sha256: string;
+}
+
+export interface RegisteredScript {
+  name: string;
+  version: number;
+  script: string;
+  sha256: string;
+  description?: string | undefined;
+  createdAt: string;
+  /** "javascript" (default) | "typescript" */
+  language?: string | undefined;
+}
+
+export interface RegisteredScriptList {
+  scripts: RegisteredScript[];
+}
+
+/** Result of POST /api/scripts/validate. */
+export interface ScriptValidation {
+  /** No issue has severity "error" */
+  ok: boolean;
+  issues: ScriptLintIssue[];
+  /** Initial step plus steps reached through ctx.branch */
+  steps: ScriptLintStep[];
+  describe?: ScriptDescribe | undefined;
+}
+
+export interface ScriptLintIssue {
+  /** "error" | "warning" */
+  severity: string;
+  /** "compile" | "init" | "view" | "walk" */
+  phase: string;
+  /** state.step the issue was found at */
+  step?:
+    | string
+    | undefined;
+  message: string;
+}
+
+export interface ScriptLintStep {
+  step: string;
+  widgetType: string;
+  stepId?: string | undefined;
 }
 
 export interface ScriptOutput {
@@ -198,18 +359,123 @@ export interface ScriptEvent {
   type: string;
   stepId?: string | undefined;
   actionId?: string | undefined;
-  data?: { [key: string]: any } | undefined;
+  data?:
+    | { [key: string]: any }
+    | undefined;
+  /**
+   * Who sent the event: "ui" (browser), "agent" (inject endpoint), or
+   * "schedule" (scheduled tick). Set by the server; client values are ignored.
+   */
+  source: string;
+}
+
+/** One change between a script's state before and after an event. */
+export interface ScriptStateChange {
+  /** Dotted path into state, e.g. "form.env" */
+  path: string;
+  /** "add" | "remove" | "replace" */
+  op: string;
+  before?: any | undefined;
+  after?: any | undefined;
+}
+
+export interface ScriptHistoryEntry {
+  seq: number;
+  /** When the event was handled (RFC3339) */
+  at: string;
+  /** Unset for the init entry */
+  event?: ScriptEvent | undefined;
+  changes: ScriptStateChange[];
+  /** View step shown after the event */
+  stepId?: string | undefined;
+  widgetType?: string | undefined;
+  durationMs: number;
+  logs: string[];
+  done: boolean;
+  /** Set when the script failed on this event */
+  error?:
+    | string
+    | undefined;
+  /** ctx.locale for the run */
+  locale?: string | undefined;
+}
+
+/** Result of GET /api/requests/{id}/script/history. */
+export interface ScriptHistory {
+  requestId: string;
+  entries: ScriptHistoryEntry[];
+  /** Oldest entries discarded to stay within the cap */
+  dropped: number;
+}
+
+/**
+ * Body of POST /api/requests/{id}/script/replay. Leave it empty to replay the
+ * recorded script; set script or script_ref to check another version against
+ * the recorded events.
+ */
+export interface ScriptReplayRequest {
+  script?: string | undefined;
+  scriptRef?: ScriptRef | undefined;
+  /** "javascript" (default) | "typescript"; applies to script */
+  language?: string | undefined;
+}
+
+/** Result of POST /api/requests/{id}/script/replay. */
+export interface ScriptReplay {
+  requestId: string;
+  /** Every replayed run and the final result matched the recording */
+  reproduced: boolean;
+  /** Replayed runs; at is the frozen clock of each run */
+  entries: ScriptHistoryEntry[];
+  /** First difference found; the replay stops there */
+  mismatch?:
+    | string
+    | undefined;
+  /** Result of the replayed flow, if it finished */
+  result?:
+    | { [key: string]: any }
+    | undefined;
+  /** Registered script version that was replayed */
+  scriptRef?: ScriptRef | undefined;
 }
 
 export interface ScriptViewSection {
   widgetType: string;
   input?: { [key: string]: any } | undefined;
+  /** Keys the answer in event.data.sections; required with several interactive sections */
+  id?: string | undefined;
+}
+
+/**
+ * Body of the 422 response to a browser submit whose section answers failed
+ * validation. Nothing is passed to update().
+ */
+export interface ScriptSectionErrors {
+  message: string;
+  errors: ScriptSectionError[];
+}
+
+export interface ScriptSectionError {
+  sectionId: string;
+  message: string;
 }
 
 export interface DisplayInput {
   content: string;
   /** "markdown" | "text" | "html" */
   format?: string | undefined;
+  title?:
+    | string
+    | undefined;
+  /** Button label for standalone display requests */
+  acknowledgeText?: string | undefined;
+}
+
+export interface DisplayOutput {
+  acknowledged: boolean;
+  /** ISO 8601 */
+  timestamp: string;
+  comment?: string | undefined;
 }
 
 export interface ScriptProgress {
@@ -245,4 +511,36 @@ export interface ScriptDescribe {
   version: string;
   apiVersion?: string | undefined;
   capabilities: string[];
+  /** Capabilities the server actually granted */
+  grants: ScriptGrant[];
+  /** Library modules the script loaded */
+  modules: ScriptModule[];
+  /** JSON Schema the final result must match */
+  outputSchema?:
+    | { [key: string]: any }
+    | undefined;
+  /** Message catalogs for ctx.t, keyed by locale */
+  messages?:
+    | { [key: string]: any }
+    | undefined;
+  /** Locale used when no catalog matches the responder */
+  defaultLocale?: string | undefined;
+}
+
+export interface ScriptModule {
+  /** e.g. "plz/wizard" */
+  name: string;
+  version: string;
+  /** Hex digest of the module source */
+  sha256: string;
+}
+
+export interface ScriptGrant {
+  capability: string;
+  /** fs: allowed roots; fetch: allowed hosts */
+  scope: string[];
+  /** fetch: calls made so far for this request */
+  callsUsed: number;
+  /** fetch: response bytes read so far for this request */
+  bytesUsed: number;
 }
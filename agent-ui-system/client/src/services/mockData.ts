@@ -18,6 +18,8 @@ export const MOCK_REQUESTS: UIRequest[] = [
     createdAt: new Date().toISOString(),
     expiresAt: new Date(Date.now() + 300000).toISOString(), // 5 mins
     scriptLogs: [],
+    priority: 0,
+    dependsOn: [],
   },
   {
     id: nanoid(),
@@ -35,6 +37,8 @@ export const MOCK_REQUESTS: UIRequest[] = [
     completedAt: new Date(Date.now() - 3500000).toISOString(),
     expiresAt: new Date(Date.now() - 3300000).toISOString(),
     scriptLogs: [],
+    priority: 0,
+    dependsOn: [],
   },
   {
     id: nanoid(),
@@ -57,6 +61,8 @@ export const MOCK_REQUESTS: UIRequest[] = [
     createdAt: new Date().toISOString(),
     expiresAt: new Date(Date.now() + 600000).toISOString(),
     scriptLogs: [],
+    priority: 0,
+    dependsOn: [],
   }
 ];
 
@@ -12,6 +12,7 @@ import {
   UIRequest,
   WidgetType,
 } from "@/proto/generated/plz_confirm/v1/request";
+import { ScriptSectionError } from "@/proto/generated/plz_confirm/v1/widgets";
 import { normalizeUIRequest } from "@/proto/normalize";
 
 let ws: WebSocket | null = null;
@@ -97,6 +98,9 @@ export const connectWebSocket = () => {
           request.uploadInput?.title ||
           request.tableInput?.title ||
           request.imageInput?.title ||
+          request.gridInput?.title ||
+          request.ratingInput?.title ||
+          request.displayInput?.title ||
           "New Request";
         const requestTypeLabel = (WidgetType as any)[request.type] ?? "unknown";
         browserNotificationService.showRequestNotification(
@@ -194,6 +198,12 @@ function buildSubmitResponseBody(requestType: WidgetType, output: any): any {
       return { type: String(typeLabel), sessionId, tableOutput: output };
     case WidgetType.image:
       return { type: String(typeLabel), sessionId, imageOutput: output };
+    case WidgetType.grid:
+      return { type: String(typeLabel), sessionId, gridOutput: output };
+    case WidgetType.rating:
+      return { type: String(typeLabel), sessionId, ratingOutput: output };
+    case WidgetType.display:
+      return { type: String(typeLabel), sessionId, displayOutput: output };
     default:
       return { type: String(typeLabel), sessionId };
   }
@@ -230,6 +240,18 @@ export const submitResponse = async (
   }
 };
 
+// ScriptEventError is thrown by submitScriptEvent when the server rejects an
+// event. sectionErrors lists per-section problems of a multi-section submit.
+export class ScriptEventError extends Error {
+  sectionErrors: ScriptSectionError[];
+
+  constructor(message: string, sectionErrors: ScriptSectionError[] = []) {
+    super(message);
+    this.name = "ScriptEventError";
+    this.sectionErrors = sectionErrors;
+  }
+}
+
 export const submitScriptEvent = async (
   requestId: string,
   event: {
@@ -248,8 +270,17 @@ export const submitScriptEvent = async (
       body: JSON.stringify(event),
     });
 
+    if (response.status === 422) {
+      const body = await response.json().catch(() => null);
+      if (body && Array.isArray(body.errors)) {
+        throw new ScriptEventError(
+          body.message || "Some answers need attention",
+          body.errors
+        );
+      }
+    }
     if (!response.ok) {
-      throw new Error("Failed to submit script event");
+      throw new ScriptEventError("Failed to submit script event");
     }
 
     const json = await response.json();
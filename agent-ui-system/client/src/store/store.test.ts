@@ -89,3 +89,42 @@ describe("request reducer script flow", () => {
     expect(requestState.history[0]?.status).toBe(RequestStatus.completed);
   });
 });
+
+describe("request reducer queue order", () => {
+  it("puts deadline-aware requests closest to expiry first within a priority", () => {
+    const store = createAppStore();
+    store.dispatch(enqueueRequest(buildScriptRequest({ id: "active" })));
+    store.dispatch(
+      enqueueRequest(
+        buildScriptRequest({ id: "plain", expiresAt: "2026-02-22T00:01:00Z" })
+      )
+    );
+    store.dispatch(
+      enqueueRequest(
+        buildScriptRequest({
+          id: "late",
+          deadlineAware: true,
+          expiresAt: "2026-02-22T00:04:00Z",
+        })
+      )
+    );
+    store.dispatch(
+      enqueueRequest(
+        buildScriptRequest({
+          id: "soon",
+          deadlineAware: true,
+          expiresAt: "2026-02-22T00:02:00Z",
+        })
+      )
+    );
+    store.dispatch(
+      enqueueRequest(buildScriptRequest({ id: "urgent", priority: 1 }))
+    );
+
+    const order = () => store.getState().request.pending.map(r => r.id);
+    expect(order()).toEqual(["urgent", "soon", "late", "plain"]);
+
+    store.dispatch(patchRequest({ id: "soon", expiryDisabled: true }));
+    expect(order()).toEqual(["urgent", "late", "plain", "soon"]);
+  });
+});
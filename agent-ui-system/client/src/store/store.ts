@@ -47,6 +47,36 @@ interface RequestState {
 
 type RequestPatch = Partial<UIRequest> & { id: string };
 
+// The expiry a deadline-aware request is racing against, in ms. Requests
+// whose expiry was disabled (touched) no longer have one.
+const urgencyDeadline = (req: UIRequest): number | null => {
+  if (!req.deadlineAware || req.expiryDisabled) return null;
+  const t = Date.parse(req.expiresAt);
+  return Number.isNaN(t) ? null : t;
+};
+
+// Same order as the server's pending list: higher priority first, then
+// deadline-aware requests closest to expiry, then arrival order.
+const comparePending = (a: UIRequest, b: UIRequest) => {
+  const byPriority = (b.priority ?? 0) - (a.priority ?? 0);
+  if (byPriority !== 0) return byPriority;
+  const aDeadline = urgencyDeadline(a);
+  const bDeadline = urgencyDeadline(b);
+  if (aDeadline !== null && bDeadline !== null) return aDeadline - bDeadline;
+  if (aDeadline !== null) return -1;
+  if (bDeadline !== null) return 1;
+  return 0;
+};
+
+const insertByPriority = (pending: UIRequest[], incoming: UIRequest) => {
+  const idx = pending.findIndex(r => comparePending(incoming, r) < 0);
+  if (idx === -1) {
+    pending.push(incoming);
+    return;
+  }
+  pending.splice(idx, 0, incoming);
+};
+
 const initialRequestState: RequestState = {
   active: null,
   pending: [],
@@ -75,7 +105,7 @@ const requestSlice = createSlice({
         state.active = incoming;
         return;
       }
-      state.pending.push(incoming);
+      insertByPriority(state.pending, incoming);
     },
     completeRequest: (
       state: RequestState,
@@ -103,9 +133,16 @@ const requestSlice = createSlice({
       if (state.active?.id === id) {
         state.active = { ...state.active, ...patch };
       }
+      const queued = state.pending.find(r => r.id === id);
       state.pending = state.pending.map(r =>
         r.id === id ? { ...r, ...patch } : r
       );
+      if (queued && comparePending(queued, { ...queued, ...patch }) !== 0) {
+        // Re-prioritized or touched while waiting: move it to its new place
+        // in the queue.
+        state.pending = state.pending.filter(r => r.id !== id);
+        insertByPriority(state.pending, { ...queued, ...patch });
+      }
       state.history = state.history.map(r =>
         r.id === id ? { ...r, ...patch } : r
       );
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	glazed_cli "github.com/go-go-golems/glazed/pkg/cli"
 	"github.com/go-go-golems/glazed/pkg/cmds/schema"
@@ -104,6 +106,85 @@ func main() {
 	}
 	rootCmd.AddCommand(cobraImageCmd)
 
+	gridCmd, err := agentcli.NewGridCommand()
+	if err != nil {
+		fatal(err)
+	}
+	cobraGridCmd, err := glazed_cli.BuildCobraCommand(gridCmd,
+		glazed_cli.WithParserConfig(parserConfig),
+	)
+	if err != nil {
+		fatal(err)
+	}
+	rootCmd.AddCommand(cobraGridCmd)
+
+	ratingCmd, err := agentcli.NewRatingCommand()
+	if err != nil {
+		fatal(err)
+	}
+	cobraRatingCmd, err := glazed_cli.BuildCobraCommand(ratingCmd,
+		glazed_cli.WithParserConfig(parserConfig),
+	)
+	if err != nil {
+		fatal(err)
+	}
+	rootCmd.AddCommand(cobraRatingCmd)
+
+	displayCmd, err := agentcli.NewDisplayCommand()
+	if err != nil {
+		fatal(err)
+	}
+	cobraDisplayCmd, err := glazed_cli.BuildCobraCommand(displayCmd,
+		glazed_cli.WithParserConfig(parserConfig),
+	)
+	if err != nil {
+		fatal(err)
+	}
+	rootCmd.AddCommand(cobraDisplayCmd)
+
+	scriptCmd := &cobra.Command{
+		Use:   "script",
+		Short: "Work with script-driven requests",
+	}
+	injectCmd, err := agentcli.NewInjectCommand()
+	if err != nil {
+		fatal(err)
+	}
+	cobraInjectCmd, err := glazed_cli.BuildCobraCommand(injectCmd,
+		glazed_cli.WithParserConfig(parserConfig),
+	)
+	if err != nil {
+		fatal(err)
+	}
+	scriptCmd.AddCommand(cobraInjectCmd)
+
+	lintCmd, err := agentcli.NewLintCommand()
+	if err != nil {
+		fatal(err)
+	}
+	cobraLintCmd, err := glazed_cli.BuildCobraCommand(lintCmd,
+		glazed_cli.WithParserConfig(parserConfig),
+	)
+	if err != nil {
+		fatal(err)
+	}
+	scriptCmd.AddCommand(cobraLintCmd)
+
+	runCmd, err := agentcli.NewScriptRunCommand()
+	if err != nil {
+		fatal(err)
+	}
+	cobraRunCmd, err := glazed_cli.BuildCobraCommand(runCmd,
+		glazed_cli.WithParserConfig(parserConfig),
+	)
+	if err != nil {
+		fatal(err)
+	}
+	scriptCmd.AddCommand(cobraRunCmd)
+	scriptCmd.AddCommand(newScriptTestCmd(ctx))
+	scriptCmd.AddCommand(newScriptTypesCmd())
+	rootCmd.AddCommand(scriptCmd)
+
 	rootCmd.AddCommand(newServeCmd(ctx))
 	rootCmd.AddCommand(newWSCmd(ctx))
 
@@ -121,17 +202,83 @@ func main() {
 
 func newServeCmd(ctx context.Context) *cobra.Command {
 	var addr string
+	var scriptFSRoots []string
+	var scriptFSMaxFileBytes int64
+	var scriptFetchHosts []string
+	var scriptFetchMaxCalls int
+	var scriptFetchMaxBytes int64
+	var scriptWarmRuntimes int
+	var scriptWarmIdle time.Duration
+	var scriptProgramCacheSize int
+	var scriptLibraryDir string
+	var scriptRegisteredOnly bool
+	var scriptRegistryDir string
+	var scriptMaxHeapGrowth int64
+	var scriptMaxCallStack int
+	var scriptMaxOutputBytes int
 
 	cmd := &cobra.Command{
 		Use:   "serve",
 		Short: "Run the plz-confirm backend server",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			srv := backend.NewServer()
+			var opts []backend.Option
+			if len(scriptFSRoots) > 0 {
+				opts = append(opts,
+					backend.WithScriptFSRoots(scriptFSRoots...),
+					backend.WithScriptFSLimits(scriptFSMaxFileBytes, 0),
+				)
+			}
+			if len(scriptFetchHosts) > 0 {
+				opts = append(opts,
+					backend.WithScriptFetchAllowlist(scriptFetchHosts...),
+					backend.WithScriptFetchLimits(scriptFetchMaxCalls, scriptFetchMaxBytes),
+				)
+			}
+			opts = append(opts,
+				backend.WithScriptProgramCacheSize(scriptProgramCacheSize),
+				backend.WithScriptResourceLimits(scriptMaxHeapGrowth, scriptMaxCallStack, scriptMaxOutputBytes),
+			)
+			if scriptLibraryDir != "" {
+				lib, err := backend.LoadScriptLibrary(scriptLibraryDir)
+				if err != nil {
+					return err
+				}
+				for _, m := range lib.Modules() {
+					log.Printf("[SCRIPT] library module %s@%s", m.Name, m.Version)
+				}
+				opts = append(opts, backend.WithScriptLibrary(lib))
+			}
+			if scriptWarmRuntimes > 0 {
+				opts = append(opts, backend.WithScriptWarmRuntimes(scriptWarmRuntimes, scriptWarmIdle))
+			}
+			if scriptRegisteredOnly {
+				opts = append(opts, backend.WithRegisteredScriptsOnly())
+			}
+			srv := backend.NewServer(opts...)
+			if scriptRegistryDir != "" {
+				if err := srv.RegisterScriptsFromDir(ctx, scriptRegistryDir); err != nil {
+					return err
+				}
+			}
 			return srv.ListenAndServe(ctx, backend.ListenOptions{Addr: addr})
 		},
 	}
 
 	cmd.Flags().StringVar(&addr, "addr", ":3000", "Listen address (default :3000)")
+	cmd.Flags().StringSliceVar(&scriptFSRoots, "script-fs-root", nil, "Directory scripts may read via the opt-in fs capability (repeatable)")
+	cmd.Flags().Int64Var(&scriptFSMaxFileBytes, "script-fs-max-file-bytes", 1<<20, "Largest file scripts may read via ctx.fs")
+	cmd.Flags().StringSliceVar(&scriptFetchHosts, "script-fetch-allow-host", nil, "Host scripts may call via the opt-in fetch capability, e.g. api.example.com or *.example.com (repeatable)")
+	cmd.Flags().IntVar(&scriptFetchMaxCalls, "script-fetch-max-calls", 10, "Most ctx.fetch calls a single script request may make")
+	cmd.Flags().Int64Var(&scriptFetchMaxBytes, "script-fetch-max-bytes", 1<<20, "Most response bytes a single script request may read via ctx.fetch")
+	cmd.Flags().IntVar(&scriptWarmRuntimes, "script-warm-runtimes", 0, "Keep up to this many script runtimes alive between events (0 re-evaluates the script on every event)")
+	cmd.Flags().DurationVar(&scriptWarmIdle, "script-warm-idle", 5*time.Minute, "Close warm script runtimes idle for longer than this")
+	cmd.Flags().IntVar(&scriptProgramCacheSize, "script-program-cache-size", 64, "Number of compiled scripts to cache (0 disables the cache)")
+	cmd.Flags().StringVar(&scriptLibraryDir, "script-library", "", "Directory of shared script modules, loaded with require(\"plz/<name>\")")
+	cmd.Flags().Int64Var(&scriptMaxHeapGrowth, "script-max-heap-growth", 0, "Interrupt a script run once the process heap grows by more than this many bytes during it (0 disables the check)")
+	cmd.Flags().IntVar(&scriptMaxCallStack, "script-max-call-stack", 4096, "Deepest JS call nesting a script may reach")
+	cmd.Flags().IntVar(&scriptMaxOutputBytes, "script-max-output-bytes", 1<<20, "Largest JSON size of a script's state, view, or result")
+	cmd.Flags().BoolVar(&scriptRegisteredOnly, "script-registered-only", false, "Reject inline script source and PUT /api/scripts; script requests must use scriptRef to a script from --script-registry-dir")
+	cmd.Flags().StringVar(&scriptRegistryDir, "script-registry-dir", "", "Directory of vetted scripts registered at startup, one name.js or name.ts per script")
 	return cmd
 }
 
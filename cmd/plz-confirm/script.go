@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/pkg/scripttest"
+)
+
+func newScriptTestCmd(ctx context.Context) *cobra.Command {
+	var update bool
+	var libraryDir string
+
+	cmd := &cobra.Command{
+		Use:   "test <fixture|dir>...",
+		Short: "Replay script flow fixtures headlessly and check their results",
+		Long: "Runs each YAML/JSON fixture against the script engine, checks its expectations, " +
+			"and compares the transcript with the fixture's .golden.json file when it exists. " +
+			"Directories are searched for .yaml, .yml, and .json fixtures.",
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths, err := fixturePaths(args)
+			if err != nil {
+				return err
+			}
+			var opts []scripttest.Option
+			if libraryDir != "" {
+				opts = append(opts, scripttest.WithLibraryDir(libraryDir))
+			}
+			opts = append(opts, scripttest.WithUpdateGolden(update))
+
+			w := cmd.OutOrStdout()
+			failed := 0
+			for _, path := range paths {
+				f, err := scripttest.LoadFixture(path)
+				if err != nil {
+					return err
+				}
+				res, err := scripttest.Run(ctx, f, opts...)
+				if err != nil {
+					return errors.Wrapf(err, "run %s", path)
+				}
+				if res.Passed() {
+					_, _ = fmt.Fprintf(w, "PASS %s (%d steps)\n", res.Name, len(f.Steps))
+					continue
+				}
+				failed++
+				_, _ = fmt.Fprintf(w, "FAIL %s\n", res.Name)
+				for _, failure := range res.Failures {
+					_, _ = fmt.Fprintf(w, "    %s\n", failure)
+				}
+			}
+			if failed > 0 {
+				return errors.Errorf("%d of %d fixtures failed", failed, len(paths))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&update, "update", false, "Write <fixture>.golden.json transcripts instead of comparing against them")
+	cmd.Flags().StringVar(&libraryDir, "script-library", "", "Directory of shared script modules, as for serve --script-library")
+	return cmd
+}
+
+func newScriptTypesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "types",
+		Short: "Print TypeScript declarations for the script contract",
+		Long: "Prints the same declarations the server serves at GET /api/scripts/types.d.ts. " +
+			"Save them as plz-confirm.d.ts next to your scripts for editor completion and type checks.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := fmt.Fprint(cmd.OutOrStdout(), scriptengine.TypeDefinitions())
+			return err
+		},
+	}
+}
+
+// fixturePaths expands directories into the fixture files they contain,
+// skipping golden transcripts.
+func fixturePaths(args []string) ([]string, error) {
+	var out []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			out = append(out, arg)
+			continue
+		}
+		entries, err := os.ReadDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || strings.HasSuffix(name, ".golden.json") {
+				continue
+			}
+			switch filepath.Ext(name) {
+			case ".yaml", ".yml", ".json":
+				out = append(out, filepath.Join(arg, name))
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no fixtures found")
+	}
+	return out, nil
+}
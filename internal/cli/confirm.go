@@ -22,10 +22,12 @@ type ConfirmCommand struct {
 var _ cmds.GlazeCommand = &ConfirmCommand{}
 
 type ConfirmSettings struct {
-	BaseURL     string `glazed:"base-url"`
-	SessionID   string `glazed:"session-id"`
-	TimeoutS    int    `glazed:"timeout"`
-	WaitTimeout int    `glazed:"wait-timeout"`
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
 
 	Title       string  `glazed:"title"`
 	Message     *string `glazed:"message"`
@@ -63,6 +65,18 @@ func NewConfirmCommand() (*ConfirmCommand, error) {
 				fields.WithDefault(300),
 				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
 			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
 			fields.New(
 				"title",
 				fields.TypeString,
@@ -100,6 +114,11 @@ func (c *ConfirmCommand) RunIntoGlazeProcessor(
 		return err
 	}
 
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
 	cl := client.New(settings.BaseURL)
 	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
 		Type:      v1.WidgetType_confirm,
@@ -110,7 +129,9 @@ func (c *ConfirmCommand) RunIntoGlazeProcessor(
 			ApproveText: settings.ApproveText,
 			RejectText:  settings.RejectText,
 		},
-		TimeoutS: settings.TimeoutS,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
 	})
 	if err != nil {
 		return errors.Wrap(err, "create confirm request")
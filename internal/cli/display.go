@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/go-go-golems/glazed/pkg/cmds"
+	"github.com/go-go-golems/glazed/pkg/cmds/fields"
+	"github.com/go-go-golems/glazed/pkg/cmds/schema"
+	"github.com/go-go-golems/glazed/pkg/cmds/values"
+	"github.com/go-go-golems/glazed/pkg/middlewares"
+	"github.com/go-go-golems/glazed/pkg/types"
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/internal/client"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+type DisplayCommand struct {
+	*cmds.CommandDescription
+}
+
+var _ cmds.GlazeCommand = &DisplayCommand{}
+
+type DisplaySettings struct {
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
+
+	Title           *string `glazed:"title"`
+	Content         string  `glazed:"content"`
+	Format          string  `glazed:"format"` // markdown|text|html
+	AcknowledgeText *string `glazed:"acknowledge-text"`
+}
+
+func NewDisplayCommand() (*DisplayCommand, error) {
+	desc := cmds.NewCommandDescription(
+		"display",
+		cmds.WithShort("Show content via the agent-ui web frontend and wait for acknowledgement"),
+		cmds.WithLong("Creates a display widget request, waits for the user to acknowledge it, and outputs the result."),
+		cmds.WithFlags(
+			fields.New(
+				"base-url",
+				fields.TypeString,
+				fields.WithDefault("http://localhost:3000"),
+				fields.WithHelp("Base URL (default: http://localhost:3000)"),
+			),
+			fields.New(
+				"session-id",
+				fields.TypeString,
+				fields.WithDefault("global"),
+				fields.WithHelp("Session ID (used for WebSocket scoping)"),
+			),
+			fields.New(
+				"timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("Request expiration in seconds (server-side)"),
+			),
+			fields.New(
+				"wait-timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
+			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
+			fields.New(
+				"title",
+				fields.TypeString,
+				fields.WithHelp("Optional heading above the content"),
+			),
+			fields.New(
+				"content",
+				fields.TypeString,
+				fields.WithHelp("Content to show; use @file to read a file or - for stdin"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"format",
+				fields.TypeString,
+				fields.WithDefault("markdown"),
+				fields.WithHelp("Content format: markdown|text|html"),
+			),
+			fields.New(
+				"acknowledge-text",
+				fields.TypeString,
+				fields.WithHelp("Optional acknowledge button text"),
+			),
+		),
+	)
+
+	return &DisplayCommand{CommandDescription: desc}, nil
+}
+
+func (c *DisplayCommand) RunIntoGlazeProcessor(
+	ctx context.Context,
+	parsedValues *values.Values,
+	gp middlewares.Processor,
+) error {
+	settings := &DisplaySettings{}
+	if err := parsedValues.DecodeSectionInto(schema.DefaultSlug, settings); err != nil {
+		return err
+	}
+
+	switch settings.Format {
+	case "markdown", "text", "html":
+	default:
+		return errors.Errorf("invalid --format %q (expected markdown|text|html)", settings.Format)
+	}
+
+	content := settings.Content
+	switch {
+	case content == "-":
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return errors.Wrap(err, "read content from stdin")
+		}
+		content = string(b)
+	case len(content) > 0 && content[0] == '@':
+		b, err := os.ReadFile(content[1:])
+		if err != nil {
+			return errors.Wrapf(err, "read content file %s", content[1:])
+		}
+		content = string(b)
+	}
+
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
+	cl := client.New(settings.BaseURL)
+	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
+		Type:      v1.WidgetType_display,
+		SessionID: settings.SessionID,
+		Input: &v1.DisplayInput{
+			Title:           settings.Title,
+			Content:         content,
+			Format:          &settings.Format,
+			AcknowledgeText: settings.AcknowledgeText,
+		},
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create display request")
+	}
+
+	completed, err := cl.WaitRequest(ctx, created.Id, settings.WaitTimeout)
+	if err != nil {
+		return errors.Wrap(err, "wait for display acknowledgement")
+	}
+
+	if completed.Status != v1.RequestStatus_completed {
+		return errors.Errorf("request %s ended with status=%s", created.Id, completed.Status.String())
+	}
+
+	out := completed.GetDisplayOutput()
+
+	row := types.NewRow(
+		types.MRP("request_id", created.Id),
+		types.MRP("acknowledged", out.GetAcknowledged()),
+		types.MRP("timestamp", out.GetTimestamp()),
+		types.MRP("comment", out.GetComment()),
+	)
+	return gp.AddRow(ctx, row)
+}
@@ -27,10 +27,12 @@ type FormCommand struct {
 var _ cmds.GlazeCommand = &FormCommand{}
 
 type FormSettings struct {
-	BaseURL     string `glazed:"base-url"`
-	SessionID   string `glazed:"session-id"`
-	TimeoutS    int    `glazed:"timeout"`
-	WaitTimeout int    `glazed:"wait-timeout"`
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
 
 	Title  string `glazed:"title"`
 	Schema string `glazed:"schema"`
@@ -66,6 +68,18 @@ func NewFormCommand() (*FormCommand, error) {
 				fields.WithDefault(300),
 				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
 			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
 			fields.New(
 				"title",
 				fields.TypeString,
@@ -128,6 +142,11 @@ func (c *FormCommand) RunIntoGlazeProcessor(
 		return errors.Wrap(err, "protojson unmarshal schema into structpb.Struct")
 	}
 
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
 	cl := client.New(settings.BaseURL)
 	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
 		Type:      v1.WidgetType_form,
@@ -136,7 +155,9 @@ func (c *FormCommand) RunIntoGlazeProcessor(
 			Title:  settings.Title,
 			Schema: schemaPB,
 		},
-		TimeoutS: settings.TimeoutS,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
 	})
 	if err != nil {
 		return errors.Wrap(err, "create form request")
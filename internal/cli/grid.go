@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/go-go-golems/glazed/pkg/cmds"
+	"github.com/go-go-golems/glazed/pkg/cmds/fields"
+	"github.com/go-go-golems/glazed/pkg/cmds/schema"
+	"github.com/go-go-golems/glazed/pkg/cmds/values"
+	"github.com/go-go-golems/glazed/pkg/middlewares"
+	"github.com/go-go-golems/glazed/pkg/types"
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/internal/client"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// maxGridCells mirrors the server-side cap on rows*cols.
+const maxGridCells = 400
+
+type GridCommand struct {
+	*cmds.CommandDescription
+}
+
+var _ cmds.GlazeCommand = &GridCommand{}
+
+type GridSettings struct {
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
+
+	Title         string   `glazed:"title"`
+	Rows          int      `glazed:"rows"`
+	Cols          int      `glazed:"cols"`
+	Cells         []string `glazed:"cell"`
+	DisabledCells []int    `glazed:"disabled-cell"`
+	CellSize      string   `glazed:"cell-size"` // small|medium|large
+}
+
+func NewGridCommand() (*GridCommand, error) {
+	desc := cmds.NewCommandDescription(
+		"grid",
+		cmds.WithShort("Request a grid cell selection via the agent-ui web frontend"),
+		cmds.WithLong("Creates a grid widget request, waits for the user to pick a cell, and outputs the result."),
+		cmds.WithFlags(
+			fields.New(
+				"base-url",
+				fields.TypeString,
+				fields.WithDefault("http://localhost:3000"),
+				fields.WithHelp("Base URL (default: http://localhost:3000)"),
+			),
+			fields.New(
+				"session-id",
+				fields.TypeString,
+				fields.WithDefault("global"),
+				fields.WithHelp("Session ID (used for WebSocket scoping)"),
+			),
+			fields.New(
+				"timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("Request expiration in seconds (server-side)"),
+			),
+			fields.New(
+				"wait-timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
+			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
+			fields.New(
+				"title",
+				fields.TypeString,
+				fields.WithHelp("Heading above the grid"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"rows",
+				fields.TypeInteger,
+				fields.WithHelp("Number of rows"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"cols",
+				fields.TypeInteger,
+				fields.WithHelp("Number of columns"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"cell",
+				fields.TypeStringList,
+				fields.WithHelp("Cell values in row-major order (repeatable or comma-separated; missing cells are empty)"),
+			),
+			fields.New(
+				"disabled-cell",
+				fields.TypeIntegerList,
+				fields.WithHelp("Zero-based indexes of cells that cannot be picked (repeatable)"),
+			),
+			fields.New(
+				"cell-size",
+				fields.TypeString,
+				fields.WithDefault("medium"),
+				fields.WithHelp("Cell size: small|medium|large"),
+			),
+		),
+	)
+
+	return &GridCommand{CommandDescription: desc}, nil
+}
+
+func (c *GridCommand) RunIntoGlazeProcessor(
+	ctx context.Context,
+	parsedValues *values.Values,
+	gp middlewares.Processor,
+) error {
+	settings := &GridSettings{}
+	if err := parsedValues.DecodeSectionInto(schema.DefaultSlug, settings); err != nil {
+		return err
+	}
+
+	if settings.Rows <= 0 || settings.Cols <= 0 {
+		return errors.New("--rows and --cols must be positive")
+	}
+	n := settings.Rows * settings.Cols
+	if n > maxGridCells {
+		return errors.Errorf("grid of %dx%d exceeds %d cells", settings.Rows, settings.Cols, maxGridCells)
+	}
+	if len(settings.Cells) > n {
+		return errors.Errorf("got %d --cell values for %d cells", len(settings.Cells), n)
+	}
+	switch settings.CellSize {
+	case "small", "medium", "large":
+	default:
+		return errors.Errorf("invalid --cell-size %q (expected small|medium|large)", settings.CellSize)
+	}
+
+	cells := make([]*v1.GridCell, n)
+	for i := range cells {
+		cells[i] = &v1.GridCell{}
+		if i < len(settings.Cells) {
+			cells[i].Value = settings.Cells[i]
+		}
+	}
+	for _, idx := range settings.DisabledCells {
+		if idx < 0 || idx >= n {
+			return errors.Errorf("invalid --disabled-cell %d (expected 0-%d)", idx, n-1)
+		}
+		disabled := true
+		cells[idx].Disabled = &disabled
+	}
+
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
+	cl := client.New(settings.BaseURL)
+	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
+		Type:      v1.WidgetType_grid,
+		SessionID: settings.SessionID,
+		Input: &v1.GridInput{
+			Title:    settings.Title,
+			Rows:     int32(settings.Rows), // #nosec G115 -- rows*cols is capped at maxGridCells.
+			Cols:     int32(settings.Cols), // #nosec G115 -- rows*cols is capped at maxGridCells.
+			Cells:    cells,
+			CellSize: &settings.CellSize,
+		},
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create grid request")
+	}
+
+	completed, err := cl.WaitRequest(ctx, created.Id, settings.WaitTimeout)
+	if err != nil {
+		return errors.Wrap(err, "wait for grid response")
+	}
+
+	if completed.Status != v1.RequestStatus_completed {
+		return errors.Errorf("request %s ended with status=%s", created.Id, completed.Status.String())
+	}
+
+	out := completed.GetGridOutput()
+	value := ""
+	if idx := int(out.GetCellIndex()); idx >= 0 && idx < len(cells) {
+		value = cells[idx].GetValue()
+	}
+
+	row := types.NewRow(
+		types.MRP("request_id", created.Id),
+		types.MRP("row", out.GetRow()),
+		types.MRP("col", out.GetCol()),
+		types.MRP("cell_index", out.GetCellIndex()),
+		types.MRP("value", value),
+		types.MRP("comment", out.GetComment()),
+	)
+	return gp.AddRow(ctx, row)
+}
@@ -24,10 +24,12 @@ type ImageCommand struct {
 var _ cmds.GlazeCommand = &ImageCommand{}
 
 type ImageSettings struct {
-	BaseURL     string `glazed:"base-url"`
-	SessionID   string `glazed:"session-id"`
-	TimeoutS    int    `glazed:"timeout"`
-	WaitTimeout int    `glazed:"wait-timeout"`
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
 
 	Title   string  `glazed:"title"`
 	Message *string `glazed:"message"`
@@ -131,6 +133,18 @@ func NewImageCommand() (*ImageCommand, error) {
 				fields.WithDefault(300),
 				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
 			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
 			fields.New(
 				"title",
 				fields.TypeString,
@@ -208,6 +222,11 @@ func (c *ImageCommand) RunIntoGlazeProcessor(
 		return errors.Errorf("--image-caption count (%d) must match --image count (%d)", len(settings.ImageCaptions), len(settings.Images))
 	}
 
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
 	cl := client.New(settings.BaseURL)
 
 	ttl := settings.TimeoutS
@@ -258,10 +277,12 @@ func (c *ImageCommand) RunIntoGlazeProcessor(
 	}
 
 	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
-		Type:      v1.WidgetType_image,
-		SessionID: settings.SessionID,
-		Input:     input,
-		TimeoutS:  settings.TimeoutS,
+		Type:          v1.WidgetType_image,
+		SessionID:     settings.SessionID,
+		Input:         input,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
 	})
 	if err != nil {
 		return errors.Wrap(err, "create image request")
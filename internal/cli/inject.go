@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-go-golems/glazed/pkg/cmds"
+	"github.com/go-go-golems/glazed/pkg/cmds/fields"
+	"github.com/go-go-golems/glazed/pkg/cmds/schema"
+	"github.com/go-go-golems/glazed/pkg/cmds/values"
+	"github.com/go-go-golems/glazed/pkg/middlewares"
+	"github.com/go-go-golems/glazed/pkg/types"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/go-go-golems/plz-confirm/internal/client"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+type InjectCommand struct {
+	*cmds.CommandDescription
+}
+
+var _ cmds.GlazeCommand = &InjectCommand{}
+
+type InjectSettings struct {
+	BaseURL string `glazed:"base-url"`
+
+	ID       string  `glazed:"id"`
+	Type     string  `glazed:"type"`
+	StepID   *string `glazed:"step-id"`
+	ActionID *string `glazed:"action-id"`
+	Data     string  `glazed:"data"`
+}
+
+func NewInjectCommand() (*InjectCommand, error) {
+	desc := cmds.NewCommandDescription(
+		"inject",
+		cmds.WithShort("Send an agent event into a running script request"),
+		cmds.WithLong("Delivers an event to the script's update() while the flow is running. The script sees it with event.source === \"agent\"."),
+		cmds.WithFlags(
+			fields.New(
+				"base-url",
+				fields.TypeString,
+				fields.WithDefault("http://localhost:3000"),
+				fields.WithHelp("Base URL (default: http://localhost:3000)"),
+			),
+			fields.New(
+				"id",
+				fields.TypeString,
+				fields.WithHelp("Script request ID"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"type",
+				fields.TypeString,
+				fields.WithHelp("Event type passed to update() as event.type"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"step-id",
+				fields.TypeString,
+				fields.WithHelp("Optional event.stepId"),
+			),
+			fields.New(
+				"action-id",
+				fields.TypeString,
+				fields.WithHelp("Optional event.actionId"),
+			),
+			fields.New(
+				"data",
+				fields.TypeString,
+				fields.WithDefault(""),
+				fields.WithHelp("Optional event.data as a JSON object"),
+			),
+		),
+	)
+
+	return &InjectCommand{CommandDescription: desc}, nil
+}
+
+func (c *InjectCommand) RunIntoGlazeProcessor(
+	ctx context.Context,
+	parsedValues *values.Values,
+	gp middlewares.Processor,
+) error {
+	settings := &InjectSettings{}
+	if err := parsedValues.DecodeSectionInto(schema.DefaultSlug, settings); err != nil {
+		return err
+	}
+
+	ev := &v1.ScriptEvent{
+		Type:     settings.Type,
+		StepId:   settings.StepID,
+		ActionId: settings.ActionID,
+	}
+	if settings.Data != "" {
+		var data map[string]any
+		if err := json.Unmarshal([]byte(settings.Data), &data); err != nil {
+			return errors.Wrap(err, "parse --data as JSON object")
+		}
+		st, err := structpb.NewStruct(data)
+		if err != nil {
+			return errors.Wrap(err, "convert --data")
+		}
+		ev.Data = st
+	}
+
+	cl := client.New(settings.BaseURL)
+	req, err := cl.InjectEvent(ctx, settings.ID, ev)
+	if err != nil {
+		return err
+	}
+
+	row := types.NewRow(
+		types.MRP("request_id", req.Id),
+		types.MRP("status", req.Status.String()),
+		types.MRP("widget_type", req.GetScriptView().GetWidgetType()),
+		types.MRP("step_id", req.GetScriptView().GetStepId()),
+	)
+	return gp.AddRow(ctx, row)
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-go-golems/glazed/pkg/cmds"
+	"github.com/go-go-golems/glazed/pkg/cmds/fields"
+	"github.com/go-go-golems/glazed/pkg/cmds/schema"
+	"github.com/go-go-golems/glazed/pkg/cmds/values"
+	"github.com/go-go-golems/glazed/pkg/middlewares"
+	"github.com/go-go-golems/glazed/pkg/types"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/go-go-golems/plz-confirm/internal/client"
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+type LintCommand struct {
+	*cmds.CommandDescription
+}
+
+var _ cmds.GlazeCommand = &LintCommand{}
+
+type LintSettings struct {
+	BaseURL string `glazed:"base-url"`
+
+	Script  string `glazed:"script"`
+	Name    string `glazed:"name"`
+	Version int    `glazed:"version"`
+	Props   string `glazed:"props"`
+}
+
+func NewLintCommand() (*LintCommand, error) {
+	desc := cmds.NewCommandDescription(
+		"lint",
+		cmds.WithShort("Validate a script without creating a request"),
+		cmds.WithLong("Sends the script to POST /api/scripts/validate, which compiles it, runs describe/init/view with the sample props, and renders every step reachable through ctx.branch. "+
+			"Outputs one row per issue and exits non-zero when any issue is an error."),
+		cmds.WithFlags(
+			fields.New(
+				"base-url",
+				fields.TypeString,
+				fields.WithDefault("http://localhost:3000"),
+				fields.WithHelp("Base URL (default: http://localhost:3000)"),
+			),
+			fields.New(
+				"script",
+				fields.TypeString,
+				fields.WithDefault(""),
+				fields.WithHelp("Path to the script file (use @file.js or - for stdin; .ts files are linted as TypeScript)"),
+			),
+			fields.New(
+				"name",
+				fields.TypeString,
+				fields.WithDefault(""),
+				fields.WithHelp("Registered script name to lint instead of --script"),
+			),
+			fields.New(
+				"version",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Registered script version (0 = latest)"),
+			),
+			fields.New(
+				"props",
+				fields.TypeString,
+				fields.WithDefault(""),
+				fields.WithHelp("Sample ctx.props as a JSON object"),
+			),
+		),
+	)
+
+	return &LintCommand{CommandDescription: desc}, nil
+}
+
+func (c *LintCommand) RunIntoGlazeProcessor(
+	ctx context.Context,
+	parsedValues *values.Values,
+	gp middlewares.Processor,
+) error {
+	settings := &LintSettings{}
+	if err := parsedValues.DecodeSectionInto(schema.DefaultSlug, settings); err != nil {
+		return err
+	}
+
+	in, err := scriptInputFromFlags("lint", settings.Script, settings.Name, settings.Version, settings.Props)
+	if err != nil {
+		return err
+	}
+
+	cl := client.New(settings.BaseURL)
+	report, err := cl.ValidateScript(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	errorCount := 0
+	for _, issue := range report.Issues {
+		if issue.GetSeverity() == "error" {
+			errorCount++
+		}
+		row := types.NewRow(
+			types.MRP("severity", issue.GetSeverity()),
+			types.MRP("phase", issue.GetPhase()),
+			types.MRP("step", issue.GetStep()),
+			types.MRP("message", issue.GetMessage()),
+		)
+		if err := gp.AddRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	if len(report.Issues) == 0 {
+		row := types.NewRow(
+			types.MRP("severity", "ok"),
+			types.MRP("phase", "walk"),
+			types.MRP("step", ""),
+			types.MRP("message", fmt.Sprintf("%d steps checked", len(report.Steps))),
+		)
+		if err := gp.AddRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	if errorCount > 0 {
+		return errors.Errorf("script has %d lint error(s)", errorCount)
+	}
+	return nil
+}
+
+// scriptInputFromFlags builds a ScriptInput from the --script, --name,
+// --version and --props flags shared by the script subcommands.
+func scriptInputFromFlags(title, script, name string, version int, props string) (*v1.ScriptInput, error) {
+	in := &v1.ScriptInput{Title: title}
+	switch {
+	case script != "" && name != "":
+		return nil, errors.New("set either --script or --name, not both")
+	case name != "":
+		ref := &v1.ScriptRef{Name: name}
+		if version > 0 {
+			v := int32(version) // #nosec G115 -- versions are small positive integers.
+			ref.Version = &v
+		}
+		in.ScriptRef = ref
+	case script != "":
+		source, err := readScriptSource(script)
+		if err != nil {
+			return nil, err
+		}
+		in.Script = source
+		if language := scriptengine.LanguageForPath(script); language != "" {
+			in.Language = &language
+		}
+	default:
+		return nil, errors.New("--script or --name is required")
+	}
+	if props != "" {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(props), &m); err != nil {
+			return nil, errors.Wrap(err, "parse --props as JSON object")
+		}
+		st, err := structpb.NewStruct(m)
+		if err != nil {
+			return nil, errors.Wrap(err, "convert --props")
+		}
+		in.Props = st
+	}
+	return in, nil
+}
+
+func readScriptSource(path string) (string, error) {
+	if path == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", errors.Wrap(err, "read script from stdin")
+		}
+		return string(b), nil
+	}
+	if len(path) > 0 && path[0] == '@' {
+		path = path[1:]
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "read script file %s", path)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,15 @@
+package cli
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// priorityValue validates the --priority flag for the int32 proto field.
+func priorityValue(v int) (int32, error) {
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return 0, errors.Errorf("priority %d out of range", v)
+	}
+	return int32(v), nil
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/go-go-golems/glazed/pkg/cmds"
+	"github.com/go-go-golems/glazed/pkg/cmds/fields"
+	"github.com/go-go-golems/glazed/pkg/cmds/schema"
+	"github.com/go-go-golems/glazed/pkg/cmds/values"
+	"github.com/go-go-golems/glazed/pkg/middlewares"
+	"github.com/go-go-golems/glazed/pkg/types"
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/internal/client"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+type RatingCommand struct {
+	*cmds.CommandDescription
+}
+
+var _ cmds.GlazeCommand = &RatingCommand{}
+
+type RatingSettings struct {
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
+
+	Title        string  `glazed:"title"`
+	Scale        int     `glazed:"scale"`
+	Style        string  `glazed:"style"` // stars|numbers|emoji|slider
+	LowLabel     *string `glazed:"low-label"`
+	HighLabel    *string `glazed:"high-label"`
+	DefaultValue *int    `glazed:"default-value"`
+}
+
+func NewRatingCommand() (*RatingCommand, error) {
+	desc := cmds.NewCommandDescription(
+		"rating",
+		cmds.WithShort("Request a rating via the agent-ui web frontend"),
+		cmds.WithLong("Creates a rating widget request, waits for the user rating, and outputs the result."),
+		cmds.WithFlags(
+			fields.New(
+				"base-url",
+				fields.TypeString,
+				fields.WithDefault("http://localhost:3000"),
+				fields.WithHelp("Base URL (default: http://localhost:3000)"),
+			),
+			fields.New(
+				"session-id",
+				fields.TypeString,
+				fields.WithDefault("global"),
+				fields.WithHelp("Session ID (used for WebSocket scoping)"),
+			),
+			fields.New(
+				"timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("Request expiration in seconds (server-side)"),
+			),
+			fields.New(
+				"wait-timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
+			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
+			fields.New(
+				"title",
+				fields.TypeString,
+				fields.WithHelp("Prompt shown above the rating control"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"scale",
+				fields.TypeInteger,
+				fields.WithDefault(5),
+				fields.WithHelp("Number of points (2-10)"),
+			),
+			fields.New(
+				"style",
+				fields.TypeString,
+				fields.WithDefault("numbers"),
+				fields.WithHelp("Rating style: stars|numbers|emoji|slider"),
+			),
+			fields.New(
+				"low-label",
+				fields.TypeString,
+				fields.WithHelp("Optional label for the lowest value"),
+			),
+			fields.New(
+				"high-label",
+				fields.TypeString,
+				fields.WithHelp("Optional label for the highest value"),
+			),
+			fields.New(
+				"default-value",
+				fields.TypeInteger,
+				fields.WithHelp("Optional initially selected value (defaults to the midpoint)"),
+			),
+		),
+	)
+
+	return &RatingCommand{CommandDescription: desc}, nil
+}
+
+func (c *RatingCommand) RunIntoGlazeProcessor(
+	ctx context.Context,
+	parsedValues *values.Values,
+	gp middlewares.Processor,
+) error {
+	settings := &RatingSettings{}
+	if err := parsedValues.DecodeSectionInto(schema.DefaultSlug, settings); err != nil {
+		return err
+	}
+
+	if settings.Scale < 2 || settings.Scale > 10 {
+		return errors.Errorf("invalid --scale %d (expected 2-10)", settings.Scale)
+	}
+	switch settings.Style {
+	case "stars", "numbers", "emoji", "slider":
+	default:
+		return errors.Errorf("invalid --style %q (expected stars|numbers|emoji|slider)", settings.Style)
+	}
+
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
+	scale := int32(settings.Scale) // #nosec G115 -- checked to be within 2..10.
+	in := &v1.RatingInput{
+		Title: settings.Title,
+		Scale: &scale,
+		Style: &settings.Style,
+	}
+	if settings.LowLabel != nil || settings.HighLabel != nil {
+		in.Labels = &v1.RatingLabels{Low: settings.LowLabel, High: settings.HighLabel}
+	}
+	if settings.DefaultValue != nil {
+		if *settings.DefaultValue < 1 || *settings.DefaultValue > settings.Scale {
+			return errors.Errorf("invalid --default-value %d (expected 1-%d)", *settings.DefaultValue, settings.Scale)
+		}
+		v := int32(*settings.DefaultValue) // #nosec G115 -- checked to be within 1..scale.
+		in.DefaultValue = &v
+	}
+
+	cl := client.New(settings.BaseURL)
+	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
+		Type:          v1.WidgetType_rating,
+		SessionID:     settings.SessionID,
+		Input:         in,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create rating request")
+	}
+
+	completed, err := cl.WaitRequest(ctx, created.Id, settings.WaitTimeout)
+	if err != nil {
+		return errors.Wrap(err, "wait for rating response")
+	}
+
+	if completed.Status != v1.RequestStatus_completed {
+		return errors.Errorf("request %s ended with status=%s", created.Id, completed.Status.String())
+	}
+
+	out := completed.GetRatingOutput()
+
+	row := types.NewRow(
+		types.MRP("request_id", created.Id),
+		types.MRP("value", out.GetValue()),
+		types.MRP("comment", out.GetComment()),
+	)
+	return gp.AddRow(ctx, row)
+}
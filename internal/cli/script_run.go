@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/go-go-golems/glazed/pkg/cmds"
+	"github.com/go-go-golems/glazed/pkg/cmds/fields"
+	"github.com/go-go-golems/glazed/pkg/cmds/schema"
+	"github.com/go-go-golems/glazed/pkg/cmds/values"
+	"github.com/go-go-golems/glazed/pkg/middlewares"
+	"github.com/go-go-golems/glazed/pkg/types"
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/internal/client"
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+type ScriptRunCommand struct {
+	*cmds.CommandDescription
+}
+
+var _ cmds.GlazeCommand = &ScriptRunCommand{}
+
+type ScriptRunSettings struct {
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
+
+	Title   string `glazed:"title"`
+	Script  string `glazed:"script"`
+	Name    string `glazed:"name"`
+	Version int    `glazed:"version"`
+	Props   string `glazed:"props"`
+}
+
+func NewScriptRunCommand() (*ScriptRunCommand, error) {
+	desc := cmds.NewCommandDescription(
+		"run",
+		cmds.WithShort("Run a script request and output its result"),
+		cmds.WithLong("Creates a script widget request, waits for the flow to finish, and outputs the result as one row. "+
+			"When describe() declares an outputSchema, its properties become typed columns sorted by name; "+
+			"other result keys follow. Objects and arrays are output as JSON strings."),
+		cmds.WithFlags(
+			fields.New(
+				"base-url",
+				fields.TypeString,
+				fields.WithDefault("http://localhost:3000"),
+				fields.WithHelp("Base URL (default: http://localhost:3000)"),
+			),
+			fields.New(
+				"session-id",
+				fields.TypeString,
+				fields.WithDefault("global"),
+				fields.WithHelp("Session ID (used for WebSocket scoping)"),
+			),
+			fields.New(
+				"timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("Request expiration in seconds (server-side)"),
+			),
+			fields.New(
+				"wait-timeout",
+				fields.TypeInteger,
+				fields.WithDefault(300),
+				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
+			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
+			fields.New(
+				"title",
+				fields.TypeString,
+				fields.WithHelp("Request title"),
+				fields.WithRequired(true),
+			),
+			fields.New(
+				"script",
+				fields.TypeString,
+				fields.WithDefault(""),
+				fields.WithHelp("Path to the script file (use @file.js or - for stdin; .ts files run as TypeScript)"),
+			),
+			fields.New(
+				"name",
+				fields.TypeString,
+				fields.WithDefault(""),
+				fields.WithHelp("Registered script name to run instead of --script"),
+			),
+			fields.New(
+				"version",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Registered script version (0 = latest)"),
+			),
+			fields.New(
+				"props",
+				fields.TypeString,
+				fields.WithDefault(""),
+				fields.WithHelp("ctx.props as a JSON object"),
+			),
+		),
+	)
+
+	return &ScriptRunCommand{CommandDescription: desc}, nil
+}
+
+func (c *ScriptRunCommand) RunIntoGlazeProcessor(
+	ctx context.Context,
+	parsedValues *values.Values,
+	gp middlewares.Processor,
+) error {
+	settings := &ScriptRunSettings{}
+	if err := parsedValues.DecodeSectionInto(schema.DefaultSlug, settings); err != nil {
+		return err
+	}
+
+	in, err := scriptInputFromFlags(settings.Title, settings.Script, settings.Name, settings.Version, settings.Props)
+	if err != nil {
+		return err
+	}
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
+	cl := client.New(settings.BaseURL)
+	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
+		Type:          v1.WidgetType_script,
+		SessionID:     settings.SessionID,
+		Input:         in,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create script request")
+	}
+
+	completed, err := cl.WaitRequest(ctx, created.Id, settings.WaitTimeout)
+	if err != nil {
+		return errors.Wrap(err, "wait for script result")
+	}
+
+	if completed.Status != v1.RequestStatus_completed {
+		return errors.Errorf("request %s ended with status=%s", created.Id, completed.Status.String())
+	}
+
+	var outputSchema map[string]any
+	if s := created.GetScriptDescribe().GetOutputSchema(); s != nil {
+		outputSchema = s.AsMap()
+	}
+	result := completed.GetScriptOutput().GetResult().AsMap()
+	return gp.AddRow(ctx, scriptResultRow(created.Id, outputSchema, result))
+}
+
+// scriptResultRow lays out a script result as request_id, the schema's
+// properties, then any remaining result keys, each group sorted by name.
+func scriptResultRow(id string, outputSchema map[string]any, result map[string]any) types.Row {
+	row := types.NewRow(types.MRP("request_id", id))
+	props, _ := outputSchema["properties"].(map[string]any)
+	for _, name := range sortedKeys(props) {
+		propSchema, _ := props[name].(map[string]any)
+		row.Set(name, scriptColumnValue(scriptengine.SchemaTypes(propSchema), result[name]))
+	}
+	for _, name := range sortedKeys(result) {
+		if _, ok := props[name]; ok {
+			continue
+		}
+		row.Set(name, scriptColumnValue(nil, result[name]))
+	}
+	return row
+}
+
+// scriptColumnValue converts a decoded JSON value to the Go type its schema
+// declares, so integers print without a decimal point. Objects and arrays
+// become JSON strings.
+func scriptColumnValue(schemaTypes []string, v any) any {
+	switch typed := v.(type) {
+	case nil:
+		return nil
+	case float64:
+		for _, t := range schemaTypes {
+			if t == "integer" && typed == math.Trunc(typed) && math.Abs(typed) < 1<<63 {
+				return int64(typed)
+			}
+		}
+		return typed
+	case map[string]any, []any:
+		b, err := json.Marshal(typed)
+		if err != nil {
+			return nil
+		}
+		return string(b)
+	default:
+		return typed
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
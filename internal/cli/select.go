@@ -23,10 +23,12 @@ type SelectCommand struct {
 var _ cmds.GlazeCommand = &SelectCommand{}
 
 type SelectSettings struct {
-	BaseURL     string `glazed:"base-url"`
-	SessionID   string `glazed:"session-id"`
-	TimeoutS    int    `glazed:"timeout"`
-	WaitTimeout int    `glazed:"wait-timeout"`
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
 
 	Title      string   `glazed:"title"`
 	Options    []string `glazed:"option"`
@@ -64,6 +66,18 @@ func NewSelectCommand() (*SelectCommand, error) {
 				fields.WithDefault(300),
 				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
 			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
 			fields.New(
 				"title",
 				fields.TypeString,
@@ -104,6 +118,11 @@ func (c *SelectCommand) RunIntoGlazeProcessor(
 		return err
 	}
 
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
 	cl := client.New(settings.BaseURL)
 
 	input := &v1.SelectInput{
@@ -114,10 +133,12 @@ func (c *SelectCommand) RunIntoGlazeProcessor(
 	}
 
 	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
-		Type:      v1.WidgetType_select,
-		SessionID: settings.SessionID,
-		Input:     input,
-		TimeoutS:  settings.TimeoutS,
+		Type:          v1.WidgetType_select,
+		SessionID:     settings.SessionID,
+		Input:         input,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
 	})
 	if err != nil {
 		return errors.Wrap(err, "create select request")
@@ -27,10 +27,12 @@ type TableCommand struct {
 var _ cmds.GlazeCommand = &TableCommand{}
 
 type TableSettings struct {
-	BaseURL     string `glazed:"base-url"`
-	SessionID   string `glazed:"session-id"`
-	TimeoutS    int    `glazed:"timeout"`
-	WaitTimeout int    `glazed:"wait-timeout"`
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
 
 	Title       string   `glazed:"title"`
 	Data        string   `glazed:"data"`
@@ -69,6 +71,18 @@ func NewTableCommand() (*TableCommand, error) {
 				fields.WithDefault(300),
 				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
 			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
 			fields.New(
 				"title",
 				fields.TypeString,
@@ -152,6 +166,11 @@ func (c *TableCommand) RunIntoGlazeProcessor(
 		pbRows = append(pbRows, st)
 	}
 
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
 	cl := client.New(settings.BaseURL)
 	input := &v1.TableInput{
 		Title:       settings.Title,
@@ -162,10 +181,12 @@ func (c *TableCommand) RunIntoGlazeProcessor(
 	}
 
 	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
-		Type:      v1.WidgetType_table,
-		SessionID: settings.SessionID,
-		Input:     input,
-		TimeoutS:  settings.TimeoutS,
+		Type:          v1.WidgetType_table,
+		SessionID:     settings.SessionID,
+		Input:         input,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
 	})
 	if err != nil {
 		return errors.Wrap(err, "create table request")
@@ -22,10 +22,12 @@ type UploadCommand struct {
 var _ cmds.GlazeCommand = &UploadCommand{}
 
 type UploadSettings struct {
-	BaseURL     string `glazed:"base-url"`
-	SessionID   string `glazed:"session-id"`
-	TimeoutS    int    `glazed:"timeout"`
-	WaitTimeout int    `glazed:"wait-timeout"`
+	BaseURL       string `glazed:"base-url"`
+	SessionID     string `glazed:"session-id"`
+	TimeoutS      int    `glazed:"timeout"`
+	WaitTimeout   int    `glazed:"wait-timeout"`
+	Priority      int    `glazed:"priority"`
+	DeadlineAware bool   `glazed:"deadline-aware"`
 
 	Title       string   `glazed:"title"`
 	Accept      []string `glazed:"accept"`
@@ -64,6 +66,18 @@ func NewUploadCommand() (*UploadCommand, error) {
 				fields.WithDefault(300),
 				fields.WithHelp("How long to wait for a response in seconds (0 = wait forever)"),
 			),
+			fields.New(
+				"priority",
+				fields.TypeInteger,
+				fields.WithDefault(0),
+				fields.WithHelp("Queue priority; higher values are shown first"),
+			),
+			fields.New(
+				"deadline-aware",
+				fields.TypeBool,
+				fields.WithDefault(false),
+				fields.WithHelp("Within the same priority, show requests closer to expiry first"),
+			),
 			fields.New(
 				"title",
 				fields.TypeString,
@@ -107,6 +121,11 @@ func (c *UploadCommand) RunIntoGlazeProcessor(
 		return err
 	}
 
+	priority, err := priorityValue(settings.Priority)
+	if err != nil {
+		return err
+	}
+
 	cl := client.New(settings.BaseURL)
 	input := &v1.UploadInput{
 		Title:       settings.Title,
@@ -117,10 +136,12 @@ func (c *UploadCommand) RunIntoGlazeProcessor(
 	}
 
 	created, err := cl.CreateRequest(ctx, client.CreateRequestParams{
-		Type:      v1.WidgetType_upload,
-		SessionID: settings.SessionID,
-		Input:     input,
-		TimeoutS:  settings.TimeoutS,
+		Type:          v1.WidgetType_upload,
+		SessionID:     settings.SessionID,
+		Input:         input,
+		TimeoutS:      settings.TimeoutS,
+		Priority:      priority,
+		DeadlineAware: settings.DeadlineAware,
 	})
 	if err != nil {
 		return errors.Wrap(err, "create upload request")
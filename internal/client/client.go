@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"github.com/go-go-golems/plz-confirm/internal/metadata"
@@ -33,6 +34,11 @@ type Client struct {
 
 var ErrWaitTimeout = stderrors.New("timeout waiting for response")
 
+const (
+	createMaxAttempts    = 4
+	createRetryBaseDelay = 250 * time.Millisecond
+)
+
 // outboundPolicy is intentionally internal for now; it establishes a default
 // transport safety baseline without introducing external configuration.
 type outboundPolicy struct {
@@ -75,6 +81,18 @@ type CreateRequestParams struct {
 
 	SessionID string
 
+	// Priority orders the request in the pending queue (higher first).
+	Priority int32
+	// DeadlineAware sorts the request by its expiry within its priority.
+	DeadlineAware bool
+	// DependsOn lists request IDs that must complete (and not be rejected)
+	// before this request is shown.
+	DependsOn []string
+
+	// IdempotencyKey deduplicates retried creates on the server. A random key
+	// is generated when empty.
+	IdempotencyKey string
+
 	Metadata *v1.RequestMetadata
 }
 
@@ -91,6 +109,12 @@ func (c *Client) CreateRequest(ctx context.Context, p CreateRequestParams) (*v1.
 	reqProto := &v1.UIRequest{
 		Type:      p.Type,
 		SessionId: p.SessionID,
+		Priority:  p.Priority,
+		DependsOn: p.DependsOn,
+	}
+	if p.DeadlineAware {
+		deadlineAware := true
+		reqProto.DeadlineAware = &deadlineAware
 	}
 	if p.Metadata != nil {
 		reqProto.Metadata = p.Metadata
@@ -146,6 +170,24 @@ func (c *Client) CreateRequest(ctx context.Context, p CreateRequestParams) (*v1.
 			return nil, errors.New("input must be *v1.ScriptInput for type=script")
 		}
 		reqProto.Input = &v1.UIRequest_ScriptInput{ScriptInput: in}
+	case v1.WidgetType_grid:
+		in, ok := p.Input.(*v1.GridInput)
+		if !ok {
+			return nil, errors.New("input must be *v1.GridInput for type=grid")
+		}
+		reqProto.Input = &v1.UIRequest_GridInput{GridInput: in}
+	case v1.WidgetType_rating:
+		in, ok := p.Input.(*v1.RatingInput)
+		if !ok {
+			return nil, errors.New("input must be *v1.RatingInput for type=rating")
+		}
+		reqProto.Input = &v1.UIRequest_RatingInput{RatingInput: in}
+	case v1.WidgetType_display:
+		in, ok := p.Input.(*v1.DisplayInput)
+		if !ok {
+			return nil, errors.New("input must be *v1.DisplayInput for type=display")
+		}
+		reqProto.Input = &v1.UIRequest_DisplayInput{DisplayInput: in}
 	default:
 		return nil, errors.New("invalid widget type")
 	}
@@ -157,33 +199,76 @@ func (c *Client) CreateRequest(ctx context.Context, p CreateRequestParams) (*v1.
 		return nil, errors.Wrap(err, "marshal protojson UIRequest")
 	}
 
+	idempotencyKey := p.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+
+	// The idempotency key makes retrying safe: if an earlier attempt reached
+	// the server, the retry returns that request instead of a duplicate.
+	delay := createRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		out, retryable, err := c.createOnce(ctx, u, bodyBytes, idempotencyKey)
+		if err == nil {
+			return out, nil
+		}
+		if !retryable || attempt >= createMaxAttempts {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) createOnce(ctx context.Context, u *url.URL, bodyBytes []byte, idempotencyKey string) (*v1.UIRequest, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(bodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "create http request")
+		return nil, false, errors.Wrap(err, "create http request")
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
 
 	resp, err := c.do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "post /api/requests")
+		// Transport failures surface as *url.Error; policy rejections do not
+		// and are never retried.
+		var urlErr *url.Error
+		retryable := stderrors.As(err, &urlErr) && ctx.Err() == nil
+		return nil, retryable, errors.Wrap(err, "post /api/requests")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(io.LimitReader(resp.Body, 16<<10))
-		return nil, errors.Errorf("create request failed: status=%d body=%s", resp.StatusCode, string(b))
+		return nil, isTransientStatus(resp.StatusCode), errors.Errorf("create request failed: status=%d body=%s", resp.StatusCode, string(b))
 	}
 
 	respBytes, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
 	if err != nil {
-		return nil, errors.Wrap(err, "read create response")
+		return nil, true, errors.Wrap(err, "read create response")
 	}
 
 	out := &v1.UIRequest{}
 	if err := protojson.Unmarshal(respBytes, out); err != nil {
-		return nil, errors.Wrap(err, "protojson unmarshal create response")
+		return nil, false, errors.Wrap(err, "protojson unmarshal create response")
+	}
+	return out, false, nil
+}
+
+func isTransientStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
 	}
-	return out, nil
 }
 
 func (c *Client) WaitRequest(ctx context.Context, id string, waitTimeoutS int) (*v1.UIRequest, error) {
@@ -235,6 +320,206 @@ func (c *Client) WaitRequest(ctx context.Context, id string, waitTimeoutS int) (
 	}
 }
 
+// SetPriority re-prioritizes a pending request. priority and deadlineAware
+// are left unchanged when nil.
+func (c *Client) SetPriority(ctx context.Context, id string, priority *int32, deadlineAware *bool) (*v1.UIRequest, error) {
+	out := &v1.UIRequest{}
+	body := &v1.PriorityUpdate{Priority: priority, DeadlineAware: deadlineAware}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/requests/"+url.PathEscape(id)+"/priority", body, out); err != nil {
+		return nil, errors.Wrap(err, "set priority")
+	}
+	return out, nil
+}
+
+// GetPresence reports whether any UI viewer is connected to the session, so
+// agents can decide to fall back to another channel instead of waiting blind.
+func (c *Client) GetPresence(ctx context.Context, sessionID string) (*v1.SessionPresence, error) {
+	if sessionID == "" {
+		sessionID = "global"
+	}
+	out := &v1.SessionPresence{}
+	if err := c.doProtoJSON(ctx, http.MethodGet, "/api/sessions/"+url.PathEscape(sessionID)+"/presence", nil, out); err != nil {
+		return nil, errors.Wrap(err, "get presence")
+	}
+	return out, nil
+}
+
+func (c *Client) CreateSession(ctx context.Context, sess *v1.Session) (*v1.Session, error) {
+	if sess == nil {
+		sess = &v1.Session{}
+	}
+	out := &v1.Session{}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/sessions", sess, out); err != nil {
+		return nil, errors.Wrap(err, "create session")
+	}
+	return out, nil
+}
+
+// ListSessions lists sessions with the given status ("active", "archived",
+// "closed", or "all"). An empty status lists active sessions.
+func (c *Client) ListSessions(ctx context.Context, status string) ([]*v1.Session, error) {
+	path := "/api/sessions"
+	if status != "" {
+		path += "?status=" + url.QueryEscape(status)
+	}
+	out := &v1.SessionList{}
+	if err := c.doProtoJSON(ctx, http.MethodGet, path, nil, out); err != nil {
+		return nil, errors.Wrap(err, "list sessions")
+	}
+	return out.Sessions, nil
+}
+
+func (c *Client) GetSession(ctx context.Context, id string) (*v1.Session, error) {
+	out := &v1.Session{}
+	if err := c.doProtoJSON(ctx, http.MethodGet, "/api/sessions/"+url.PathEscape(id), nil, out); err != nil {
+		return nil, errors.Wrap(err, "get session")
+	}
+	return out, nil
+}
+
+func (c *Client) RenameSession(ctx context.Context, id string, name string) (*v1.Session, error) {
+	out := &v1.Session{}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/sessions/"+url.PathEscape(id)+"/rename", &v1.Session{Name: name}, out); err != nil {
+		return nil, errors.Wrap(err, "rename session")
+	}
+	return out, nil
+}
+
+func (c *Client) ArchiveSession(ctx context.Context, id string) (*v1.Session, error) {
+	out := &v1.Session{}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/sessions/"+url.PathEscape(id)+"/archive", nil, out); err != nil {
+		return nil, errors.Wrap(err, "archive session")
+	}
+	return out, nil
+}
+
+// CloseSession closes the session and cancels all of its pending requests.
+func (c *Client) CloseSession(ctx context.Context, id string) (*v1.Session, error) {
+	out := &v1.Session{}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/sessions/"+url.PathEscape(id)+"/close", nil, out); err != nil {
+		return nil, errors.Wrap(err, "close session")
+	}
+	return out, nil
+}
+
+// InjectEvent delivers an agent-side event into a running script request.
+// The script's update() sees it with event.source === "agent".
+func (c *Client) InjectEvent(ctx context.Context, id string, ev *v1.ScriptEvent) (*v1.UIRequest, error) {
+	out := &v1.UIRequest{}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/requests/"+url.PathEscape(id)+"/inject", ev, out); err != nil {
+		return nil, errors.Wrap(err, "inject script event")
+	}
+	return out, nil
+}
+
+// ScriptHistory fetches the recorded init and event runs of a script request.
+func (c *Client) ScriptHistory(ctx context.Context, id string) (*v1.ScriptHistory, error) {
+	out := &v1.ScriptHistory{}
+	if err := c.doProtoJSON(ctx, http.MethodGet, "/api/requests/"+url.PathEscape(id)+"/script/history", nil, out); err != nil {
+		return nil, errors.Wrap(err, "get script history")
+	}
+	return out, nil
+}
+
+// ReplayScript re-runs a finished script request from its recorded history
+// in a sandbox and reports whether it reproduces the same runs and result.
+// Pass nil to replay the recorded script, or set Script or ScriptRef to check
+// another version.
+func (c *Client) ReplayScript(ctx context.Context, id string, req *v1.ScriptReplayRequest) (*v1.ScriptReplay, error) {
+	if req == nil {
+		req = &v1.ScriptReplayRequest{}
+	}
+	out := &v1.ScriptReplay{}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/requests/"+url.PathEscape(id)+"/script/replay", req, out); err != nil {
+		return nil, errors.Wrap(err, "replay script")
+	}
+	return out, nil
+}
+
+// PutScript registers source under name. Registering the same source as the
+// latest version returns that version unchanged.
+func (c *Client) PutScript(ctx context.Context, name string, source string, description string) (*v1.RegisteredScript, error) {
+	body := &v1.RegisteredScript{Script: source}
+	if description != "" {
+		body.Description = &description
+	}
+	out := &v1.RegisteredScript{}
+	if err := c.doProtoJSON(ctx, http.MethodPut, "/api/scripts/"+url.PathEscape(name), body, out); err != nil {
+		return nil, errors.Wrap(err, "put script")
+	}
+	return out, nil
+}
+
+// GetScript fetches a registered script version, or the latest when version
+// is 0.
+func (c *Client) GetScript(ctx context.Context, name string, version int32) (*v1.RegisteredScript, error) {
+	path := "/api/scripts/" + url.PathEscape(name)
+	if version > 0 {
+		path += "/versions/" + strconv.FormatInt(int64(version), 10)
+	}
+	out := &v1.RegisteredScript{}
+	if err := c.doProtoJSON(ctx, http.MethodGet, path, nil, out); err != nil {
+		return nil, errors.Wrap(err, "get script")
+	}
+	return out, nil
+}
+
+// ValidateScript lints a script (inline or by scriptRef) with the sample
+// props in in, without creating a request.
+func (c *Client) ValidateScript(ctx context.Context, in *v1.ScriptInput) (*v1.ScriptValidation, error) {
+	out := &v1.ScriptValidation{}
+	if err := c.doProtoJSON(ctx, http.MethodPost, "/api/scripts/validate", in, out); err != nil {
+		return nil, errors.Wrap(err, "validate script")
+	}
+	return out, nil
+}
+
+// doProtoJSON sends an optional protojson body to path and decodes the
+// protojson response into out.
+func (c *Client) doProtoJSON(ctx context.Context, method string, path string, body proto.Message, out proto.Message) error {
+	u, err := url.Parse(c.BaseURL + path)
+	if err != nil {
+		return errors.Wrap(err, "parse url")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "marshal protojson body")
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return errors.Wrap(err, "create http request")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return errors.Wrapf(err, "%s %s", method, u.Path)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 16<<10))
+		return errors.Errorf("status=%d body=%s", resp.StatusCode, string(b))
+	}
+
+	respBytes, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return errors.Wrap(err, "read response")
+	}
+	if err := protojson.Unmarshal(respBytes, out); err != nil {
+		return errors.Wrap(err, "protojson unmarshal response")
+	}
+	return nil
+}
+
 type UploadImageResponse struct {
 	ID       string `json:"id"`
 	URL      string `json:"url"`
@@ -364,6 +649,13 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 	return c.HTTPClient.Do(req)
 }
 
+// ValidateOutboundURL applies the default outbound SSRF rules (scheme
+// allowlist, cloud metadata and link-local blocking) to u. It is shared with
+// other components that make outbound requests on a user's behalf.
+func ValidateOutboundURL(u *url.URL) error {
+	return validateOutboundURL(u, defaultOutboundPolicy())
+}
+
 func validateOutboundURL(u *url.URL, policy outboundPolicy) error {
 	if u == nil {
 		return errors.New("outbound URL is required")
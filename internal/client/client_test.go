@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestWaitRequest_RetriesOn408(t *testing.T) {
@@ -136,6 +137,78 @@ func TestCreateRequest_ScriptInput(t *testing.T) {
 	}
 }
 
+func TestCreateRequest_RetriesTransientFailuresWithSameIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	var firstKey atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/requests" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			t.Errorf("expected Idempotency-Key header")
+		}
+		if prev, ok := firstKey.Load().(string); ok && prev != key {
+			t.Errorf("idempotency key changed between attempts: %q != %q", prev, key)
+		}
+		firstKey.Store(key)
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		out := &v1.UIRequest{
+			Id:        "req-1",
+			Type:      v1.WidgetType_confirm,
+			SessionId: "global",
+			Status:    v1.RequestStatus_pending,
+		}
+		b, _ := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(out)
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	got, err := c.CreateRequest(context.Background(), CreateRequestParams{
+		Type:  v1.WidgetType_confirm,
+		Input: &v1.ConfirmInput{Title: "t"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRequest returned error: %v", err)
+	}
+	if got.Id != "req-1" {
+		t.Fatalf("unexpected request id: %q", got.Id)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+}
+
+func TestCreateRequest_DoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	if _, err := c.CreateRequest(context.Background(), CreateRequestParams{
+		Type:  v1.WidgetType_confirm,
+		Input: &v1.ConfirmInput{Title: "t"},
+	}); err == nil {
+		t.Fatalf("expected error for 400 response")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+}
+
 func TestWaitOnceRejectsMetadataIPHost(t *testing.T) {
 	t.Parallel()
 
@@ -167,3 +240,132 @@ func TestWaitOnceRejectsUnsupportedScheme(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestInjectEvent(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/requests/script-1/inject" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		ev := &v1.ScriptEvent{}
+		if err := protojson.Unmarshal(body, ev); err != nil {
+			t.Errorf("protojson unmarshal body: %v", err)
+		}
+		if ev.GetType() != "tests_done" || ev.GetData().AsMap()["passed"] != true {
+			t.Errorf("unexpected injected event: %v", ev)
+		}
+		b, _ := protojson.Marshal(&v1.UIRequest{Id: "script-1", Status: v1.RequestStatus_pending})
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	data, err := structpb.NewStruct(map[string]any{"passed": true})
+	if err != nil {
+		t.Fatalf("struct: %v", err)
+	}
+	req, err := New(srv.URL).InjectEvent(context.Background(), "script-1", &v1.ScriptEvent{Type: "tests_done", Data: data})
+	if err != nil {
+		t.Fatalf("InjectEvent returned error: %v", err)
+	}
+	if req.GetId() != "script-1" {
+		t.Fatalf("unexpected response: %v", req)
+	}
+}
+
+func TestValidateScript(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/scripts/validate" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		in := &v1.ScriptInput{}
+		if err := protojson.Unmarshal(body, in); err != nil {
+			t.Errorf("protojson unmarshal body: %v", err)
+		}
+		if in.GetScriptRef().GetName() != "deploy-wizard" {
+			t.Errorf("unexpected script input: %v", in)
+		}
+		b, _ := protojson.Marshal(&v1.ScriptValidation{
+			Issues: []*v1.ScriptLintIssue{{Severity: "error", Phase: "view", Message: "bad view"}},
+		})
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	report, err := New(srv.URL).ValidateScript(context.Background(), &v1.ScriptInput{
+		ScriptRef: &v1.ScriptRef{Name: "deploy-wizard"},
+	})
+	if err != nil {
+		t.Fatalf("ValidateScript returned error: %v", err)
+	}
+	if report.GetOk() || len(report.GetIssues()) != 1 {
+		t.Fatalf("unexpected report: %v", report)
+	}
+}
+
+func TestScriptHistory(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/requests/script-1/script/history" || r.Method != http.MethodGet {
+			http.NotFound(w, r)
+			return
+		}
+		b, _ := protojson.Marshal(&v1.ScriptHistory{
+			RequestId: "script-1",
+			Entries:   []*v1.ScriptHistoryEntry{{Seq: 1}, {Seq: 2}},
+		})
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	history, err := New(srv.URL).ScriptHistory(context.Background(), "script-1")
+	if err != nil {
+		t.Fatalf("ScriptHistory returned error: %v", err)
+	}
+	if history.GetRequestId() != "script-1" || len(history.GetEntries()) != 2 {
+		t.Fatalf("unexpected history: %v", history)
+	}
+}
+
+func TestReplayScript(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/requests/script-1/script/replay" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		in := &v1.ScriptReplayRequest{}
+		if err := protojson.Unmarshal(body, in); err != nil || in.GetScriptRef().GetName() != "deploy" {
+			http.Error(w, "bad replay request", http.StatusBadRequest)
+			return
+		}
+		b, _ := protojson.Marshal(&v1.ScriptReplay{RequestId: "script-1", Reproduced: true})
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	replay, err := New(srv.URL).ReplayScript(context.Background(), "script-1", &v1.ScriptReplayRequest{
+		ScriptRef: &v1.ScriptRef{Name: "deploy"},
+	})
+	if err != nil {
+		t.Fatalf("ReplayScript returned error: %v", err)
+	}
+	if !replay.GetReproduced() {
+		t.Fatalf("unexpected replay: %v", replay)
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dop251/goja"
+	"github.com/dop251/goja_nodejs/require"
 	ggjengine "github.com/go-go-golems/go-go-goja/engine"
 	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
 )
@@ -22,24 +24,48 @@ const maxScriptLogBytes = 64 * 1024
 const scriptLogTruncatedLine = "[system] log output truncated"
 
 var (
-	ErrScriptSetup      = errors.New("script setup failed")
-	ErrScriptValidation = errors.New("script validation failed")
-	ErrScriptRuntime    = errors.New("script runtime failed")
-	ErrScriptTimeout    = errors.New("script execution timeout")
-	ErrScriptCancelled  = errors.New("script execution cancelled")
+	ErrScriptSetup       = errors.New("script setup failed")
+	ErrScriptValidation  = errors.New("script validation failed")
+	ErrScriptRuntime     = errors.New("script runtime failed")
+	ErrScriptTimeout     = errors.New("script execution timeout")
+	ErrScriptCancelled   = errors.New("script execution cancelled")
+	ErrScriptMemoryLimit = errors.New("script memory limit exceeded")
+	ErrScriptStackLimit  = errors.New("script call stack limit exceeded")
+	ErrScriptOutputLimit = errors.New("script output limit exceeded")
 )
 
 type Engine struct {
 	runtimeFactory *ggjengine.Factory
 	factoryErr     error
+	fs             fsConfig
+	fetch          fetchConfig
+	limits         limitsConfig
+	pool           *runtimePool
+	programs       *programCache
+	library        *Library
+	moduleUses     sync.Map // *goja.Runtime -> *moduleTracker, during InitAndView
 }
 
-func New() *Engine {
-	factory, err := ggjengine.NewBuilder().Build()
-	return &Engine{
-		runtimeFactory: factory,
-		factoryErr:     err,
+func New(opts ...Option) *Engine {
+	e := &Engine{
+		fs:       defaultFSConfig(),
+		fetch:    defaultFetchConfig(),
+		limits:   defaultLimitsConfig(),
+		pool:     newRuntimePool(),
+		programs: newProgramCache(defaultProgramCacheSize),
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.factoryErr != nil {
+		return e
+	}
+	builder := ggjengine.NewBuilder(ggjengine.WithRequireOptions(require.WithLoader(noSourceLoader)))
+	if e.library != nil {
+		builder.WithModules(e.library.moduleSpecs(e.trackModuleLoad)...)
+	}
+	e.runtimeFactory, e.factoryErr = builder.Build()
+	return e
 }
 
 type InitAndViewResult struct {
@@ -47,6 +73,15 @@ type InitAndViewResult struct {
 	State    map[string]any
 	View     map[string]any
 	Logs     []string
+	// Grants lists the opt-in capabilities granted to the script. Pass them
+	// back via WithGrants on UpdateAndView.
+	Grants []Grant
+	// Modules lists the library modules the script loaded, in load order.
+	Modules []ModuleRef
+	// Schedule is the tick requested by the view, if any.
+	Schedule *Schedule
+	// Locale is the ctx.locale init() and view() saw.
+	Locale string
 }
 
 type UpdateAndViewResult struct {
@@ -55,6 +90,52 @@ type UpdateAndViewResult struct {
 	State  map[string]any
 	View   map[string]any
 	Logs   []string
+	// Grants carries the recorded grants forward with updated budgets; store
+	// them in place of the ones passed via WithGrants.
+	Grants []Grant
+	// Schedule is the tick requested by the view, if any. It replaces any
+	// tick scheduled by an earlier view.
+	Schedule *Schedule
+	// Locale is the ctx.locale update() and view() saw.
+	Locale string
+}
+
+// RunError is returned by UpdateAndView and View when the script fails after
+// it started running. Logs holds the console output captured before the
+// failure. Grants carries the fetch budget the run spent; store it like a
+// successful run's grants so a throwing script cannot reuse calls it already
+// made.
+type RunError struct {
+	Err    error
+	Logs   []string
+	Grants []Grant
+}
+
+// FailedRunLogs returns the logs captured by the run that produced err, or
+// nil when err is not a RunError.
+func FailedRunLogs(err error) []string {
+	var runErr *RunError
+	if errors.As(err, &runErr) {
+		return runErr.Logs
+	}
+	return nil
+}
+
+func (e *RunError) Error() string { return e.Err.Error() }
+
+func (e *RunError) Unwrap() error { return e.Err }
+
+// carryGrants returns grants with the fetch grant's budget replaced by what
+// fetcher has used. fetcher is nil when the run never restored fetch.
+func carryGrants(grants []Grant, fetcher *scriptFetch) []Grant {
+	var out []Grant
+	for _, g := range grants {
+		if g.Capability == capabilityFetch && fetcher != nil {
+			g = fetcher.grant()
+		}
+		out = append(out, g)
+	}
+	return out
 }
 
 type runLogCollector struct {
@@ -70,13 +151,16 @@ func newRunLogCollector() *runLogCollector {
 }
 
 func (c *runLogCollector) Add(level string, args ...goja.Value) {
-	if c == nil {
+	if c == nil || c.truncated {
 		return
 	}
-	if c.truncated {
+	c.addLine("[" + level + "] " + formatConsoleArgs(args))
+}
+
+func (c *runLogCollector) addLine(line string) {
+	if c == nil || c.truncated {
 		return
 	}
-	line := "[" + level + "] " + formatConsoleArgs(args)
 	if len(c.lines) >= maxScriptLogLines || c.bytes+len(line) > maxScriptLogBytes {
 		c.lines = append(c.lines, scriptLogTruncatedLine)
 		c.bytes += len(scriptLogTruncatedLine)
@@ -172,30 +256,43 @@ func (e *Engine) newRuntime(ctx context.Context, collector *runLogCollector) (*g
 		_ = rt.Close(ctx)
 		return nil, err
 	}
+	if e.limits.maxCallStackSize > 0 {
+		rt.VM.SetMaxCallStackSize(e.limits.maxCallStackSize)
+	}
 	return rt, nil
 }
 
-func (e *Engine) InitAndView(ctx context.Context, in *v1.ScriptInput) (*InitAndViewResult, error) {
+func (e *Engine) InitAndView(ctx context.Context, in *v1.ScriptInput, opts ...RunOption) (*InitAndViewResult, error) {
 	if in == nil {
 		return nil, fmt.Errorf("%w: script input is required", ErrScriptValidation)
 	}
 	if strings.TrimSpace(in.GetScript()) == "" {
 		return nil, fmt.Errorf("%w: script source is required", ErrScriptValidation)
 	}
+	cfg := newRunConfig(opts)
 
 	collector := newRunLogCollector()
 	rt, err := e.newRuntime(ctx, collector)
 	if err != nil {
 		return nil, err
 	}
+	tracker := &moduleTracker{}
+	e.moduleUses.Store(rt.VM, tracker)
+	keep := false
 	defer func() {
+		e.moduleUses.Delete(rt.VM)
+		if keep {
+			e.pool.checkin(cfg.runtimeKey, &warmRuntime{rt: rt, script: in.GetScript()})
+			return
+		}
 		_ = rt.Close(ctx)
 	}()
 
 	var out InitAndViewResult
-	run := func() error {
-		if _, err := rt.VM.RunString(buildExportsProgram(in.GetScript())); err != nil {
-			return fmt.Errorf("%w: script load failed: %v", ErrScriptValidation, err)
+	var fetcher *scriptFetch
+	run := func(runCtx context.Context) error {
+		if err := e.loadScript(rt.VM, in); err != nil {
+			return err
 		}
 
 		hasDescribe, err := evalBool(rt.VM.RunString(`typeof __pc_exports.describe === "function"`))
@@ -218,7 +315,8 @@ func (e *Engine) InitAndView(ctx context.Context, in *v1.ScriptInput) (*InitAndV
 			return fmt.Errorf("%w: script must export describe/init/view/update functions", ErrScriptValidation)
 		}
 
-		scriptCtx := defaultScriptContext(in.GetProps())
+		scriptCtx := defaultScriptContext(in.GetProps(), cfg.clock())
+		setContextLocale(scriptCtx, Messages{}, cfg.acceptLanguage)
 		if err := rt.VM.Set("__pc_ctx", scriptCtx); err != nil {
 			return fmt.Errorf("%w: set ctx failed: %v", ErrScriptSetup, err)
 		}
@@ -228,22 +326,52 @@ func (e *Engine) InitAndView(ctx context.Context, in *v1.ScriptInput) (*InitAndV
 
 		describeVal, err := rt.VM.RunString(`__pc_exports.describe(__pc_ctx)`)
 		if err != nil {
-			return fmt.Errorf("%w: describe() failed: %v", ErrScriptRuntime, err)
+			return callError("describe()", err)
 		}
 		describeMap, err := expectMap(describeVal.Export(), "describe result")
 		if err != nil {
 			return err
 		}
+		if err := e.checkOutputSize("describe result", describeMap); err != nil {
+			return err
+		}
+		if raw, ok := describeMap["outputSchema"]; ok && raw != nil {
+			if err := checkOutputSchema(raw, "describe.outputSchema"); err != nil {
+				return fmt.Errorf("%w: %v", ErrScriptValidation, err)
+			}
+		}
+		if err := checkMessages(describeMap); err != nil {
+			return fmt.Errorf("%w: %v", ErrScriptValidation, err)
+		}
 		out.Describe = describeMap
+		// describe() runs before the catalogs are known; init() and view()
+		// see the locale negotiated against them.
+		out.Locale = setContextLocale(scriptCtx, MessagesFromDescribe(describeMap), cfg.acceptLanguage)
+
+		sfs, grant, err := e.grantFS(rt.VM, describeMap, collector)
+		if err != nil {
+			return err
+		}
+		defer sfs.Close()
+		if grant != nil {
+			out.Grants = append(out.Grants, *grant)
+		}
+		fetcher, err = e.grantFetch(runCtx, rt.VM, describeMap, collector)
+		if err != nil {
+			return err
+		}
 
 		stateVal, err := rt.VM.RunString(`__pc_exports.init(__pc_ctx)`)
 		if err != nil {
-			return fmt.Errorf("%w: init() failed: %v", ErrScriptRuntime, err)
+			return callError("init()", err)
 		}
 		stateMap, err := expectMap(stateVal.Export(), "init result")
 		if err != nil {
 			return err
 		}
+		if err := e.checkOutputSize("init result", stateMap); err != nil {
+			return err
+		}
 		out.State = stateMap
 
 		if err := rt.VM.Set("__pc_state", stateMap); err != nil {
@@ -252,21 +380,33 @@ func (e *Engine) InitAndView(ctx context.Context, in *v1.ScriptInput) (*InitAndV
 
 		viewVal, err := rt.VM.RunString(`__pc_exports.view(__pc_state, __pc_ctx)`)
 		if err != nil {
-			return fmt.Errorf("%w: view() failed: %v", ErrScriptRuntime, err)
+			return callError("view()", err)
 		}
 		viewMap, err := expectMap(viewVal.Export(), "view result")
 		if err != nil {
 			return err
 		}
+		out.Schedule, err = takeSchedule(viewMap)
+		if err != nil {
+			return err
+		}
+		if err := e.checkOutputSize("view result", viewMap); err != nil {
+			return err
+		}
 		out.View = viewMap
 
 		return nil
 	}
 
-	if err := runWithTimeout(ctx, rt.VM, timeoutFromInput(in), run); err != nil {
+	if err := runWithTimeout(ctx, rt.VM, timeoutFromInput(in), e.limits.maxHeapGrowth, run); err != nil {
 		return nil, err
 	}
+	if fetcher != nil {
+		out.Grants = append(out.Grants, fetcher.grant())
+	}
+	out.Modules = append(out.Modules, tracker.refs...)
 	out.Logs = collector.Snapshot()
+	keep = e.pool.enabled() && cfg.runtimeKey != ""
 
 	return &out, nil
 }
@@ -276,6 +416,7 @@ func (e *Engine) UpdateAndView(
 	in *v1.ScriptInput,
 	state map[string]any,
 	event map[string]any,
+	opts ...RunOption,
 ) (*UpdateAndViewResult, error) {
 	if in == nil {
 		return nil, fmt.Errorf("%w: script input is required", ErrScriptValidation)
@@ -289,43 +430,73 @@ func (e *Engine) UpdateAndView(
 	if event == nil {
 		event = map[string]any{}
 	}
+	cfg := newRunConfig(opts)
 
 	collector := newRunLogCollector()
-	rt, err := e.newRuntime(ctx, collector)
-	if err != nil {
-		return nil, err
+	warm := e.pool.checkout(cfg.runtimeKey, in.GetScript())
+	var rt *ggjengine.Runtime
+	if warm != nil {
+		rt = warm.rt
+		if err := installConsoleCapture(rt.VM, collector); err != nil {
+			_ = rt.Close(ctx)
+			return nil, err
+		}
+	} else {
+		var err error
+		rt, err = e.newRuntime(ctx, collector)
+		if err != nil {
+			return nil, err
+		}
 	}
+	keep := false
 	defer func() {
+		if keep {
+			e.pool.checkin(cfg.runtimeKey, &warmRuntime{rt: rt, script: in.GetScript()})
+			return
+		}
 		_ = rt.Close(ctx)
 	}()
 
 	var out UpdateAndViewResult
-	run := func() error {
-		if _, err := rt.VM.RunString(buildExportsProgram(in.GetScript())); err != nil {
-			return fmt.Errorf("%w: script load failed: %v", ErrScriptValidation, err)
-		}
+	var fetcher *scriptFetch
+	run := func(runCtx context.Context) error {
+		if warm == nil {
+			if err := e.loadScript(rt.VM, in); err != nil {
+				return err
+			}
 
-		hasUpdate, err := evalBool(rt.VM.RunString(`typeof __pc_exports.update === "function"`))
-		if err != nil {
-			return err
-		}
-		hasView, err := evalBool(rt.VM.RunString(`typeof __pc_exports.view === "function"`))
-		if err != nil {
-			return err
-		}
-		if !hasUpdate || !hasView {
-			return fmt.Errorf("%w: script must export update/view functions", ErrScriptValidation)
+			hasUpdate, err := evalBool(rt.VM.RunString(`typeof __pc_exports.update === "function"`))
+			if err != nil {
+				return err
+			}
+			hasView, err := evalBool(rt.VM.RunString(`typeof __pc_exports.view === "function"`))
+			if err != nil {
+				return err
+			}
+			if !hasUpdate || !hasView {
+				return fmt.Errorf("%w: script must export update/view functions", ErrScriptValidation)
+			}
+			if err := rt.VM.Set("__pc_state", state); err != nil {
+				return fmt.Errorf("%w: set state failed: %v", ErrScriptSetup, err)
+			}
 		}
 
-		scriptCtx := defaultScriptContext(in.GetProps())
+		scriptCtx := defaultScriptContext(in.GetProps(), cfg.clock())
+		out.Locale = setContextLocale(scriptCtx, cfg.messages, cfg.acceptLanguage)
 		if err := rt.VM.Set("__pc_ctx", scriptCtx); err != nil {
 			return fmt.Errorf("%w: set ctx failed: %v", ErrScriptSetup, err)
 		}
 		if err := attachContextHelpers(rt.VM); err != nil {
 			return err
 		}
-		if err := rt.VM.Set("__pc_state", state); err != nil {
-			return fmt.Errorf("%w: set state failed: %v", ErrScriptSetup, err)
+		sfs, err := e.restoreFS(rt.VM, cfg.grants)
+		if err != nil {
+			return err
+		}
+		defer sfs.Close()
+		fetcher, err = e.restoreFetch(runCtx, rt.VM, cfg.grants, collector)
+		if err != nil {
+			return err
 		}
 		if err := rt.VM.Set("__pc_event", event); err != nil {
 			return fmt.Errorf("%w: set event failed: %v", ErrScriptSetup, err)
@@ -333,7 +504,7 @@ func (e *Engine) UpdateAndView(
 
 		updateVal, err := rt.VM.RunString(`__pc_exports.update(__pc_state, __pc_event, __pc_ctx)`)
 		if err != nil {
-			return fmt.Errorf("%w: update() failed: %v", ErrScriptRuntime, err)
+			return callError("update()", err)
 		}
 
 		updateMap, err := expectMap(updateVal.Export(), "update result")
@@ -347,35 +518,116 @@ func (e *Engine) UpdateAndView(
 			if err != nil {
 				return err
 			}
+			if err := e.checkOutputSize("update.result", resultMap); err != nil {
+				return err
+			}
+			if cfg.outputSchema != nil {
+				if err := validateOutput(cfg.outputSchema, resultMap, "result"); err != nil {
+					return fmt.Errorf("%w: update.result does not match describe().outputSchema: %v", ErrScriptRuntime, err)
+				}
+			}
 			out.Result = resultMap
 			return nil
 		}
 
-		out.State = updateMap
-		if err := rt.VM.Set("__pc_state", out.State); err != nil {
+		// The live value stays in the runtime; the JSON snapshot is what gets
+		// stored and what a cold runtime starts from.
+		if err := rt.VM.Set("__pc_state", updateVal); err != nil {
 			return fmt.Errorf("%w: set next state failed: %v", ErrScriptSetup, err)
 		}
+		snapshot, err := rt.VM.RunString(`JSON.parse(JSON.stringify(__pc_state))`)
+		if err != nil {
+			return fmt.Errorf("%w: state is not JSON-serializable: %v", ErrScriptValidation, err)
+		}
+		out.State, err = expectMap(snapshot.Export(), "update result")
+		if err != nil {
+			return err
+		}
+		if err := e.checkOutputSize("update result", out.State); err != nil {
+			return err
+		}
 
 		viewVal, err := rt.VM.RunString(`__pc_exports.view(__pc_state, __pc_ctx)`)
 		if err != nil {
-			return fmt.Errorf("%w: view() failed: %v", ErrScriptRuntime, err)
+			return callError("view()", err)
 		}
 		viewMap, err := expectMap(viewVal.Export(), "view result")
 		if err != nil {
 			return err
 		}
+		out.Schedule, err = takeSchedule(viewMap)
+		if err != nil {
+			return err
+		}
+		if err := e.checkOutputSize("view result", viewMap); err != nil {
+			return err
+		}
 		out.View = viewMap
 		return nil
 	}
 
-	if err := runWithTimeout(ctx, rt.VM, timeoutFromInput(in), run); err != nil {
-		return nil, err
+	if err := runWithTimeout(ctx, rt.VM, timeoutFromInput(in), e.limits.maxHeapGrowth, run); err != nil {
+		return nil, &RunError{Err: err, Logs: collector.Snapshot(), Grants: carryGrants(cfg.grants, fetcher)}
 	}
+	out.Grants = carryGrants(cfg.grants, fetcher)
 	out.Logs = collector.Snapshot()
+	// A runtime is only kept after a clean run; on error it may hold a
+	// partially updated state.
+	keep = !out.Done && e.pool.enabled() && cfg.runtimeKey != ""
 
 	return &out, nil
 }
 
+// Rekey moves the warm runtime kept for from to the key to, for callers
+// that only learn the request id after InitAndView.
+func (e *Engine) Rekey(from, to string) {
+	e.pool.rekey(from, to)
+}
+
+// Release drops the warm runtime kept for key, if any.
+func (e *Engine) Release(key string) {
+	e.pool.release(key)
+}
+
+// EvictIdle closes warm runtimes that have been idle for longer than the
+// configured TTL.
+func (e *Engine) EvictIdle() {
+	e.pool.evictIdle()
+}
+
+// Stats reports engine cache activity.
+type Stats struct {
+	Programs     ProgramCacheStats
+	WarmRuntimes int
+}
+
+// Stats returns a snapshot of the compiled-program cache and warm runtime
+// pool.
+func (e *Engine) Stats() Stats {
+	return Stats{
+		Programs:     e.programs.snapshot(),
+		WarmRuntimes: e.pool.size(),
+	}
+}
+
+// Close releases all warm runtimes.
+func (e *Engine) Close() {
+	e.pool.closeAll()
+}
+
+// Sandbox returns an engine with e's library, resource limits, and compiled
+// program cache that grants no fs or fetch capability and keeps no warm
+// runtimes. It is meant for re-running recorded flows without side effects.
+func (e *Engine) Sandbox() *Engine {
+	s := New(WithLibrary(e.library))
+	s.limits = e.limits
+	s.programs = e.programs
+	if e.factoryErr != nil {
+		s.factoryErr = e.factoryErr
+	}
+	return s
+}
+
 func buildExportsProgram(script string) string {
 	return `
 var __pc_module = { exports: {} };
@@ -441,6 +693,9 @@ function __pc_branch(state, event, spec) {
 }
 ` + script + `
 var __pc_exports = __pc_module.exports;
+if (__pc_exports && __pc_exports.__esModule && __pc_exports.default) {
+  __pc_exports = __pc_exports.default;
+}
 `
 }
 
@@ -479,7 +734,7 @@ func expectMap(v any, name string) (map[string]any, error) {
 	return m, nil
 }
 
-func defaultScriptContext(propsStruct interface{ AsMap() map[string]any }) map[string]any {
+func defaultScriptContext(propsStruct interface{ AsMap() map[string]any }, now time.Time) map[string]any {
 	props := map[string]any{}
 	if propsStruct != nil {
 		props = propsStruct.AsMap()
@@ -491,7 +746,7 @@ func defaultScriptContext(propsStruct interface{ AsMap() map[string]any }) map[s
 	rng := rand.New(rand.NewSource(seed))
 	return map[string]any{
 		"props": props,
-		"now":   time.Now().UTC().Format(time.RFC3339Nano),
+		"now":   now.UTC().Format(time.RFC3339Nano),
 		"seed":  float64(seed),
 		"random": func() float64 {
 			return rng.Float64()
@@ -543,27 +798,60 @@ func timeoutFromInput(in *v1.ScriptInput) time.Duration {
 	return defaultTimeout
 }
 
+// runWithTimeout runs fn with a deadline, interrupting vm when it passes or
+// ctx is cancelled. With maxHeapGrowth > 0 it also samples the heap and
+// interrupts the run once it has grown by more than that many bytes.
 func runWithTimeout(
 	ctx context.Context,
 	vm interface{ Interrupt(any) },
 	timeout time.Duration,
-	fn func() error,
+	maxHeapGrowth uint64,
+	fn func(context.Context) error,
 ) error {
 	runCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	stop := make(chan struct{})
-	defer close(stop)
-
+	watcherDone := make(chan struct{})
+	heapExceeded := false
 	go func() {
-		select {
-		case <-runCtx.Done():
-			vm.Interrupt(runCtx.Err())
-		case <-stop:
+		defer close(watcherDone)
+		var tick <-chan time.Time
+		var baseline uint64
+		if maxHeapGrowth > 0 {
+			baseline = heapInUse()
+			ticker := time.NewTicker(heapCheckInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-runCtx.Done():
+				vm.Interrupt(runCtx.Err())
+				return
+			case <-tick:
+				if inUse := heapInUse(); inUse > baseline && inUse-baseline > maxHeapGrowth {
+					heapExceeded = true
+					vm.Interrupt(ErrScriptMemoryLimit)
+					// Cancel too, so callers that swallow script errors
+					// still see the run is over.
+					cancel()
+					return
+				}
+			case <-stop:
+				return
+			}
 		}
 	}()
 
-	err := fn()
+	err := fn(runCtx)
+	// Wait for the watcher so a late Interrupt cannot hit a runtime that is
+	// reused for the next event.
+	close(stop)
+	<-watcherDone
+	if heapExceeded {
+		return fmt.Errorf("%w: heap grew by more than %d bytes during the run", ErrScriptMemoryLimit, maxHeapGrowth)
+	}
 	if runErr := runCtx.Err(); runErr != nil {
 		if errors.Is(runErr, context.DeadlineExceeded) {
 			if err != nil {
@@ -214,6 +214,83 @@ module.exports = {
 	}
 }
 
+func TestWithNowFreezesContextClock(t *testing.T) {
+	t.Parallel()
+
+	script := `
+module.exports = {
+  describe: function () { return { name: "clock", version: "1.0.0" }; },
+  init: function (ctx) {
+    return { now: ctx.now, year: new Date().getUTCFullYear() };
+  },
+  view: function (state) {
+    return { widgetType: "confirm", input: { title: "clock" } };
+  },
+  update: function (state, event, ctx) {
+    return { done: true, result: { now: ctx.now } };
+  }
+};
+`
+	frozen := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	in := &v1.ScriptInput{Script: script}
+
+	e := New(WithWarmRuntimes(4, time.Minute))
+	defer e.Close()
+	initRes, err := e.InitAndView(context.Background(), in, WithNow(frozen))
+	if err != nil {
+		t.Fatalf("InitAndView failed: %v", err)
+	}
+	if initRes.State["now"] != "2024-03-01T12:30:00Z" {
+		t.Fatalf("expected frozen ctx.now in init, got %v", initRes.State)
+	}
+	// Date is left on the wall clock so scripts that measure elapsed time
+	// keep working.
+	if year, _ := initRes.State["year"].(int64); year == 2024 {
+		t.Fatalf("expected Date to read the wall clock, got %v", initRes.State)
+	}
+
+	later := frozen.Add(90 * time.Second)
+	upd, err := e.UpdateAndView(context.Background(), in, initRes.State, map[string]any{"type": "submit"}, WithNow(later))
+	if err != nil {
+		t.Fatalf("UpdateAndView failed: %v", err)
+	}
+	if upd.Result["now"] != "2024-03-01T12:31:30Z" {
+		t.Fatalf("expected frozen ctx.now in update, got %v", upd.Result)
+	}
+}
+
+func TestSandboxDeniesCapabilities(t *testing.T) {
+	t.Parallel()
+
+	script := `
+module.exports = {
+  describe: function () { return { name: "caps", version: "1.0.0", capabilities: ["fs", "fetch"] }; },
+  init: function (ctx) { return { fs: typeof ctx.fs, fetch: typeof ctx.fetch }; },
+  view: function () { return { widgetType: "confirm", input: { title: "caps" } }; },
+  update: function () { return { done: true, result: {} }; }
+};
+`
+	e := New(WithFSRoots(t.TempDir()), WithFetchAllowlist("example.com"))
+	defer e.Close()
+	live, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: script})
+	if err != nil {
+		t.Fatalf("InitAndView failed: %v", err)
+	}
+	if live.State["fs"] != "object" || live.State["fetch"] != "function" {
+		t.Fatalf("expected capabilities on the live engine, got %v", live.State)
+	}
+
+	sandbox := e.Sandbox()
+	defer sandbox.Close()
+	out, err := sandbox.InitAndView(context.Background(), &v1.ScriptInput{Script: script})
+	if err != nil {
+		t.Fatalf("sandbox InitAndView failed: %v", err)
+	}
+	if out.State["fs"] != "undefined" || out.State["fetch"] != "undefined" || len(out.Grants) != 0 {
+		t.Fatalf("expected no capabilities in the sandbox, got state=%v grants=%v", out.State, out.Grants)
+	}
+}
+
 func TestBranchHelperSupportsRoutesAndPredicates(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,272 @@
+package scriptengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/go-go-golems/plz-confirm/internal/client"
+)
+
+const (
+	capabilityFetch           = "fetch"
+	defaultFetchMaxCalls      = 10
+	defaultFetchMaxBytes      = 1 << 20
+	defaultFetchCallTimeout   = 5 * time.Second
+	fetchDeniedNoHostsLogLine = "[system] fetch capability denied: no hosts allowed"
+	fetchGrantedLogLinePrefix = "[system] fetch capability granted: hosts="
+	fetchUserAgent            = "plz-confirm-script"
+	fetchMaxRedirects         = 5
+)
+
+type fetchConfig struct {
+	allowedHosts []string
+	maxCalls     int
+	maxBytes     int64
+	client       *http.Client
+}
+
+func defaultFetchConfig() fetchConfig {
+	return fetchConfig{
+		maxCalls: defaultFetchMaxCalls,
+		maxBytes: defaultFetchMaxBytes,
+		client:   newFetchHTTPClient(),
+	}
+}
+
+// newFetchHTTPClient builds the client shared by all ctx.fetch calls. Proxies
+// from the environment are ignored and every dialed address is re-checked, so
+// a hostname that resolves to a blocked IP (e.g. cloud metadata) is refused.
+func newFetchHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: defaultFetchCallTimeout,
+		Control: func(_ string, address string, _ syscall.RawConn) error {
+			return client.ValidateOutboundURL(&url.URL{Scheme: "http", Host: address})
+		},
+	}
+	return &http.Client{
+		Timeout: defaultFetchCallTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: defaultFetchCallTimeout,
+			MaxIdleConns:        16,
+			IdleConnTimeout:     30 * time.Second,
+		},
+	}
+}
+
+// hosts returns the configured allowlist, lower-cased and without blanks.
+func (c fetchConfig) hosts() []string {
+	out := make([]string, 0, len(c.allowedHosts))
+	for _, h := range c.allowedHosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// restrict returns the hosts in scope that are still configured. Hosts removed
+// from the server configuration since the grant was recorded are dropped.
+func (c fetchConfig) restrict(scope []string) []string {
+	allowed := map[string]bool{}
+	for _, h := range c.hosts() {
+		allowed[h] = true
+	}
+	out := make([]string, 0, len(scope))
+	for _, h := range scope {
+		if allowed[h] {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// hostAllowed reports whether host matches one of the patterns. A pattern is
+// either an exact host name or "*.example.com", which matches subdomains only.
+func hostAllowed(host string, patterns []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, p := range patterns {
+		if suffix, ok := strings.CutPrefix(p, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == p {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptFetch implements ctx.fetch. Calls and response bytes are counted
+// against a per-request budget that carries over between init and updates.
+type scriptFetch struct {
+	ctx       context.Context
+	client    *http.Client
+	hosts     []string
+	maxCalls  int
+	maxBytes  int64
+	calls     int
+	bytes     int64
+	collector *runLogCollector
+}
+
+func (c fetchConfig) open(ctx context.Context, g Grant, collector *runLogCollector) *scriptFetch {
+	f := &scriptFetch{
+		ctx:       ctx,
+		hosts:     c.restrict(g.Scope),
+		maxCalls:  c.maxCalls,
+		maxBytes:  c.maxBytes,
+		calls:     g.CallsUsed,
+		bytes:     g.BytesUsed,
+		collector: collector,
+	}
+	httpClient := *c.client
+	httpClient.CheckRedirect = f.checkRedirect
+	f.client = &httpClient
+	return f
+}
+
+func (f *scriptFetch) grant() Grant {
+	return Grant{
+		Capability: capabilityFetch,
+		Scope:      append([]string(nil), f.hosts...),
+		CallsUsed:  f.calls,
+		BytesUsed:  f.bytes,
+	}
+}
+
+func (f *scriptFetch) checkURL(u *url.URL) error {
+	if err := client.ValidateOutboundURL(u); err != nil {
+		return err
+	}
+	if !hostAllowed(u.Hostname(), f.hosts) {
+		return fmt.Errorf("host %q is not allowed", u.Hostname())
+	}
+	return nil
+}
+
+func (f *scriptFetch) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= fetchMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", fetchMaxRedirects)
+	}
+	return f.checkURL(req.URL)
+}
+
+func (f *scriptFetch) fetch(rawURL string, opts map[string]any) (map[string]any, error) {
+	method := http.MethodGet
+	if m, ok := opts["method"].(string); ok && m != "" {
+		method = strings.ToUpper(m)
+	}
+	if method != http.MethodGet && method != http.MethodHead {
+		return nil, fmt.Errorf("fetch: method %s is not allowed (GET, HEAD)", method)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: invalid url: %v", err)
+	}
+	if err := f.checkURL(u); err != nil {
+		f.collector.addLine(fmt.Sprintf("[fetch] %s %s denied: %v", method, u.Redacted(), err))
+		return nil, fmt.Errorf("fetch: %v", err)
+	}
+	if f.calls >= f.maxCalls {
+		f.collector.addLine(fmt.Sprintf("[fetch] %s %s denied: call budget exhausted", method, u.Redacted()))
+		return nil, fmt.Errorf("fetch: call budget of %d exhausted", f.maxCalls)
+	}
+	f.calls++
+
+	req, err := http.NewRequestWithContext(f.ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: %v", err)
+	}
+	req.Header.Set("User-Agent", fetchUserAgent)
+	if headers, ok := opts["headers"].(map[string]any); ok {
+		for k, v := range headers {
+			req.Header.Set(k, fmt.Sprint(v))
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		f.collector.addLine(fmt.Sprintf("[fetch] %s %s failed: %v", method, u.Redacted(), err))
+		return nil, fmt.Errorf("fetch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	remaining := f.maxBytes - f.bytes
+	body, err := io.ReadAll(io.LimitReader(resp.Body, remaining+1))
+	f.bytes += min(int64(len(body)), remaining)
+	if err != nil {
+		f.collector.addLine(fmt.Sprintf("[fetch] %s %s failed: %v", method, u.Redacted(), err))
+		return nil, fmt.Errorf("fetch: %v", err)
+	}
+	if int64(len(body)) > remaining {
+		f.collector.addLine(fmt.Sprintf("[fetch] %s %s failed: byte budget of %d exhausted", method, u.Redacted(), f.maxBytes))
+		return nil, fmt.Errorf("fetch: byte budget of %d exhausted", f.maxBytes)
+	}
+	f.collector.addLine(fmt.Sprintf("[fetch] %s %s -> %d (%d bytes)", method, u.Redacted(), resp.StatusCode, len(body)))
+
+	headers := map[string]any{}
+	for k := range resp.Header {
+		headers[strings.ToLower(k)] = resp.Header.Get(k)
+	}
+	return map[string]any{
+		"status":  resp.StatusCode,
+		"ok":      resp.StatusCode >= 200 && resp.StatusCode < 300,
+		"headers": headers,
+		"body":    string(body),
+	}, nil
+}
+
+func (f *scriptFetch) install(vm *goja.Runtime) error {
+	ctxObj := vm.Get("__pc_ctx").ToObject(vm)
+	if err := ctxObj.Set("fetch", f.fetch); err != nil {
+		return fmt.Errorf("%w: set ctx.fetch: %v", ErrScriptSetup, err)
+	}
+	return nil
+}
+
+// grantFetch installs ctx.fetch during init when describe() declares the
+// "fetch" capability and the operator allowed at least one host.
+func (e *Engine) grantFetch(ctx context.Context, vm *goja.Runtime, describe map[string]any, collector *runLogCollector) (*scriptFetch, error) {
+	if !declaresCapability(describe, capabilityFetch) {
+		return nil, nil
+	}
+	hosts := e.fetch.hosts()
+	if len(hosts) == 0 {
+		collector.addLine(fetchDeniedNoHostsLogLine)
+		return nil, nil
+	}
+	f := e.fetch.open(ctx, Grant{Capability: capabilityFetch, Scope: hosts}, collector)
+	if err := f.install(vm); err != nil {
+		return nil, err
+	}
+	collector.addLine(fetchGrantedLogLinePrefix + strings.Join(hosts, ","))
+	return f, nil
+}
+
+// restoreFetch re-installs ctx.fetch for update/view from a recorded grant,
+// resuming its budget.
+func (e *Engine) restoreFetch(ctx context.Context, vm *goja.Runtime, grants []Grant, collector *runLogCollector) (*scriptFetch, error) {
+	for _, g := range grants {
+		if g.Capability != capabilityFetch {
+			continue
+		}
+		f := e.fetch.open(ctx, g, collector)
+		if err := f.install(vm); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	return nil, nil
+}
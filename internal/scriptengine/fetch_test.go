@@ -0,0 +1,230 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const fetchScript = `
+module.exports = {
+  describe: function () {
+    return { name: "lookup", version: "1.0.0", capabilities: ["fetch"] };
+  },
+  init: function (ctx) {
+    var state = { hasFetch: !!ctx.fetch };
+    if (!ctx.fetch) return state;
+    var res = ctx.fetch(ctx.props.base + "/ticket");
+    state.status = res.status;
+    state.ticket = JSON.parse(res.body).title;
+    state.contentType = res.headers["content-type"];
+    try { ctx.fetch("http://169.254.169.254/latest/meta-data"); state.metadata = "allowed"; } catch (e) { state.metadata = "denied"; }
+    try { ctx.fetch("http://example.com/"); state.other = "allowed"; } catch (e) { state.other = "denied"; }
+    try { ctx.fetch(ctx.props.base + "/redirect"); state.redirect = "allowed"; } catch (e) { state.redirect = "denied"; }
+    try { ctx.fetch(ctx.props.base + "/ticket", { method: "POST" }); state.post = "allowed"; } catch (e) { state.post = "denied"; }
+    return state;
+  },
+  view: function (state) {
+    return { widgetType: "confirm", input: { title: "Approve " + state.ticket + "?" } };
+  },
+  update: function (state, event, ctx) {
+    var results = [];
+    for (var i = 0; i < 3; i++) {
+      try { ctx.fetch(ctx.props.base + "/ticket"); results.push("ok"); } catch (e) { results.push("denied"); }
+    }
+    return { done: true, result: { results: results } };
+  }
+};
+`
+
+func TestFetchCapabilityIsPolicyControlled(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ticket":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"title":"PROJ-1"}`))
+		case "/redirect":
+			_, port, _ := net.SplitHostPort(r.Host)
+			http.Redirect(w, r, "http://localhost:"+port+"/ticket", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	e := New(WithFetchAllowlist("127.0.0.1"), WithFetchLimits(3, 0))
+	props, err := structpb.NewStruct(map[string]any{"base": srv.URL})
+	if err != nil {
+		t.Fatalf("props: %v", err)
+	}
+	in := &v1.ScriptInput{Script: fetchScript, Props: props}
+
+	out, err := e.InitAndView(context.Background(), in)
+	if err != nil {
+		t.Fatalf("InitAndView returned error: %v", err)
+	}
+	if out.State["ticket"] != "PROJ-1" || out.State["contentType"] != "application/json" {
+		t.Fatalf("unexpected fetch result: %#v", out.State)
+	}
+	for _, key := range []string{"metadata", "other", "redirect", "post"} {
+		if out.State[key] != "denied" {
+			t.Fatalf("expected %s fetch to be denied, got %v", key, out.State[key])
+		}
+	}
+	if len(out.Grants) != 1 || out.Grants[0].Capability != "fetch" || out.Grants[0].Scope[0] != "127.0.0.1" {
+		t.Fatalf("unexpected grants: %#v", out.Grants)
+	}
+	// The redirect call reached the server before being refused, so it counts.
+	if out.Grants[0].CallsUsed != 2 {
+		t.Fatalf("expected 2 calls used after init, got %d", out.Grants[0].CallsUsed)
+	}
+	if !containsLogPrefix(out.Logs, fetchGrantedLogLinePrefix) {
+		t.Fatalf("expected grant to be logged, got %v", out.Logs)
+	}
+	if !containsLogPrefix(out.Logs, "[fetch] GET "+srv.URL+"/ticket -> 200") {
+		t.Fatalf("expected fetch call to be logged, got %v", out.Logs)
+	}
+
+	// The budget carries over: only one of the three update calls fits.
+	updated, err := e.UpdateAndView(context.Background(), in, out.State, map[string]any{"type": "submit"}, WithGrants(out.Grants...))
+	if err != nil {
+		t.Fatalf("UpdateAndView returned error: %v", err)
+	}
+	results, _ := updated.Result["results"].([]any)
+	if len(results) != 3 || results[0] != "ok" || results[1] != "denied" || results[2] != "denied" {
+		t.Fatalf("unexpected update results: %#v", updated.Result["results"])
+	}
+	if len(updated.Grants) != 1 || updated.Grants[0].CallsUsed != 3 {
+		t.Fatalf("expected budget to be carried forward, got %#v", updated.Grants)
+	}
+}
+
+func TestFetchCapabilityEnforcesByteBudget(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 64)))
+	}))
+	defer srv.Close()
+
+	e := New(WithFetchAllowlist("127.0.0.1"), WithFetchLimits(0, 100))
+	props, err := structpb.NewStruct(map[string]any{"base": srv.URL})
+	if err != nil {
+		t.Fatalf("props: %v", err)
+	}
+	script := `
+module.exports = {
+  describe: function () { return { name: "bytes", version: "1.0.0", capabilities: ["fetch"] }; },
+  init: function (ctx) {
+    var out = [];
+    for (var i = 0; i < 2; i++) {
+      try { out.push(ctx.fetch(ctx.props.base).body.length); } catch (e) { out.push(-1); }
+    }
+    return { sizes: out };
+  },
+  view: function () { return { widgetType: "confirm", input: { title: "ok" } }; },
+  update: function () { return { done: true, result: {} }; }
+};`
+	out, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: script, Props: props})
+	if err != nil {
+		t.Fatalf("InitAndView returned error: %v", err)
+	}
+	sizes, _ := out.State["sizes"].([]any)
+	if len(sizes) != 2 || sizes[0] != int64(64) || sizes[1] != int64(-1) {
+		t.Fatalf("expected second fetch to exceed the byte budget, got %#v", out.State["sizes"])
+	}
+	if out.Grants[0].BytesUsed != 100 {
+		t.Fatalf("expected byte budget to be fully used, got %d", out.Grants[0].BytesUsed)
+	}
+	if !containsLogPrefix(out.Logs, "[fetch] GET "+srv.URL+" failed: byte budget of 100 exhausted") {
+		t.Fatalf("expected budget failure to be logged, got %v", out.Logs)
+	}
+}
+
+func TestFetchBudgetIsReportedWhenUpdateThrows(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	e := New(WithFetchAllowlist("127.0.0.1"), WithFetchLimits(5, 0))
+	props, err := structpb.NewStruct(map[string]any{"base": srv.URL})
+	if err != nil {
+		t.Fatalf("props: %v", err)
+	}
+	script := `
+module.exports = {
+  describe: function () { return { name: "thrower", version: "1.0.0", capabilities: ["fetch"] }; },
+  init: function () { return {}; },
+  view: function () { return { widgetType: "confirm", input: { title: "ok" } }; },
+  update: function (state, event, ctx) {
+    ctx.fetch(ctx.props.base);
+    ctx.fetch(ctx.props.base);
+    throw new Error("boom");
+  }
+};`
+	in := &v1.ScriptInput{Script: script, Props: props}
+	out, err := e.InitAndView(context.Background(), in)
+	if err != nil {
+		t.Fatalf("InitAndView returned error: %v", err)
+	}
+
+	_, err = e.UpdateAndView(context.Background(), in, out.State, map[string]any{"type": "submit"}, WithGrants(out.Grants...))
+	var runErr *RunError
+	if !errors.As(err, &runErr) || !errors.Is(err, ErrScriptRuntime) {
+		t.Fatalf("expected a RunError wrapping a runtime error, got %v", err)
+	}
+	if len(runErr.Grants) != 1 || runErr.Grants[0].CallsUsed != 2 {
+		t.Fatalf("expected the failed run to report 2 calls used, got %#v", runErr.Grants)
+	}
+}
+
+func TestFetchCapabilityDeniedWithoutAllowlist(t *testing.T) {
+	t.Parallel()
+
+	e := New()
+	out, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: fetchScript})
+	if err != nil {
+		t.Fatalf("InitAndView returned error: %v", err)
+	}
+	if out.State["hasFetch"] != false {
+		t.Fatalf("expected ctx.fetch to be absent, got %#v", out.State)
+	}
+	if len(out.Grants) != 0 {
+		t.Fatalf("expected no grants, got %#v", out.Grants)
+	}
+	if !containsLogPrefix(out.Logs, fetchDeniedNoHostsLogLine) {
+		t.Fatalf("expected denial to be logged, got %v", out.Logs)
+	}
+}
+
+func TestFetchHostAllowed(t *testing.T) {
+	t.Parallel()
+
+	patterns := []string{"api.example.com", "*.internal.example"}
+	cases := map[string]bool{
+		"api.example.com":      true,
+		"API.example.com":      true,
+		"other.example.com":    false,
+		"svc.internal.example": true,
+		"internal.example":     false,
+		"evilinternal.example": false,
+		"api.example.com.evil": false,
+	}
+	for host, want := range cases {
+		if got := hostAllowed(host, patterns); got != want {
+			t.Errorf("hostAllowed(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
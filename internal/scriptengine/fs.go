@@ -0,0 +1,253 @@
+package scriptengine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	capabilityFS           = "fs"
+	defaultFSMaxFileBytes  = 1 << 20
+	defaultFSMaxDirEntries = 1000
+	fsDeniedNoRootsLogLine = "[system] fs capability denied: no roots configured"
+	fsGrantedLogLinePrefix = "[system] fs capability granted: roots="
+)
+
+type fsConfig struct {
+	roots         []string
+	maxFileBytes  int64
+	maxDirEntries int
+}
+
+func defaultFSConfig() fsConfig {
+	return fsConfig{
+		maxFileBytes:  defaultFSMaxFileBytes,
+		maxDirEntries: defaultFSMaxDirEntries,
+	}
+}
+
+// scriptFS implements ctx.fs. Every access goes through os.Root, so paths
+// (including symlinks) cannot escape the configured roots.
+type scriptFS struct {
+	roots         []*os.Root
+	maxFileBytes  int64
+	maxDirEntries int
+}
+
+// absRoots returns the configured roots as absolute paths.
+func (c fsConfig) absRoots() ([]string, error) {
+	out := make([]string, 0, len(c.roots))
+	for _, dir := range c.roots {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%w: fs root %q: %v", ErrScriptSetup, dir, err)
+		}
+		out = append(out, abs)
+	}
+	return out, nil
+}
+
+// open opens the configured roots that appear in scope. Roots removed from
+// the server configuration since the grant was recorded are not reopened.
+func (c fsConfig) open(scope []string) (*scriptFS, error) {
+	configured, err := c.absRoots()
+	if err != nil {
+		return nil, err
+	}
+	allowed := map[string]bool{}
+	for _, dir := range configured {
+		allowed[dir] = true
+	}
+	out := &scriptFS{
+		maxFileBytes:  c.maxFileBytes,
+		maxDirEntries: c.maxDirEntries,
+	}
+	for _, dir := range scope {
+		if !allowed[dir] {
+			continue
+		}
+		root, err := os.OpenRoot(dir)
+		if err != nil {
+			out.Close()
+			return nil, fmt.Errorf("%w: open fs root %q: %v", ErrScriptSetup, dir, err)
+		}
+		out.roots = append(out.roots, root)
+	}
+	return out, nil
+}
+
+func (f *scriptFS) Close() {
+	if f == nil {
+		return
+	}
+	for _, r := range f.roots {
+		_ = r.Close()
+	}
+}
+
+// resolve maps a script path to a root and a path relative to it. Absolute
+// paths must lie under one of the roots; relative paths resolve against the
+// first root.
+func (f *scriptFS) resolve(path string) (*os.Root, string, error) {
+	if len(f.roots) == 0 {
+		return nil, "", fmt.Errorf("fs: no roots granted")
+	}
+	if !filepath.IsAbs(path) {
+		return f.roots[0], cleanRelative(path), nil
+	}
+	clean := filepath.Clean(path)
+	for _, r := range f.roots {
+		rel, err := filepath.Rel(r.Name(), clean)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return r, rel, nil
+	}
+	return nil, "", fmt.Errorf("fs: path %q is outside the granted roots", path)
+}
+
+func cleanRelative(path string) string {
+	if path == "" {
+		return "."
+	}
+	return filepath.Clean(path)
+}
+
+func (f *scriptFS) readFile(path string) (string, error) {
+	root, rel, err := f.resolve(path)
+	if err != nil {
+		return "", err
+	}
+	file, err := root.Open(rel)
+	if err != nil {
+		return "", fmt.Errorf("fs: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("fs: %v", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("fs: %q is a directory", path)
+	}
+	if info.Size() > f.maxFileBytes {
+		return "", fmt.Errorf("fs: %q is %d bytes, limit is %d", path, info.Size(), f.maxFileBytes)
+	}
+	b, err := io.ReadAll(io.LimitReader(file, f.maxFileBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("fs: %v", err)
+	}
+	if int64(len(b)) > f.maxFileBytes {
+		return "", fmt.Errorf("fs: %q exceeds %d bytes", path, f.maxFileBytes)
+	}
+	return string(b), nil
+}
+
+func (f *scriptFS) listDir(path string) ([]any, error) {
+	root, rel, err := f.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := root.Open(rel)
+	if err != nil {
+		return nil, fmt.Errorf("fs: %v", err)
+	}
+	defer dir.Close()
+
+	entries, err := dir.ReadDir(f.maxDirEntries + 1)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("fs: %v", err)
+	}
+	if len(entries) > f.maxDirEntries {
+		return nil, fmt.Errorf("fs: %q has more than %d entries", path, f.maxDirEntries)
+	}
+	out := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		item := map[string]any{
+			"name":  entry.Name(),
+			"isDir": entry.IsDir(),
+		}
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			item["size"] = info.Size()
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (f *scriptFS) install(vm *goja.Runtime) error {
+	obj := vm.NewObject()
+	if err := obj.Set("readFile", f.readFile); err != nil {
+		return fmt.Errorf("%w: set ctx.fs.readFile: %v", ErrScriptSetup, err)
+	}
+	if err := obj.Set("listDir", f.listDir); err != nil {
+		return fmt.Errorf("%w: set ctx.fs.listDir: %v", ErrScriptSetup, err)
+	}
+	ctxObj := vm.Get("__pc_ctx").ToObject(vm)
+	if err := ctxObj.Set("fs", obj); err != nil {
+		return fmt.Errorf("%w: set ctx.fs: %v", ErrScriptSetup, err)
+	}
+	return nil
+}
+
+func declaresCapability(describe map[string]any, capability string) bool {
+	caps, _ := describe["capabilities"].([]any)
+	for _, c := range caps {
+		if s, ok := c.(string); ok && s == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// grantFS installs ctx.fs during init when describe() declares the "fs"
+// capability and the operator configured roots. The grant is logged and
+// returned so it can be recorded on the request.
+func (e *Engine) grantFS(vm *goja.Runtime, describe map[string]any, collector *runLogCollector) (*scriptFS, *Grant, error) {
+	if !declaresCapability(describe, capabilityFS) {
+		return nil, nil, nil
+	}
+	if len(e.fs.roots) == 0 {
+		collector.addLine(fsDeniedNoRootsLogLine)
+		return nil, nil, nil
+	}
+	roots, err := e.fs.absRoots()
+	if err != nil {
+		return nil, nil, err
+	}
+	sfs, err := e.fs.open(roots)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sfs.install(vm); err != nil {
+		sfs.Close()
+		return nil, nil, err
+	}
+	collector.addLine(fsGrantedLogLinePrefix + strings.Join(roots, ","))
+	return sfs, &Grant{Capability: capabilityFS, Scope: roots}, nil
+}
+
+// restoreFS re-installs ctx.fs for update/view from a recorded grant.
+func (e *Engine) restoreFS(vm *goja.Runtime, grants []Grant) (*scriptFS, error) {
+	for _, g := range grants {
+		if g.Capability != capabilityFS {
+			continue
+		}
+		sfs, err := e.fs.open(g.Scope)
+		if err != nil {
+			return nil, err
+		}
+		if err := sfs.install(vm); err != nil {
+			sfs.Close()
+			return nil, err
+		}
+		return sfs, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,123 @@
+package scriptengine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const fsScript = `
+module.exports = {
+  describe: function () {
+    return { name: "review", version: "1.0.0", capabilities: ["fs"] };
+  },
+  init: function (ctx) {
+    var state = { hasFS: !!ctx.fs };
+    if (!ctx.fs) return state;
+    state.diff = ctx.fs.readFile("change.diff");
+    state.entries = ctx.fs.listDir(".").map(function (e) { return e.name; }).sort();
+    try { ctx.fs.readFile("../outside.txt"); state.escape = "allowed"; } catch (e) { state.escape = "denied"; }
+    try { ctx.fs.readFile("big.txt"); state.big = "allowed"; } catch (e) { state.big = "denied"; }
+    return state;
+  },
+  view: function (state) {
+    return { widgetType: "confirm", input: { title: "Approve?" } };
+  },
+  update: function (state, event, ctx) {
+    return { done: true, result: { diff: ctx.fs ? ctx.fs.readFile("change.diff") : null } };
+  }
+};
+`
+
+func TestFSCapabilityIsScopedToRoots(t *testing.T) {
+	t.Parallel()
+
+	parent := t.TempDir()
+	root := filepath.Join(parent, "repo")
+	if err := os.Mkdir(root, 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeTestFile(t, filepath.Join(root, "change.diff"), "+added line\n")
+	writeTestFile(t, filepath.Join(root, "big.txt"), strings.Repeat("x", 64))
+	writeTestFile(t, filepath.Join(parent, "outside.txt"), "secret")
+
+	e := New(WithFSRoots(root), WithFSLimits(32, 0))
+	out, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: fsScript})
+	if err != nil {
+		t.Fatalf("InitAndView returned error: %v", err)
+	}
+	if out.State["diff"] != "+added line\n" {
+		t.Fatalf("unexpected diff contents: %#v", out.State["diff"])
+	}
+	if out.State["escape"] != "denied" {
+		t.Fatalf("expected path escape to be denied, got %v", out.State["escape"])
+	}
+	if out.State["big"] != "denied" {
+		t.Fatalf("expected oversized read to be denied, got %v", out.State["big"])
+	}
+	entries, _ := out.State["entries"].([]any)
+	if len(entries) != 2 || entries[0] != "big.txt" || entries[1] != "change.diff" {
+		t.Fatalf("unexpected listDir entries: %#v", out.State["entries"])
+	}
+
+	if len(out.Grants) != 1 || out.Grants[0].Capability != "fs" || len(out.Grants[0].Scope) != 1 || out.Grants[0].Scope[0] != root {
+		t.Fatalf("unexpected grants: %#v", out.Grants)
+	}
+	if !containsLogPrefix(out.Logs, fsGrantedLogLinePrefix) {
+		t.Fatalf("expected grant to be logged, got %v", out.Logs)
+	}
+
+	// Updates only see fs when the recorded grant is passed back.
+	updated, err := e.UpdateAndView(context.Background(), &v1.ScriptInput{Script: fsScript}, out.State, map[string]any{"type": "submit"}, WithGrants(out.Grants...))
+	if err != nil {
+		t.Fatalf("UpdateAndView returned error: %v", err)
+	}
+	if updated.Result["diff"] != "+added line\n" {
+		t.Fatalf("expected fs access in update with grant, got %#v", updated.Result["diff"])
+	}
+	ungranted, err := e.UpdateAndView(context.Background(), &v1.ScriptInput{Script: fsScript}, out.State, map[string]any{"type": "submit"})
+	if err != nil {
+		t.Fatalf("UpdateAndView returned error: %v", err)
+	}
+	if ungranted.Result["diff"] != nil {
+		t.Fatalf("expected no fs access without grant, got %#v", ungranted.Result["diff"])
+	}
+}
+
+func TestFSCapabilityDeniedWithoutConfiguredRoots(t *testing.T) {
+	t.Parallel()
+
+	out, err := New().InitAndView(context.Background(), &v1.ScriptInput{Script: fsScript})
+	if err != nil {
+		t.Fatalf("InitAndView returned error: %v", err)
+	}
+	if out.State["hasFS"] != false {
+		t.Fatalf("expected ctx.fs to be absent, got state=%#v", out.State)
+	}
+	if len(out.Grants) != 0 {
+		t.Fatalf("expected no grants, got %#v", out.Grants)
+	}
+	if !containsLogPrefix(out.Logs, fsDeniedNoRootsLogLine) {
+		t.Fatalf("expected denial to be logged, got %v", out.Logs)
+	}
+}
+
+func writeTestFile(t *testing.T, path string, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func containsLogPrefix(lines []string, prefix string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
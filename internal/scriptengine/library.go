@@ -0,0 +1,211 @@
+package scriptengine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/dop251/goja"
+	"github.com/dop251/goja_nodejs/require"
+	ggjengine "github.com/go-go-golems/go-go-goja/engine"
+)
+
+const libraryModulePrefix = "plz/"
+
+var libraryModuleNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*(/[a-z0-9][a-z0-9_-]*)*$`)
+
+// ModuleRef identifies one version of a library module.
+type ModuleRef struct {
+	Name    string // require name without version, e.g. "plz/wizard"
+	Version string // empty for unversioned modules
+	SHA256  string
+}
+
+type libraryModule struct {
+	ref     ModuleRef
+	program *goja.Program
+}
+
+// Library is a set of named, versioned script modules that scripts load with
+// require("plz/<name>") (latest version) or require("plz/<name>@<version>").
+type Library struct {
+	// modules is keyed by require name, with and without a version pin.
+	modules map[string]*libraryModule
+	refs    []ModuleRef
+}
+
+// LoadLibrary reads every .js file under dir. A file "wizard@1.2.0.js" is the
+// module "plz/wizard" at version 1.2.0; "forms/select.js" is the unversioned
+// module "plz/forms/select". A name is either versioned or unversioned, not
+// both. All modules are compiled up front, so syntax errors surface here.
+func LoadLibrary(dir string) (*Library, error) {
+	lib := &Library{modules: map[string]*libraryModule{}}
+	byName := map[string][]*libraryModule{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".js" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name, version, err := parseLibraryFileName(rel)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(src)
+		program, err := goja.Compile(libraryModulePrefix+strings.TrimSuffix(filepath.ToSlash(rel), ".js"),
+			"(function (exports, require, module) {\n"+string(src)+"\n})", false)
+		if err != nil {
+			return fmt.Errorf("compile %s: %w", rel, err)
+		}
+		m := &libraryModule{
+			ref:     ModuleRef{Name: name, Version: version, SHA256: hex.EncodeToString(sum[:])},
+			program: program,
+		}
+		byName[name] = append(byName[name], m)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load script library %s: %w", dir, err)
+	}
+
+	for name, versions := range byName {
+		if len(versions) > 1 {
+			for _, m := range versions {
+				if m.ref.Version == "" {
+					return nil, fmt.Errorf("load script library %s: module %q has both versioned and unversioned files", dir, name)
+				}
+			}
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return compareModuleVersions(versions[i].ref.Version, versions[j].ref.Version) < 0
+		})
+		for _, m := range versions {
+			if m.ref.Version != "" {
+				lib.modules[name+"@"+m.ref.Version] = m
+			}
+			lib.refs = append(lib.refs, m.ref)
+		}
+		lib.modules[name] = versions[len(versions)-1]
+	}
+	sort.Slice(lib.refs, func(i, j int) bool {
+		if lib.refs[i].Name != lib.refs[j].Name {
+			return lib.refs[i].Name < lib.refs[j].Name
+		}
+		return compareModuleVersions(lib.refs[i].Version, lib.refs[j].Version) < 0
+	})
+	return lib, nil
+}
+
+func parseLibraryFileName(rel string) (string, string, error) {
+	base := strings.TrimSuffix(filepath.ToSlash(rel), ".js")
+	name, version, versioned := strings.Cut(base, "@")
+	if !libraryModuleNameRE.MatchString(name) {
+		return "", "", fmt.Errorf("invalid module file name %q", rel)
+	}
+	if versioned {
+		if _, err := semver.NewVersion(version); err != nil {
+			return "", "", fmt.Errorf("invalid module version in %q: %v", rel, err)
+		}
+	}
+	return libraryModulePrefix + name, version, nil
+}
+
+func compareModuleVersions(a, b string) int {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
+
+// Modules lists every module version in the library, sorted by name and
+// version.
+func (l *Library) Modules() []ModuleRef {
+	if l == nil {
+		return nil
+	}
+	return append([]ModuleRef(nil), l.refs...)
+}
+
+// moduleSpecs registers each require name as a native module. Loads are
+// reported to track so the engine can record them per run.
+func (l *Library) moduleSpecs(track func(*goja.Runtime, ModuleRef)) []ggjengine.ModuleSpec {
+	names := make([]string, 0, len(l.modules))
+	for name := range l.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	specs := make([]ggjengine.ModuleSpec, 0, len(names))
+	for _, name := range names {
+		m := l.modules[name]
+		specs = append(specs, ggjengine.NativeModuleSpec{
+			ModuleID:   "library:" + name,
+			ModuleName: name,
+			Loader:     m.loader(track),
+		})
+	}
+	return specs
+}
+
+func (m *libraryModule) loader(track func(*goja.Runtime, ModuleRef)) require.ModuleLoader {
+	return func(vm *goja.Runtime, module *goja.Object) {
+		fnVal, err := vm.RunProgram(m.program)
+		if err != nil {
+			panic(vm.NewGoError(fmt.Errorf("load %s: %w", m.ref.Name, err)))
+		}
+		fn, ok := goja.AssertFunction(fnVal)
+		if !ok {
+			panic(vm.NewTypeError("load %s: module wrapper is not a function", m.ref.Name))
+		}
+		exports := module.Get("exports")
+		if _, err := fn(exports, exports, vm.Get("require"), module); err != nil {
+			panic(vm.NewGoError(fmt.Errorf("load %s: %w", m.ref.Name, err)))
+		}
+		track(vm, m.ref)
+	}
+}
+
+// noSourceLoader keeps require() from reading script files off the server's
+// disk; only library modules resolve.
+func noSourceLoader(string) ([]byte, error) {
+	return nil, require.ModuleFileDoesNotExistError
+}
+
+// moduleTracker records the library modules loaded by one runtime.
+type moduleTracker struct {
+	refs []ModuleRef
+}
+
+func (t *moduleTracker) add(ref ModuleRef) {
+	for _, r := range t.refs {
+		if r == ref {
+			return
+		}
+	}
+	t.refs = append(t.refs, ref)
+}
+
+func (e *Engine) trackModuleLoad(vm *goja.Runtime, ref ModuleRef) {
+	if t, ok := e.moduleUses.Load(vm); ok {
+		t.(*moduleTracker).add(ref)
+	}
+}
@@ -0,0 +1,107 @@
+package scriptengine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const libraryScript = `
+var wizard = require("plz/wizard");
+var pinned = require("plz/wizard@1.0.0");
+var select = require("plz/forms/select");
+module.exports = {
+  describe: function () { return { name: "lib", version: "1.0.0" }; },
+  init: function () {
+    var local;
+    try { require("./secrets.js"); local = "loaded"; } catch (e) { local = "denied"; }
+    return { latest: wizard.version, pinned: pinned.version, title: select.title("env"), local: local };
+  },
+  view: function () { return { widgetType: "confirm", input: { title: "ok?" } }; },
+  update: function () { return { done: true, result: {} }; }
+};
+`
+
+func writeLibrary(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "forms"), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeTestFile(t, filepath.Join(dir, "wizard@1.0.0.js"), `exports.version = "1.0.0";`)
+	writeTestFile(t, filepath.Join(dir, "wizard@1.2.0.js"), `exports.version = "1.2.0";`)
+	writeTestFile(t, filepath.Join(dir, "forms", "select.js"), `module.exports = { title: function (s) { return "Pick " + s; } };`)
+	writeTestFile(t, filepath.Join(dir, "README.md"), "not a module")
+	return dir
+}
+
+func TestLibraryModulesResolveAndAreRecorded(t *testing.T) {
+	t.Parallel()
+
+	dir := writeLibrary(t)
+	writeTestFile(t, filepath.Join(dir, "secrets.js"), `module.exports = "secret";`)
+	lib, err := LoadLibrary(dir)
+	if err != nil {
+		t.Fatalf("LoadLibrary: %v", err)
+	}
+	if got := len(lib.Modules()); got != 4 {
+		t.Fatalf("expected 4 module versions, got %d: %#v", got, lib.Modules())
+	}
+
+	e := New(WithLibrary(lib))
+	out, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: libraryScript})
+	if err != nil {
+		t.Fatalf("InitAndView: %v", err)
+	}
+	if out.State["latest"] != "1.2.0" || out.State["pinned"] != "1.0.0" || out.State["title"] != "Pick env" {
+		t.Fatalf("unexpected module resolution: %#v", out.State)
+	}
+	if out.State["local"] != "denied" {
+		t.Fatalf("expected relative require to be denied, got %v", out.State["local"])
+	}
+
+	var names []string
+	for _, m := range out.Modules {
+		if len(m.SHA256) != 64 {
+			t.Fatalf("expected sha256 digest, got %#v", m)
+		}
+		names = append(names, m.Name+"@"+m.Version)
+	}
+	if strings.Join(names, ",") != "plz/wizard@1.2.0,plz/wizard@1.0.0,plz/forms/select@" {
+		t.Fatalf("unexpected recorded modules: %v", names)
+	}
+}
+
+func TestLoadLibraryRejectsInvalidLayouts(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]map[string]string{
+		"mixed versions": {"wizard.js": "", "wizard@1.0.0.js": ""},
+		"bad version":    {"wizard@latest.js": ""},
+		"bad name":       {"Wizard.js": ""},
+		"syntax error":   {"wizard.js": "exports.x = ;"},
+	}
+	for name, files := range cases {
+		dir := t.TempDir()
+		for file, src := range files {
+			writeTestFile(t, filepath.Join(dir, file), src)
+		}
+		if _, err := LoadLibrary(dir); err == nil {
+			t.Errorf("%s: expected LoadLibrary to fail", name)
+		}
+	}
+}
+
+func TestLibraryDirLoadErrorFailsScripts(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithLibraryDir(filepath.Join(t.TempDir(), "missing")))
+	_, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: libraryScript})
+	if err == nil || !strings.Contains(err.Error(), "load script library") {
+		t.Fatalf("expected library load error, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package scriptengine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/metrics"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	defaultMaxCallStackSize = 4096
+	defaultMaxOutputBytes   = 1 << 20
+	heapCheckInterval       = 10 * time.Millisecond
+	heapMetric              = "/memory/classes/heap/objects:bytes"
+)
+
+// limitsConfig bounds what a single script run may consume besides time.
+type limitsConfig struct {
+	// maxHeapGrowth is how far the process heap may grow during one run;
+	// 0 disables the check.
+	maxHeapGrowth uint64
+	// maxCallStackSize is the deepest JS call nesting allowed.
+	maxCallStackSize int
+	// maxOutputBytes bounds the JSON size of each describe, state, view, and
+	// result a script returns.
+	maxOutputBytes int
+}
+
+func defaultLimitsConfig() limitsConfig {
+	return limitsConfig{
+		maxCallStackSize: defaultMaxCallStackSize,
+		maxOutputBytes:   defaultMaxOutputBytes,
+	}
+}
+
+// heapInUse reports bytes occupied by heap objects across the process. Go
+// has no per-goroutine accounting, so runs measure growth of this value and
+// concurrent runs count against each other.
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// callError wraps an error thrown by a script function. Stack overflows are
+// uncatchable in goja and get their own class.
+func callError(call string, err error) error {
+	var overflow *goja.StackOverflowError
+	if errors.As(err, &overflow) {
+		return fmt.Errorf("%w: %s: maximum call stack size exceeded", ErrScriptStackLimit, call)
+	}
+	return fmt.Errorf("%w: %s failed: %v", ErrScriptRuntime, call, err)
+}
+
+// checkOutputSize rejects a value whose JSON encoding exceeds the configured
+// limit. Values that do not encode are left to later validation.
+func (e *Engine) checkOutputSize(name string, v map[string]any) error {
+	if e.limits.maxOutputBytes <= 0 || v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	if len(b) > e.limits.maxOutputBytes {
+		return fmt.Errorf("%w: %s is %d bytes (limit %d)", ErrScriptOutputLimit, name, len(b), e.limits.maxOutputBytes)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+func limitScript(initBody string) string {
+	return `
+module.exports = {
+  describe: function () { return { name: "limits", version: "1.0.0" }; },
+  init: function () { ` + initBody + ` },
+  view: function (state) { return { widgetType: "display", input: { content: state.text || "" } }; },
+  update: function (state) { return state; }
+};`
+}
+
+func TestCallStackLimit(t *testing.T) {
+	e := New(WithResourceLimits(0, 64, 0))
+	in := &v1.ScriptInput{Script: limitScript(`function deep(n) { return n === 0 ? 0 : 1 + deep(n - 1); } return { depth: deep(1000) };`)}
+
+	_, err := e.InitAndView(context.Background(), in)
+	if !errors.Is(err, ErrScriptStackLimit) {
+		t.Fatalf("expected stack limit error, got %v", err)
+	}
+	if errors.Is(err, ErrScriptRuntime) {
+		t.Fatalf("stack overflow should not be a plain runtime error: %v", err)
+	}
+
+	// try/catch cannot swallow it.
+	in = &v1.ScriptInput{Script: limitScript(`function deep() { return deep(); } try { deep(); } catch (e) {} return {};`)}
+	if _, err := e.InitAndView(context.Background(), in); !errors.Is(err, ErrScriptStackLimit) {
+		t.Fatalf("expected uncatchable stack limit error, got %v", err)
+	}
+}
+
+func TestOutputLimit(t *testing.T) {
+	e := New(WithResourceLimits(0, 0, 1024))
+
+	in := &v1.ScriptInput{Script: limitScript(`return { text: "ok" };`)}
+	if _, err := e.InitAndView(context.Background(), in); err != nil {
+		t.Fatalf("small output should pass: %v", err)
+	}
+
+	in = &v1.ScriptInput{Script: limitScript(`return { text: new Array(2000).join("x") };`)}
+	_, err := e.InitAndView(context.Background(), in)
+	if !errors.Is(err, ErrScriptOutputLimit) {
+		t.Fatalf("expected output limit error, got %v", err)
+	}
+}
+
+func TestHeapGrowthLimit(t *testing.T) {
+	e := New(WithResourceLimits(8<<20, 0, 0))
+	timeout := int64(10_000)
+	in := &v1.ScriptInput{
+		Script:    limitScript(`var keep = []; for (;;) { keep.push(new Array(1024).join("y") + keep.length); } `),
+		TimeoutMs: &timeout,
+	}
+
+	_, err := e.InitAndView(context.Background(), in)
+	if !errors.Is(err, ErrScriptMemoryLimit) {
+		t.Fatalf("expected memory limit error, got %v", err)
+	}
+	if errors.Is(err, ErrScriptTimeout) || errors.Is(err, ErrScriptCancelled) {
+		t.Fatalf("memory limit should not be reported as timeout/cancel: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package scriptengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// maxLintSteps bounds how many distinct steps WalkSteps renders.
+const maxLintSteps = 64
+
+// lintProbeEvents are sent to update() from every reached step to discover
+// the ctx.branch specs it uses. Both confirm outcomes are tried because
+// scripts often only branch on one of them.
+var lintProbeEvents = []map[string]any{
+	{"type": "submit", "source": "ui", "data": map[string]any{"approved": true}},
+	{"type": "submit", "source": "ui", "data": map[string]any{"approved": false}},
+}
+
+// StepView is the view a script renders for one state.step value.
+type StepView struct {
+	Step     string
+	View     map[string]any
+	Schedule *Schedule
+	// Err is set when view() failed or returned an invalid view.
+	Err error
+}
+
+// WalkResult lists the steps reached from a script's initial state.
+type WalkResult struct {
+	Steps []StepView
+	// Truncated reports that more than maxLintSteps steps were reachable.
+	Truncated bool
+}
+
+// WalkSteps renders view() for every step reachable from state through
+// ctx.branch. Targets are collected from the branch specs update() passes
+// when probed with synthetic submit events, whichever route the probe
+// actually takes. The initial step itself is not rendered; callers already
+// have its view from InitAndView.
+//
+// The walk is static in spirit: update() errors are ignored, the resulting
+// state is discarded, and fs/fetch capabilities are not granted. Of the run
+// options only WithLocale and WithMessages apply.
+func (e *Engine) WalkSteps(ctx context.Context, in *v1.ScriptInput, state map[string]any, opts ...RunOption) (*WalkResult, error) {
+	if in == nil {
+		return nil, fmt.Errorf("%w: script input is required", ErrScriptValidation)
+	}
+	if strings.TrimSpace(in.GetScript()) == "" {
+		return nil, fmt.Errorf("%w: script source is required", ErrScriptValidation)
+	}
+	cfg := newRunConfig(opts)
+	base, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("%w: state is not JSON-serializable: %v", ErrScriptValidation, err)
+	}
+
+	rt, err := e.newRuntime(ctx, newRunLogCollector())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rt.Close(ctx) }()
+
+	var out WalkResult
+	run := func(runCtx context.Context) error {
+		if err := e.loadScript(rt.VM, in); err != nil {
+			return err
+		}
+		scriptCtx := defaultScriptContext(in.GetProps(), time.Now())
+		setContextLocale(scriptCtx, cfg.messages, cfg.acceptLanguage)
+		if err := rt.VM.Set("__pc_ctx", scriptCtx); err != nil {
+			return fmt.Errorf("%w: set ctx failed: %v", ErrScriptSetup, err)
+		}
+		if err := attachContextHelpers(rt.VM); err != nil {
+			return err
+		}
+		if err := rt.VM.Set("__pc_lint_base", string(base)); err != nil {
+			return fmt.Errorf("%w: set state failed: %v", ErrScriptSetup, err)
+		}
+		if _, err := rt.VM.RunString(lintHelpers); err != nil {
+			return fmt.Errorf("%w: attach lint helpers failed: %v", ErrScriptSetup, err)
+		}
+
+		initial, _ := state["step"].(string)
+		visited := map[string]bool{initial: true}
+		queue := probeTargets(runCtx, rt.VM, initial)
+		for len(queue) > 0 {
+			step := queue[0]
+			queue = queue[1:]
+			if visited[step] {
+				continue
+			}
+			// Probe errors are swallowed, so stop here once the run has
+			// been interrupted; runWithTimeout reports why.
+			if runCtx.Err() != nil {
+				return nil
+			}
+			if len(out.Steps) == maxLintSteps {
+				out.Truncated = true
+				return nil
+			}
+			visited[step] = true
+			out.Steps = append(out.Steps, e.renderStep(rt.VM, step))
+			queue = append(queue, probeTargets(runCtx, rt.VM, step)...)
+		}
+		return nil
+	}
+
+	if err := runWithTimeout(ctx, rt.VM, timeoutFromInput(in), e.limits.maxHeapGrowth, run); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// probeTargets sends the probe events to update() from step and returns the
+// branch targets it named.
+func probeTargets(ctx context.Context, vm *goja.Runtime, step string) []string {
+	var targets []string
+	for _, ev := range lintProbeEvents {
+		if ctx.Err() != nil {
+			return targets
+		}
+		if _, err := vm.RunString(`__pc_lint_targets = []`); err != nil {
+			return targets
+		}
+		if err := vm.Set("__pc_event", ev); err != nil {
+			return targets
+		}
+		if err := vm.Set("__pc_lint_step", step); err != nil {
+			return targets
+		}
+		// Probe errors are expected: scripts read event data the probe
+		// does not carry.
+		_, _ = vm.RunString(`__pc_exports.update(__pc_lintState(__pc_lint_step), __pc_event, __pc_ctx)`)
+		var recorded []string
+		if err := vm.ExportTo(vm.Get("__pc_lint_targets"), &recorded); err == nil {
+			targets = append(targets, recorded...)
+		}
+	}
+	return targets
+}
+
+func (e *Engine) renderStep(vm *goja.Runtime, step string) StepView {
+	out := StepView{Step: step}
+	if err := vm.Set("__pc_lint_step", step); err != nil {
+		out.Err = fmt.Errorf("%w: set step failed: %v", ErrScriptSetup, err)
+		return out
+	}
+	viewVal, err := vm.RunString(`__pc_exports.view(__pc_lintState(__pc_lint_step), __pc_ctx)`)
+	if err != nil {
+		out.Err = callError("view()", err)
+		return out
+	}
+	viewMap, err := expectMap(viewVal.Export(), "view result")
+	if err != nil {
+		out.Err = err
+		return out
+	}
+	out.Schedule, out.Err = takeSchedule(viewMap)
+	if out.Err == nil {
+		out.Err = e.checkOutputSize("view result", viewMap)
+	}
+	out.View = viewMap
+	return out
+}
+
+// lintHelpers wraps ctx.branch to record every target named in a spec, and
+// builds fresh copies of the base state with state.step replaced.
+const lintHelpers = `
+var __pc_lint_targets = [];
+function __pc_lintState(step) {
+  var state = JSON.parse(__pc_lint_base);
+  if (state && typeof state === "object" && step) state.step = step;
+  return state;
+}
+function __pc_lintRecord(spec) {
+  if (!spec || typeof spec !== "object") return;
+  var add = function(target) {
+    if (typeof target === "string" && target.length > 0) __pc_lint_targets.push(target);
+  };
+  if (Array.isArray(spec.rules)) {
+    for (var i = 0; i < spec.rules.length; i++) {
+      var rule = spec.rules[i];
+      if (rule && typeof rule === "object") add(rule.step || rule.to);
+    }
+  }
+  var routes = spec.routes;
+  if (!routes || typeof routes !== "object" || Array.isArray(routes)) routes = spec;
+  for (var key in routes) {
+    if (Object.prototype.hasOwnProperty.call(routes, key)) add(routes[key]);
+  }
+}
+(function() {
+  var branch = __pc_ctx.branch;
+  __pc_ctx.branch = function(state, event, spec) {
+    __pc_lintRecord(spec);
+    return branch(state, event, spec);
+  };
+})();
+`
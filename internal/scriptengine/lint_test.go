@@ -0,0 +1,63 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const walkScript = `
+module.exports = {
+  describe: function () { return { name: "walk", version: "1.0.0" }; },
+  init: function () { return { step: "a" }; },
+  view: function (state) {
+    if (state.step === "c") throw new Error("no view for c");
+    return { widgetType: "confirm", stepId: state.step, input: { title: state.step } };
+  },
+  update: function (state, event, ctx) {
+    if (state.step === "a") {
+      // Only the rejected probe reaches this branch.
+      if (event.data.approved) return { done: true, result: {} };
+      return ctx.branch(state, event, { rejected: "b" });
+    }
+    if (state.step === "b") {
+      return ctx.branch(state, event, { rules: [{ when: false, to: "c" }], default: "a" });
+    }
+    throw new Error("unreachable");
+  }
+};`
+
+func TestWalkStepsFollowsBranchTargets(t *testing.T) {
+	e := New()
+	res, err := e.WalkSteps(context.Background(), &v1.ScriptInput{Script: walkScript}, map[string]any{"step": "a"})
+	if err != nil {
+		t.Fatalf("WalkSteps: %v", err)
+	}
+	if res.Truncated || len(res.Steps) != 2 {
+		t.Fatalf("expected steps b and c, got %+v", res)
+	}
+	if b := res.Steps[0]; b.Step != "b" || b.Err != nil || b.View["stepId"] != "b" {
+		t.Fatalf("unexpected step b: %+v", b)
+	}
+	if c := res.Steps[1]; c.Step != "c" || !errors.Is(c.Err, ErrScriptRuntime) {
+		t.Fatalf("expected view error for step c, got %+v", c)
+	}
+}
+
+func TestWalkStepsStopsOnTimeout(t *testing.T) {
+	e := New()
+	script := `
+module.exports = {
+  describe: function () { return { name: "spin", version: "1.0.0" }; },
+  init: function () { return { step: "a" }; },
+  view: function () { return { widgetType: "confirm", input: { title: "x" } }; },
+  update: function () { for (;;) {} }
+};`
+	timeout := int64(50)
+	_, err := e.WalkSteps(context.Background(), &v1.ScriptInput{Script: script, TimeoutMs: &timeout}, map[string]any{"step": "a"})
+	if !errors.Is(err, ErrScriptTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package scriptengine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultLocale is ctx.locale when a script declares no defaultLocale and
+// nothing better is known.
+const defaultLocale = "en"
+
+// Messages holds the message catalogs a script declares in describe():
+// Catalogs maps a locale tag such as "de" or "pt-BR" to its key/message
+// pairs, and DefaultLocale is used when none of the responder's languages
+// has a catalog.
+type Messages struct {
+	Catalogs      map[string]map[string]string
+	DefaultLocale string
+}
+
+// MessagesFromDescribe returns the messages and defaultLocale declared in a
+// describe() result. Entries that are not strings are skipped; InitAndView
+// rejects them up front.
+func MessagesFromDescribe(describe map[string]any) Messages {
+	var m Messages
+	m.DefaultLocale, _ = describe["defaultLocale"].(string)
+	raw, _ := describe["messages"].(map[string]any)
+	for locale, v := range raw {
+		entries, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		catalog := make(map[string]string, len(entries))
+		for key, msg := range entries {
+			if s, ok := msg.(string); ok {
+				catalog[key] = s
+			}
+		}
+		if m.Catalogs == nil {
+			m.Catalogs = map[string]map[string]string{}
+		}
+		m.Catalogs[locale] = catalog
+	}
+	return m
+}
+
+// checkMessages reports whether describe's messages and defaultLocale have
+// the expected shape.
+func checkMessages(describe map[string]any) error {
+	if v, ok := describe["defaultLocale"]; ok && v != nil {
+		if s, ok := v.(string); !ok || strings.TrimSpace(s) == "" {
+			return fmt.Errorf("describe.defaultLocale must be a non-empty string")
+		}
+	}
+	v, ok := describe["messages"]
+	if !ok || v == nil {
+		return nil
+	}
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("describe.messages must be an object of locale catalogs")
+	}
+	for locale, entries := range raw {
+		if !validLocaleTag(locale) {
+			return fmt.Errorf("describe.messages has invalid locale %q", locale)
+		}
+		catalog, ok := entries.(map[string]any)
+		if !ok {
+			return fmt.Errorf("describe.messages.%s must be an object", locale)
+		}
+		for key, msg := range catalog {
+			if _, ok := msg.(string); !ok {
+				return fmt.Errorf("describe.messages.%s.%s must be a string", locale, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Negotiate picks ctx.locale for an Accept-Language value: the first
+// preferred language with a catalog (matching "de-AT" to "de" and the
+// reverse), else DefaultLocale. Without catalogs the first preferred
+// language is used as is.
+func (m Messages) Negotiate(acceptLanguage string) string {
+	prefs := parseAcceptLanguage(acceptLanguage)
+	if len(m.Catalogs) == 0 && len(prefs) > 0 {
+		return prefs[0]
+	}
+	for _, pref := range prefs {
+		if locale, ok := m.match(pref); ok {
+			return locale
+		}
+	}
+	return m.fallback()
+}
+
+func (m Messages) fallback() string {
+	if m.DefaultLocale != "" {
+		return m.DefaultLocale
+	}
+	return defaultLocale
+}
+
+// match finds the catalog for tag: an exact match first, then one sharing
+// its base language.
+func (m Messages) match(tag string) (string, bool) {
+	locales := make([]string, 0, len(m.Catalogs))
+	for locale := range m.Catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+	base := baseLanguage(tag)
+	for _, locale := range locales {
+		if strings.EqualFold(locale, base) {
+			return locale, true
+		}
+	}
+	for _, locale := range locales {
+		if strings.EqualFold(baseLanguage(locale), base) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Warnings lists keys that some catalogs define and others lack, and a
+// DefaultLocale without a catalog. Either makes ctx.t fall back for some
+// responders.
+func (m Messages) Warnings() []string {
+	if len(m.Catalogs) == 0 {
+		return nil
+	}
+	var out []string
+	if m.DefaultLocale != "" {
+		if _, ok := m.Catalogs[m.DefaultLocale]; !ok {
+			out = append(out, fmt.Sprintf("describe.defaultLocale %q has no catalog in describe.messages", m.DefaultLocale))
+		}
+	}
+	keys := map[string]bool{}
+	for _, catalog := range m.Catalogs {
+		for key := range catalog {
+			keys[key] = true
+		}
+	}
+	locales := make([]string, 0, len(m.Catalogs))
+	for locale := range m.Catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		var missing []string
+		for key := range keys {
+			if _, ok := m.Catalogs[locale][key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			out = append(out, fmt.Sprintf("describe.messages.%s is missing %s", locale, strings.Join(missing, ", ")))
+		}
+	}
+	return out
+}
+
+// translate looks key up in the catalog for locale, then its base language,
+// then DefaultLocale, and fills {name} placeholders from params. An unknown
+// key is returned as is.
+func (m Messages) translate(locale, key string, params map[string]any) string {
+	msg := key
+	for _, tag := range []string{locale, baseLanguage(locale), m.fallback()} {
+		catalog, ok := m.match(tag)
+		if !ok {
+			continue
+		}
+		if s, ok := m.Catalogs[catalog][key]; ok {
+			msg = s
+			break
+		}
+	}
+	return interpolate(msg, params)
+}
+
+// interpolate replaces {name} with params[name]. Placeholders without a
+// matching param are left in place.
+func interpolate(msg string, params map[string]any) string {
+	if len(params) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(msg, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(msg[open:], '}')
+		if end < 0 {
+			break
+		}
+		name := msg[open+1 : open+end]
+		v, ok := params[name]
+		if !ok {
+			b.WriteString(msg[:open+end+1])
+			msg = msg[open+end+1:]
+			continue
+		}
+		b.WriteString(msg[:open])
+		b.WriteString(formatParam(v))
+		msg = msg[open+end+1:]
+	}
+	b.WriteString(msg)
+	return b.String()
+}
+
+func formatParam(v any) string {
+	switch n := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return n
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) < 1e15 {
+			return strconv.FormatInt(int64(n), 10)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseAcceptLanguage returns the language tags in an Accept-Language value
+// ordered by preference. Wildcards, q=0 entries, and malformed tags are
+// dropped.
+func parseAcceptLanguage(header string) []string {
+	type pref struct {
+		tag string
+		q   float64
+	}
+	var prefs []pref
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "*" || !validLocaleTag(tag) {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || name != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		prefs = append(prefs, pref{tag: tag, q: q})
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+	out := make([]string, len(prefs))
+	for i, p := range prefs {
+		out[i] = p.tag
+	}
+	return out
+}
+
+func validLocaleTag(tag string) bool {
+	if tag == "" || len(tag) > 35 || tag[0] == '-' || tag[len(tag)-1] == '-' {
+		return false
+	}
+	for _, r := range tag {
+		if !(r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func baseLanguage(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	return base
+}
+
+// setContextLocale sets ctx.locale and ctx.t for a run whose responder
+// prefers acceptLanguage, and returns the chosen locale.
+func setContextLocale(scriptCtx map[string]any, m Messages, acceptLanguage string) string {
+	locale := m.Negotiate(acceptLanguage)
+	scriptCtx["locale"] = locale
+	scriptCtx["t"] = func(key string, params map[string]any) string {
+		return m.translate(locale, key, params)
+	}
+	return locale
+}
@@ -0,0 +1,127 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+var deployMessages = Messages{
+	Catalogs: map[string]map[string]string{
+		"en":    {"title": "Deploy {env}?", "ok": "Deploy"},
+		"de":    {"title": "{env} ausrollen?", "ok": "Ausrollen"},
+		"pt-BR": {"title": "Implantar {env}?"},
+	},
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":                          "en",
+		"de":                        "de",
+		"de-AT,de;q=0.9,en;q=0.8":   "de",
+		"fr-FR,fr;q=0.9":            "en",
+		"fr;q=0.9,pt-BR;q=0.95":     "pt-BR",
+		"pt":                        "pt-BR",
+		"en;q=0,de;q=0.1":           "de",
+		"*, bad tag!, DE":           "de",
+		"es, en-GB;q=0.7, de;q=0.6": "en",
+	}
+	for header, want := range cases {
+		if got := deployMessages.Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+
+	withDefault := deployMessages
+	withDefault.DefaultLocale = "de"
+	if got := withDefault.Negotiate("fr"); got != "de" {
+		t.Errorf("expected defaultLocale fallback, got %q", got)
+	}
+	if got := (Messages{}).Negotiate("fr-CA,fr;q=0.8"); got != "fr-CA" {
+		t.Errorf("expected first preference without catalogs, got %q", got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]any{"env": "prod"}
+	cases := []struct {
+		locale, key, want string
+	}{
+		{"de", "title", "prod ausrollen?"},
+		{"de-AT", "title", "prod ausrollen?"},
+		{"pt-BR", "ok", "Deploy"},
+		{"fr", "title", "Deploy prod?"},
+		{"de", "missing", "missing"},
+	}
+	for _, tc := range cases {
+		if got := deployMessages.translate(tc.locale, tc.key, params); got != tc.want {
+			t.Errorf("translate(%q, %q) = %q, want %q", tc.locale, tc.key, got, tc.want)
+		}
+	}
+	if got := interpolate("{n} of {total} {unknown}", map[string]any{"n": float64(2), "total": int64(3)}); got != "2 of 3 {unknown}" {
+		t.Errorf("unexpected interpolation %q", got)
+	}
+	if got := deployMessages.Warnings(); len(got) != 1 || got[0] != "describe.messages.pt-BR is missing ok" {
+		t.Errorf("unexpected warnings %v", got)
+	}
+}
+
+func TestContextLocaleAndTranslation(t *testing.T) {
+	t.Parallel()
+
+	script := `
+module.exports = {
+  describe: function () {
+    return {
+      name: "i18n", version: "1.0.0",
+      messages: { en: { title: "Deploy {env}?" }, de: { title: "{env} ausrollen?" } }
+    };
+  },
+  init: function (ctx) { return { env: "prod", startedIn: ctx.locale }; },
+  view: function (state, ctx) {
+    return { widgetType: "confirm", input: { title: ctx.t("title", { env: state.env }) } };
+  },
+  update: function (state, event, ctx) { return { done: true, result: { locale: ctx.locale } }; }
+};
+`
+	e := New()
+	in := &v1.ScriptInput{Script: script}
+	initRes, err := e.InitAndView(context.Background(), in, WithLocale("de-AT,de;q=0.9"))
+	if err != nil {
+		t.Fatalf("InitAndView failed: %v", err)
+	}
+	if initRes.Locale != "de" || initRes.State["startedIn"] != "de" {
+		t.Fatalf("expected de locale, got result=%q state=%v", initRes.Locale, initRes.State["startedIn"])
+	}
+	if title := initRes.View["input"].(map[string]any)["title"]; title != "prod ausrollen?" {
+		t.Fatalf("expected German title, got %v", title)
+	}
+
+	messages := MessagesFromDescribe(initRes.Describe)
+	vr, err := e.View(context.Background(), in, initRes.State, WithLocale("en-US"), WithMessages(messages))
+	if err != nil {
+		t.Fatalf("View failed: %v", err)
+	}
+	if title := vr.View["input"].(map[string]any)["title"]; title != "Deploy prod?" || vr.Locale != "en" {
+		t.Fatalf("expected English re-render, got %v (%s)", title, vr.Locale)
+	}
+
+	upd, err := e.UpdateAndView(context.Background(), in, initRes.State, map[string]any{"type": "submit"},
+		WithLocale("de"), WithMessages(messages))
+	if err != nil || upd.Result["locale"] != "de" {
+		t.Fatalf("expected update to see de, got %v err=%v", upd, err)
+	}
+
+	bad := strings.Replace(script, `de: { title: "{env} ausrollen?" }`, `de: { title: 1 }`, 1)
+	_, err = e.InitAndView(context.Background(), &v1.ScriptInput{Script: bad})
+	if !errors.Is(err, ErrScriptValidation) || !strings.Contains(err.Error(), "describe.messages.de.title must be a string") {
+		t.Fatalf("expected validation error for bad catalog, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package scriptengine
+
+import "time"
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithFSRoots enables the opt-in "fs" capability. Scripts that declare "fs"
+// in describe().capabilities get read-only ctx.fs access confined to roots.
+func WithFSRoots(roots ...string) Option {
+	return func(e *Engine) {
+		e.fs.roots = append(e.fs.roots, roots...)
+	}
+}
+
+// WithFSLimits bounds ctx.fs.readFile sizes and ctx.fs.listDir entry counts.
+// Non-positive values keep the defaults.
+func WithFSLimits(maxFileBytes int64, maxDirEntries int) Option {
+	return func(e *Engine) {
+		if maxFileBytes > 0 {
+			e.fs.maxFileBytes = maxFileBytes
+		}
+		if maxDirEntries > 0 {
+			e.fs.maxDirEntries = maxDirEntries
+		}
+	}
+}
+
+// WithFetchAllowlist enables the opt-in "fetch" capability. Scripts that
+// declare "fetch" in describe().capabilities get ctx.fetch for these hosts.
+// A host is an exact name or "*.example.com" for any subdomain.
+func WithFetchAllowlist(hosts ...string) Option {
+	return func(e *Engine) {
+		e.fetch.allowedHosts = append(e.fetch.allowedHosts, hosts...)
+	}
+}
+
+// WithFetchLimits bounds how many ctx.fetch calls a request may make and how
+// many response bytes it may read in total. Non-positive values keep the
+// defaults.
+func WithFetchLimits(maxCalls int, maxBytes int64) Option {
+	return func(e *Engine) {
+		if maxCalls > 0 {
+			e.fetch.maxCalls = maxCalls
+		}
+		if maxBytes > 0 {
+			e.fetch.maxBytes = maxBytes
+		}
+	}
+}
+
+// WithResourceLimits bounds a single script run. maxHeapGrowth is how many
+// bytes the process heap may grow while the run is in progress (sampled every
+// 10ms; 0 keeps the check off). maxCallStackSize caps JS call nesting, and
+// maxOutputBytes caps the JSON size of each describe, state, view, and result
+// the script returns. Non-positive values keep the defaults.
+func WithResourceLimits(maxHeapGrowth int64, maxCallStackSize int, maxOutputBytes int) Option {
+	return func(e *Engine) {
+		if maxHeapGrowth > 0 {
+			e.limits.maxHeapGrowth = uint64(maxHeapGrowth)
+		}
+		if maxCallStackSize > 0 {
+			e.limits.maxCallStackSize = maxCallStackSize
+		}
+		if maxOutputBytes > 0 {
+			e.limits.maxOutputBytes = maxOutputBytes
+		}
+	}
+}
+
+// WithWarmRuntimes keeps up to maxRuntimes runtimes alive between events for
+// requests run with WithRuntimeKey, evicting those idle for longer than
+// idleTTL. A warm runtime keeps the script's live state (functions, Dates)
+// and skips re-parsing the script; requests without one fall back to
+// rebuilding from the stored state. maxRuntimes <= 0 disables the pool.
+func WithWarmRuntimes(maxRuntimes int, idleTTL time.Duration) Option {
+	return func(e *Engine) {
+		e.pool.maxRuntimes = maxRuntimes
+		if idleTTL > 0 {
+			e.pool.idleTTL = idleTTL
+		}
+	}
+}
+
+// WithProgramCacheSize bounds how many compiled scripts are kept, least
+// recently used first out. size <= 0 disables the cache.
+func WithProgramCacheSize(size int) Option {
+	return func(e *Engine) {
+		e.programs.capacity = size
+	}
+}
+
+// WithLibrary makes the library's modules available to scripts via require.
+func WithLibrary(lib *Library) Option {
+	return func(e *Engine) {
+		e.library = lib
+	}
+}
+
+// WithLibraryDir loads a library from dir (see LoadLibrary). A load error
+// makes every script run fail with ErrScriptSetup; load the library up front
+// with LoadLibrary to fail fast instead.
+func WithLibraryDir(dir string) Option {
+	return func(e *Engine) {
+		lib, err := LoadLibrary(dir)
+		if err != nil {
+			e.factoryErr = err
+			return
+		}
+		e.library = lib
+	}
+}
+
+// Grant records a capability the engine granted to a script, with the scope
+// it was granted for (roots for fs, hosts for fetch) and, for fetch, the
+// budget used so far.
+type Grant struct {
+	Capability string
+	Scope      []string
+	CallsUsed  int
+	BytesUsed  int64
+}
+
+// RunOption configures a single InitAndView, UpdateAndView, or View call.
+type RunOption func(*runConfig)
+
+type runConfig struct {
+	grants         []Grant
+	runtimeKey     string
+	now            time.Time
+	outputSchema   map[string]any
+	acceptLanguage string
+	messages       Messages
+}
+
+// WithGrants re-applies the grants recorded when the request was created, so
+// update() and view() see the same capabilities as init().
+func WithGrants(grants ...Grant) RunOption {
+	return func(c *runConfig) {
+		c.grants = append(c.grants, grants...)
+	}
+}
+
+// WithRuntimeKey identifies the request an event belongs to so a warm runtime
+// can be reused. When one is found, its live state is used and the state
+// argument is ignored; callers must Release the key whenever the stored state
+// changes outside UpdateAndView or the request ends. InitAndView keeps its
+// runtime under the key too, so the first event is warm.
+func WithRuntimeKey(key string) RunOption {
+	return func(c *runConfig) {
+		c.runtimeKey = key
+	}
+}
+
+// WithNow sets ctx.now for the run, so a recorded run can be repeated with
+// the clock it saw. Without it ctx.now is the wall clock when the run starts.
+// Date keeps reading the wall clock either way.
+func WithNow(now time.Time) RunOption {
+	return func(c *runConfig) {
+		c.now = now
+	}
+}
+
+// WithOutputSchema checks the result of a finishing update() against schema,
+// the outputSchema declared by describe(). A mismatch fails the run with
+// ErrScriptRuntime.
+func WithOutputSchema(schema map[string]any) RunOption {
+	return func(c *runConfig) {
+		c.outputSchema = schema
+	}
+}
+
+// WithLocale sets the responder's preferred languages, as an Accept-Language
+// value, from which ctx.locale is negotiated. A locale chosen earlier, such as
+// "de", works too.
+func WithLocale(acceptLanguage string) RunOption {
+	return func(c *runConfig) {
+		c.acceptLanguage = acceptLanguage
+	}
+}
+
+// WithMessages supplies the catalogs describe() declared, which back ctx.t in
+// UpdateAndView and View. InitAndView reads them from describe() itself.
+func WithMessages(m Messages) RunOption {
+	return func(c *runConfig) {
+		c.messages = m
+	}
+}
+
+// clock returns the time to expose as ctx.now.
+func (c *runConfig) clock() time.Time {
+	if c.now.IsZero() {
+		return time.Now()
+	}
+	return c.now
+}
+
+func newRunConfig(opts []RunOption) *runConfig {
+	c := &runConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
@@ -0,0 +1,167 @@
+package scriptengine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ggjengine "github.com/go-go-golems/go-go-goja/engine"
+)
+
+const defaultWarmIdleTTL = 5 * time.Minute
+
+// warmRuntime is a runtime that already evaluated a request's script and
+// holds its live state as __pc_state.
+type warmRuntime struct {
+	rt       *ggjengine.Runtime
+	script   string
+	lastUsed time.Time
+}
+
+// runtimePool keeps warm runtimes keyed by request. A runtime is removed
+// from the pool while in use, so concurrent events for the same key never
+// share a VM; the loser simply runs cold.
+type runtimePool struct {
+	mu          sync.Mutex
+	maxRuntimes int
+	idleTTL     time.Duration
+	entries     map[string]*warmRuntime
+	now         func() time.Time
+}
+
+func newRuntimePool() *runtimePool {
+	return &runtimePool{
+		idleTTL: defaultWarmIdleTTL,
+		entries: map[string]*warmRuntime{},
+		now:     time.Now,
+	}
+}
+
+func (p *runtimePool) enabled() bool {
+	return p != nil && p.maxRuntimes > 0
+}
+
+// checkout takes the warm runtime for key out of the pool. A runtime that was
+// built from a different script is closed instead of being returned.
+func (p *runtimePool) checkout(key string, script string) *warmRuntime {
+	if !p.enabled() || key == "" {
+		return nil
+	}
+	p.mu.Lock()
+	stale := p.sweepLocked()
+	w := p.entries[key]
+	delete(p.entries, key)
+	p.mu.Unlock()
+
+	if w != nil && w.script != script {
+		stale = append(stale, w)
+		w = nil
+	}
+	closeWarmRuntimes(stale)
+	return w
+}
+
+// checkin returns a runtime to the pool, evicting the least recently used
+// runtimes when the pool is over capacity.
+func (p *runtimePool) checkin(key string, w *warmRuntime) {
+	w.lastUsed = p.now()
+	p.mu.Lock()
+	stale := p.sweepLocked()
+	if prev := p.entries[key]; prev != nil {
+		stale = append(stale, prev)
+	}
+	p.entries[key] = w
+	for len(p.entries) > p.maxRuntimes {
+		oldestKey := ""
+		for k, e := range p.entries {
+			if oldestKey == "" || e.lastUsed.Before(p.entries[oldestKey].lastUsed) {
+				oldestKey = k
+			}
+		}
+		stale = append(stale, p.entries[oldestKey])
+		delete(p.entries, oldestKey)
+	}
+	p.mu.Unlock()
+	closeWarmRuntimes(stale)
+}
+
+func (p *runtimePool) rekey(from, to string) {
+	if !p.enabled() {
+		return
+	}
+	p.mu.Lock()
+	w := p.entries[from]
+	delete(p.entries, from)
+	var stale []*warmRuntime
+	if w != nil {
+		if prev := p.entries[to]; prev != nil {
+			stale = append(stale, prev)
+		}
+		p.entries[to] = w
+	}
+	p.mu.Unlock()
+	closeWarmRuntimes(stale)
+}
+
+func (p *runtimePool) release(key string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	w := p.entries[key]
+	delete(p.entries, key)
+	p.mu.Unlock()
+	if w != nil {
+		closeWarmRuntimes([]*warmRuntime{w})
+	}
+}
+
+func (p *runtimePool) evictIdle() {
+	if !p.enabled() {
+		return
+	}
+	p.mu.Lock()
+	stale := p.sweepLocked()
+	p.mu.Unlock()
+	closeWarmRuntimes(stale)
+}
+
+func (p *runtimePool) closeAll() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	stale := make([]*warmRuntime, 0, len(p.entries))
+	for k, w := range p.entries {
+		stale = append(stale, w)
+		delete(p.entries, k)
+	}
+	p.mu.Unlock()
+	closeWarmRuntimes(stale)
+}
+
+func (p *runtimePool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// sweepLocked drops runtimes idle for longer than idleTTL and returns them
+// for closing outside the lock.
+func (p *runtimePool) sweepLocked() []*warmRuntime {
+	var stale []*warmRuntime
+	cutoff := p.now().Add(-p.idleTTL)
+	for k, w := range p.entries {
+		if w.lastUsed.Before(cutoff) {
+			stale = append(stale, w)
+			delete(p.entries, k)
+		}
+	}
+	return stale
+}
+
+func closeWarmRuntimes(ws []*warmRuntime) {
+	for _, w := range ws {
+		_ = w.rt.Close(context.Background())
+	}
+}
@@ -0,0 +1,160 @@
+package scriptengine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const warmScript = `
+var loads = 0;
+loads++;
+module.exports = {
+  describe: function () { return { name: "warm", version: "1.0.0" }; },
+  init: function () { return { n: 0 }; },
+  view: function (state) { return { widgetType: "confirm", input: { title: "n=" + state.n } }; },
+  update: function (state, event) {
+    var next = { n: state.n + 1, loads: loads };
+    next.at = state.at || new Date(0);
+    next.fn = state.fn || function () { return "live"; };
+    next.live = typeof state.fn === "function" ? state.fn() : "none";
+    next.isDate = state.at instanceof Date;
+    return next;
+  }
+};
+`
+
+func TestWarmRuntimeKeepsLiveStateBetweenEvents(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithWarmRuntimes(4, time.Minute))
+	defer e.Close()
+	in := &v1.ScriptInput{Script: warmScript}
+	ev := map[string]any{"type": "tick"}
+
+	first, err := e.UpdateAndView(context.Background(), in, map[string]any{"n": int64(0)}, ev, WithRuntimeKey("req-1"))
+	if err != nil {
+		t.Fatalf("first UpdateAndView: %v", err)
+	}
+	// The stored snapshot is plain JSON: functions dropped, Dates as strings.
+	if _, ok := first.State["fn"]; ok {
+		t.Fatalf("expected function to be dropped from snapshot, got %#v", first.State)
+	}
+	if first.State["at"] != "1970-01-01T00:00:00.000Z" {
+		t.Fatalf("expected Date to be serialized in snapshot, got %#v", first.State["at"])
+	}
+
+	// The state argument is ignored when a warm runtime exists.
+	second, err := e.UpdateAndView(context.Background(), in, map[string]any{}, ev, WithRuntimeKey("req-1"))
+	if err != nil {
+		t.Fatalf("second UpdateAndView: %v", err)
+	}
+	if second.State["n"] != int64(2) || second.State["loads"] != int64(1) {
+		t.Fatalf("expected warm runtime to be reused, got %#v", second.State)
+	}
+	if second.State["live"] != "live" || second.State["isDate"] != true {
+		t.Fatalf("expected live function and Date in state, got %#v", second.State)
+	}
+
+	// After Release the stored snapshot is the fallback again.
+	e.Release("req-1")
+	third, err := e.UpdateAndView(context.Background(), in, second.State, ev, WithRuntimeKey("req-1"))
+	if err != nil {
+		t.Fatalf("third UpdateAndView: %v", err)
+	}
+	if third.State["n"] != int64(3) || third.State["live"] != "none" || third.State["isDate"] != false {
+		t.Fatalf("expected cold run from snapshot, got %#v", third.State)
+	}
+}
+
+func TestInitRuntimeIsKeptWarmAndRekeyed(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithWarmRuntimes(4, time.Minute))
+	defer e.Close()
+	in := &v1.ScriptInput{Script: warmScript}
+
+	if _, err := e.InitAndView(context.Background(), in, WithRuntimeKey("init-1")); err != nil {
+		t.Fatalf("InitAndView: %v", err)
+	}
+	e.Rekey("init-1", "req-1")
+	if e.pool.entries["init-1"] != nil || e.pool.entries["req-1"] == nil {
+		t.Fatalf("expected the init runtime under req-1, got %v", e.pool.entries)
+	}
+
+	out, err := e.UpdateAndView(context.Background(), in, map[string]any{}, map[string]any{"type": "tick"}, WithRuntimeKey("req-1"))
+	if err != nil {
+		t.Fatalf("UpdateAndView: %v", err)
+	}
+	if out.State["n"] != int64(1) || out.State["loads"] != int64(1) {
+		t.Fatalf("expected the first event to run in the init runtime, got %#v", out.State)
+	}
+}
+
+func TestWarmRuntimeIsDiscardedWhenScriptChanges(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithWarmRuntimes(4, time.Minute))
+	defer e.Close()
+	ev := map[string]any{"type": "tick"}
+	state := map[string]any{"n": int64(0)}
+
+	if _, err := e.UpdateAndView(context.Background(), &v1.ScriptInput{Script: warmScript}, state, ev, WithRuntimeKey("req-1")); err != nil {
+		t.Fatalf("UpdateAndView: %v", err)
+	}
+	changed := &v1.ScriptInput{Script: warmScript + "\n// v2\n"}
+	out, err := e.UpdateAndView(context.Background(), changed, state, ev, WithRuntimeKey("req-1"))
+	if err != nil {
+		t.Fatalf("UpdateAndView: %v", err)
+	}
+	if out.State["n"] != int64(1) {
+		t.Fatalf("expected cold run for changed script, got %#v", out.State)
+	}
+}
+
+func TestWarmRuntimePoolIsBounded(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithWarmRuntimes(2, time.Minute))
+	defer e.Close()
+	now := time.Unix(1000, 0)
+	e.pool.now = func() time.Time { return now }
+	in := &v1.ScriptInput{Script: warmScript}
+
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := e.UpdateAndView(context.Background(), in, map[string]any{"n": int64(0)}, nil, WithRuntimeKey(key)); err != nil {
+			t.Fatalf("UpdateAndView %s: %v", key, err)
+		}
+		now = now.Add(time.Second)
+	}
+	if got := e.pool.size(); got != 2 {
+		t.Fatalf("expected pool size 2, got %d", got)
+	}
+	if e.pool.entries["a"] != nil {
+		t.Fatalf("expected least recently used runtime to be evicted")
+	}
+
+	now = now.Add(2 * time.Minute)
+	e.EvictIdle()
+	if got := e.pool.size(); got != 0 {
+		t.Fatalf("expected idle runtimes to be evicted, got %d", got)
+	}
+}
+
+func TestWarmRuntimesDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	e := New()
+	in := &v1.ScriptInput{Script: warmScript}
+	for i := 0; i < 2; i++ {
+		out, err := e.UpdateAndView(context.Background(), in, map[string]any{"n": int64(0)}, nil, WithRuntimeKey("req-1"))
+		if err != nil {
+			t.Fatalf("UpdateAndView: %v", err)
+		}
+		if out.State["n"] != int64(1) {
+			t.Fatalf("expected every run to start from the given state, got %#v", out.State)
+		}
+	}
+}
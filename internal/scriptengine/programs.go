@@ -0,0 +1,155 @@
+package scriptengine
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dop251/goja"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const (
+	defaultProgramCacheSize = 64
+	scriptProgramName       = "script.js"
+)
+
+// ProgramCacheStats reports compiled-program cache activity.
+type ProgramCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Capacity  int
+}
+
+type cachedProgram struct {
+	key     string
+	program *goja.Program
+}
+
+// programCache is an LRU of compiled script programs keyed by the SHA-256 of
+// the script source. goja programs are immutable and can run in many
+// runtimes at once, so cached entries are shared across requests.
+type programCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	stats    ProgramCacheStats
+}
+
+func newProgramCache(capacity int) *programCache {
+	return &programCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func scriptKey(script, language string) string {
+	sum := sha256.Sum256([]byte(script))
+	if language == LanguageTypeScript {
+		return "ts:" + hex.EncodeToString(sum[:])
+	}
+	return hex.EncodeToString(sum[:])
+}
+
+// compileScript compiles script, transpiling it first when language is
+// TypeScript. language must already be normalized.
+func compileScript(script, language string) (*goja.Program, error) {
+	if language == LanguageTypeScript {
+		js, err := transpileTypeScript(script)
+		if err != nil {
+			return nil, err
+		}
+		script = js
+	}
+	program, err := goja.Compile(scriptProgramName, buildExportsProgram(script), false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: script load failed: %v", ErrScriptValidation, err)
+	}
+	return program, nil
+}
+
+// get returns the compiled program for script, compiling it on a miss.
+// Scripts that fail to compile are not cached.
+func (c *programCache) get(script, language string) (*goja.Program, error) {
+	language, err := NormalizeLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	if c.capacity <= 0 {
+		return compileScript(script, language)
+	}
+	key := scriptKey(script, language)
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.stats.Hits++
+		program := el.Value.(*cachedProgram).program
+		c.mu.Unlock()
+		return program, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	program, err := compileScript(script, language)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		// Another caller compiled the same script concurrently.
+		c.order.MoveToFront(el)
+		return el.Value.(*cachedProgram).program, nil
+	}
+	c.entries[key] = c.order.PushFront(&cachedProgram{key: key, program: program})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedProgram).key)
+		c.stats.Evictions++
+	}
+	return program, nil
+}
+
+func (c *programCache) snapshot() ProgramCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := c.stats
+	out.Entries = c.order.Len()
+	out.Capacity = max(c.capacity, 0)
+	return out
+}
+
+// loadScript evaluates the module wrapper of in's script in vm from the
+// cached program.
+func (e *Engine) loadScript(vm *goja.Runtime, in *v1.ScriptInput) error {
+	program, err := e.programs.get(in.GetScript(), in.GetLanguage())
+	if err != nil {
+		return err
+	}
+	if _, err := vm.RunProgram(program); err != nil {
+		var overflow *goja.StackOverflowError
+		if errors.As(err, &overflow) {
+			return fmt.Errorf("%w: script load: maximum call stack size exceeded", ErrScriptStackLimit)
+		}
+		return fmt.Errorf("%w: script load failed: %v", ErrScriptValidation, err)
+	}
+	return nil
+}
+
+// Compile checks that in's script compiles (after transpiling, for
+// TypeScript) and caches the program for later runs.
+func (e *Engine) Compile(in *v1.ScriptInput) error {
+	_, err := e.programs.get(in.GetScript(), in.GetLanguage())
+	return err
+}
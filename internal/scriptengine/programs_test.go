@@ -0,0 +1,73 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+func cacheTestScript(title string) string {
+	return `
+module.exports = {
+  describe: function () { return { name: "cached", version: "1.0.0" }; },
+  init: function () { return {}; },
+  view: function () { return { widgetType: "confirm", input: { title: "` + title + `" } }; },
+  update: function () { return { done: true, result: {} }; }
+};`
+}
+
+func TestProgramCacheReusesCompiledScripts(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithProgramCacheSize(2))
+	run := func(script string) {
+		t.Helper()
+		if _, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: script}); err != nil {
+			t.Fatalf("InitAndView: %v", err)
+		}
+	}
+
+	run(cacheTestScript("a"))
+	run(cacheTestScript("a"))
+	run(cacheTestScript("b"))
+	run(cacheTestScript("c")) // evicts "a"
+	run(cacheTestScript("a"))
+
+	got := e.Stats().Programs
+	want := ProgramCacheStats{Hits: 1, Misses: 4, Evictions: 2, Entries: 2, Capacity: 2}
+	if got != want {
+		t.Fatalf("unexpected cache stats: got %+v want %+v", got, want)
+	}
+}
+
+func TestProgramCacheSkipsScriptsThatFailToCompile(t *testing.T) {
+	t.Parallel()
+
+	e := New()
+	for i := 0; i < 2; i++ {
+		_, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: "module.exports = {"})
+		if !errors.Is(err, ErrScriptValidation) || !strings.Contains(err.Error(), "script load failed") {
+			t.Fatalf("expected load validation error, got %v", err)
+		}
+	}
+	if got := e.Stats().Programs; got.Entries != 0 || got.Misses != 2 {
+		t.Fatalf("expected failed compiles to stay out of the cache, got %+v", got)
+	}
+}
+
+func TestProgramCacheCanBeDisabled(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithProgramCacheSize(0))
+	for i := 0; i < 2; i++ {
+		if _, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: cacheTestScript("a")}); err != nil {
+			t.Fatalf("InitAndView: %v", err)
+		}
+	}
+	if got := e.Stats().Programs; got != (ProgramCacheStats{}) {
+		t.Fatalf("expected no cache activity, got %+v", got)
+	}
+}
@@ -0,0 +1,55 @@
+package scriptengine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxScheduleDelay bounds how far ahead a script may schedule a tick.
+const MaxScheduleDelay = 24 * time.Hour
+
+// Schedule asks the host to send the script a synthetic event after a delay.
+// Scripts request one by returning view.schedule = { afterMs, event }; the
+// event has the same shape as a UI event ({ type, stepId, actionId, data }).
+type Schedule struct {
+	After time.Duration
+	Event map[string]any
+}
+
+// takeSchedule removes "schedule" from view and validates it. It returns nil
+// when the view does not schedule a tick.
+func takeSchedule(view map[string]any) (*Schedule, error) {
+	raw, ok := view["schedule"]
+	delete(view, "schedule")
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	m, err := expectMap(raw, "view.schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	var afterMs float64
+	switch v := m["afterMs"].(type) {
+	case int64:
+		afterMs = float64(v)
+	case float64:
+		afterMs = v
+	default:
+		return nil, fmt.Errorf("%w: view.schedule.afterMs must be a number", ErrScriptValidation)
+	}
+	after := time.Duration(afterMs * float64(time.Millisecond))
+	if afterMs < 0 || after > MaxScheduleDelay {
+		return nil, fmt.Errorf("%w: view.schedule.afterMs must be between 0 and %d", ErrScriptValidation, MaxScheduleDelay.Milliseconds())
+	}
+
+	event, err := expectMap(m["event"], "view.schedule.event")
+	if err != nil {
+		return nil, err
+	}
+	if typ, _ := event["type"].(string); strings.TrimSpace(typ) == "" {
+		return nil, fmt.Errorf("%w: view.schedule.event.type is required", ErrScriptValidation)
+	}
+	return &Schedule{After: after, Event: event}, nil
+}
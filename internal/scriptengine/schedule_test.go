@@ -0,0 +1,69 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+func scheduleScript(schedule string) string {
+	return `
+module.exports = {
+  describe: function () { return { name: "ci-wait", version: "1.0.0" }; },
+  init: function () { return { polls: 0 }; },
+  view: function (state) {
+    return {
+      widgetType: "display",
+      input: { title: "Waiting for CI…" },
+      schedule: ` + schedule + `
+    };
+  },
+  update: function (state) { state.polls++; return state; }
+};`
+}
+
+func TestViewScheduleIsReturnedAndStripped(t *testing.T) {
+	e := New()
+	in := &v1.ScriptInput{Script: scheduleScript(`{ afterMs: 1500, event: { type: "tick", data: { n: 1 } } }`)}
+
+	res, err := e.InitAndView(context.Background(), in)
+	if err != nil {
+		t.Fatalf("InitAndView: %v", err)
+	}
+	if res.Schedule == nil || res.Schedule.After != 1500*time.Millisecond {
+		t.Fatalf("unexpected schedule: %+v", res.Schedule)
+	}
+	if res.Schedule.Event["type"] != "tick" {
+		t.Fatalf("unexpected schedule event: %+v", res.Schedule.Event)
+	}
+	if _, ok := res.View["schedule"]; ok {
+		t.Fatalf("schedule should be removed from the view")
+	}
+
+	upd, err := e.UpdateAndView(context.Background(), in, res.State, res.Schedule.Event)
+	if err != nil {
+		t.Fatalf("UpdateAndView: %v", err)
+	}
+	if upd.Schedule == nil || upd.State["polls"] != int64(1) {
+		t.Fatalf("unexpected update result: schedule=%+v state=%v", upd.Schedule, upd.State)
+	}
+}
+
+func TestViewScheduleValidation(t *testing.T) {
+	e := New()
+	for _, schedule := range []string{
+		`{ afterMs: -1, event: { type: "tick" } }`,
+		`{ afterMs: 90000000, event: { type: "tick" } }`,
+		`{ afterMs: "soon", event: { type: "tick" } }`,
+		`{ afterMs: 10, event: {} }`,
+		`{ afterMs: 10 }`,
+	} {
+		_, err := e.InitAndView(context.Background(), &v1.ScriptInput{Script: scheduleScript(schedule)})
+		if !errors.Is(err, ErrScriptValidation) {
+			t.Fatalf("schedule %s: expected validation error, got %v", schedule, err)
+		}
+	}
+}
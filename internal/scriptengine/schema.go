@@ -0,0 +1,361 @@
+package scriptengine
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// The output schema is a subset of JSON Schema: type (a name or a list of
+// names), enum, const, properties, required, additionalProperties, items,
+// minLength/maxLength/pattern, minimum/maximum/exclusiveMinimum/
+// exclusiveMaximum, and minItems/maxItems. Other keywords such as title and
+// description are accepted and ignored.
+
+var schemaTypeNames = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"object":  true,
+	"array":   true,
+}
+
+// checkOutputSchema reports whether raw is a schema the validator
+// understands, naming the offending keyword by its path.
+func checkOutputSchema(raw any, path string) error {
+	s, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s must be an object", path)
+	}
+	if t, ok := s["type"]; ok {
+		if _, err := schemaTypes(t); err != nil {
+			return fmt.Errorf("%s.type %v", path, err)
+		}
+	}
+	if v, ok := s["enum"]; ok {
+		if _, ok := v.([]any); !ok {
+			return fmt.Errorf("%s.enum must be an array", path)
+		}
+	}
+	for _, kw := range []string{"minLength", "maxLength", "minItems", "maxItems"} {
+		if v, ok := s[kw]; ok {
+			if n, ok := schemaNumber(v); !ok || n < 0 || n != math.Trunc(n) {
+				return fmt.Errorf("%s.%s must be a non-negative integer", path, kw)
+			}
+		}
+	}
+	for _, kw := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum"} {
+		if v, ok := s[kw]; ok {
+			if _, ok := schemaNumber(v); !ok {
+				return fmt.Errorf("%s.%s must be a number", path, kw)
+			}
+		}
+	}
+	if v, ok := s["pattern"]; ok {
+		p, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s.pattern must be a string", path)
+		}
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("%s.pattern is invalid: %v", path, err)
+		}
+	}
+	if v, ok := s["required"]; ok {
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s.required must be an array of strings", path)
+		}
+		for _, item := range items {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("%s.required must be an array of strings", path)
+			}
+		}
+	}
+	if v, ok := s["properties"]; ok {
+		props, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s.properties must be an object", path)
+		}
+		for name, sub := range props {
+			if err := checkOutputSchema(sub, path+".properties."+name); err != nil {
+				return err
+			}
+		}
+	}
+	if v, ok := s["additionalProperties"]; ok {
+		if _, ok := v.(bool); !ok {
+			if err := checkOutputSchema(v, path+".additionalProperties"); err != nil {
+				return err
+			}
+		}
+	}
+	if v, ok := s["items"]; ok {
+		if err := checkOutputSchema(v, path+".items"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOutput checks value against schema, a checked output schema, and
+// describes the first violation with a dotted path rooted at path.
+func validateOutput(schema map[string]any, value any, path string) error {
+	if names := SchemaTypes(schema); len(names) > 0 {
+		matched := false
+		for _, name := range names {
+			if schemaTypeMatches(name, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s must be %s, got %s", path, strings.Join(names, " or "), jsonTypeName(value))
+		}
+	}
+	if v, ok := schema["const"]; ok && !jsonEqual(v, value) {
+		return fmt.Errorf("%s must equal %v", path, v)
+	}
+	if v, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, option := range v {
+			if jsonEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s must be one of %v", path, v)
+		}
+	}
+
+	switch typed := value.(type) {
+	case string:
+		n := float64(utf8.RuneCountInString(typed))
+		if limit, ok := schemaNumber(schema["minLength"]); ok && n < limit {
+			return fmt.Errorf("%s must be at least %v characters", path, limit)
+		}
+		if limit, ok := schemaNumber(schema["maxLength"]); ok && n > limit {
+			return fmt.Errorf("%s must be at most %v characters", path, limit)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(typed) {
+				return fmt.Errorf("%s must match pattern %q", path, p)
+			}
+		}
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, r := range required {
+				name, _ := r.(string)
+				if _, ok := typed[name]; !ok {
+					return fmt.Errorf("%s.%s is required", path, name)
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		keys := make([]string, 0, len(typed))
+		for k := range typed {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := props[k].(map[string]any); ok {
+				if err := validateOutput(sub, typed[k], path+"."+k); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, ok := props[k]; ok {
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s.%s is not allowed", path, k)
+				}
+			case map[string]any:
+				if err := validateOutput(extra, typed[k], path+"."+k); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		n := float64(len(typed))
+		if limit, ok := schemaNumber(schema["minItems"]); ok && n < limit {
+			return fmt.Errorf("%s must have at least %v items", path, limit)
+		}
+		if limit, ok := schemaNumber(schema["maxItems"]); ok && n > limit {
+			return fmt.Errorf("%s must have at most %v items", path, limit)
+		}
+		if sub, ok := schema["items"].(map[string]any); ok {
+			for i, item := range typed {
+				if err := validateOutput(sub, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		if n, ok := schemaNumber(value); ok {
+			if limit, ok := schemaNumber(schema["minimum"]); ok && n < limit {
+				return fmt.Errorf("%s must be >= %v", path, limit)
+			}
+			if limit, ok := schemaNumber(schema["maximum"]); ok && n > limit {
+				return fmt.Errorf("%s must be <= %v", path, limit)
+			}
+			if limit, ok := schemaNumber(schema["exclusiveMinimum"]); ok && n <= limit {
+				return fmt.Errorf("%s must be > %v", path, limit)
+			}
+			if limit, ok := schemaNumber(schema["exclusiveMaximum"]); ok && n >= limit {
+				return fmt.Errorf("%s must be < %v", path, limit)
+			}
+		}
+	}
+	return nil
+}
+
+// OutputSchema returns the outputSchema declared in a describe() result, or
+// nil when there is none.
+func OutputSchema(describe map[string]any) map[string]any {
+	s, _ := describe["outputSchema"].(map[string]any)
+	return s
+}
+
+// SchemaTypes returns the type names a schema allows, or nil when it does not
+// restrict the type.
+func SchemaTypes(schema map[string]any) []string {
+	names, _ := schemaTypes(schema["type"])
+	return names
+}
+
+func schemaTypes(raw any) ([]string, error) {
+	var names []string
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		names = []string{v}
+	case []any:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("must be a type name or an array of type names")
+			}
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("must not be an empty array")
+		}
+	default:
+		return nil, fmt.Errorf("must be a type name or an array of type names")
+	}
+	for _, name := range names {
+		if !schemaTypeNames[name] {
+			return nil, fmt.Errorf("has unknown type %q", name)
+		}
+	}
+	return names, nil
+}
+
+func schemaTypeMatches(name string, value any) bool {
+	switch name {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := schemaNumber(value)
+		return ok
+	case "integer":
+		n, ok := schemaNumber(value)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	default:
+		return false
+	}
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	if _, ok := schemaNumber(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func schemaNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// jsonEqual compares two decoded JSON values, treating numbers of different
+// Go types as equal when their values are.
+func jsonEqual(a, b any) bool {
+	if x, ok := schemaNumber(a); ok {
+		y, ok := schemaNumber(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
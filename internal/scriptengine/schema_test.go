@@ -0,0 +1,126 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+var deployResultSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"env", "approved"},
+	"properties": map[string]any{
+		"env":      map[string]any{"type": "string", "enum": []any{"staging", "prod"}},
+		"approved": map[string]any{"type": "boolean"},
+		"replicas": map[string]any{"type": "integer", "minimum": float64(1), "maximum": float64(10)},
+		"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string", "pattern": "^[a-z]+$"}, "maxItems": float64(2)},
+		"note":     map[string]any{"type": []any{"string", "null"}, "maxLength": float64(5)},
+	},
+	"additionalProperties": false,
+}
+
+func TestValidateOutput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		result map[string]any
+		want   string
+	}{
+		{name: "valid", result: map[string]any{"env": "prod", "approved": true, "replicas": int64(3), "tags": []any{"web"}, "note": nil}},
+		{name: "float integer", result: map[string]any{"env": "prod", "approved": true, "replicas": float64(3)}},
+		{name: "missing required", result: map[string]any{"env": "prod"}, want: "result.approved is required"},
+		{name: "wrong type", result: map[string]any{"env": "prod", "approved": "yes"}, want: "result.approved must be boolean, got string"},
+		{name: "enum", result: map[string]any{"env": "dev", "approved": true}, want: "result.env must be one of [staging prod]"},
+		{name: "not integer", result: map[string]any{"env": "prod", "approved": true, "replicas": 2.5}, want: "result.replicas must be integer, got number"},
+		{name: "maximum", result: map[string]any{"env": "prod", "approved": true, "replicas": int64(11)}, want: "result.replicas must be <= 10"},
+		{name: "item pattern", result: map[string]any{"env": "prod", "approved": true, "tags": []any{"web", "DB"}}, want: `result.tags[1] must match pattern "^[a-z]+$"`},
+		{name: "max items", result: map[string]any{"env": "prod", "approved": true, "tags": []any{"a", "b", "c"}}, want: "result.tags must have at most 2 items"},
+		{name: "max length", result: map[string]any{"env": "prod", "approved": true, "note": "too long"}, want: "result.note must be at most 5 characters"},
+		{name: "additional", result: map[string]any{"env": "prod", "approved": true, "extra": 1}, want: "result.extra is not allowed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateOutput(deployResultSchema, tc.result, "result")
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("expected valid result, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("expected %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestCheckOutputSchemaRejectsUnsupportedSchemas(t *testing.T) {
+	t.Parallel()
+
+	if err := checkOutputSchema(deployResultSchema, "describe.outputSchema"); err != nil {
+		t.Fatalf("expected deploy schema to be accepted, got %v", err)
+	}
+	cases := map[string]any{
+		"describe.outputSchema must be an object":                                    "object",
+		`describe.outputSchema.type has unknown type "text"`:                         map[string]any{"type": "text"},
+		"describe.outputSchema.properties.n.minimum must be a number":                map[string]any{"properties": map[string]any{"n": map[string]any{"minimum": "1"}}},
+		"describe.outputSchema.required must be an array of strings":                 map[string]any{"required": "env"},
+		"describe.outputSchema.items.maxLength must be a non-negative integer":       map[string]any{"items": map[string]any{"maxLength": -1.0}},
+		"describe.outputSchema.additionalProperties.type must not be an empty array": map[string]any{"additionalProperties": map[string]any{"type": []any{}}},
+	}
+	for want, schema := range cases {
+		if err := checkOutputSchema(schema, "describe.outputSchema"); err == nil || err.Error() != want {
+			t.Errorf("expected %q, got %v", want, err)
+		}
+	}
+}
+
+func TestOutputSchemaIsCheckedAtInitAndEnforcedOnDone(t *testing.T) {
+	t.Parallel()
+
+	script := `
+module.exports = {
+  describe: function () {
+    return {
+      name: "typed", version: "1.0.0",
+      outputSchema: { type: "object", required: ["count"], properties: { count: { type: "integer" } } }
+    };
+  },
+  init: function () { return {}; },
+  view: function () { return { widgetType: "confirm", input: { title: "ok?" } }; },
+  update: function (state, event) { return { done: true, result: event.data }; }
+};
+`
+	e := New()
+	in := &v1.ScriptInput{Script: script}
+	initRes, err := e.InitAndView(context.Background(), in)
+	if err != nil {
+		t.Fatalf("InitAndView failed: %v", err)
+	}
+	schema := OutputSchema(initRes.Describe)
+	if schema == nil {
+		t.Fatal("expected describe to carry the output schema")
+	}
+
+	ok, err := e.UpdateAndView(context.Background(), in, initRes.State,
+		map[string]any{"type": "submit", "data": map[string]any{"count": int64(2)}}, WithOutputSchema(schema))
+	if err != nil || !ok.Done {
+		t.Fatalf("expected valid result to finish the flow, got done=%v err=%v", ok != nil && ok.Done, err)
+	}
+
+	_, err = e.UpdateAndView(context.Background(), in, initRes.State,
+		map[string]any{"type": "submit", "data": map[string]any{"count": "two"}}, WithOutputSchema(schema))
+	if !errors.Is(err, ErrScriptRuntime) || !strings.Contains(err.Error(), "result.count must be integer, got string") {
+		t.Fatalf("expected runtime error for schema violation, got %v", err)
+	}
+
+	bad := strings.Replace(script, `type: "integer"`, `type: "int"`, 1)
+	_, err = e.InitAndView(context.Background(), &v1.ScriptInput{Script: bad})
+	if !errors.Is(err, ErrScriptValidation) || !strings.Contains(err.Error(), `describe.outputSchema.properties.count.type has unknown type "int"`) {
+		t.Fatalf("expected validation error for unsupported schema, got %v", err)
+	}
+}
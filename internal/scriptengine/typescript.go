@@ -0,0 +1,63 @@
+package scriptengine
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+)
+
+// Script source languages accepted in ScriptInput.language.
+const (
+	LanguageJavaScript = "javascript"
+	LanguageTypeScript = "typescript"
+)
+
+// NormalizeLanguage returns the canonical name of a script language. An empty
+// language is JavaScript.
+func NormalizeLanguage(language string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(language)) {
+	case "", "js", LanguageJavaScript:
+		return LanguageJavaScript, nil
+	case "ts", LanguageTypeScript:
+		return LanguageTypeScript, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported script language %q", ErrScriptValidation, language)
+	}
+}
+
+// LanguageForPath guesses a script's language from its file extension. It
+// returns "" (JavaScript) for anything but .ts, .mts, and .cts.
+func LanguageForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ts", ".mts", ".cts":
+		return LanguageTypeScript
+	default:
+		return ""
+	}
+}
+
+// transpileTypeScript strips types from a TypeScript script and lowers it to
+// CommonJS that goja can run. ES module exports end up on module.exports,
+// with a default export under "default"; see buildExportsProgram.
+func transpileTypeScript(source string) (string, error) {
+	res := api.Transform(source, api.TransformOptions{
+		Loader:     api.LoaderTS,
+		Format:     api.FormatCommonJS,
+		Target:     api.ES2017,
+		Sourcefile: "script.ts",
+	})
+	if len(res.Errors) > 0 {
+		msgs := make([]string, 0, len(res.Errors))
+		for _, m := range res.Errors {
+			if m.Location != nil {
+				msgs = append(msgs, fmt.Sprintf("%s:%d:%d: %s", m.Location.File, m.Location.Line, m.Location.Column+1, m.Text))
+				continue
+			}
+			msgs = append(msgs, m.Text)
+		}
+		return "", fmt.Errorf("%w: typescript transpile failed: %s", ErrScriptValidation, strings.Join(msgs, "; "))
+	}
+	return string(res.Code), nil
+}
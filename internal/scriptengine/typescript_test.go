@@ -0,0 +1,99 @@
+package scriptengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const typedScript = `
+interface State { step: "confirm" | "done"; count: number }
+
+const script: PlzConfirm.Script<State> = {
+  describe: () => ({ name: "typed", version: "1.0.0" }),
+  init: (): State => ({ step: "confirm", count: 0 }),
+  view: (state: State) => ({ widgetType: "confirm", stepId: state.step, input: { title: "n=" + state.count } }),
+  update(state: State, event) {
+    const approved = (event.data as PlzConfirm.ConfirmOutput | undefined)?.approved;
+    if (approved) return { done: true, result: { count: state.count } };
+    return { ...state, count: state.count + 1 };
+  },
+};
+export default script;
+`
+
+func TestTypeScriptScriptRuns(t *testing.T) {
+	t.Parallel()
+
+	e := New()
+	language := "ts"
+	in := &v1.ScriptInput{Script: typedScript, Language: &language}
+	initRes, err := e.InitAndView(context.Background(), in)
+	if err != nil {
+		t.Fatalf("InitAndView: %v", err)
+	}
+	if initRes.Describe["name"] != "typed" || initRes.View["stepId"] != "confirm" {
+		t.Fatalf("unexpected init result: %#v %#v", initRes.Describe, initRes.View)
+	}
+	upd, err := e.UpdateAndView(context.Background(), in, initRes.State, map[string]any{
+		"type": "submit",
+		"data": map[string]any{"approved": false},
+	})
+	if err != nil {
+		t.Fatalf("UpdateAndView: %v", err)
+	}
+	if upd.State["count"] != int64(1) {
+		t.Fatalf("expected count 1, got %#v", upd.State)
+	}
+
+	// Without the language the source is JavaScript and does not compile.
+	if err := e.Compile(&v1.ScriptInput{Script: typedScript}); !errors.Is(err, ErrScriptValidation) {
+		t.Fatalf("expected TypeScript source to fail as JavaScript, got %v", err)
+	}
+}
+
+func TestTypeScriptErrorsAndLanguages(t *testing.T) {
+	t.Parallel()
+
+	e := New()
+	language := LanguageTypeScript
+	err := e.Compile(&v1.ScriptInput{Script: "const x: number = ;", Language: &language})
+	if !errors.Is(err, ErrScriptValidation) || !strings.Contains(err.Error(), "script.ts:1:") {
+		t.Fatalf("expected transpile error with location, got %v", err)
+	}
+
+	unknown := "coffee"
+	if err := e.Compile(&v1.ScriptInput{Script: "module.exports = {};", Language: &unknown}); !errors.Is(err, ErrScriptValidation) {
+		t.Fatalf("expected unsupported language error, got %v", err)
+	}
+
+	for path, want := range map[string]string{"flow.ts": LanguageTypeScript, "@flow.TS": LanguageTypeScript, "flow.js": "", "-": ""} {
+		if got := LanguageForPath(path); got != want {
+			t.Fatalf("LanguageForPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestTypeDefinitionsParse(t *testing.T) {
+	t.Parallel()
+
+	defs := TypeDefinitions()
+	for _, want := range []string{
+		"interface ConfirmInput {\n    title: string;\n    message?: string;",
+		"options?: (string | SelectOption)[];",
+		`source: "ui" | "agent" | "schedule";`,
+		"interface ScriptViewFields {",
+		"    grid: GridSelection;",
+		"interface Script<",
+	} {
+		if !strings.Contains(defs, want) {
+			t.Fatalf("type definitions missing %q:\n%s", want, defs)
+		}
+	}
+	if _, err := transpileTypeScript(defs); err != nil {
+		t.Fatalf("type definitions do not parse as TypeScript: %v", err)
+	}
+}
@@ -0,0 +1,346 @@
+package scriptengine
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// scriptWidget pairs a view widgetType with the widgets.proto messages for
+// its input and for the event.data it produces. display has no output.
+type scriptWidget struct {
+	widgetType string
+	input      protoreflect.Name
+	output     protoreflect.Name
+}
+
+var scriptWidgets = []scriptWidget{
+	{"confirm", "ConfirmInput", "ConfirmOutput"},
+	{"select", "SelectInput", "SelectOutput"},
+	{"grid", "GridInput", "GridSelection"},
+	{"rating", "RatingInput", "RatingOutput"},
+	{"form", "FormInput", "FormOutput"},
+	{"table", "TableInput", "TableOutput"},
+	{"upload", "UploadInput", "UploadOutput"},
+	{"image", "ImageInput", "ImageOutput"},
+	{"display", "DisplayInput", ""},
+	{"diff", "DiffInput", "DiffOutput"},
+	{"keyvalue", "KeyValueInput", "KeyValueOutput"},
+	{"checklist", "ChecklistInput", "ChecklistOutput"},
+}
+
+// tsFieldOverride replaces the type generated for a field where the script
+// view accepts more than the proto says, or the proto documents a closed set
+// of strings in a comment. optional forces the field to be optional.
+type tsFieldOverride struct {
+	tsType   string
+	optional bool
+}
+
+var tsFieldOverrides = map[string]tsFieldOverride{
+	"SelectInput.options":       {tsType: "(string | SelectOption)[]"},
+	"ImageInput.mode":           {tsType: `"select" | "confirm"`, optional: true},
+	"RatingInput.style":         {tsType: `"stars" | "numbers" | "emoji" | "slider"`},
+	"GridInput.cellSize":        {tsType: `"small" | "medium" | "large"`},
+	"DisplayInput.format":       {tsType: `"markdown" | "text" | "html"`},
+	"DiffInput.layout":          {tsType: `"unified" | "split"`},
+	"DiffHunkDecision.decision": {tsType: `"approve" | "reject"`},
+	"KeyValueItem.value":        {tsType: "string | number | boolean"},
+	"KeyValueItem.style":        {tsType: `"default" | "muted" | "success" | "warning" | "danger"`},
+	"ScriptToast.style":         {tsType: `"info" | "success" | "warning" | "error"`},
+	"ScriptEvent.source":        {tsType: `"ui" | "agent" | "schedule"`},
+	"ScriptEvent.data":          {tsType: "D"},
+}
+
+// tsExtraFields are script-only input fields that widgets.proto does not
+// carry.
+var tsExtraFields = map[protoreflect.Name][]string{
+	"SelectInput": {"defaults?: { selectedSingle?: string; selectedMulti?: { values: string[] } };"},
+	"FormInput":   {"defaults?: Record<string, unknown>;"},
+	"TableInput":  {"defaults?: { selectedSingle?: unknown; selectedMulti?: { values: unknown[] } };"},
+	"RatingInput": {"defaults?: { value?: number };"},
+	"ScriptView":  {"showBack?: boolean;", "schedule?: ScriptSchedule;"},
+}
+
+// tsOmittedFields are fields of generated messages the script does not set:
+// the view's widgetType/input/sections are typed by hand as a union.
+var tsOmittedFields = map[string]bool{
+	"ScriptView.widgetType": true,
+	"ScriptView.input":      true,
+	"ScriptView.sections":   true,
+}
+
+var (
+	typeDefinitionsOnce sync.Once
+	typeDefinitions     string
+)
+
+// TypeDefinitions returns TypeScript declarations for the script contract.
+// Widget inputs and outputs, events, and view fields are generated from the
+// widgets.proto descriptors; ctx and the exported functions are written out
+// by hand.
+func TypeDefinitions() string {
+	typeDefinitionsOnce.Do(func() {
+		typeDefinitions = generateTypeDefinitions()
+	})
+	return typeDefinitions
+}
+
+type tsGenerator struct {
+	msgs    protoreflect.MessageDescriptors
+	b       strings.Builder
+	emitted map[protoreflect.Name]bool
+	queue   []protoreflect.MessageDescriptor
+}
+
+func generateTypeDefinitions() string {
+	g := &tsGenerator{
+		msgs:    v1.File_plz_confirm_v1_widgets_proto.Messages(),
+		emitted: map[protoreflect.Name]bool{},
+	}
+	g.b.WriteString(typingsHeader)
+	g.b.WriteString("declare namespace PlzConfirm {\n")
+	g.b.WriteString(typingsSelectOption)
+
+	for _, w := range scriptWidgets {
+		g.enqueue(w.input)
+		if w.output != "" {
+			g.enqueue(w.output)
+		}
+	}
+	g.enqueue("ScriptEvent")
+	g.enqueue("ScriptView")
+	for len(g.queue) > 0 {
+		md := g.queue[0]
+		g.queue = g.queue[1:]
+		g.writeMessage(md)
+	}
+
+	g.b.WriteString("\n  /** view().input for each widgetType. */\n  interface WidgetInputs {\n")
+	for _, w := range scriptWidgets {
+		fmt.Fprintf(&g.b, "    %s: %s;\n", w.widgetType, w.input)
+	}
+	g.b.WriteString("  }\n\n  /** event.data each widgetType submits. */\n  interface WidgetOutputs {\n")
+	for _, w := range scriptWidgets {
+		if w.output != "" {
+			fmt.Fprintf(&g.b, "    %s: %s;\n", w.widgetType, w.output)
+		}
+	}
+	g.b.WriteString("  }\n")
+	g.b.WriteString(typingsContract)
+	g.b.WriteString("}\n")
+	g.b.WriteString(typingsModules)
+	return g.b.String()
+}
+
+func (g *tsGenerator) enqueue(name protoreflect.Name) {
+	if g.emitted[name] {
+		return
+	}
+	md := g.msgs.ByName(name)
+	if md == nil {
+		panic(fmt.Sprintf("scriptengine: widgets.proto has no message %s", name))
+	}
+	g.emitted[name] = true
+	g.queue = append(g.queue, md)
+}
+
+func (g *tsGenerator) writeMessage(md protoreflect.MessageDescriptor) {
+	name := md.Name()
+	decl := string(name)
+	switch name {
+	case "ScriptEvent":
+		decl = "ScriptEvent<D = Record<string, unknown>>"
+	case "ScriptView":
+		decl = "ScriptViewFields"
+	}
+	fmt.Fprintf(&g.b, "\n  interface %s {\n", decl)
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		key := string(name) + "." + fd.JSONName()
+		if tsOmittedFields[key] {
+			continue
+		}
+		tsType := g.fieldType(fd)
+		optional := fd.HasPresence() || fd.IsList() || fd.IsMap()
+		if o, ok := tsFieldOverrides[key]; ok {
+			tsType = o.tsType
+			optional = optional || o.optional
+		}
+		mark := ""
+		if optional {
+			mark = "?"
+		}
+		fmt.Fprintf(&g.b, "    %s%s: %s;\n", fd.JSONName(), mark, tsType)
+	}
+	for _, extra := range tsExtraFields[name] {
+		fmt.Fprintf(&g.b, "    %s\n", extra)
+	}
+	g.b.WriteString("  }\n")
+}
+
+func (g *tsGenerator) fieldType(fd protoreflect.FieldDescriptor) string {
+	if fd.IsMap() {
+		return "Record<string, " + g.singularType(fd.MapValue()) + ">"
+	}
+	t := g.singularType(fd)
+	if fd.IsList() {
+		if strings.Contains(t, "|") {
+			t = "(" + t + ")"
+		}
+		return t + "[]"
+	}
+	return t
+}
+
+func (g *tsGenerator) singularType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return "string"
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, fmt.Sprintf("%q", values.Get(i).Name()))
+		}
+		return strings.Join(names, " | ")
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch fd.Message().FullName() {
+		case "google.protobuf.Struct":
+			return "Record<string, unknown>"
+		case "google.protobuf.Value":
+			return "unknown"
+		}
+		g.enqueue(fd.Message().Name())
+		return string(fd.Message().Name())
+	default:
+		// Scripts pass plain JS numbers, including for 64-bit fields.
+		return "number"
+	}
+}
+
+const typingsHeader = `// Code generated by plz-confirm from widgets.proto. DO NOT EDIT.
+//
+// TypeScript declarations for the plz-confirm script contract, served at
+// GET /api/scripts/types.d.ts. Save next to your scripts and write:
+//
+//   const script: PlzConfirm.Script<State> = { describe, init, view, update };
+//   export default script;
+
+`
+
+const typingsSelectOption = `
+  /** Rich select option; selectedSingle/selectedMulti carry its value. */
+  interface SelectOption {
+    value: string;
+    label?: string;
+    description?: string;
+    badge?: string;
+    icon?: string;
+    disabled?: boolean;
+  }
+`
+
+const typingsContract = `
+  type WidgetType = keyof WidgetInputs;
+
+  /** One widget: a single-widget view or an entry of view.sections. */
+  type WidgetSpec = {
+    [K in WidgetType]: { widgetType: K; input: WidgetInputs[K] };
+  }[WidgetType];
+
+  /** Asks the server to send an event to update() later. */
+  interface ScriptSchedule {
+    afterMs: number;
+    event: { type: string; stepId?: string; actionId?: string; data?: Record<string, unknown> };
+  }
+
+  /** An entry of view.sections; id keys its answer in event.data.sections when a view has several interactive sections. */
+  type ScriptViewSection = WidgetSpec & { id?: string };
+
+  /** What view() returns: one widget, or sections mixing read-only widgets (display, keyvalue) with one or more interactive ones. */
+  type ScriptView = ScriptViewFields & (WidgetSpec | { widgetType?: WidgetType | "sections"; sections: ScriptViewSection[] });
+
+  /** What describe() returns. */
+  interface ScriptDescription {
+    name: string;
+    version: string;
+    apiVersion?: string;
+    capabilities?: ("fs" | "fetch" | (string & {}))[];
+    /** JSON Schema (subset) the final result must match; checked before the request completes. */
+    outputSchema?: Record<string, unknown>;
+    /** Message catalogs for ctx.t, keyed by locale, e.g. { en: { title: "Deploy {env}?" } }. */
+    messages?: Record<string, Record<string, string>>;
+    /** Locale used when no catalog matches the responder; defaults to "en". */
+    defaultLocale?: string;
+  }
+
+  interface BranchRule<S> {
+    when: string | boolean | ((event: ScriptEvent, state: S) => boolean);
+    step?: string;
+    to?: string;
+  }
+
+  interface BranchSpec<S> {
+    rules?: BranchRule<S>[];
+    routes?: Record<string, string>;
+    [route: string]: unknown;
+  }
+
+  interface FetchResponse {
+    status: number;
+    ok: boolean;
+    headers: Record<string, string>;
+    body: string;
+  }
+
+  interface ScriptFS {
+    readFile(path: string): string;
+    listDir(path: string): { name: string; isDir: boolean; size: number }[];
+  }
+
+  interface ScriptContext<P = Record<string, unknown>> {
+    props: P;
+    /** Server time, RFC 3339 with nanoseconds. */
+    now: string;
+    seed: number;
+    /** Locale negotiated from the responder's Accept-Language against describe().messages. */
+    locale: string;
+    /** Looks key up in describe().messages for ctx.locale and fills {name} placeholders. */
+    t(key: string, params?: Record<string, string | number | boolean>): string;
+    random(): number;
+    randomInt(min: number, max: number): number;
+    /** Sets state.step from the first matching rule or route and returns state. */
+    branch<S extends { step?: string }>(state: S, event: ScriptEvent, spec: BranchSpec<S>): S;
+    /** Present when the "fs" capability was granted. */
+    fs?: ScriptFS;
+    /** Present when the "fetch" capability was granted. */
+    fetch?(url: string, options?: { method?: "GET" | "HEAD"; headers?: Record<string, string> }): FetchResponse;
+  }
+
+  interface ScriptDone<R = Record<string, unknown>> {
+    done: true;
+    result: R;
+  }
+
+  interface Script<S extends object = Record<string, unknown>, P = Record<string, unknown>, R = Record<string, unknown>> {
+    describe(ctx: ScriptContext<P>): ScriptDescription;
+    init(ctx: ScriptContext<P>): S;
+    view(state: S, ctx: ScriptContext<P>): ScriptView;
+    update(state: S, event: ScriptEvent, ctx: ScriptContext<P>): S | ScriptDone<R>;
+  }
+`
+
+const typingsModules = `
+/** Library modules loaded with require("plz/...") or import. */
+declare module "plz/*" {
+  const mod: any;
+  export = mod;
+}
+`
@@ -0,0 +1,112 @@
+package scriptengine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+type ViewResult struct {
+	View map[string]any
+	Logs []string
+	// Grants carries the recorded grants forward with updated budgets.
+	Grants []Grant
+	// Schedule is the tick requested by the view, if any.
+	Schedule *Schedule
+	// Locale is the ctx.locale view() saw.
+	Locale string
+}
+
+// View renders view() for state without calling update(). It is used when
+// the server restores a stored state, so it always runs in a fresh runtime
+// and drops the warm runtime kept under WithRuntimeKey, whose live state no
+// longer matches.
+func (e *Engine) View(
+	ctx context.Context,
+	in *v1.ScriptInput,
+	state map[string]any,
+	opts ...RunOption,
+) (*ViewResult, error) {
+	if in == nil {
+		return nil, fmt.Errorf("%w: script input is required", ErrScriptValidation)
+	}
+	if strings.TrimSpace(in.GetScript()) == "" {
+		return nil, fmt.Errorf("%w: script source is required", ErrScriptValidation)
+	}
+	if state == nil {
+		state = map[string]any{}
+	}
+	cfg := newRunConfig(opts)
+	if cfg.runtimeKey != "" {
+		e.pool.release(cfg.runtimeKey)
+	}
+
+	collector := newRunLogCollector()
+	rt, err := e.newRuntime(ctx, collector)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rt.Close(ctx) }()
+
+	var out ViewResult
+	var fetcher *scriptFetch
+	run := func(runCtx context.Context) error {
+		if err := e.loadScript(rt.VM, in); err != nil {
+			return err
+		}
+		hasView, err := evalBool(rt.VM.RunString(`typeof __pc_exports.view === "function"`))
+		if err != nil {
+			return err
+		}
+		if !hasView {
+			return fmt.Errorf("%w: script must export a view function", ErrScriptValidation)
+		}
+		if err := rt.VM.Set("__pc_state", state); err != nil {
+			return fmt.Errorf("%w: set state failed: %v", ErrScriptSetup, err)
+		}
+		scriptCtx := defaultScriptContext(in.GetProps(), cfg.clock())
+		out.Locale = setContextLocale(scriptCtx, cfg.messages, cfg.acceptLanguage)
+		if err := rt.VM.Set("__pc_ctx", scriptCtx); err != nil {
+			return fmt.Errorf("%w: set ctx failed: %v", ErrScriptSetup, err)
+		}
+		if err := attachContextHelpers(rt.VM); err != nil {
+			return err
+		}
+		sfs, err := e.restoreFS(rt.VM, cfg.grants)
+		if err != nil {
+			return err
+		}
+		defer sfs.Close()
+		fetcher, err = e.restoreFetch(runCtx, rt.VM, cfg.grants, collector)
+		if err != nil {
+			return err
+		}
+
+		viewVal, err := rt.VM.RunString(`__pc_exports.view(__pc_state, __pc_ctx)`)
+		if err != nil {
+			return callError("view()", err)
+		}
+		viewMap, err := expectMap(viewVal.Export(), "view result")
+		if err != nil {
+			return err
+		}
+		out.Schedule, err = takeSchedule(viewMap)
+		if err != nil {
+			return err
+		}
+		if err := e.checkOutputSize("view result", viewMap); err != nil {
+			return err
+		}
+		out.View = viewMap
+		return nil
+	}
+
+	if err := runWithTimeout(ctx, rt.VM, timeoutFromInput(in), e.limits.maxHeapGrowth, run); err != nil {
+		return nil, &RunError{Err: err, Logs: collector.Snapshot(), Grants: carryGrants(cfg.grants, fetcher)}
+	}
+	out.Grants = carryGrants(cfg.grants, fetcher)
+	out.Logs = collector.Snapshot()
+	return &out, nil
+}
@@ -0,0 +1,51 @@
+package scriptengine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+func TestViewRendersStateWithoutUpdate(t *testing.T) {
+	t.Parallel()
+
+	e := New()
+	out, err := e.View(context.Background(), &v1.ScriptInput{Script: happyPathScript}, map[string]any{"step": "select"})
+	if err != nil {
+		t.Fatalf("View returned error: %v", err)
+	}
+	if got := out.View["widgetType"]; got != "select" {
+		t.Fatalf("unexpected view widgetType: %v", got)
+	}
+}
+
+func TestViewDropsWarmRuntime(t *testing.T) {
+	t.Parallel()
+
+	e := New(WithWarmRuntimes(4, time.Minute))
+	defer e.Close()
+	in := &v1.ScriptInput{Script: warmScript}
+	ev := map[string]any{"type": "tick"}
+
+	if _, err := e.UpdateAndView(context.Background(), in, map[string]any{"n": int64(0)}, ev, WithRuntimeKey("req-1")); err != nil {
+		t.Fatalf("UpdateAndView: %v", err)
+	}
+	out, err := e.View(context.Background(), in, map[string]any{"n": int64(0)}, WithRuntimeKey("req-1"))
+	if err != nil {
+		t.Fatalf("View: %v", err)
+	}
+	if got := out.View["input"].(map[string]any)["title"]; got != "n=0" {
+		t.Fatalf("expected view of the given state, got %v", got)
+	}
+
+	// The next event starts from the state passed in, not the old live one.
+	next, err := e.UpdateAndView(context.Background(), in, map[string]any{"n": int64(0)}, ev, WithRuntimeKey("req-1"))
+	if err != nil {
+		t.Fatalf("UpdateAndView after View: %v", err)
+	}
+	if next.State["n"] != int64(1) {
+		t.Fatalf("expected a cold runtime after View, got %#v", next.State)
+	}
+}
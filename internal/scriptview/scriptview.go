@@ -0,0 +1,801 @@
+// Package scriptview turns the object a script's view() returns into a
+// ScriptView and checks each widget's input. The server and the scripttest
+// harness share it, so a fixture fails on the views the server rejects.
+package scriptview
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// FromMap converts what view() returned to a ScriptView, checking the
+// layout and each widget's input.
+func FromMap(m map[string]any) (*v1.ScriptView, error) {
+	if m == nil {
+		return nil, fmt.Errorf("view must be object")
+	}
+	widgetType, _ := m["widgetType"].(string)
+	inputMap := map[string]any{}
+	hasTopLevelInput := false
+	if raw, ok := m["input"]; ok {
+		hasTopLevelInput = true
+		typed, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("view.input must be object")
+		}
+		inputMap = typed
+	}
+
+	sections, parsedSections, err := mapSections(m["sections"])
+	if err != nil {
+		return nil, err
+	}
+	multiSection := false
+	if len(parsedSections) > 0 {
+		interactive, err := interactiveSections(parsedSections)
+		if err != nil {
+			return nil, err
+		}
+		if len(interactive) > 1 {
+			multiSection = true
+			if wt := strings.TrimSpace(widgetType); wt != "" && !strings.EqualFold(wt, SectionsWidgetType) {
+				return nil, fmt.Errorf("view.widgetType must be omitted or %q when a view has several interactive sections", SectionsWidgetType)
+			}
+			if hasTopLevelInput {
+				return nil, fmt.Errorf("view.input is not allowed when a view has several interactive sections")
+			}
+			widgetType = SectionsWidgetType
+		} else if strings.TrimSpace(widgetType) == "" {
+			widgetType = interactive[0].widgetType
+		} else if !strings.EqualFold(strings.TrimSpace(widgetType), interactive[0].widgetType) {
+			return nil, fmt.Errorf("view.widgetType must match the interactive section widgetType")
+		}
+		if !multiSection && !hasTopLevelInput {
+			inputMap = interactive[0].input
+		}
+	}
+	if strings.TrimSpace(widgetType) == "" {
+		return nil, fmt.Errorf("view.widgetType is required")
+	}
+	if !multiSection {
+		if err := validateInput(widgetType, inputMap); err != nil {
+			return nil, err
+		}
+	}
+	inputStruct, err := toStruct(inputMap)
+	if err != nil {
+		return nil, err
+	}
+	view := &v1.ScriptView{
+		WidgetType: widgetType,
+		Input:      inputStruct,
+		Sections:   sections,
+	}
+	if stepID, ok := m["stepId"].(string); ok && strings.TrimSpace(stepID) != "" {
+		view.StepId = &stepID
+	}
+	if title, ok := m["title"].(string); ok && strings.TrimSpace(title) != "" {
+		view.Title = &title
+	}
+	if description, ok := m["description"].(string); ok && strings.TrimSpace(description) != "" {
+		view.Description = &description
+	}
+	progress, err := mapProgress(m["progress"])
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil {
+		view.Progress = progress
+	}
+	if allowBack, ok := m["allowBack"].(bool); ok {
+		view.AllowBack = &allowBack
+	} else if showBack, ok := m["showBack"].(bool); ok {
+		view.AllowBack = &showBack
+	}
+	if backLabel, ok := m["backLabel"].(string); ok && strings.TrimSpace(backLabel) != "" {
+		view.BackLabel = &backLabel
+	}
+	toastCfg, err := mapToast(m["toast"])
+	if err != nil {
+		return nil, err
+	}
+	if toastCfg != nil {
+		view.Toast = toastCfg
+	}
+	return view, nil
+}
+
+type parsedSection struct {
+	index      int
+	id         string
+	widgetType string
+	input      map[string]any
+}
+
+func mapSections(raw any) ([]*v1.ScriptViewSection, []parsedSection, error) {
+	if raw == nil {
+		return nil, nil, nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("view.sections must be an array")
+	}
+	if len(items) == 0 {
+		return nil, nil, fmt.Errorf("view.sections must include at least one section")
+	}
+
+	sections := make([]*v1.ScriptViewSection, 0, len(items))
+	parsed := make([]parsedSection, 0, len(items))
+	ids := map[string]bool{}
+	for i, item := range items {
+		sectionMap, ok := item.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("view.sections[%d] must be an object", i)
+		}
+		widgetType, _ := sectionMap["widgetType"].(string)
+		if strings.TrimSpace(widgetType) == "" {
+			return nil, nil, fmt.Errorf("view.sections[%d].widgetType is required", i)
+		}
+		var id string
+		if rawID, ok := sectionMap["id"]; ok && rawID != nil {
+			s, ok := rawID.(string)
+			if !ok || strings.TrimSpace(s) == "" {
+				return nil, nil, fmt.Errorf("view.sections[%d].id must be a non-empty string", i)
+			}
+			if ids[s] {
+				return nil, nil, fmt.Errorf("view.sections[%d].id %q is not unique", i, s)
+			}
+			ids[s] = true
+			id = s
+		}
+
+		inputMap := map[string]any{}
+		if rawInput, ok := sectionMap["input"]; ok {
+			typed, ok := rawInput.(map[string]any)
+			if !ok {
+				return nil, nil, fmt.Errorf("view.sections[%d].input must be object", i)
+			}
+			inputMap = typed
+		}
+		if err := validateInput(widgetType, inputMap); err != nil {
+			return nil, nil, err
+		}
+		inputStruct, err := toStruct(inputMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		section := &v1.ScriptViewSection{
+			WidgetType: widgetType,
+			Input:      inputStruct,
+		}
+		if id != "" {
+			section.Id = &id
+		}
+		sections = append(sections, section)
+		parsed = append(parsed, parsedSection{
+			index:      i,
+			id:         id,
+			widgetType: strings.ToLower(strings.TrimSpace(widgetType)),
+			input:      inputMap,
+		})
+	}
+	return sections, parsed, nil
+}
+
+// readOnlyWidgets are view widgets that only show context; in a sectioned
+// view they sit next to the interactive sections.
+var readOnlyWidgets = map[string]bool{
+	"display":  true,
+	"keyvalue": true,
+}
+
+// ReadOnly reports whether widgetType only shows context and takes no input.
+func ReadOnly(widgetType string) bool {
+	return readOnlyWidgets[strings.ToLower(strings.TrimSpace(widgetType))]
+}
+
+// SectionsWidgetType is the view widgetType of a view with several
+// interactive sections, whose answers are submitted together.
+const SectionsWidgetType = "sections"
+
+// interactiveSections returns the sections that take input. A view needs at
+// least one; when it has several, each needs an id to key its answer in
+// event.data.sections.
+func interactiveSections(sections []parsedSection) ([]*parsedSection, error) {
+	var interactive []*parsedSection
+	for i := range sections {
+		if readOnlyWidgets[sections[i].widgetType] {
+			continue
+		}
+		interactive = append(interactive, &sections[i])
+	}
+	if len(interactive) == 0 {
+		return nil, fmt.Errorf("view.sections must include at least one interactive section")
+	}
+	if len(interactive) > 1 {
+		for _, section := range interactive {
+			if section.id == "" {
+				return nil, fmt.Errorf("view.sections[%d].id is required when a view has several interactive sections", section.index)
+			}
+		}
+	}
+	return interactive, nil
+}
+
+// ValidateInput checks input against the rules for widgetType. Standalone
+// requests use it too, so a widget is accepted the same way on both paths.
+// Widget types without rules are always valid.
+func ValidateInput(widgetType string, input map[string]any) error {
+	return validateInput(widgetType, input)
+}
+
+func validateInput(widgetType string, input map[string]any) error {
+	switch strings.ToLower(strings.TrimSpace(widgetType)) {
+	case "grid":
+		return validateGridInput(input)
+	case "display":
+		return validateDisplayInput(input)
+	case "rating":
+		return validateRatingInput(input)
+	case "select":
+		return validateSelectInput(input)
+	case "diff":
+		return validateDiffInput(input)
+	case "keyvalue":
+		return validateKeyValueInput(input)
+	case "checklist":
+		return validateChecklistInput(input)
+	default:
+		return nil
+	}
+}
+
+func validateDisplayInput(input map[string]any) error {
+	content, _ := input["content"].(string)
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("view.input.content is required for display widget")
+	}
+	if rawFormat, ok := input["format"]; ok {
+		format, ok := rawFormat.(string)
+		if !ok {
+			return fmt.Errorf("view.input.format must be string for display widget")
+		}
+		switch strings.ToLower(strings.TrimSpace(format)) {
+		case "", "markdown", "text", "html":
+		default:
+			return fmt.Errorf("view.input.format must be markdown, text, or html for display widget")
+		}
+	}
+	return nil
+}
+
+// maxGridCells caps rows*cols of a grid widget.
+const maxGridCells = 400
+
+func validateGridInput(input map[string]any) error {
+	rows, ok := numberAsPositiveInt(input["rows"])
+	if !ok {
+		return fmt.Errorf("view.input.rows must be a positive integer for grid widget")
+	}
+	cols, ok := numberAsPositiveInt(input["cols"])
+	if !ok {
+		return fmt.Errorf("view.input.cols must be a positive integer for grid widget")
+	}
+	// Check each side first so the product cannot overflow.
+	if rows > maxGridCells || cols > maxGridCells || rows*cols > maxGridCells {
+		return fmt.Errorf("view.input grid size exceeds max cells (%d)", maxGridCells)
+	}
+	if rawSize, ok := input["cellSize"]; ok {
+		size, ok := rawSize.(string)
+		if !ok {
+			return fmt.Errorf("view.input.cellSize must be string for grid widget")
+		}
+		switch size {
+		case "", "small", "medium", "large":
+		default:
+			return fmt.Errorf("view.input.cellSize must be small, medium, or large for grid widget")
+		}
+	}
+
+	cellsV, ok := input["cells"]
+	if !ok {
+		return fmt.Errorf("view.input.cells is required for grid widget")
+	}
+	cells, ok := cellsV.([]any)
+	if !ok {
+		return fmt.Errorf("view.input.cells must be an array for grid widget")
+	}
+	if len(cells) != rows*cols {
+		return fmt.Errorf("view.input.cells length must equal rows*cols for grid widget")
+	}
+	for i, cellV := range cells {
+		cell, ok := cellV.(map[string]any)
+		if !ok {
+			return fmt.Errorf("view.input.cells[%d] must be an object for grid widget", i)
+		}
+		if v, ok := cell["value"]; ok {
+			if _, ok := v.(string); !ok {
+				return fmt.Errorf("view.input.cells[%d].value must be string", i)
+			}
+		}
+		if v, ok := cell["style"]; ok {
+			if _, ok := v.(string); !ok {
+				return fmt.Errorf("view.input.cells[%d].style must be string", i)
+			}
+		}
+		if v, ok := cell["disabled"]; ok {
+			if _, ok := v.(bool); !ok {
+				return fmt.Errorf("view.input.cells[%d].disabled must be boolean", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRatingInput(input map[string]any) error {
+	title, _ := input["title"].(string)
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("view.input.title is required for rating widget")
+	}
+
+	scale := 5
+	if rawScale, ok := input["scale"]; ok {
+		n, ok := AsInt(rawScale)
+		if !ok {
+			return fmt.Errorf("view.input.scale must be integer for rating widget")
+		}
+		if n < 2 || n > 10 {
+			return fmt.Errorf("view.input.scale must be between 2 and 10 for rating widget")
+		}
+		scale = n
+	}
+
+	if rawStyle, ok := input["style"]; ok {
+		style, ok := rawStyle.(string)
+		if !ok {
+			return fmt.Errorf("view.input.style must be string for rating widget")
+		}
+		switch strings.ToLower(strings.TrimSpace(style)) {
+		case "", "stars", "numbers", "emoji", "slider":
+		default:
+			return fmt.Errorf("view.input.style must be stars, numbers, emoji, or slider for rating widget")
+		}
+	}
+
+	if rawLabels, ok := input["labels"]; ok {
+		labels, ok := rawLabels.(map[string]any)
+		if !ok {
+			return fmt.Errorf("view.input.labels must be object for rating widget")
+		}
+		if low, ok := labels["low"]; ok {
+			if _, ok := low.(string); !ok {
+				return fmt.Errorf("view.input.labels.low must be string for rating widget")
+			}
+		}
+		if high, ok := labels["high"]; ok {
+			if _, ok := high.(string); !ok {
+				return fmt.Errorf("view.input.labels.high must be string for rating widget")
+			}
+		}
+	}
+
+	if rawDefault, ok := input["defaultValue"]; ok {
+		n, ok := AsInt(rawDefault)
+		if !ok {
+			return fmt.Errorf("view.input.defaultValue must be integer for rating widget")
+		}
+		if n < 1 || n > scale {
+			return fmt.Errorf("view.input.defaultValue must be between 1 and scale for rating widget")
+		}
+	}
+	return nil
+}
+
+func validateSelectInput(input map[string]any) error {
+	optionsV, ok := input["options"]
+	if !ok {
+		return nil
+	}
+	options, ok := optionsV.([]any)
+	if !ok {
+		return fmt.Errorf("view.input.options must be an array for select widget")
+	}
+	for i, option := range options {
+		if s, ok := option.(string); ok {
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("view.input.options[%d] must not be empty", i)
+			}
+			continue
+		}
+		optionMap, ok := option.(map[string]any)
+		if !ok {
+			return fmt.Errorf("view.input.options[%d] must be string or object", i)
+		}
+		value, _ := optionMap["value"].(string)
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("view.input.options[%d].value is required for object options", i)
+		}
+		if label, ok := optionMap["label"]; ok {
+			if _, ok := label.(string); !ok {
+				return fmt.Errorf("view.input.options[%d].label must be string", i)
+			}
+		}
+		if description, ok := optionMap["description"]; ok {
+			if _, ok := description.(string); !ok {
+				return fmt.Errorf("view.input.options[%d].description must be string", i)
+			}
+		}
+		if badge, ok := optionMap["badge"]; ok {
+			if _, ok := badge.(string); !ok {
+				return fmt.Errorf("view.input.options[%d].badge must be string", i)
+			}
+		}
+		if icon, ok := optionMap["icon"]; ok {
+			if _, ok := icon.(string); !ok {
+				return fmt.Errorf("view.input.options[%d].icon must be string", i)
+			}
+		}
+		if disabled, ok := optionMap["disabled"]; ok {
+			if _, ok := disabled.(bool); !ok {
+				return fmt.Errorf("view.input.options[%d].disabled must be boolean", i)
+			}
+		}
+	}
+	return nil
+}
+
+func validateDiffInput(input map[string]any) error {
+	filesV, ok := input["files"]
+	if !ok {
+		return fmt.Errorf("view.input.files is required for diff widget")
+	}
+	files, ok := filesV.([]any)
+	if !ok || len(files) == 0 {
+		return fmt.Errorf("view.input.files must be a non-empty array for diff widget")
+	}
+	if rawLayout, ok := input["layout"]; ok {
+		layout, ok := rawLayout.(string)
+		if !ok {
+			return fmt.Errorf("view.input.layout must be string for diff widget")
+		}
+		switch strings.ToLower(strings.TrimSpace(layout)) {
+		case "", "unified", "split":
+		default:
+			return fmt.Errorf("view.input.layout must be unified or split for diff widget")
+		}
+	}
+	if v, ok := input["requireAll"]; ok {
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("view.input.requireAll must be boolean for diff widget")
+		}
+	}
+
+	hunkIDs := map[string]bool{}
+	for i, fileV := range files {
+		file, ok := fileV.(map[string]any)
+		if !ok {
+			return fmt.Errorf("view.input.files[%d] must be an object for diff widget", i)
+		}
+		path, _ := file["path"].(string)
+		if strings.TrimSpace(path) == "" {
+			return fmt.Errorf("view.input.files[%d].path is required for diff widget", i)
+		}
+		for _, key := range []string{"oldPath", "language"} {
+			if v, ok := file[key]; ok {
+				if _, ok := v.(string); !ok {
+					return fmt.Errorf("view.input.files[%d].%s must be string", i, key)
+				}
+			}
+		}
+		hunks, ok := file["hunks"].([]any)
+		if !ok || len(hunks) == 0 {
+			return fmt.Errorf("view.input.files[%d].hunks must be a non-empty array for diff widget", i)
+		}
+		for j, hunkV := range hunks {
+			hunk, ok := hunkV.(map[string]any)
+			if !ok {
+				return fmt.Errorf("view.input.files[%d].hunks[%d] must be an object", i, j)
+			}
+			id, _ := hunk["id"].(string)
+			if strings.TrimSpace(id) == "" {
+				return fmt.Errorf("view.input.files[%d].hunks[%d].id is required", i, j)
+			}
+			if hunkIDs[id] {
+				return fmt.Errorf("view.input.files[%d].hunks[%d].id %q is not unique", i, j, id)
+			}
+			hunkIDs[id] = true
+			if v, ok := hunk["header"]; ok {
+				if _, ok := v.(string); !ok {
+					return fmt.Errorf("view.input.files[%d].hunks[%d].header must be string", i, j)
+				}
+			}
+			lines, ok := hunk["lines"].([]any)
+			if !ok {
+				return fmt.Errorf("view.input.files[%d].hunks[%d].lines must be an array", i, j)
+			}
+			for k, line := range lines {
+				if _, ok := line.(string); !ok {
+					return fmt.Errorf("view.input.files[%d].hunks[%d].lines[%d] must be string", i, j, k)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateKeyValueInput(input map[string]any) error {
+	itemsV, ok := input["items"]
+	if !ok {
+		return fmt.Errorf("view.input.items is required for keyvalue widget")
+	}
+	items, ok := itemsV.([]any)
+	if !ok || len(items) == 0 {
+		return fmt.Errorf("view.input.items must be a non-empty array for keyvalue widget")
+	}
+	for i, itemV := range items {
+		item, ok := itemV.(map[string]any)
+		if !ok {
+			return fmt.Errorf("view.input.items[%d] must be an object for keyvalue widget", i)
+		}
+		key, _ := item["key"].(string)
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("view.input.items[%d].key is required for keyvalue widget", i)
+		}
+		switch item["value"].(type) {
+		case string, bool, int, int32, int64, float32, float64:
+		default:
+			return fmt.Errorf("view.input.items[%d].value must be string, number, or boolean", i)
+		}
+		if rawStyle, ok := item["style"]; ok {
+			style, ok := rawStyle.(string)
+			if !ok {
+				return fmt.Errorf("view.input.items[%d].style must be string", i)
+			}
+			switch strings.ToLower(strings.TrimSpace(style)) {
+			case "", "default", "muted", "success", "warning", "danger":
+			default:
+				return fmt.Errorf("view.input.items[%d].style must be default, muted, success, warning, or danger", i)
+			}
+		}
+		if v, ok := item["monospace"]; ok {
+			if _, ok := v.(bool); !ok {
+				return fmt.Errorf("view.input.items[%d].monospace must be boolean", i)
+			}
+		}
+	}
+	return nil
+}
+
+func validateChecklistInput(input map[string]any) error {
+	itemsV, ok := input["items"]
+	if !ok {
+		return fmt.Errorf("view.input.items is required for checklist widget")
+	}
+	items, ok := itemsV.([]any)
+	if !ok || len(items) == 0 {
+		return fmt.Errorf("view.input.items must be a non-empty array for checklist widget")
+	}
+	ids := map[string]bool{}
+	for i, itemV := range items {
+		item, ok := itemV.(map[string]any)
+		if !ok {
+			return fmt.Errorf("view.input.items[%d] must be an object for checklist widget", i)
+		}
+		id, _ := item["id"].(string)
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("view.input.items[%d].id is required for checklist widget", i)
+		}
+		if ids[id] {
+			return fmt.Errorf("view.input.items[%d].id %q is not unique", i, id)
+		}
+		ids[id] = true
+		label, _ := item["label"].(string)
+		if strings.TrimSpace(label) == "" {
+			return fmt.Errorf("view.input.items[%d].label is required for checklist widget", i)
+		}
+		if v, ok := item["description"]; ok {
+			if _, ok := v.(string); !ok {
+				return fmt.Errorf("view.input.items[%d].description must be string", i)
+			}
+		}
+		for _, key := range []string{"required", "checked"} {
+			if v, ok := item[key]; ok {
+				if _, ok := v.(bool); !ok {
+					return fmt.Errorf("view.input.items[%d].%s must be boolean", i, key)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func numberAsPositiveInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, n > 0
+	case int32:
+		return int(n), n > 0
+	case int64:
+		return int(n), n > 0
+	case float32:
+		i := int(n)
+		return i, float32(i) == n && i > 0
+	case float64:
+		i := int(n)
+		return i, float64(i) == n && i > 0
+	default:
+		return 0, false
+	}
+}
+
+// AsInt returns v as an int when it is a whole number.
+func AsInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float32:
+		i := int(n)
+		return i, float32(i) == n
+	case float64:
+		i := int(n)
+		return i, float64(i) == n
+	default:
+		return 0, false
+	}
+}
+
+func numberAsInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		if uint64(n) > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		if n > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case float32:
+		f := float64(n)
+		if math.IsNaN(f) || math.IsInf(f, 0) || f < math.MinInt64 || f > math.MaxInt64 {
+			return 0, false
+		}
+		i := int64(f)
+		return i, float64(i) == f
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) || n < math.MinInt64 || n > math.MaxInt64 {
+			return 0, false
+		}
+		i := int64(n)
+		return i, float64(i) == n
+	default:
+		return 0, false
+	}
+}
+
+func mapProgress(raw any) (*v1.ScriptProgress, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	progressMap, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("view.progress must be object")
+	}
+	current, ok := numberAsInt64(progressMap["current"])
+	if !ok {
+		return nil, fmt.Errorf("view.progress.current is required and must be integer")
+	}
+	total, ok := numberAsInt64(progressMap["total"])
+	if !ok {
+		return nil, fmt.Errorf("view.progress.total is required and must be integer")
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("view.progress.total must be > 0")
+	}
+	if current < 0 {
+		return nil, fmt.Errorf("view.progress.current must be >= 0")
+	}
+	if current > total {
+		return nil, fmt.Errorf("view.progress.current must be <= total")
+	}
+	if current > math.MaxInt32 || total > math.MaxInt32 {
+		return nil, fmt.Errorf("view.progress values exceed int32 range")
+	}
+
+	progress := &v1.ScriptProgress{
+		Current: int32(current),
+		Total:   int32(total),
+	}
+	if label, ok := progressMap["label"].(string); ok && strings.TrimSpace(label) != "" {
+		progress.Label = &label
+	}
+	return progress, nil
+}
+
+func mapToast(raw any) (*v1.ScriptToast, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	toastMap, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("view.toast must be object")
+	}
+	message, _ := toastMap["message"].(string)
+	if strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("view.toast.message is required")
+	}
+
+	toast := &v1.ScriptToast{
+		Message: message,
+	}
+	if rawDuration, ok := toastMap["duration"]; ok {
+		d, ok := AsInt(rawDuration)
+		if !ok {
+			return nil, fmt.Errorf("view.toast.duration must be integer")
+		}
+		if d <= 0 || d > 30000 {
+			return nil, fmt.Errorf("view.toast.duration must be between 1 and 30000")
+		}
+		duration := int32(d)
+		toast.DurationMs = &duration
+	}
+	if rawDuration, ok := toastMap["durationMs"]; ok {
+		d, ok := AsInt(rawDuration)
+		if !ok {
+			return nil, fmt.Errorf("view.toast.durationMs must be integer")
+		}
+		if d <= 0 || d > 30000 {
+			return nil, fmt.Errorf("view.toast.durationMs must be between 1 and 30000")
+		}
+		duration := int32(d)
+		toast.DurationMs = &duration
+	}
+	if rawStyle, ok := toastMap["style"]; ok {
+		style, ok := rawStyle.(string)
+		if !ok {
+			return nil, fmt.Errorf("view.toast.style must be string")
+		}
+		switch strings.ToLower(strings.TrimSpace(style)) {
+		case "", "info", "success", "warning", "error":
+		default:
+			return nil, fmt.Errorf("view.toast.style must be info, success, warning, or error")
+		}
+		if strings.TrimSpace(style) != "" {
+			toast.Style = &style
+		}
+	}
+	return toast, nil
+}
+
+func toStruct(m map[string]any) (*structpb.Struct, error) {
+	if m == nil {
+		m = map[string]any{}
+	}
+	return structpb.NewStruct(m)
+}
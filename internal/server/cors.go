@@ -7,7 +7,7 @@ func withCORS(next http.Handler) http.Handler {
 		// Minimal permissive CORS, matching the existing Node demo posture.
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,Idempotency-Key")
 		w.Header().Set("X-Content-Type-Options", "nosniff")
 
 		if r.Method == http.MethodOptions {
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// requestSettled runs the follow-up work for req reaching a terminal state:
+// its warm script runtime and pending tick are dropped and affected
+// dependents are announced.
+func (s *Server) requestSettled(ctx context.Context, req *v1.UIRequest) {
+	s.scripts.Release(req.Id)
+	s.ticks.cancel(req.Id)
+	s.broadcastSettledDependents(ctx, req)
+}
+
+// broadcastSettledDependents notifies UIs about requests affected by req
+// reaching a terminal state: newly unblocked dependents are announced as
+// new_request, and dependents cancelled by a failed prerequisite as
+// request_completed.
+func (s *Server) broadcastSettledDependents(ctx context.Context, req *v1.UIRequest) {
+	unblocked, cancelled := s.store.SettledDependents(ctx, req.Id)
+	for _, dep := range cancelled {
+		s.scripts.Release(dep.Id)
+		s.ticks.cancel(dep.Id)
+		if msg, err := marshalWSEvent("request_completed", dep); err == nil {
+			s.ws.BroadcastRawJSON(dep.SessionId, msg)
+		} else {
+			log.Printf("[WS] marshal request_completed (dependency failed) failed: %v", err)
+		}
+	}
+	for _, dep := range unblocked {
+		if msg, err := marshalWSEvent("new_request", dep); err == nil {
+			s.ws.BroadcastRawJSON(dep.SessionId, msg)
+		} else {
+			log.Printf("[WS] marshal new_request (unblocked) failed: %v", err)
+		}
+	}
+}
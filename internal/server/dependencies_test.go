@@ -0,0 +1,178 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+func TestDependentRequestsAreHiddenUntilPrerequisitesComplete(t *testing.T) {
+	s := New(store.New())
+	h := s.Handler()
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	deploy := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_confirm,
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "deploy?"},
+		},
+	})
+	region := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_select,
+		DependsOn: []string{deploy.Id},
+		Input: &v1.UIRequest_SelectInput{
+			SelectInput: &v1.SelectInput{Title: "which region?", Options: []string{"eu", "us"}},
+		},
+	})
+	notify := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		DependsOn: []string{region.Id},
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "notify on-call?"},
+		},
+	})
+
+	conn := dialWS(t, ts.URL, "global")
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// Only the prerequisite is replayed; dependents stay hidden.
+	eventType, eventReq := readWSEvent(t, conn)
+	if eventType != "new_request" || eventReq.Id != deploy.Id {
+		t.Fatalf("expected replay of %s only, got %q for %s", deploy.Id, eventType, eventReq.Id)
+	}
+	if pending := s.store.Pending(t.Context()); len(pending) != 1 {
+		t.Fatalf("expected 1 visible pending request, got %d", len(pending))
+	}
+
+	body := strings.NewReader(`{"selectOutput":{"selectedSingle":"eu"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/requests/"+region.Id+"/response", body)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for blocked request, got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	postResponse(t, h, deploy.Id, &v1.UIRequest{
+		Output: &v1.UIRequest_ConfirmOutput{ConfirmOutput: &v1.ConfirmOutput{Approved: true}},
+	})
+	if eventType, eventReq = readWSEvent(t, conn); eventType != "request_completed" || eventReq.Id != deploy.Id {
+		t.Fatalf("expected request_completed for deploy, got %q for %s", eventType, eventReq.Id)
+	}
+	if eventType, eventReq = readWSEvent(t, conn); eventType != "new_request" || eventReq.Id != region.Id {
+		t.Fatalf("expected new_request for unblocked region, got %q for %s", eventType, eventReq.Id)
+	}
+	if blocked, err := s.store.Blocked(t.Context(), notify.Id); err != nil || !blocked {
+		t.Fatalf("expected notify to wait for region, got blocked=%v err=%v", blocked, err)
+	}
+}
+
+func TestClosedSessionCancelsDependentsInOtherSessions(t *testing.T) {
+	s := New(store.New())
+	h := s.Handler()
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	region := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_select,
+		SessionId: "deploy-7",
+		Input: &v1.UIRequest_SelectInput{
+			SelectInput: &v1.SelectInput{Title: "which region?", Options: []string{"eu", "us"}},
+		},
+	})
+	notify := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		DependsOn: []string{region.Id},
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "notify on-call?"},
+		},
+	})
+
+	conn := dialWS(t, ts.URL, "global")
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// Cancelling the prerequisite cancels its dependent, announced in the
+	// dependent's own session.
+	doSessionCall(t, h, http.MethodPost, "/api/sessions/deploy-7/close", nil, http.StatusOK, nil)
+	eventType, eventReq := readWSEvent(t, conn)
+	if eventType != "request_completed" || eventReq.Id != notify.Id {
+		t.Fatalf("expected request_completed for the dependent, got %q for %s", eventType, eventReq.Id)
+	}
+	if eventReq.GetStatus() != v1.RequestStatus_cancelled || eventReq.GetError() != store.DependencyFailedReason {
+		t.Fatalf("expected dependent to be cancelled with %s, got status=%v error=%q", store.DependencyFailedReason, eventReq.GetStatus(), eventReq.GetError())
+	}
+}
+
+func TestRejectedPrerequisiteCancelsDependents(t *testing.T) {
+	s := New(store.New())
+	h := s.Handler()
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	deploy := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_confirm,
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "deploy?"},
+		},
+	})
+	region := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_select,
+		DependsOn: []string{deploy.Id},
+		Input: &v1.UIRequest_SelectInput{
+			SelectInput: &v1.SelectInput{Title: "which region?", Options: []string{"eu", "us"}},
+		},
+	})
+
+	conn := dialWS(t, ts.URL, "global")
+	defer func() {
+		_ = conn.Close()
+	}()
+	if eventType, _ := readWSEvent(t, conn); eventType != "new_request" {
+		t.Fatalf("expected initial new_request, got %q", eventType)
+	}
+
+	postResponse(t, h, deploy.Id, &v1.UIRequest{
+		Output: &v1.UIRequest_ConfirmOutput{ConfirmOutput: &v1.ConfirmOutput{Approved: false}},
+	})
+	if eventType, eventReq := readWSEvent(t, conn); eventType != "request_completed" || eventReq.Id != deploy.Id {
+		t.Fatalf("expected request_completed for deploy, got %q for %s", eventType, eventReq.Id)
+	}
+	eventType, eventReq := readWSEvent(t, conn)
+	if eventType != "request_completed" || eventReq.Id != region.Id {
+		t.Fatalf("expected request_completed for cancelled dependent, got %q for %s", eventType, eventReq.Id)
+	}
+	if eventReq.Status != v1.RequestStatus_cancelled {
+		t.Fatalf("expected cancelled dependent, got %v", eventReq.Status)
+	}
+
+	// New requests depending on a rejected prerequisite are cancelled on arrival.
+	late := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		DependsOn: []string{deploy.Id},
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "too late"},
+		},
+	})
+	if late.GetStatus() != v1.RequestStatus_cancelled {
+		t.Fatalf("expected late dependent to be cancelled on create, got %v", late.GetStatus())
+	}
+
+	body := strings.NewReader(`{"type":"confirm","dependsOn":["missing"],"confirmInput":{"title":"x"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/requests", body)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown dependency, got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestCreateRequestIsIdempotentPerKey(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	confirm := &v1.UIRequest{
+		Type: v1.WidgetType_confirm,
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "deploy?"},
+		},
+	}
+
+	first, code := postWithIdempotencyKey(t, h, "key-1", confirm)
+	if code != http.StatusCreated {
+		t.Fatalf("expected 201 on first create, got %d", code)
+	}
+	replay, code := postWithIdempotencyKey(t, h, "key-1", confirm)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200 on replay, got %d", code)
+	}
+	if replay.Id != first.Id {
+		t.Fatalf("expected replay to return %s, got %s", first.Id, replay.Id)
+	}
+	if n := len(s.store.Pending(t.Context())); n != 1 {
+		t.Fatalf("expected a single pending request, got %d", n)
+	}
+
+	other, code := postWithIdempotencyKey(t, h, "key-2", confirm)
+	if code != http.StatusCreated || other.Id == first.Id {
+		t.Fatalf("expected a new request for a different key, got status=%d id=%s", code, other.Id)
+	}
+
+	_, code = postWithIdempotencyKey(t, h, "key-1", &v1.UIRequest{
+		Type: v1.WidgetType_select,
+		Input: &v1.UIRequest_SelectInput{
+			SelectInput: &v1.SelectInput{Title: "which?", Options: []string{"a"}},
+		},
+	})
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 when reusing a key for a different request, got %d", code)
+	}
+
+	withField := &v1.UIRequest{
+		Type:           v1.WidgetType_confirm,
+		IdempotencyKey: toPtr("key-3"),
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "field key"},
+		},
+	}
+	_, code = postWithIdempotencyKey(t, h, "key-4", withField)
+	if code != http.StatusBadRequest {
+		t.Fatalf("expected 400 when header and field keys differ, got %d", code)
+	}
+	fromField, code := postWithIdempotencyKey(t, h, "", withField)
+	if code != http.StatusCreated {
+		t.Fatalf("expected 201 for field key, got %d", code)
+	}
+	if again, _ := postWithIdempotencyKey(t, h, "", withField); again.Id != fromField.Id {
+		t.Fatalf("expected field key replay to return %s, got %s", fromField.Id, again.Id)
+	}
+}
+
+func postWithIdempotencyKey(t *testing.T, h http.Handler, key string, reqProto *v1.UIRequest) (*v1.UIRequest, int) {
+	t.Helper()
+
+	body, err := protojson.Marshal(reqProto)
+	if err != nil {
+		t.Fatalf("marshal UIRequest: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/requests", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	out := &v1.UIRequest{}
+	if rr.Code < 300 {
+		if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+			t.Fatalf("unmarshal create response: %v body=%s", err, rr.Body.String())
+		}
+	}
+	return out, rr.Code
+}
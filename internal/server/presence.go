@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// sessionActivityTTL is how long the last activity of a session without
+// viewers is remembered before the idle sweep drops it.
+const sessionActivityTTL = 24 * time.Hour
+
+func (c *wsClient) markActivity(now time.Time) {
+	c.lastActivity.Store(now.UTC().UnixNano())
+}
+
+// markActivity records viewer activity for a session. It is called for WS
+// traffic as well as for UI-driven HTTP calls (touch, response, event), since
+// the browser rarely sends anything over the socket itself.
+func (b *wsBroadcaster) markActivity(sessionID string, now time.Time) {
+	if sessionID == "" {
+		sessionID = "global"
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if prev, ok := b.lastActivityBySession[sessionID]; ok && prev.After(now) {
+		return
+	}
+	b.lastActivityBySession[sessionID] = now.UTC()
+}
+
+// forgetSession drops the remembered activity of a closed session.
+func (b *wsBroadcaster) forgetSession(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.lastActivityBySession, sessionID)
+}
+
+// expireActivity drops the activity of sessions that have had no viewer
+// since before cutoff, so ad-hoc session ids do not accumulate forever.
+func (b *wsBroadcaster) expireActivity(cutoff time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sessionID, last := range b.lastActivityBySession {
+		if len(b.clientsBySession[sessionID]) == 0 && last.Before(cutoff) {
+			delete(b.lastActivityBySession, sessionID)
+		}
+	}
+}
+
+func (b *wsBroadcaster) presence(sessionID string) *v1.SessionPresence {
+	if sessionID == "" {
+		sessionID = "global"
+	}
+
+	b.mu.Lock()
+	clients := make([]*wsClient, 0, len(b.clientsBySession[sessionID]))
+	for c := range b.clientsBySession[sessionID] {
+		clients = append(clients, c)
+	}
+	lastActivity, hasActivity := b.lastActivityBySession[sessionID]
+	b.mu.Unlock()
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].connectedAt.Before(clients[j].connectedAt)
+	})
+
+	out := &v1.SessionPresence{
+		SessionId:   sessionID,
+		Connected:   len(clients) > 0,
+		ViewerCount: int32(len(clients)), // #nosec G115 -- bounded by open sockets.
+		Viewers:     make([]*v1.SessionViewer, 0, len(clients)),
+	}
+	for _, c := range clients {
+		clientActivity := time.Unix(0, c.lastActivity.Load()).UTC()
+		if clientActivity.After(lastActivity) {
+			lastActivity = clientActivity
+			hasActivity = true
+		}
+		out.Viewers = append(out.Viewers, &v1.SessionViewer{
+			ConnectedAt:    c.connectedAt.Format(time.RFC3339Nano),
+			LastActivityAt: clientActivity.Format(time.RFC3339Nano),
+		})
+	}
+	if hasActivity {
+		ts := lastActivity.Format(time.RFC3339Nano)
+		out.LastActivityAt = &ts
+	}
+	return out
+}
+
+func (s *Server) handleSessionPresence(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeProtoJSON(w, http.StatusOK, s.ws.presence(sessionID))
+}
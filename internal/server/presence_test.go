@@ -0,0 +1,120 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestPresenceTracksConnectedViewers(t *testing.T) {
+	s := New(store.New())
+	h := s.Handler()
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	before := getPresence(t, h, "presence-a")
+	if before.GetConnected() || before.GetViewerCount() != 0 {
+		t.Fatalf("expected no viewers before connect, got %+v", before)
+	}
+	if before.LastActivityAt != nil {
+		t.Fatalf("expected no last activity for unseen session, got %q", before.GetLastActivityAt())
+	}
+
+	conn := dialWS(t, ts.URL, "presence-a")
+	waitForViewerCount(t, h, "presence-a", 1)
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		SessionId: "presence-a",
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "anyone there?"},
+		},
+	})
+	if created.GetPresence() == nil {
+		t.Fatalf("expected presence on create response")
+	}
+	if !created.GetPresence().GetConnected() || created.GetPresence().GetViewerCount() != 1 {
+		t.Fatalf("unexpected presence on create response: %+v", created.GetPresence())
+	}
+	if stored := getRequest(t, h, created.Id); stored.GetPresence() != nil {
+		t.Fatalf("presence must not be persisted on the stored request")
+	}
+
+	other := getPresence(t, h, "presence-b")
+	if other.GetConnected() {
+		t.Fatalf("expected presence to be scoped per session, got %+v", other)
+	}
+
+	_ = conn.Close()
+	after := waitForViewerCount(t, h, "presence-a", 0)
+	if after.GetConnected() {
+		t.Fatalf("expected disconnected presence after close, got %+v", after)
+	}
+	if after.LastActivityAt == nil {
+		t.Fatalf("expected last activity to survive disconnect")
+	}
+}
+
+func TestSessionActivityIsForgotten(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	now := time.Now()
+	doSessionCall(t, h, http.MethodPost, "/api/sessions", &v1.Session{Id: "closing"}, http.StatusCreated, nil)
+	s.ws.markActivity("closing", now)
+	s.ws.markActivity("stale", now.Add(-2*sessionActivityTTL))
+	s.ws.markActivity("recent", now)
+
+	doSessionCall(t, h, http.MethodPost, "/api/sessions/closing/close", nil, http.StatusOK, nil)
+	if got := getPresence(t, h, "closing"); got.LastActivityAt != nil {
+		t.Fatalf("expected closing a session to forget its activity, got %q", got.GetLastActivityAt())
+	}
+
+	s.ws.expireActivity(now.Add(-sessionActivityTTL))
+	if got := getPresence(t, h, "stale"); got.LastActivityAt != nil {
+		t.Fatalf("expected the sweep to drop stale activity, got %q", got.GetLastActivityAt())
+	}
+	if got := getPresence(t, h, "recent"); got.LastActivityAt == nil {
+		t.Fatalf("expected the sweep to keep recent activity")
+	}
+}
+
+func getPresence(t *testing.T, h http.Handler, sessionID string) *v1.SessionPresence {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sessions/"+sessionID+"/presence", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("presence request failed status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	out := &v1.SessionPresence{}
+	if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+		t.Fatalf("unmarshal presence response: %v body=%s", err, rr.Body.String())
+	}
+	return out
+}
+
+func waitForViewerCount(t *testing.T, h http.Handler, sessionID string, want int32) *v1.SessionPresence {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		p := getPresence(t, h, sessionID)
+		if p.GetViewerCount() == want {
+			return p
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d viewers for session %q, got %d", want, sessionID, p.GetViewerCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -7,11 +7,14 @@ import (
 	stderrors "errors"
 	"fmt"
 	"io"
+	"log"
 	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
 	"github.com/go-go-golems/plz-confirm/internal/store"
 	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -85,48 +88,140 @@ func scriptInputWithSeed(in *v1.ScriptInput, seed int64) (*v1.ScriptInput, error
 	return clone, nil
 }
 
+// Script event sources, recorded on ScriptEvent.source and visible to
+// update() as event.source.
+const (
+	scriptEventSourceUI       = "ui"
+	scriptEventSourceAgent    = "agent"
+	scriptEventSourceSchedule = "schedule"
+)
+
 func (s *Server) handleScriptEvent(w http.ResponseWriter, r *http.Request, id string) {
+	event, ok := readScriptEvent(w, r)
+	if !ok {
+		return
+	}
+	event.Source = scriptEventSourceUI
+
 	// Serialize script event processing per request ID to avoid lost updates
 	// from concurrent read-modify-write cycles.
 	unlock := s.scriptEventLocks.Lock(id)
 	defer unlock()
 
-	existingReq, err := s.store.Get(r.Context(), id)
+	req, err := s.applyScriptEvent(r.Context(), id, event, r.Header.Get("Accept-Language"))
 	if err != nil {
-		if stderrors.Is(err, store.ErrNotFound) {
-			http.Error(w, "request not found", http.StatusNotFound)
-			return
-		}
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		writeScriptEventError(w, err)
 		return
 	}
-	if existingReq.Type != v1.WidgetType_script {
-		http.Error(w, "request is not script widget", http.StatusBadRequest)
-		return
-	}
-	if existingReq.Status != v1.RequestStatus_pending {
-		http.Error(w, "request already completed", http.StatusConflict)
+	s.ws.markActivity(req.SessionId, time.Now().UTC())
+	writeProtoJSON(w, http.StatusOK, req)
+}
+
+// handleScriptInject delivers an agent-side event into a running script. It
+// takes the same path as browser events, but the script sees
+// event.source === "agent" and the session's presence is left untouched.
+func (s *Server) handleScriptInject(w http.ResponseWriter, r *http.Request, id string) {
+	event, ok := readScriptEvent(w, r)
+	if !ok {
 		return
 	}
-	if existingReq.GetScriptInput() == nil {
-		http.Error(w, "missing script input", http.StatusBadRequest)
+	event.Source = scriptEventSourceAgent
+
+	unlock := s.scriptEventLocks.Lock(id)
+	defer unlock()
+
+	// The agent is not the responder, so its languages are ignored.
+	req, err := s.applyScriptEvent(r.Context(), id, event, "")
+	if err != nil {
+		writeScriptEventError(w, err)
 		return
 	}
+	// #nosec G706 -- req.Id is server-generated and quoted; the event type is quoted for log safety.
+	log.Printf("[API] Injected %q event into script request %q", event.GetType(), req.Id)
+	writeProtoJSON(w, http.StatusOK, req)
+}
 
+func readScriptEvent(w http.ResponseWriter, r *http.Request) (*v1.ScriptEvent, bool) {
 	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
 	if err != nil {
 		http.Error(w, "failed to read request body", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 	event := &v1.ScriptEvent{}
 	if err := protojson.Unmarshal(bodyBytes, event); err != nil {
 		http.Error(w, "invalid protojson ScriptEvent: "+err.Error(), http.StatusBadRequest)
-		return
+		return nil, false
 	}
 	if strings.TrimSpace(event.GetType()) == "" {
 		http.Error(w, "script event type is required", http.StatusBadRequest)
+		return nil, false
+	}
+	return event, true
+}
+
+// scriptEventError is a failed script event with the HTTP status it maps to.
+// sections, when set, is written as the response body instead of msg.
+type scriptEventError struct {
+	status   int
+	msg      string
+	sections *v1.ScriptSectionErrors
+}
+
+func (e *scriptEventError) Error() string { return e.msg }
+
+func eventErrorf(status int, format string, args ...any) error {
+	return &scriptEventError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+func writeScriptEventError(w http.ResponseWriter, err error) {
+	var evErr *scriptEventError
+	if stderrors.As(err, &evErr) {
+		if evErr.sections != nil {
+			writeProtoJSON(w, evErr.status, evErr.sections)
+			return
+		}
+		http.Error(w, evErr.msg, evErr.status)
 		return
 	}
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+// applyScriptEvent runs event through the script of pending request id,
+// stores and broadcasts the result, and (re)schedules the next tick. Callers
+// must hold the request's scriptEventLocks entry.
+func (s *Server) applyScriptEvent(ctx context.Context, id string, event *v1.ScriptEvent, acceptLanguage string) (*v1.UIRequest, error) {
+	existingReq, err := s.store.Get(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, store.ErrNotFound) {
+			return nil, eventErrorf(http.StatusNotFound, "request not found")
+		}
+		return nil, eventErrorf(http.StatusInternalServerError, "internal error")
+	}
+	if existingReq.Type != v1.WidgetType_script {
+		return nil, eventErrorf(http.StatusBadRequest, "request is not script widget")
+	}
+	if existingReq.Status != v1.RequestStatus_pending {
+		return nil, eventErrorf(http.StatusConflict, "request already completed")
+	}
+	if existingReq.GetScriptInput() == nil {
+		return nil, eventErrorf(http.StatusBadRequest, "missing script input")
+	}
+	if handlesScriptBack(existingReq, event) {
+		req, handled, err := s.applyScriptBack(ctx, existingReq, event, acceptLanguage)
+		if handled {
+			return req, err
+		}
+	}
+	if checksSectionAnswers(existingReq, event) {
+		if errs := validateSectionAnswers(existingReq.GetScriptView(), event); errs != nil {
+			return nil, &scriptEventError{status: http.StatusUnprocessableEntity, msg: errs.GetMessage(), sections: errs}
+		}
+	}
+	if checksWidgetAnswer(existingReq, event) {
+		if msg := validateWidgetAnswer(existingReq.GetScriptView(), event); msg != "" {
+			return nil, eventErrorf(http.StatusUnprocessableEntity, "%s", msg)
+		}
+	}
 
 	state := map[string]any{}
 	if existingReq.GetScriptState() != nil {
@@ -137,29 +232,48 @@ func (s *Server) handleScriptEvent(w http.ResponseWriter, r *http.Request, id st
 		var err error
 		seed, err = newScriptSeed()
 		if err != nil {
-			http.Error(w, "failed to allocate script seed", http.StatusInternalServerError)
-			return
+			return nil, eventErrorf(http.StatusInternalServerError, "failed to allocate script seed")
 		}
 	}
 	state = ensureSeedInState(state, seed)
 	eventMap := eventToMap(event)
 	seededInput, err := scriptInputWithSeed(existingReq.GetScriptInput(), seed)
 	if err != nil {
-		http.Error(w, "invalid script input: "+err.Error(), http.StatusBadRequest)
-		return
+		return nil, eventErrorf(http.StatusBadRequest, "invalid script input: %v", err)
 	}
 
-	updateResult, err := s.scripts.UpdateAndView(r.Context(), seededInput, state, eventMap)
+	runnableInput, err := s.runnableScriptInput(ctx, seededInput)
 	if err != nil {
-		http.Error(w, "script update failed: "+err.Error(), statusForScriptError(err))
-		return
+		return nil, eventErrorf(http.StatusBadRequest, "invalid script input: %v", err)
+	}
+
+	// A cold runtime mutates state in place, so diff against a fresh copy.
+	before := existingReq.GetScriptState().AsMap()
+	started := time.Now()
+	updateResult, err := s.scripts.UpdateAndView(ctx, runnableInput, state, eventMap,
+		scriptengine.WithGrants(grantsFromDescribe(existingReq.GetScriptDescribe())...),
+		scriptengine.WithRuntimeKey(id),
+		scriptengine.WithNow(started),
+		scriptengine.WithOutputSchema(outputSchemaFromDescribe(existingReq.GetScriptDescribe())),
+		scriptengine.WithLocale(scriptLocalePreference(existingReq, acceptLanguage)),
+		scriptengine.WithMessages(messagesFromDescribe(existingReq.GetScriptDescribe())),
+	)
+	if err != nil {
+		s.keepFailedRunGrants(ctx, id, err)
+		entry := newScriptHistoryEntry(event, started, scriptengine.FailedRunLogs(err))
+		msg := err.Error()
+		entry.Error = &msg
+		s.recordScriptHistory(ctx, id, entry)
+		return nil, eventErrorf(statusForScriptError(err), "script update failed: %v", err)
 	}
+	entry := newScriptHistoryEntry(event, started, updateResult.Logs)
+	entry.Locale = &updateResult.Locale
 
 	if updateResult.Done {
 		resultStruct, err := mapToStruct(updateResult.Result)
 		if err != nil {
-			http.Error(w, "invalid script result: "+err.Error(), http.StatusBadRequest)
-			return
+			s.keepScriptGrants(ctx, id, updateResult.Grants)
+			return nil, eventErrorf(http.StatusBadRequest, "invalid script result: %v", err)
 		}
 		outputReq := &v1.UIRequest{
 			Type:       v1.WidgetType_script,
@@ -171,54 +285,65 @@ func (s *Server) handleScriptEvent(w http.ResponseWriter, r *http.Request, id st
 				},
 			},
 		}
-		req, err := s.store.Complete(r.Context(), id, outputReq)
+		req, err := s.store.Complete(ctx, id, outputReq)
 		if err != nil {
 			if stderrors.Is(err, store.ErrNotFound) {
-				http.Error(w, "request not found", http.StatusNotFound)
-				return
+				return nil, eventErrorf(http.StatusNotFound, "request not found")
 			}
 			if stderrors.Is(err, store.ErrAlreadyCompleted) {
-				http.Error(w, "request already completed", http.StatusConflict)
-				return
+				return nil, eventErrorf(http.StatusConflict, "request already completed")
 			}
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
+			if stderrors.Is(err, store.ErrBlocked) {
+				return nil, eventErrorf(http.StatusConflict, "request blocked by dependencies")
+			}
+			return nil, eventErrorf(http.StatusInternalServerError, "internal error")
 		}
 
+		entry.Done = true
+		s.recordScriptHistory(ctx, id, entry)
+
 		if msg, err := marshalWSEvent("request_completed", req); err == nil {
 			s.ws.BroadcastRawJSON(req.SessionId, msg)
 		}
-
-		writeProtoJSON(w, http.StatusOK, req)
-		return
+		s.requestSettled(ctx, req)
+		return req, nil
 	}
 	updateResult.State = ensureSeedInState(updateResult.State, seed)
 
 	stateStruct, viewProto, err := scriptUpdateResultToProto(updateResult)
 	if err != nil {
-		http.Error(w, "invalid script update result: "+err.Error(), http.StatusBadRequest)
-		return
+		s.scripts.Release(id)
+		s.keepScriptGrants(ctx, id, updateResult.Grants)
+		msg := "invalid script update result: " + err.Error()
+		entry.Error = &msg
+		s.recordScriptHistory(ctx, id, entry)
+		return nil, eventErrorf(http.StatusBadRequest, "invalid script update result: %v", err)
 	}
 
-	req, err := s.store.PatchScript(r.Context(), id, stateStruct, viewProto, updateResult.Logs)
+	req, err := s.store.PatchScript(ctx, id, stateStruct, viewProto, updateResult.Logs, grantsToProto(updateResult.Grants), updateResult.Locale)
 	if err != nil {
+		// The warm runtime already advanced past the stored state.
+		s.scripts.Release(id)
 		if stderrors.Is(err, store.ErrNotFound) {
-			http.Error(w, "request not found", http.StatusNotFound)
-			return
+			return nil, eventErrorf(http.StatusNotFound, "request not found")
 		}
 		if stderrors.Is(err, store.ErrAlreadyCompleted) {
-			http.Error(w, "request already completed", http.StatusConflict)
-			return
+			return nil, eventErrorf(http.StatusConflict, "request already completed")
 		}
-		http.Error(w, "internal error", http.StatusInternalServerError)
-		return
+		return nil, eventErrorf(http.StatusInternalServerError, "internal error")
 	}
+	if snapshot, err := mapToStruct(ensureSeedInState(existingReq.GetScriptState().AsMap(), seed)); err == nil {
+		s.pushScriptSnapshot(ctx, id, event, snapshot, stateStruct)
+	}
+	s.scheduleScriptTick(id, updateResult.Schedule)
+	entry.Changes = scriptStateDiff(before, stateStruct.AsMap())
+	setScriptHistoryView(entry, viewProto)
+	s.recordScriptHistory(ctx, id, entry)
 
 	if msg, err := marshalWSEvent("request_updated", req); err == nil {
 		s.ws.BroadcastRawJSON(req.SessionId, msg)
 	}
-
-	writeProtoJSON(w, http.StatusOK, req)
+	return req, nil
 }
 
 func scriptInitResultToProto(res *scriptengine.InitAndViewResult) (*structpb.Struct, *v1.ScriptView, *v1.ScriptDescribe, error) {
@@ -229,7 +354,7 @@ func scriptInitResultToProto(res *scriptengine.InitAndViewResult) (*structpb.Str
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("state: %w", err)
 	}
-	viewProto, err := mapToScriptView(res.View)
+	viewProto, err := scriptview.FromMap(res.View)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("view: %w", err)
 	}
@@ -237,6 +362,14 @@ func scriptInitResultToProto(res *scriptengine.InitAndViewResult) (*structpb.Str
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("describe: %w", err)
 	}
+	describeProto.Grants = grantsToProto(res.Grants)
+	for _, m := range res.Modules {
+		describeProto.Modules = append(describeProto.Modules, &v1.ScriptModule{
+			Name:    m.Name,
+			Version: m.Version,
+			Sha256:  m.SHA256,
+		})
+	}
 	return stateStruct, viewProto, describeProto, nil
 }
 
@@ -248,560 +381,94 @@ func scriptUpdateResultToProto(res *scriptengine.UpdateAndViewResult) (*structpb
 	if err != nil {
 		return nil, nil, fmt.Errorf("state: %w", err)
 	}
-	viewProto, err := mapToScriptView(res.View)
+	viewProto, err := scriptview.FromMap(res.View)
 	if err != nil {
 		return nil, nil, fmt.Errorf("view: %w", err)
 	}
 	return stateStruct, viewProto, nil
 }
 
-func eventToMap(ev *v1.ScriptEvent) map[string]any {
-	m := map[string]any{"type": ev.GetType()}
-	if ev.GetStepId() != "" {
-		m["stepId"] = ev.GetStepId()
-	}
-	if ev.GetActionId() != "" {
-		m["actionId"] = ev.GetActionId()
-	}
-	if ev.GetData() != nil {
-		m["data"] = ev.GetData().AsMap()
-	}
-	return m
-}
-
-func mapToStruct(m map[string]any) (*structpb.Struct, error) {
-	if m == nil {
-		m = map[string]any{}
-	}
-	return structpb.NewStruct(m)
-}
-
-func mapToScriptView(m map[string]any) (*v1.ScriptView, error) {
-	if m == nil {
-		return nil, fmt.Errorf("view must be object")
-	}
-	widgetType, _ := m["widgetType"].(string)
-	inputMap := map[string]any{}
-	hasTopLevelInput := false
-	if raw, ok := m["input"]; ok {
-		hasTopLevelInput = true
-		typed, ok := raw.(map[string]any)
-		if !ok {
-			return nil, fmt.Errorf("view.input must be object")
-		}
-		inputMap = typed
-	}
-
-	sections, parsedSections, err := mapToScriptViewSections(m["sections"])
-	if err != nil {
-		return nil, err
-	}
-	if len(parsedSections) > 0 {
-		interactive, err := selectInteractiveSection(parsedSections)
-		if err != nil {
-			return nil, err
-		}
-		if strings.TrimSpace(widgetType) == "" {
-			widgetType = interactive.widgetType
-		} else if !strings.EqualFold(strings.TrimSpace(widgetType), interactive.widgetType) {
-			return nil, fmt.Errorf("view.widgetType must match the interactive section widgetType")
-		}
-		if !hasTopLevelInput {
-			inputMap = interactive.input
-		}
-	}
-	if strings.TrimSpace(widgetType) == "" {
-		return nil, fmt.Errorf("view.widgetType is required")
-	}
-	if err := validateScriptViewInput(widgetType, inputMap); err != nil {
-		return nil, err
-	}
-	inputStruct, err := mapToStruct(inputMap)
-	if err != nil {
-		return nil, err
-	}
-	view := &v1.ScriptView{
-		WidgetType: widgetType,
-		Input:      inputStruct,
-		Sections:   sections,
-	}
-	if stepID, ok := m["stepId"].(string); ok && strings.TrimSpace(stepID) != "" {
-		view.StepId = &stepID
-	}
-	if title, ok := m["title"].(string); ok && strings.TrimSpace(title) != "" {
-		view.Title = &title
-	}
-	if description, ok := m["description"].(string); ok && strings.TrimSpace(description) != "" {
-		view.Description = &description
-	}
-	progress, err := mapToScriptProgress(m["progress"])
-	if err != nil {
-		return nil, err
-	}
-	if progress != nil {
-		view.Progress = progress
-	}
-	if allowBack, ok := m["allowBack"].(bool); ok {
-		view.AllowBack = &allowBack
-	} else if showBack, ok := m["showBack"].(bool); ok {
-		view.AllowBack = &showBack
-	}
-	if backLabel, ok := m["backLabel"].(string); ok && strings.TrimSpace(backLabel) != "" {
-		view.BackLabel = &backLabel
-	}
-	toastCfg, err := mapToScriptToast(m["toast"])
-	if err != nil {
-		return nil, err
-	}
-	if toastCfg != nil {
-		view.Toast = toastCfg
-	}
-	return view, nil
-}
-
-type parsedScriptViewSection struct {
-	widgetType string
-	input      map[string]any
-}
-
-func mapToScriptViewSections(raw any) ([]*v1.ScriptViewSection, []parsedScriptViewSection, error) {
-	if raw == nil {
-		return nil, nil, nil
-	}
-	items, ok := raw.([]any)
-	if !ok {
-		return nil, nil, fmt.Errorf("view.sections must be an array")
-	}
-	if len(items) == 0 {
-		return nil, nil, fmt.Errorf("view.sections must include at least one section")
-	}
-
-	sections := make([]*v1.ScriptViewSection, 0, len(items))
-	parsed := make([]parsedScriptViewSection, 0, len(items))
-	for i, item := range items {
-		sectionMap, ok := item.(map[string]any)
-		if !ok {
-			return nil, nil, fmt.Errorf("view.sections[%d] must be an object", i)
-		}
-		widgetType, _ := sectionMap["widgetType"].(string)
-		if strings.TrimSpace(widgetType) == "" {
-			return nil, nil, fmt.Errorf("view.sections[%d].widgetType is required", i)
-		}
-
-		inputMap := map[string]any{}
-		if rawInput, ok := sectionMap["input"]; ok {
-			typed, ok := rawInput.(map[string]any)
-			if !ok {
-				return nil, nil, fmt.Errorf("view.sections[%d].input must be object", i)
-			}
-			inputMap = typed
-		}
-		if err := validateScriptViewInput(widgetType, inputMap); err != nil {
-			return nil, nil, err
-		}
-		inputStruct, err := mapToStruct(inputMap)
-		if err != nil {
-			return nil, nil, err
-		}
-		sections = append(sections, &v1.ScriptViewSection{
-			WidgetType: widgetType,
-			Input:      inputStruct,
-		})
-		parsed = append(parsed, parsedScriptViewSection{
-			widgetType: strings.ToLower(strings.TrimSpace(widgetType)),
-			input:      inputMap,
+// grantsFromDescribe returns the capability grants recorded at create time.
+func grantsFromDescribe(desc *v1.ScriptDescribe) []scriptengine.Grant {
+	out := make([]scriptengine.Grant, 0, len(desc.GetGrants()))
+	for _, g := range desc.GetGrants() {
+		out = append(out, scriptengine.Grant{
+			Capability: g.GetCapability(),
+			Scope:      append([]string(nil), g.GetScope()...),
+			CallsUsed:  int(g.GetCallsUsed()),
+			BytesUsed:  g.GetBytesUsed(),
 		})
 	}
-	return sections, parsed, nil
+	return out
 }
 
-func selectInteractiveSection(sections []parsedScriptViewSection) (*parsedScriptViewSection, error) {
-	interactiveCount := 0
-	var interactive *parsedScriptViewSection
-	for i := range sections {
-		if sections[i].widgetType == "display" {
-			continue
-		}
-		interactiveCount++
-		interactive = &sections[i]
-	}
-	if interactiveCount != 1 {
-		return nil, fmt.Errorf("view.sections must include exactly one interactive section")
-	}
-	return interactive, nil
-}
-
-func validateScriptViewInput(widgetType string, input map[string]any) error {
-	switch strings.ToLower(strings.TrimSpace(widgetType)) {
-	case "grid":
-		return validateGridInput(input)
-	case "display":
-		return validateDisplayInput(input)
-	case "rating":
-		return validateRatingInput(input)
-	case "select":
-		return validateSelectInput(input)
-	default:
+// outputSchemaFromDescribe returns the output schema recorded at create time,
+// or nil when the script did not declare one.
+func outputSchemaFromDescribe(desc *v1.ScriptDescribe) map[string]any {
+	if desc.GetOutputSchema() == nil {
 		return nil
 	}
+	return desc.GetOutputSchema().AsMap()
 }
 
-func validateDisplayInput(input map[string]any) error {
-	content, _ := input["content"].(string)
-	if strings.TrimSpace(content) == "" {
-		return fmt.Errorf("view.input.content is required for display widget")
-	}
-	if rawFormat, ok := input["format"]; ok {
-		format, ok := rawFormat.(string)
-		if !ok {
-			return fmt.Errorf("view.input.format must be string for display widget")
-		}
-		switch strings.ToLower(strings.TrimSpace(format)) {
-		case "", "markdown", "text", "html":
-		default:
-			return fmt.Errorf("view.input.format must be markdown, text, or html for display widget")
-		}
-	}
-	return nil
-}
-
-func validateGridInput(input map[string]any) error {
-	rows, ok := numberAsPositiveInt(input["rows"])
-	if !ok {
-		return fmt.Errorf("view.input.rows must be a positive integer for grid widget")
-	}
-	cols, ok := numberAsPositiveInt(input["cols"])
-	if !ok {
-		return fmt.Errorf("view.input.cols must be a positive integer for grid widget")
-	}
-	if rows*cols > 400 {
-		return fmt.Errorf("view.input grid size exceeds max cells (400)")
-	}
-
-	cellsV, ok := input["cells"]
-	if !ok {
-		return fmt.Errorf("view.input.cells is required for grid widget")
-	}
-	cells, ok := cellsV.([]any)
-	if !ok {
-		return fmt.Errorf("view.input.cells must be an array for grid widget")
-	}
-	if len(cells) != rows*cols {
-		return fmt.Errorf("view.input.cells length must equal rows*cols for grid widget")
-	}
-	for i, cellV := range cells {
-		cell, ok := cellV.(map[string]any)
-		if !ok {
-			return fmt.Errorf("view.input.cells[%d] must be an object for grid widget", i)
-		}
-		if v, ok := cell["value"]; ok {
-			if _, ok := v.(string); !ok {
-				return fmt.Errorf("view.input.cells[%d].value must be string", i)
-			}
-		}
-		if v, ok := cell["style"]; ok {
-			if _, ok := v.(string); !ok {
-				return fmt.Errorf("view.input.cells[%d].style must be string", i)
-			}
-		}
-		if v, ok := cell["disabled"]; ok {
-			if _, ok := v.(bool); !ok {
-				return fmt.Errorf("view.input.cells[%d].disabled must be boolean", i)
-			}
-		}
-	}
-
-	return nil
-}
-
-func validateRatingInput(input map[string]any) error {
-	title, _ := input["title"].(string)
-	if strings.TrimSpace(title) == "" {
-		return fmt.Errorf("view.input.title is required for rating widget")
-	}
-
-	scale := 5
-	if rawScale, ok := input["scale"]; ok {
-		n, ok := numberAsInt(rawScale)
-		if !ok {
-			return fmt.Errorf("view.input.scale must be integer for rating widget")
-		}
-		if n < 2 || n > 10 {
-			return fmt.Errorf("view.input.scale must be between 2 and 10 for rating widget")
-		}
-		scale = n
-	}
-
-	if rawStyle, ok := input["style"]; ok {
-		style, ok := rawStyle.(string)
-		if !ok {
-			return fmt.Errorf("view.input.style must be string for rating widget")
-		}
-		switch strings.ToLower(strings.TrimSpace(style)) {
-		case "", "stars", "numbers", "emoji", "slider":
-		default:
-			return fmt.Errorf("view.input.style must be stars, numbers, emoji, or slider for rating widget")
-		}
-	}
-
-	if rawLabels, ok := input["labels"]; ok {
-		labels, ok := rawLabels.(map[string]any)
-		if !ok {
-			return fmt.Errorf("view.input.labels must be object for rating widget")
-		}
-		if low, ok := labels["low"]; ok {
-			if _, ok := low.(string); !ok {
-				return fmt.Errorf("view.input.labels.low must be string for rating widget")
-			}
-		}
-		if high, ok := labels["high"]; ok {
-			if _, ok := high.(string); !ok {
-				return fmt.Errorf("view.input.labels.high must be string for rating widget")
-			}
-		}
-	}
-
-	if rawDefault, ok := input["defaultValue"]; ok {
-		n, ok := numberAsInt(rawDefault)
-		if !ok {
-			return fmt.Errorf("view.input.defaultValue must be integer for rating widget")
-		}
-		if n < 1 || n > scale {
-			return fmt.Errorf("view.input.defaultValue must be between 1 and scale for rating widget")
-		}
-	}
-	return nil
-}
-
-func validateSelectInput(input map[string]any) error {
-	optionsV, ok := input["options"]
-	if !ok {
-		return nil
-	}
-	options, ok := optionsV.([]any)
-	if !ok {
-		return fmt.Errorf("view.input.options must be an array for select widget")
-	}
-	for i, option := range options {
-		if s, ok := option.(string); ok {
-			if strings.TrimSpace(s) == "" {
-				return fmt.Errorf("view.input.options[%d] must not be empty", i)
-			}
-			continue
-		}
-		optionMap, ok := option.(map[string]any)
-		if !ok {
-			return fmt.Errorf("view.input.options[%d] must be string or object", i)
-		}
-		value, _ := optionMap["value"].(string)
-		if strings.TrimSpace(value) == "" {
-			return fmt.Errorf("view.input.options[%d].value is required for object options", i)
-		}
-		if label, ok := optionMap["label"]; ok {
-			if _, ok := label.(string); !ok {
-				return fmt.Errorf("view.input.options[%d].label must be string", i)
-			}
-		}
-		if description, ok := optionMap["description"]; ok {
-			if _, ok := description.(string); !ok {
-				return fmt.Errorf("view.input.options[%d].description must be string", i)
-			}
-		}
-		if badge, ok := optionMap["badge"]; ok {
-			if _, ok := badge.(string); !ok {
-				return fmt.Errorf("view.input.options[%d].badge must be string", i)
-			}
-		}
-		if icon, ok := optionMap["icon"]; ok {
-			if _, ok := icon.(string); !ok {
-				return fmt.Errorf("view.input.options[%d].icon must be string", i)
-			}
-		}
-		if disabled, ok := optionMap["disabled"]; ok {
-			if _, ok := disabled.(bool); !ok {
-				return fmt.Errorf("view.input.options[%d].disabled must be boolean", i)
-			}
-		}
+// keepScriptGrants stores the grants of a run whose state and view are
+// discarded, so fetch calls it made still count against the budget.
+func (s *Server) keepScriptGrants(ctx context.Context, id string, grants []scriptengine.Grant) {
+	if len(grants) == 0 {
+		return
 	}
-	return nil
-}
-
-func numberAsPositiveInt(v any) (int, bool) {
-	switch n := v.(type) {
-	case int:
-		return n, n > 0
-	case int32:
-		return int(n), n > 0
-	case int64:
-		return int(n), n > 0
-	case float32:
-		i := int(n)
-		return i, float32(i) == n && i > 0
-	case float64:
-		i := int(n)
-		return i, float64(i) == n && i > 0
-	default:
-		return 0, false
+	if _, err := s.store.PatchScript(ctx, id, nil, nil, nil, grantsToProto(grants), ""); err != nil {
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Failed to keep the fetch budget of request %q: %v", id, err)
 	}
 }
 
-func numberAsInt(v any) (int, bool) {
-	switch n := v.(type) {
-	case int:
-		return n, true
-	case int32:
-		return int(n), true
-	case int64:
-		return int(n), true
-	case float32:
-		i := int(n)
-		return i, float32(i) == n
-	case float64:
-		i := int(n)
-		return i, float64(i) == n
-	default:
-		return 0, false
+// keepFailedRunGrants stores the grants carried by a failed run's
+// scriptengine.RunError.
+func (s *Server) keepFailedRunGrants(ctx context.Context, id string, err error) {
+	var runErr *scriptengine.RunError
+	if stderrors.As(err, &runErr) {
+		s.keepScriptGrants(ctx, id, runErr.Grants)
 	}
 }
 
-func numberAsInt64(v any) (int64, bool) {
-	switch n := v.(type) {
-	case int:
-		return int64(n), true
-	case int8:
-		return int64(n), true
-	case int16:
-		return int64(n), true
-	case int32:
-		return int64(n), true
-	case int64:
-		return n, true
-	case uint:
-		if uint64(n) > math.MaxInt64 {
-			return 0, false
-		}
-		return int64(n), true
-	case uint8:
-		return int64(n), true
-	case uint16:
-		return int64(n), true
-	case uint32:
-		return int64(n), true
-	case uint64:
-		if n > math.MaxInt64 {
-			return 0, false
-		}
-		return int64(n), true
-	case float32:
-		f := float64(n)
-		if math.IsNaN(f) || math.IsInf(f, 0) || f < math.MinInt64 || f > math.MaxInt64 {
-			return 0, false
-		}
-		i := int64(f)
-		return i, float64(i) == f
-	case float64:
-		if math.IsNaN(n) || math.IsInf(n, 0) || n < math.MinInt64 || n > math.MaxInt64 {
-			return 0, false
-		}
-		i := int64(n)
-		return i, float64(i) == n
-	default:
-		return 0, false
+// grantsToProto converts engine grants, including budget usage, for storage
+// on the request's describe.
+func grantsToProto(grants []scriptengine.Grant) []*v1.ScriptGrant {
+	out := make([]*v1.ScriptGrant, 0, len(grants))
+	for _, g := range grants {
+		out = append(out, &v1.ScriptGrant{
+			Capability: g.Capability,
+			Scope:      append([]string(nil), g.Scope...),
+			CallsUsed:  int32(min(g.CallsUsed, math.MaxInt32)), // #nosec G115 -- clamped to int32 range.
+			BytesUsed:  g.BytesUsed,
+		})
 	}
+	return out
 }
 
-func mapToScriptProgress(raw any) (*v1.ScriptProgress, error) {
-	if raw == nil {
-		return nil, nil
-	}
-	progressMap, ok := raw.(map[string]any)
-	if !ok {
-		return nil, fmt.Errorf("view.progress must be object")
-	}
-	current, ok := numberAsInt64(progressMap["current"])
-	if !ok {
-		return nil, fmt.Errorf("view.progress.current is required and must be integer")
-	}
-	total, ok := numberAsInt64(progressMap["total"])
-	if !ok {
-		return nil, fmt.Errorf("view.progress.total is required and must be integer")
-	}
-	if total <= 0 {
-		return nil, fmt.Errorf("view.progress.total must be > 0")
-	}
-	if current < 0 {
-		return nil, fmt.Errorf("view.progress.current must be >= 0")
-	}
-	if current > total {
-		return nil, fmt.Errorf("view.progress.current must be <= total")
+func eventToMap(ev *v1.ScriptEvent) map[string]any {
+	m := map[string]any{"type": ev.GetType()}
+	if ev.GetStepId() != "" {
+		m["stepId"] = ev.GetStepId()
 	}
-	if current > math.MaxInt32 || total > math.MaxInt32 {
-		return nil, fmt.Errorf("view.progress values exceed int32 range")
+	if ev.GetActionId() != "" {
+		m["actionId"] = ev.GetActionId()
 	}
-
-	progress := &v1.ScriptProgress{
-		Current: int32(current),
-		Total:   int32(total),
+	if ev.GetData() != nil {
+		m["data"] = ev.GetData().AsMap()
 	}
-	if label, ok := progressMap["label"].(string); ok && strings.TrimSpace(label) != "" {
-		progress.Label = &label
+	if ev.GetSource() != "" {
+		m["source"] = ev.GetSource()
 	}
-	return progress, nil
+	return m
 }
 
-func mapToScriptToast(raw any) (*v1.ScriptToast, error) {
-	if raw == nil {
-		return nil, nil
-	}
-	toastMap, ok := raw.(map[string]any)
-	if !ok {
-		return nil, fmt.Errorf("view.toast must be object")
-	}
-	message, _ := toastMap["message"].(string)
-	if strings.TrimSpace(message) == "" {
-		return nil, fmt.Errorf("view.toast.message is required")
-	}
-
-	toast := &v1.ScriptToast{
-		Message: message,
-	}
-	if rawDuration, ok := toastMap["duration"]; ok {
-		d, ok := numberAsInt(rawDuration)
-		if !ok {
-			return nil, fmt.Errorf("view.toast.duration must be integer")
-		}
-		if d <= 0 || d > 30000 {
-			return nil, fmt.Errorf("view.toast.duration must be between 1 and 30000")
-		}
-		duration := int32(d)
-		toast.DurationMs = &duration
-	}
-	if rawDuration, ok := toastMap["durationMs"]; ok {
-		d, ok := numberAsInt(rawDuration)
-		if !ok {
-			return nil, fmt.Errorf("view.toast.durationMs must be integer")
-		}
-		if d <= 0 || d > 30000 {
-			return nil, fmt.Errorf("view.toast.durationMs must be between 1 and 30000")
-		}
-		duration := int32(d)
-		toast.DurationMs = &duration
-	}
-	if rawStyle, ok := toastMap["style"]; ok {
-		style, ok := rawStyle.(string)
-		if !ok {
-			return nil, fmt.Errorf("view.toast.style must be string")
-		}
-		switch strings.ToLower(strings.TrimSpace(style)) {
-		case "", "info", "success", "warning", "error":
-		default:
-			return nil, fmt.Errorf("view.toast.style must be info, success, warning, or error")
-		}
-		if strings.TrimSpace(style) != "" {
-			toast.Style = &style
-		}
+func mapToStruct(m map[string]any) (*structpb.Struct, error) {
+	if m == nil {
+		m = map[string]any{}
 	}
-	return toast, nil
+	return structpb.NewStruct(m)
 }
 
 func mapToScriptDescribe(m map[string]any) (*v1.ScriptDescribe, error) {
@@ -829,6 +496,23 @@ func mapToScriptDescribe(m map[string]any) (*v1.ScriptDescribe, error) {
 			desc.Capabilities = append(desc.Capabilities, capStr)
 		}
 	}
+	if schema, ok := m["outputSchema"].(map[string]any); ok {
+		schemaStruct, err := mapToStruct(schema)
+		if err != nil {
+			return nil, fmt.Errorf("describe.outputSchema: %w", err)
+		}
+		desc.OutputSchema = schemaStruct
+	}
+	if messages, ok := m["messages"].(map[string]any); ok {
+		messagesStruct, err := mapToStruct(messages)
+		if err != nil {
+			return nil, fmt.Errorf("describe.messages: %w", err)
+		}
+		desc.Messages = messagesStruct
+	}
+	if defaultLocale, ok := m["defaultLocale"].(string); ok && defaultLocale != "" {
+		desc.DefaultLocale = &defaultLocale
+	}
 	return desc, nil
 }
 
@@ -836,6 +520,12 @@ func statusForScriptError(err error) int {
 	if err == nil {
 		return http.StatusBadRequest
 	}
+	if stderrors.Is(err, scriptengine.ErrScriptOutputLimit) {
+		return http.StatusRequestEntityTooLarge
+	}
+	if stderrors.Is(err, scriptengine.ErrScriptMemoryLimit) || stderrors.Is(err, scriptengine.ErrScriptStackLimit) {
+		return http.StatusUnprocessableEntity
+	}
 	if stderrors.Is(err, scriptengine.ErrScriptTimeout) {
 		return http.StatusGatewayTimeout
 	}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// scriptEventBack is the event type the browser sends for the back button.
+const scriptEventBack = "back"
+
+// handlesScriptBack reports whether event is a back press the server answers
+// from its snapshots: it came from the browser and the current view offers
+// back navigation.
+func handlesScriptBack(req *v1.UIRequest, event *v1.ScriptEvent) bool {
+	return event.GetType() == scriptEventBack &&
+		event.GetSource() == scriptEventSourceUI &&
+		req.GetScriptView().GetAllowBack()
+}
+
+// applyScriptBack restores the newest state snapshot of pending script
+// request req and renders view() for it without calling update(). It
+// reports false when there is no snapshot to go back to, in which case the
+// event is passed to update() as usual.
+func (s *Server) applyScriptBack(ctx context.Context, req *v1.UIRequest, event *v1.ScriptEvent, acceptLanguage string) (*v1.UIRequest, bool, error) {
+	id := req.Id
+	snapshot, err := s.store.LatestScriptSnapshot(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, store.ErrNoScriptSnapshot) {
+			return nil, false, nil
+		}
+		if stderrors.Is(err, store.ErrNotFound) {
+			return nil, true, eventErrorf(http.StatusNotFound, "request not found")
+		}
+		return nil, true, eventErrorf(http.StatusInternalServerError, "internal error")
+	}
+
+	restored := snapshot.AsMap()
+	seed, ok := stateSeedValue(restored)
+	if !ok {
+		seed, ok = stateSeedValue(req.GetScriptState().AsMap())
+	}
+	if !ok {
+		seed, err = newScriptSeed()
+		if err != nil {
+			return nil, true, eventErrorf(http.StatusInternalServerError, "failed to allocate script seed")
+		}
+	}
+	restored = ensureSeedInState(restored, seed)
+	seededInput, err := scriptInputWithSeed(req.GetScriptInput(), seed)
+	if err != nil {
+		return nil, true, eventErrorf(http.StatusBadRequest, "invalid script input: %v", err)
+	}
+	runnableInput, err := s.runnableScriptInput(ctx, seededInput)
+	if err != nil {
+		return nil, true, eventErrorf(http.StatusBadRequest, "invalid script input: %v", err)
+	}
+
+	started := time.Now()
+	viewResult, err := s.scripts.View(ctx, runnableInput, restored,
+		scriptengine.WithGrants(grantsFromDescribe(req.GetScriptDescribe())...),
+		scriptengine.WithRuntimeKey(id),
+		scriptengine.WithNow(started),
+		scriptengine.WithLocale(scriptLocalePreference(req, acceptLanguage)),
+		scriptengine.WithMessages(messagesFromDescribe(req.GetScriptDescribe())),
+	)
+	if err != nil {
+		s.keepFailedRunGrants(ctx, id, err)
+		entry := newScriptHistoryEntry(event, started, scriptengine.FailedRunLogs(err))
+		msg := err.Error()
+		entry.Error = &msg
+		s.recordScriptHistory(ctx, id, entry)
+		return nil, true, eventErrorf(statusForScriptError(err), "script view failed: %v", err)
+	}
+	entry := newScriptHistoryEntry(event, started, viewResult.Logs)
+	entry.Locale = &viewResult.Locale
+
+	stateStruct, err := mapToStruct(restored)
+	if err != nil {
+		return nil, true, eventErrorf(http.StatusBadRequest, "invalid script state snapshot: %v", err)
+	}
+	viewProto, err := scriptview.FromMap(viewResult.View)
+	if err != nil {
+		s.keepScriptGrants(ctx, id, viewResult.Grants)
+		msg := "invalid script view: " + err.Error()
+		entry.Error = &msg
+		s.recordScriptHistory(ctx, id, entry)
+		return nil, true, eventErrorf(http.StatusBadRequest, "invalid script view: %v", err)
+	}
+
+	next, err := s.store.PatchScript(ctx, id, stateStruct, viewProto, viewResult.Logs, grantsToProto(viewResult.Grants), viewResult.Locale)
+	if err != nil {
+		if stderrors.Is(err, store.ErrNotFound) {
+			return nil, true, eventErrorf(http.StatusNotFound, "request not found")
+		}
+		if stderrors.Is(err, store.ErrAlreadyCompleted) {
+			return nil, true, eventErrorf(http.StatusConflict, "request already completed")
+		}
+		return nil, true, eventErrorf(http.StatusInternalServerError, "internal error")
+	}
+	if err := s.store.PopScriptSnapshot(ctx, id); err != nil {
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Failed to drop restored snapshot for request %q: %v", id, err)
+	}
+	s.scheduleScriptTick(id, viewResult.Schedule)
+	entry.Changes = scriptStateDiff(req.GetScriptState().AsMap(), stateStruct.AsMap())
+	setScriptHistoryView(entry, viewProto)
+	s.recordScriptHistory(ctx, id, entry)
+
+	if msg, err := marshalWSEvent("request_updated", next); err == nil {
+		s.ws.BroadcastRawJSON(next.SessionId, msg)
+	}
+	return next, true, nil
+}
+
+// pushScriptSnapshot saves state as the step to return to on back when the
+// browser event that replaced it actually changed it. Snapshots are
+// best-effort; a failure is logged and does not fail the event.
+func (s *Server) pushScriptSnapshot(ctx context.Context, id string, event *v1.ScriptEvent, state, next *structpb.Struct) {
+	if event.GetSource() != scriptEventSourceUI || event.GetType() == scriptEventBack {
+		return
+	}
+	if state == nil || proto.Equal(state, next) {
+		return
+	}
+	if err := s.store.PushScriptSnapshot(ctx, id, state); err != nil {
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Failed to record state snapshot for request %q: %v", id, err)
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const scriptBackFlow = `
+module.exports = {
+  describe: function () { return { name: "back", version: "1.0.0" }; },
+  init: function () { return { step: "a", answers: {}, updates: 0 }; },
+  view: function (state) {
+    return {
+      widgetType: "confirm",
+      stepId: state.step,
+      allowBack: state.step !== "a",
+      input: { title: "Step " + state.step }
+    };
+  },
+  update: function (state, event) {
+    state.updates++;
+    if (event.type === "back") {
+      state.step = "handled-by-update";
+      return state;
+    }
+    if (event.type === "note") {
+      state.note = event.data.text;
+      return state;
+    }
+    state.answers[state.step] = event.data.approved;
+    state.step = { a: "b", b: "c" }[state.step] || "c";
+    return state;
+  }
+};
+`
+
+func createScriptBackFlow(t *testing.T) (http.Handler, *store.Store, string) {
+	t.Helper()
+
+	st := store.New()
+	h := New(st).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Back", Script: scriptBackFlow},
+		},
+	})
+	return h, st, created.Id
+}
+
+func TestScriptBackRestoresSnapshotsWithoutUpdate(t *testing.T) {
+	t.Parallel()
+
+	h, st, id := createScriptBackFlow(t)
+
+	submit := func(approved bool) *v1.UIRequest {
+		return postScriptEvent(t, h, id, &v1.ScriptEvent{
+			Type: "submit",
+			Data: mustStruct(t, map[string]any{"approved": approved}),
+		})
+	}
+	back := func() *v1.UIRequest {
+		return postScriptEvent(t, h, id, &v1.ScriptEvent{Type: "back"})
+	}
+
+	submit(true)
+	atC := submit(false)
+	if atC.GetScriptView().GetStepId() != "c" {
+		t.Fatalf("expected step c, got %q", atC.GetScriptView().GetStepId())
+	}
+
+	atB := back()
+	state := atB.GetScriptState().AsMap()
+	if atB.GetScriptView().GetStepId() != "b" || state["step"] != "b" || state["updates"] != float64(1) {
+		t.Fatalf("expected restored step b after one update, got view=%q state=%v", atB.GetScriptView().GetStepId(), state)
+	}
+	if answers := state["answers"].(map[string]any); len(answers) != 1 || answers["a"] != true {
+		t.Fatalf("expected only step a's answer to survive, got %v", answers)
+	}
+
+	atA := back()
+	if atA.GetScriptView().GetStepId() != "a" || atA.GetScriptView().GetAllowBack() {
+		t.Fatalf("expected initial step without back, got %+v", atA.GetScriptView())
+	}
+
+	// The first step does not offer back, so the event reaches update().
+	handled := back()
+	if got := handled.GetScriptState().AsMap()["step"]; got != "handled-by-update" {
+		t.Fatalf("expected update() to handle back without allowBack, got step %v", got)
+	}
+
+	// Going forward again after a back continues from the restored state.
+	redo := submit(true)
+	if _, err := st.LatestScriptSnapshot(t.Context(), id); err != nil {
+		t.Fatalf("expected a snapshot after moving forward again: %v", err)
+	}
+	if redo.GetScriptView().GetStepId() != "c" {
+		t.Fatalf("expected step c, got %q", redo.GetScriptView().GetStepId())
+	}
+
+	history := getScriptHistory(t, h, id, http.StatusOK)
+	var backs int
+	for _, e := range history.GetEntries() {
+		if e.GetEvent().GetType() == "back" && e.GetError() == "" && len(e.GetChanges()) > 0 {
+			backs++
+		}
+	}
+	if backs != 3 {
+		t.Fatalf("expected 3 back entries with changes in history, got %d", backs)
+	}
+}
+
+func TestScriptBackSkipsAgentEventsAndFallsThroughWithoutSnapshot(t *testing.T) {
+	t.Parallel()
+
+	h, st, id := createScriptBackFlow(t)
+
+	postScriptInject(t, h, id, &v1.ScriptEvent{
+		Type: "note",
+		Data: mustStruct(t, map[string]any{"text": "from agent"}),
+	})
+	if _, err := st.LatestScriptSnapshot(t.Context(), id); err != store.ErrNoScriptSnapshot {
+		t.Fatalf("expected agent events not to be snapshotted, got %v", err)
+	}
+
+	atB := postScriptEvent(t, h, id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": true}),
+	})
+	if atB.GetScriptState().AsMap()["note"] != "from agent" {
+		t.Fatalf("expected agent note to be kept, got %v", atB.GetScriptState().AsMap())
+	}
+
+	atA := postScriptEvent(t, h, id, &v1.ScriptEvent{Type: "back"})
+	state := atA.GetScriptState().AsMap()
+	if state["step"] != "a" || state["note"] != "from agent" {
+		t.Fatalf("expected step a with the agent note, got %v", state)
+	}
+
+	// Step b allows back, but with an empty stack the event reaches update().
+	postScriptEvent(t, h, id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": true}),
+	})
+	if err := st.PopScriptSnapshot(t.Context(), id); err != nil {
+		t.Fatalf("PopScriptSnapshot: %v", err)
+	}
+	handled := postScriptEvent(t, h, id, &v1.ScriptEvent{Type: "back"})
+	if got := handled.GetScriptState().AsMap()["step"]; got != "handled-by-update" {
+		t.Fatalf("expected update() to handle back with no snapshot, got step %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ScriptStateChange ops.
+const (
+	stateChangeAdd     = "add"
+	stateChangeRemove  = "remove"
+	stateChangeReplace = "replace"
+)
+
+// handleScriptHistory returns the recorded init and event runs of a script
+// request, including after it has completed.
+//
+// Paths:
+// - GET /api/requests/{id}/script/history
+func (s *Server) handleScriptHistory(w http.ResponseWriter, r *http.Request, id string) {
+	req, err := s.store.Get(r.Context(), id)
+	if err != nil {
+		if stderrors.Is(err, store.ErrNotFound) {
+			http.Error(w, "request not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if req.Type != v1.WidgetType_script {
+		http.Error(w, "request is not script widget", http.StatusBadRequest)
+		return
+	}
+	history, err := s.store.ScriptHistory(r.Context(), id)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	writeProtoJSON(w, http.StatusOK, history)
+}
+
+// newScriptHistoryEntry starts a history entry for a run that began at
+// started. event is nil for init.
+func newScriptHistoryEntry(event *v1.ScriptEvent, started time.Time, logs []string) *v1.ScriptHistoryEntry {
+	return &v1.ScriptHistoryEntry{
+		At:         started.UTC().Format(time.RFC3339Nano),
+		Event:      event,
+		DurationMs: time.Since(started).Milliseconds(),
+		Logs:       append([]string(nil), logs...),
+	}
+}
+
+// setScriptHistoryView records the view shown after the run.
+func setScriptHistoryView(entry *v1.ScriptHistoryEntry, view *v1.ScriptView) {
+	widgetType := view.GetWidgetType()
+	entry.WidgetType = &widgetType
+	entry.StepId = view.StepId
+}
+
+// recordScriptHistory appends entry to request id's history. History is
+// best-effort; a failure is logged and does not fail the event.
+func (s *Server) recordScriptHistory(ctx context.Context, id string, entry *v1.ScriptHistoryEntry) {
+	if err := s.store.AppendScriptHistory(ctx, id, entry); err != nil {
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Failed to record history for request %q: %v", id, err)
+	}
+}
+
+// scriptStateDiff lists the changes from before to after. Nested objects are
+// compared key by key with dotted paths; any other value (including arrays)
+// is compared as a whole.
+func scriptStateDiff(before, after map[string]any) []*v1.ScriptStateChange {
+	var out []*v1.ScriptStateChange
+	diffStateMaps("", before, after, &out)
+	return out
+}
+
+func diffStateMaps(prefix string, before, after map[string]any, out *[]*v1.ScriptStateChange) {
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		b, inBefore := before[k]
+		a, inAfter := after[k]
+		switch {
+		case !inBefore:
+			*out = append(*out, stateChange(path, stateChangeAdd, nil, a, false, true))
+		case !inAfter:
+			*out = append(*out, stateChange(path, stateChangeRemove, b, nil, true, false))
+		default:
+			bm, bIsMap := b.(map[string]any)
+			am, aIsMap := a.(map[string]any)
+			if bIsMap && aIsMap {
+				diffStateMaps(path, bm, am, out)
+				continue
+			}
+			if !reflect.DeepEqual(b, a) {
+				*out = append(*out, stateChange(path, stateChangeReplace, b, a, true, true))
+			}
+		}
+	}
+}
+
+func stateChange(path, op string, before, after any, hasBefore, hasAfter bool) *v1.ScriptStateChange {
+	c := &v1.ScriptStateChange{Path: path, Op: op}
+	if hasBefore {
+		c.Before = historyValue(before)
+	}
+	if hasAfter {
+		c.After = historyValue(after)
+	}
+	return c
+}
+
+func historyValue(v any) *structpb.Value {
+	pv, err := structpb.NewValue(v)
+	if err != nil {
+		return structpb.NewStringValue("<unrepresentable>")
+	}
+	return pv
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const scriptHistoryFlow = `
+module.exports = {
+  describe: function () { return { name: "history", version: "1.0.0" }; },
+  init: function () { return { step: "confirm", form: { env: "staging" } }; },
+  view: function (state) {
+    if (state.step === "confirm") {
+      return { widgetType: "confirm", stepId: "confirm", input: { title: "Deploy?" } };
+    }
+    return { widgetType: "select", stepId: "pick-env", input: { title: "Env", options: ["staging", "prod"] } };
+  },
+  update: function (state, event) {
+    if (event.type === "boom") {
+      console.log("checking " + state.form.env);
+      throw new Error("kaboom");
+    }
+    if (state.step === "confirm") {
+      console.log("rejected, picking env");
+      state.step = "pick";
+      state.form.env = "prod";
+      state.reason = "rejected";
+      return state;
+    }
+    return { done: true, result: { env: event.data.selectedSingle } };
+  }
+};
+`
+
+func getScriptHistory(t *testing.T, h http.Handler, id string, wantStatus int) *v1.ScriptHistory {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/requests/"+id+"/script/history", nil))
+	if rr.Code != wantStatus {
+		t.Fatalf("history status=%d want=%d body=%s", rr.Code, wantStatus, rr.Body.String())
+	}
+	out := &v1.ScriptHistory{}
+	if wantStatus == http.StatusOK {
+		if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+			t.Fatalf("unmarshal ScriptHistory: %v body=%s", err, rr.Body.String())
+		}
+	}
+	return out
+}
+
+func TestScriptHistoryRecordsInitEventsAndFailures(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "History", Script: scriptHistoryFlow},
+		},
+	})
+
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": false}),
+	})
+
+	body, err := protojson.Marshal(&v1.ScriptEvent{Type: "boom"})
+	if err != nil {
+		t.Fatalf("marshal ScriptEvent: %v", err)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/requests/"+created.Id+"/event", bytes.NewReader(body)))
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected failing event to return 422, got %d", rr.Code)
+	}
+
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"selectedSingle": "prod"}),
+	})
+
+	history := getScriptHistory(t, h, created.Id, http.StatusOK)
+	entries := history.GetEntries()
+	if history.GetRequestId() != created.Id || len(entries) != 4 || history.GetDropped() != 0 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	for i, e := range entries {
+		if e.GetSeq() != int32(i+1) || e.GetAt() == "" {
+			t.Fatalf("entry %d has seq=%d at=%q", i, e.GetSeq(), e.GetAt())
+		}
+	}
+
+	initEntry := entries[0]
+	if initEntry.GetEvent() != nil || initEntry.GetStepId() != "confirm" || initEntry.GetWidgetType() != "confirm" {
+		t.Fatalf("unexpected init entry: %+v", initEntry)
+	}
+
+	reject := entries[1]
+	if reject.GetEvent().GetType() != "submit" || reject.GetEvent().GetSource() != "ui" || reject.GetStepId() != "pick-env" {
+		t.Fatalf("unexpected reject entry: %+v", reject)
+	}
+	if len(reject.GetLogs()) != 1 {
+		t.Fatalf("expected the update's log line, got %v", reject.GetLogs())
+	}
+	changes := map[string]*v1.ScriptStateChange{}
+	for _, c := range reject.GetChanges() {
+		changes[c.GetPath()] = c
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 state changes, got %+v", reject.GetChanges())
+	}
+	if c := changes["step"]; c.GetOp() != "replace" || c.GetBefore().GetStringValue() != "confirm" || c.GetAfter().GetStringValue() != "pick" {
+		t.Fatalf("unexpected step change: %+v", c)
+	}
+	if c := changes["form.env"]; c.GetOp() != "replace" || c.GetAfter().GetStringValue() != "prod" {
+		t.Fatalf("unexpected nested change: %+v", c)
+	}
+	if c := changes["reason"]; c.GetOp() != "add" || c.GetAfter().GetStringValue() != "rejected" {
+		t.Fatalf("unexpected added key: %+v", c)
+	}
+
+	failed := entries[2]
+	if failed.GetEvent().GetType() != "boom" || failed.GetError() == "" || len(failed.GetChanges()) != 0 {
+		t.Fatalf("unexpected failed entry: %+v", failed)
+	}
+	if logs := failed.GetLogs(); len(logs) != 1 || logs[0] != "[log] checking prod" {
+		t.Fatalf("expected the failed run's log line, got %v", logs)
+	}
+
+	if done := entries[3]; !done.GetDone() || done.GetError() != "" {
+		t.Fatalf("unexpected final entry: %+v", done)
+	}
+
+	getScriptHistory(t, h, "missing", http.StatusNotFound)
+}
+
+func TestScriptHistoryIsCapped(t *testing.T) {
+	t.Parallel()
+
+	st := store.New()
+	h := New(st).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "History", Script: scriptHistoryFlow},
+		},
+	})
+	for i := 0; i < store.MaxScriptHistoryEntries+4; i++ {
+		if err := st.AppendScriptHistory(context.Background(), created.Id, &v1.ScriptHistoryEntry{}); err != nil {
+			t.Fatalf("AppendScriptHistory: %v", err)
+		}
+	}
+
+	history := getScriptHistory(t, h, created.Id, http.StatusOK)
+	if len(history.GetEntries()) != store.MaxScriptHistoryEntries || history.GetDropped() != 5 {
+		t.Fatalf("expected %d entries and 5 dropped, got %d and %d",
+			store.MaxScriptHistoryEntries, len(history.GetEntries()), history.GetDropped())
+	}
+	if first := history.GetEntries()[0].GetSeq(); first != 6 {
+		t.Fatalf("expected oldest kept entry to be seq 6, got %d", first)
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const scriptAwaitTests = `
+module.exports = {
+  describe: function () { return { name: "await-tests", version: "1.0.0" }; },
+  init: function () { return { step: "running", sources: [] }; },
+  view: function (state) {
+    if (state.step === "running") {
+      return { widgetType: "display", input: { title: "Tests", content: "Running…" } };
+    }
+    return { widgetType: "confirm", input: { title: "Tests: " + state.summary + ". Merge?" } };
+  },
+  update: function (state, event) {
+    state.sources = state.sources.concat([event.source]);
+    if (event.type === "tests_done" && event.source === "agent") {
+      state.step = "review";
+      state.summary = event.data.summary;
+      return state;
+    }
+    if (state.step === "review" && event.type === "submit") {
+      return { done: true, result: { approved: event.data.approved, sources: state.sources } };
+    }
+    return state;
+  }
+};`
+
+func postScriptInject(t *testing.T, h http.Handler, id string, ev *v1.ScriptEvent) *v1.UIRequest {
+	t.Helper()
+
+	body, err := protojson.Marshal(ev)
+	if err != nil {
+		t.Fatalf("marshal ScriptEvent: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/requests/"+id+"/inject", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("inject failed status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	out := &v1.UIRequest{}
+	if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+		t.Fatalf("unmarshal inject response: %v body=%s", err, rr.Body.String())
+	}
+	return out
+}
+
+func TestScriptInjectDeliversAgentEvent(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Await tests", Script: scriptAwaitTests},
+		},
+	})
+
+	// A browser can't pose as the agent: the server overwrites source.
+	spoofed := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type:   "tests_done",
+		Source: scriptEventSourceAgent,
+		Data:   mustStruct(t, map[string]any{"summary": "fake"}),
+	})
+	if got := spoofed.GetScriptView().GetWidgetType(); got != "display" {
+		t.Fatalf("ui event must not be treated as agent event, got view %q", got)
+	}
+
+	injected := postScriptInject(t, h, created.Id, &v1.ScriptEvent{
+		Type: "tests_done",
+		Data: mustStruct(t, map[string]any{"summary": "128 passed"}),
+	})
+	if got := injected.GetScriptView().GetWidgetType(); got != "confirm" {
+		t.Fatalf("expected confirm view after inject, got %q", got)
+	}
+
+	completed := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": true}),
+	})
+	if completed.Status != v1.RequestStatus_completed {
+		t.Fatalf("expected completed, got %v", completed.Status)
+	}
+	sources := completed.GetScriptOutput().GetResult().AsMap()["sources"]
+	want := []any{"ui", "agent", "ui"}
+	got, ok := sources.([]any)
+	if !ok || len(got) != len(want) {
+		t.Fatalf("unexpected event sources: %v", sources)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected event sources: %v", sources)
+		}
+	}
+}
+
+func TestScriptInjectRejectsSettledRequest(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Await tests", Script: scriptAwaitTests},
+		},
+	})
+	postScriptInject(t, h, created.Id, &v1.ScriptEvent{
+		Type: "tests_done",
+		Data: mustStruct(t, map[string]any{"summary": "ok"}),
+	})
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": false}),
+	})
+
+	body, _ := protojson.Marshal(&v1.ScriptEvent{Type: "tests_done"})
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/requests/"+created.Id+"/inject", bytes.NewReader(body)))
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for completed request, got %d body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/requests/missing/inject", bytes.NewReader(body)))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown request, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// scriptLintSeed seeds ctx.random during validation so reports are
+// reproducible.
+const scriptLintSeed = 1
+
+// ScriptLintIssue severities.
+const (
+	scriptLintError   = "error"
+	scriptLintWarning = "warning"
+)
+
+// handleScriptValidate checks a script without creating a request. The body
+// is a ScriptInput (inline script or scriptRef, plus sample props). Problems
+// with the script are reported in the ScriptValidation body with status 200;
+// only malformed requests get an error status.
+//
+// Paths:
+// - POST /api/scripts/validate
+func (s *Server) handleScriptValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, maxRegisteredScriptBytes+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(bodyBytes) > maxRegisteredScriptBytes {
+		http.Error(w, "script too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	in := &v1.ScriptInput{}
+	if err := protojson.Unmarshal(bodyBytes, in); err != nil {
+		http.Error(w, "invalid protojson ScriptInput: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if ref := in.GetScriptRef(); ref != nil {
+		if in.GetScript() != "" {
+			http.Error(w, "set either script or scriptRef, not both", http.StatusBadRequest)
+			return
+		}
+		rs, err := s.store.GetScript(r.Context(), ref.GetName(), ref.GetVersion())
+		if err != nil {
+			writeScriptStoreError(w, err)
+			return
+		}
+		resolved, ok := proto.Clone(in).(*v1.ScriptInput)
+		if !ok {
+			http.Error(w, "failed to clone script input", http.StatusInternalServerError)
+			return
+		}
+		resolved.Script = rs.Script
+		in = resolved
+	} else if s.registeredScriptsOnly {
+		http.Error(w, "inline scripts are disabled; use scriptRef", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(in.GetScript()) == "" {
+		http.Error(w, "script source is required", http.StatusBadRequest)
+		return
+	}
+
+	report, err := s.lintScript(r.Context(), in)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeProtoJSON(w, http.StatusOK, report)
+}
+
+// lintScript runs the checks behind POST /api/scripts/validate: compile,
+// describe/init/view with the sample props, then every step reachable
+// through ctx.branch.
+func (s *Server) lintScript(ctx context.Context, in *v1.ScriptInput) (*v1.ScriptValidation, error) {
+	report := &v1.ScriptValidation{}
+	issue := func(severity, phase, step, msg string) {
+		li := &v1.ScriptLintIssue{Severity: severity, Phase: phase, Message: msg}
+		if step != "" {
+			li.Step = &step
+		}
+		report.Issues = append(report.Issues, li)
+	}
+	defer func() {
+		report.Ok = true
+		for _, li := range report.Issues {
+			if li.GetSeverity() == scriptLintError {
+				report.Ok = false
+			}
+		}
+	}()
+
+	if err := s.scripts.Compile(in); err != nil {
+		issue(scriptLintError, "compile", "", err.Error())
+		return report, nil
+	}
+
+	seeded, err := scriptInputWithSeed(in, scriptLintSeed)
+	if err != nil {
+		return nil, err
+	}
+	initResult, err := s.scripts.InitAndView(ctx, seeded)
+	if err != nil {
+		issue(scriptLintError, "init", "", err.Error())
+		return report, nil
+	}
+	initial, _ := initResult.State["step"].(string)
+	_, view, describe, err := scriptInitResultToProto(initResult)
+	if err != nil {
+		issue(scriptLintError, "view", initial, err.Error())
+		return report, nil
+	}
+	report.Describe = describe
+	report.Steps = append(report.Steps, lintStep(initial, view))
+
+	messages := scriptengine.MessagesFromDescribe(initResult.Describe)
+	for _, msg := range messages.Warnings() {
+		issue(scriptLintWarning, "init", initial, msg)
+	}
+
+	walk, err := s.scripts.WalkSteps(ctx, seeded, initResult.State, scriptengine.WithMessages(messages))
+	if err != nil {
+		issue(scriptLintError, "walk", "", err.Error())
+		return report, nil
+	}
+	for _, sv := range walk.Steps {
+		if sv.Err != nil {
+			issue(scriptLintError, "view", sv.Step, sv.Err.Error())
+			continue
+		}
+		view, err := scriptview.FromMap(sv.View)
+		if err != nil {
+			issue(scriptLintError, "view", sv.Step, err.Error())
+			continue
+		}
+		report.Steps = append(report.Steps, lintStep(sv.Step, view))
+	}
+	if walk.Truncated {
+		issue(scriptLintWarning, "walk", "", fmt.Sprintf("stopped after %d steps", len(walk.Steps)))
+	}
+	return report, nil
+}
+
+func lintStep(step string, view *v1.ScriptView) *v1.ScriptLintStep {
+	return &v1.ScriptLintStep{
+		Step:       step,
+		WidgetType: view.GetWidgetType(),
+		StepId:     view.StepId,
+	}
+}
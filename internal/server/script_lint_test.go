@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// scriptBranchy reaches "review" and "details" only through ctx.branch, and
+// renders an invalid display view for "details".
+const scriptBranchy = `
+module.exports = {
+  describe: function () {
+    return { name: "branchy", version: "1.0.0" };
+  },
+  init: function (ctx) {
+    return { step: "confirm", env: ctx.props.env };
+  },
+  view: function (state) {
+    switch (state.step) {
+      case "confirm":
+        return { widgetType: "confirm", stepId: "confirm", input: { title: "Deploy " + state.env + "?" } };
+      case "review":
+        return { widgetType: "select", stepId: "review", input: { title: "Why?", options: ["a", "b"] } };
+      case "details":
+        return { widgetType: "display", stepId: "details", input: {} };
+    }
+    throw new Error("unknown step " + state.step);
+  },
+  update: function (state, event, ctx) {
+    if (state.step === "confirm") {
+      return ctx.branch(state, event, { approved: "details", rejected: "review" });
+    }
+    if (state.step === "review") {
+      return ctx.branch(state, event, { rules: [{ when: "event.data.selectedSingle", step: "details" }] });
+    }
+    return { done: true, result: {} };
+  }
+};
+`
+
+func postScriptValidate(t *testing.T, h http.Handler, in *v1.ScriptInput, wantStatus int) *v1.ScriptValidation {
+	t.Helper()
+
+	b, err := protojson.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal ScriptInput: %v", err)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/scripts/validate", bytes.NewReader(b)))
+	if rr.Code != wantStatus {
+		t.Fatalf("validate status=%d want=%d body=%s", rr.Code, wantStatus, rr.Body.String())
+	}
+	out := &v1.ScriptValidation{}
+	if wantStatus == http.StatusOK {
+		if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+			t.Fatalf("unmarshal ScriptValidation: %v body=%s", err, rr.Body.String())
+		}
+	}
+	return out
+}
+
+func TestScriptValidateWalksBranchTargets(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+
+	report := postScriptValidate(t, h, &v1.ScriptInput{
+		Script: scriptBranchy,
+		Props:  mustStruct(t, map[string]any{"env": "prod"}),
+	}, http.StatusOK)
+	if report.GetOk() {
+		t.Fatalf("expected invalid details view to fail validation: %+v", report)
+	}
+	if report.GetDescribe().GetName() != "branchy" {
+		t.Fatalf("expected describe in report, got %+v", report.GetDescribe())
+	}
+
+	var steps []string
+	for _, s := range report.GetSteps() {
+		steps = append(steps, s.GetStep()+"="+s.GetWidgetType())
+	}
+	if len(steps) != 2 || steps[0] != "confirm=confirm" || steps[1] != "review=select" {
+		t.Fatalf("unexpected walked steps: %v", steps)
+	}
+	if len(report.GetIssues()) != 1 {
+		t.Fatalf("expected one issue, got %+v", report.GetIssues())
+	}
+	issue := report.GetIssues()[0]
+	if issue.GetSeverity() != "error" || issue.GetPhase() != "view" || issue.GetStep() != "details" {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+
+	// The same script registered and linted by reference.
+	putScript(t, h, "branchy", scriptBranchy, http.StatusCreated)
+	byRef := postScriptValidate(t, h, &v1.ScriptInput{
+		ScriptRef: &v1.ScriptRef{Name: "branchy"},
+		Props:     mustStruct(t, map[string]any{"env": "prod"}),
+	}, http.StatusOK)
+	if byRef.GetOk() || len(byRef.GetSteps()) != 2 {
+		t.Fatalf("expected the same report by reference, got %+v", byRef)
+	}
+}
+
+func TestScriptValidateReportsEarlyFailures(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+
+	report := postScriptValidate(t, h, &v1.ScriptInput{Script: "module.exports = {"}, http.StatusOK)
+	if report.GetOk() || len(report.GetIssues()) != 1 || report.GetIssues()[0].GetPhase() != "compile" {
+		t.Fatalf("expected a compile issue, got %+v", report)
+	}
+
+	report = postScriptValidate(t, h, &v1.ScriptInput{Script: "module.exports = { describe: function () { return {}; } };"}, http.StatusOK)
+	if report.GetOk() || len(report.GetIssues()) != 1 || report.GetIssues()[0].GetPhase() != "init" {
+		t.Fatalf("expected an init issue for a missing export, got %+v", report)
+	}
+
+	report = postScriptValidate(t, h, &v1.ScriptInput{Script: scriptWizard}, http.StatusOK)
+	if !report.GetOk() || len(report.GetIssues()) != 0 {
+		t.Fatalf("expected the wizard script to validate, got %+v", report)
+	}
+
+	postScriptValidate(t, h, &v1.ScriptInput{}, http.StatusBadRequest)
+	postScriptValidate(t, h, &v1.ScriptInput{ScriptRef: &v1.ScriptRef{Name: "missing"}}, http.StatusNotFound)
+
+	restricted := New(store.New(), WithRegisteredScriptsOnly()).Handler()
+	postScriptValidate(t, restricted, &v1.ScriptInput{Script: scriptWizard}, http.StatusBadRequest)
+}
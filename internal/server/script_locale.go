@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// messagesFromDescribe returns the message catalogs recorded at create time.
+func messagesFromDescribe(desc *v1.ScriptDescribe) scriptengine.Messages {
+	return scriptengine.MessagesFromDescribe(map[string]any{
+		"messages":      desc.GetMessages().AsMap(),
+		"defaultLocale": desc.GetDefaultLocale(),
+	})
+}
+
+// scriptLocalePreference returns the languages to negotiate ctx.locale from:
+// the responding browser's Accept-Language when the run came from it, else
+// the locale req was last rendered for.
+func scriptLocalePreference(req *v1.UIRequest, acceptLanguage string) string {
+	if acceptLanguage != "" {
+		return acceptLanguage
+	}
+	return req.GetScriptLocale()
+}
+
+// localizeScriptView re-renders the view of pending script request id when
+// acceptLanguage negotiates to a locale other than the one the view was
+// rendered for, and stores the result. It returns nil when nothing changed.
+// Failures are logged; the caller's request still succeeds.
+//
+// A request holds one view, so the stored locale is that of the last
+// responder to touch it or send an event. The re-render is returned to the
+// touching browser only and not broadcast: other responders keep the view
+// they have until the next event, instead of flipping language whenever
+// someone else opens the request.
+func (s *Server) localizeScriptView(ctx context.Context, id string, acceptLanguage string) *v1.UIRequest {
+	if acceptLanguage == "" {
+		return nil
+	}
+	unlock := s.scriptEventLocks.Lock(id)
+	defer unlock()
+
+	req, err := s.store.Get(ctx, id)
+	if err != nil || req.Type != v1.WidgetType_script || req.Status != v1.RequestStatus_pending || req.GetScriptInput() == nil {
+		return nil
+	}
+	messages := messagesFromDescribe(req.GetScriptDescribe())
+	if messages.Negotiate(acceptLanguage) == req.GetScriptLocale() {
+		return nil
+	}
+
+	state := req.GetScriptState().AsMap()
+	in := req.GetScriptInput()
+	if seed, ok := stateSeedValue(state); ok {
+		if in, err = scriptInputWithSeed(in, seed); err != nil {
+			return nil
+		}
+	}
+	runnableInput, err := s.runnableScriptInput(ctx, in)
+	if err != nil {
+		return nil
+	}
+	viewResult, err := s.scripts.View(ctx, runnableInput, state,
+		scriptengine.WithGrants(grantsFromDescribe(req.GetScriptDescribe())...),
+		scriptengine.WithNow(time.Now()),
+		scriptengine.WithLocale(acceptLanguage),
+		scriptengine.WithMessages(messages),
+	)
+	if err != nil {
+		s.keepFailedRunGrants(ctx, id, err)
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Failed to re-render request %q for its responder's locale: %v", id, err)
+		return nil
+	}
+	viewProto, err := scriptview.FromMap(viewResult.View)
+	if err != nil {
+		s.keepScriptGrants(ctx, id, viewResult.Grants)
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Failed to re-render request %q for its responder's locale: %v", id, err)
+		return nil
+	}
+	next, err := s.store.PatchScript(ctx, id, nil, viewProto, viewResult.Logs, grantsToProto(viewResult.Grants), viewResult.Locale)
+	if err != nil {
+		return nil
+	}
+	return next
+}
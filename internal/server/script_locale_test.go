@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const scriptLocaleFlow = `
+module.exports = {
+  describe: function () {
+    return {
+      name: "i18n", version: "1.0.0",
+      messages: {
+        en: { confirm: "Deploy {env}?", pick: "Pick a region" },
+        de: { confirm: "{env} ausrollen?", pick: "Region wählen" }
+      }
+    };
+  },
+  init: function () { return { step: "confirm", env: "prod" }; },
+  view: function (state, ctx) {
+    if (state.step === "confirm") {
+      return { widgetType: "confirm", stepId: "confirm", input: { title: ctx.t("confirm", { env: state.env }) } };
+    }
+    return { widgetType: "select", stepId: "pick", input: { title: ctx.t("pick"), options: ["eu", "us"] } };
+  },
+  update: function (state, event, ctx) {
+    if (state.step === "confirm") {
+      state.step = "pick";
+      return state;
+    }
+    return { done: true, result: { region: event.data.selectedSingle, locale: ctx.locale } };
+  }
+};
+`
+
+func postWithLanguage(t *testing.T, h http.Handler, path string, acceptLanguage string, msg *v1.ScriptEvent) *v1.UIRequest {
+	t.Helper()
+
+	var body []byte
+	if msg != nil {
+		var err error
+		if body, err = protojson.Marshal(msg); err != nil {
+			t.Fatalf("marshal ScriptEvent: %v", err)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", acceptLanguage)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST %s failed status=%d body=%s", path, rr.Code, rr.Body.String())
+	}
+	out := &v1.UIRequest{}
+	if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+		t.Fatalf("unmarshal response: %v body=%s", err, rr.Body.String())
+	}
+	return out
+}
+
+func scriptViewTitle(req *v1.UIRequest) any {
+	return req.GetScriptView().GetInput().AsMap()["title"]
+}
+
+func TestScriptViewFollowsResponderLocale(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Localized", Script: scriptLocaleFlow},
+		},
+	})
+	if created.GetScriptLocale() != "en" || scriptViewTitle(created) != "Deploy prod?" {
+		t.Fatalf("expected default en view on create, got locale=%q title=%v", created.GetScriptLocale(), scriptViewTitle(created))
+	}
+	if created.GetScriptDescribe().GetMessages().AsMap()["de"] == nil {
+		t.Fatal("expected describe to record the message catalogs")
+	}
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+	conn := dialWS(t, ts.URL, "global")
+	defer func() {
+		_ = conn.Close()
+	}()
+	if eventType, _ := readWSEvent(t, conn); eventType != "new_request" {
+		t.Fatalf("expected the pending request on connect, got %s", eventType)
+	}
+
+	touched := postWithLanguage(t, h, "/api/requests/"+created.Id+"/touch", "de-DE,de;q=0.9,en;q=0.5", nil)
+	if touched.GetScriptLocale() != "de" || scriptViewTitle(touched) != "prod ausrollen?" {
+		t.Fatalf("expected touch to re-render in de, got locale=%q title=%v", touched.GetScriptLocale(), scriptViewTitle(touched))
+	}
+	if state := touched.GetScriptState().AsMap(); state["step"] != "confirm" {
+		t.Fatalf("expected re-render to keep state, got %v", state)
+	}
+
+	// Agent events carry no responder language, so the stored locale is kept.
+	picked := postScriptInject(t, h, created.Id, &v1.ScriptEvent{Type: "submit"})
+	if picked.GetScriptLocale() != "de" || scriptViewTitle(picked) != "Region wählen" {
+		t.Fatalf("expected inject to keep de, got locale=%q title=%v", picked.GetScriptLocale(), scriptViewTitle(picked))
+	}
+	// The touch re-render went to the toucher only; the session's first
+	// update is the inject.
+	if eventType, eventReq := readWSEvent(t, conn); eventType != "request_updated" || scriptViewTitle(eventReq) != "Region wählen" {
+		t.Fatalf("expected no broadcast for the touch re-render, got %s %v", eventType, scriptViewTitle(eventReq))
+	}
+
+	done := postWithLanguage(t, h, "/api/requests/"+created.Id+"/event", "en-GB", &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"selectedSingle": "eu"}),
+	})
+	if got := done.GetScriptOutput().GetResult().AsMap()["locale"]; got != "en" {
+		t.Fatalf("expected the final event to run in en, got %v", got)
+	}
+
+	history := getScriptHistory(t, h, created.Id, http.StatusOK)
+	var locales []string
+	for _, entry := range history.GetEntries() {
+		locales = append(locales, entry.GetLocale())
+	}
+	if len(locales) != 3 || locales[0] != "en" || locales[1] != "de" || locales[2] != "en" {
+		t.Fatalf("expected history locales [en de en], got %v", locales)
+	}
+
+	replay, _ := postScriptReplay(t, h, created.Id, nil, http.StatusOK)
+	if !replay.GetReproduced() {
+		t.Fatalf("expected localized flow to replay, mismatch=%q", replay.GetMismatch())
+	}
+}
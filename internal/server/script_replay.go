@@ -0,0 +1,304 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// errReplayUnavailable marks recorded data that cannot be replayed.
+var errReplayUnavailable = stderrors.New("script replay unavailable")
+
+// handleScriptReplay re-runs a finished script request from its stored input,
+// seed, and event history in a sandboxed engine, freezing the clock of each
+// run at the time it was recorded, and reports whether the runs and the
+// result match the recording.
+//
+// Paths:
+// - POST /api/requests/{id}/script/replay
+func (s *Server) handleScriptReplay(w http.ResponseWriter, r *http.Request, id string) {
+	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	replayReq := &v1.ScriptReplayRequest{}
+	if len(bytes.TrimSpace(bodyBytes)) > 0 {
+		if err := protojson.Unmarshal(bodyBytes, replayReq); err != nil {
+			http.Error(w, "invalid protojson ScriptReplayRequest: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	req, err := s.store.Get(r.Context(), id)
+	if err != nil {
+		if stderrors.Is(err, store.ErrNotFound) {
+			http.Error(w, "request not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if req.Type != v1.WidgetType_script {
+		http.Error(w, "request is not script widget", http.StatusBadRequest)
+		return
+	}
+	if req.Status == v1.RequestStatus_pending {
+		http.Error(w, "request still pending", http.StatusConflict)
+		return
+	}
+	history, err := s.store.ScriptHistory(r.Context(), id)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if history.GetDropped() > 0 {
+		http.Error(w, "script history is incomplete; oldest runs were dropped", http.StatusConflict)
+		return
+	}
+
+	in, err := s.replayScriptInput(r.Context(), req.GetScriptInput(), replayReq)
+	if err != nil {
+		if stderrors.Is(err, store.ErrScriptNotFound) {
+			http.Error(w, "script not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "invalid script input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	out, err := s.replayScript(r.Context(), req, in, history.GetEntries())
+	if err != nil {
+		if stderrors.Is(err, errReplayUnavailable) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "invalid script input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeProtoJSON(w, http.StatusOK, out)
+}
+
+// replayScriptInput returns the recorded input of a script request, with its
+// source swapped for the one in override when set. The recorded props, and so
+// the seed, are kept.
+func (s *Server) replayScriptInput(ctx context.Context, recorded *v1.ScriptInput, override *v1.ScriptReplayRequest) (*v1.ScriptInput, error) {
+	if recorded == nil {
+		return nil, fmt.Errorf("missing script input")
+	}
+	if override.Script == nil && override.ScriptRef == nil {
+		return recorded, nil
+	}
+	in, ok := proto.Clone(recorded).(*v1.ScriptInput)
+	if !ok {
+		return nil, fmt.Errorf("failed to clone script input")
+	}
+	in.Script = override.GetScript()
+	in.Language = override.Language
+	in.ScriptRef = nil
+	if ref := override.GetScriptRef(); ref != nil {
+		in.ScriptRef = &v1.ScriptRef{Name: ref.GetName(), Version: ref.Version}
+	}
+	return s.pinScriptRef(ctx, in)
+}
+
+// replayScript runs the init and event runs in entries against in and
+// compares each with its recording, stopping at the first difference. Back
+// presses are answered from state snapshots the same way applyScriptBack
+// does. Capabilities are not granted, so flows that depend on fs or fetch do
+// not reproduce.
+func (s *Server) replayScript(ctx context.Context, req *v1.UIRequest, in *v1.ScriptInput, entries []*v1.ScriptHistoryEntry) (*v1.ScriptReplay, error) {
+	if len(entries) == 0 || entries[0].GetEvent() != nil {
+		return nil, fmt.Errorf("%w: script history has no init run", errReplayUnavailable)
+	}
+	seed, ok := stateSeedValue(in.GetProps().AsMap())
+	if !ok {
+		return nil, fmt.Errorf("%w: recorded script input has no seed", errReplayUnavailable)
+	}
+	runnable, err := s.runnableScriptInput(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := s.scripts.Sandbox()
+	defer engine.Close()
+
+	out := &v1.ScriptReplay{RequestId: req.Id, ScriptRef: in.GetScriptRef()}
+	var (
+		state     *structpb.Struct
+		view      *v1.ScriptView
+		snapshots []*structpb.Struct
+		// outputSchema and messages come from the replayed script's describe().
+		outputSchema map[string]any
+		messages     scriptengine.Messages
+	)
+	for i, rec := range entries {
+		now, err := time.Parse(time.RFC3339Nano, rec.GetAt())
+		if err != nil {
+			return nil, fmt.Errorf("%w: history entry %d has invalid time %q", errReplayUnavailable, rec.GetSeq(), rec.GetAt())
+		}
+		started := time.Now()
+		entry := &v1.ScriptHistoryEntry{Seq: rec.GetSeq(), At: rec.GetAt(), Event: rec.GetEvent()}
+
+		event := rec.GetEvent()
+		switch {
+		case i == 0:
+			res, err := engine.InitAndView(ctx, runnable, scriptengine.WithNow(now), scriptengine.WithLocale(rec.GetLocale()))
+			if err != nil {
+				setReplayError(entry, err.Error())
+				break
+			}
+			entry.Logs = res.Logs
+			entry.Locale = &res.Locale
+			res.State = ensureSeedInState(res.State, seed)
+			next, nextView, desc, err := scriptInitResultToProto(res)
+			if err != nil {
+				setReplayError(entry, "script init result invalid: "+err.Error())
+				break
+			}
+			entry.Changes = scriptStateDiff(nil, next.AsMap())
+			setScriptHistoryView(entry, nextView)
+			state, view = next, nextView
+			outputSchema = outputSchemaFromDescribe(desc)
+			messages = messagesFromDescribe(desc)
+		case event == nil:
+			out.Mismatch = replayMismatch(rec, "recorded init run after the first run")
+		case state == nil:
+			out.Mismatch = replayMismatch(rec, "recorded event after the replayed flow finished")
+		case event.GetType() == scriptEventBack && event.GetSource() == scriptEventSourceUI &&
+			view.GetAllowBack() && len(snapshots) > 0:
+			restored := ensureSeedInState(snapshots[len(snapshots)-1].AsMap(), seed)
+			res, err := engine.View(ctx, runnable, restored, scriptengine.WithNow(now),
+				scriptengine.WithLocale(rec.GetLocale()), scriptengine.WithMessages(messages))
+			if err != nil {
+				setReplayError(entry, "script view failed: "+err.Error())
+				break
+			}
+			entry.Logs = res.Logs
+			entry.Locale = &res.Locale
+			next, err := mapToStruct(restored)
+			if err != nil {
+				setReplayError(entry, "invalid script state snapshot: "+err.Error())
+				break
+			}
+			nextView, err := scriptview.FromMap(res.View)
+			if err != nil {
+				setReplayError(entry, "invalid script view: "+err.Error())
+				break
+			}
+			snapshots = snapshots[:len(snapshots)-1]
+			entry.Changes = scriptStateDiff(state.AsMap(), next.AsMap())
+			setScriptHistoryView(entry, nextView)
+			state, view = next, nextView
+		default:
+			res, err := engine.UpdateAndView(ctx, runnable, state.AsMap(), eventToMap(event),
+				scriptengine.WithNow(now), scriptengine.WithOutputSchema(outputSchema),
+				scriptengine.WithLocale(rec.GetLocale()), scriptengine.WithMessages(messages))
+			if err != nil {
+				setReplayError(entry, "script update failed: "+err.Error())
+				break
+			}
+			entry.Logs = res.Logs
+			entry.Locale = &res.Locale
+			if res.Done {
+				result, err := mapToStruct(res.Result)
+				if err != nil {
+					setReplayError(entry, "invalid script result: "+err.Error())
+					break
+				}
+				entry.Done = true
+				out.Result = result
+				state, view = nil, nil
+				break
+			}
+			res.State = ensureSeedInState(res.State, seed)
+			next, nextView, err := scriptUpdateResultToProto(res)
+			if err != nil {
+				setReplayError(entry, "invalid script update result: "+err.Error())
+				break
+			}
+			if event.GetSource() == scriptEventSourceUI && event.GetType() != scriptEventBack && !proto.Equal(state, next) {
+				if len(snapshots) == store.MaxScriptSnapshots {
+					snapshots = snapshots[1:]
+				}
+				snapshots = append(snapshots, state)
+			}
+			entry.Changes = scriptStateDiff(state.AsMap(), next.AsMap())
+			setScriptHistoryView(entry, nextView)
+			state, view = next, nextView
+		}
+		if out.Mismatch != nil {
+			break
+		}
+		entry.DurationMs = time.Since(started).Milliseconds()
+		out.Entries = append(out.Entries, entry)
+		if diff := diffReplayEntry(rec, entry); diff != "" {
+			out.Mismatch = replayMismatch(rec, diff)
+			break
+		}
+	}
+
+	if out.Mismatch == nil && out.Result != nil {
+		if recorded := req.GetScriptOutput().GetResult(); !proto.Equal(recorded, out.Result) {
+			msg := "replayed result differs from the recorded result"
+			out.Mismatch = &msg
+		}
+	}
+	out.Reproduced = out.Mismatch == nil
+	return out, nil
+}
+
+func setReplayError(entry *v1.ScriptHistoryEntry, msg string) {
+	entry.Error = &msg
+}
+
+func replayMismatch(rec *v1.ScriptHistoryEntry, msg string) *string {
+	msg = fmt.Sprintf("seq %d: %s", rec.GetSeq(), msg)
+	return &msg
+}
+
+// diffReplayEntry describes the first difference between a recorded run and
+// its replay, or returns "" when they match. Logs, timings, and error text
+// are not compared.
+func diffReplayEntry(want, got *v1.ScriptHistoryEntry) string {
+	switch {
+	case want.Error != nil && got.Error == nil:
+		return "recorded run failed but the replay succeeded"
+	case want.Error == nil && got.Error != nil:
+		return "replay failed: " + got.GetError()
+	case want.GetDone() != got.GetDone():
+		if want.GetDone() {
+			return "recorded run finished the flow but the replay did not"
+		}
+		return "replay finished the flow but the recorded run did not"
+	case want.GetWidgetType() != got.GetWidgetType():
+		return fmt.Sprintf("replay showed widget %q, recorded %q", got.GetWidgetType(), want.GetWidgetType())
+	case want.GetStepId() != got.GetStepId():
+		return fmt.Sprintf("replay showed step %q, recorded %q", got.GetStepId(), want.GetStepId())
+	}
+	wantChanges, gotChanges := want.GetChanges(), got.GetChanges()
+	for i := 0; i < max(len(wantChanges), len(gotChanges)); i++ {
+		if i >= len(wantChanges) || i >= len(gotChanges) || !proto.Equal(wantChanges[i], gotChanges[i]) {
+			var path string
+			if i < len(wantChanges) {
+				path = wantChanges[i].GetPath()
+			} else {
+				path = gotChanges[i].GetPath()
+			}
+			return fmt.Sprintf("state change at %q differs", path)
+		}
+	}
+	return ""
+}
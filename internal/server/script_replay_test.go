@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const scriptReplayFlow = `
+module.exports = {
+  describe: function () { return { name: "replay", version: "1.0.0" }; },
+  init: function (ctx) { return { step: "pick", startedAt: ctx.now, token: ctx.randomInt(1, 1000000) }; },
+  view: function (state) {
+    if (state.step === "pick") {
+      return { widgetType: "select", stepId: "pick", input: { title: "Env", options: ["staging", "prod"] } };
+    }
+    return { widgetType: "confirm", stepId: "confirm", allowBack: true, input: { title: "Deploy to " + state.env + "?" } };
+  },
+  update: function (state, event, ctx) {
+    if (event.type === "boom") throw new Error("kaboom");
+    if (state.step === "pick") {
+      state.step = "confirm";
+      state.env = event.data.selectedSingle;
+      state.pickedAt = ctx.now;
+      return state;
+    }
+    return { done: true, result: { env: state.env, approved: event.data.approved, token: state.token, at: ctx.now, roll: ctx.random() } };
+  }
+};
+`
+
+func postScriptReplay(t *testing.T, h http.Handler, id string, body *v1.ScriptReplayRequest, wantStatus int) (*v1.ScriptReplay, string) {
+	t.Helper()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = protojson.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal ScriptReplayRequest: %v", err)
+		}
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/requests/"+id+"/script/replay", bytes.NewReader(payload)))
+	if rr.Code != wantStatus {
+		t.Fatalf("replay status=%d want=%d body=%s", rr.Code, wantStatus, rr.Body.String())
+	}
+	out := &v1.ScriptReplay{}
+	if wantStatus == http.StatusOK {
+		if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+			t.Fatalf("unmarshal ScriptReplay: %v body=%s", err, rr.Body.String())
+		}
+	}
+	return out, rr.Body.String()
+}
+
+func runScriptReplayFlow(t *testing.T, h http.Handler) *v1.UIRequest {
+	t.Helper()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Replay", Script: scriptReplayFlow},
+		},
+	})
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"selectedSingle": "staging"}),
+	})
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "back"})
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"selectedSingle": "prod"}),
+	})
+
+	body, err := protojson.Marshal(&v1.ScriptEvent{Type: "boom"})
+	if err != nil {
+		t.Fatalf("marshal ScriptEvent: %v", err)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/requests/"+created.Id+"/event", bytes.NewReader(body)))
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected failing event to return 422, got %d", rr.Code)
+	}
+
+	done := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": true}),
+	})
+	if done.Status != v1.RequestStatus_completed {
+		t.Fatalf("expected completed flow, got %s", done.Status)
+	}
+	return done
+}
+
+func TestScriptReplayReproducesRecordedFlow(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	done := runScriptReplayFlow(t, h)
+
+	// Replaying twice gives the same answer: the clock and seed are frozen.
+	for i := 0; i < 2; i++ {
+		replay, _ := postScriptReplay(t, h, done.Id, nil, http.StatusOK)
+		if !replay.GetReproduced() || replay.Mismatch != nil {
+			t.Fatalf("expected reproduced replay, got mismatch=%q", replay.GetMismatch())
+		}
+		if len(replay.GetEntries()) != 6 {
+			t.Fatalf("expected init plus 5 replayed events, got %d", len(replay.GetEntries()))
+		}
+		if replay.GetEntries()[4].Error == nil {
+			t.Fatalf("expected the failing event to fail again, got %v", replay.GetEntries()[4])
+		}
+		result := replay.GetResult().AsMap()
+		if result["env"] != "prod" || result["at"] != replay.GetEntries()[5].GetAt() {
+			t.Fatalf("expected result computed with the recorded clock, got %v", result)
+		}
+	}
+}
+
+func TestScriptReplayReportsChangedScript(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	done := runScriptReplayFlow(t, h)
+
+	changed := strings.Replace(scriptReplayFlow, `state.env = event.data.selectedSingle;`, `state.env = "dev";`, 1)
+	replay, _ := postScriptReplay(t, h, done.Id, &v1.ScriptReplayRequest{Script: &changed}, http.StatusOK)
+	if replay.GetReproduced() {
+		t.Fatal("expected changed script not to reproduce the recorded flow")
+	}
+	if !strings.Contains(replay.GetMismatch(), "seq 2: state change at \"env\" differs") {
+		t.Fatalf("unexpected mismatch: %q", replay.GetMismatch())
+	}
+	if len(replay.GetEntries()) != 2 {
+		t.Fatalf("expected replay to stop at the first difference, got %d entries", len(replay.GetEntries()))
+	}
+}
+
+func TestScriptReplayRejectsPendingAndNonScriptRequests(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	pending := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Replay", Script: scriptReplayFlow},
+		},
+	})
+	if _, body := postScriptReplay(t, h, pending.Id, nil, http.StatusConflict); !strings.Contains(body, "request still pending") {
+		t.Fatalf("unexpected body: %s", body)
+	}
+
+	confirm := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:  v1.WidgetType_confirm,
+		Input: &v1.UIRequest_ConfirmInput{ConfirmInput: &v1.ConfirmInput{Title: "ok?"}},
+	})
+	postScriptReplay(t, h, confirm.Id, nil, http.StatusBadRequest)
+	postScriptReplay(t, h, "missing", nil, http.StatusNotFound)
+}
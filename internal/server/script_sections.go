@@ -0,0 +1,236 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// scriptEventSubmit is the event type the browser sends for a widget submit.
+const scriptEventSubmit = "submit"
+
+// checksSectionAnswers reports whether event is a browser submit for a view
+// with several interactive sections, whose answers are checked before
+// update() runs.
+func checksSectionAnswers(req *v1.UIRequest, event *v1.ScriptEvent) bool {
+	return event.GetType() == scriptEventSubmit &&
+		event.GetSource() == scriptEventSourceUI &&
+		req.GetScriptView().GetWidgetType() == scriptview.SectionsWidgetType
+}
+
+// checksWidgetAnswer reports whether event is a browser submit for a
+// single-widget view whose answer is checked before update() runs, so a
+// client cannot skip the required ticks or hunk decisions.
+func checksWidgetAnswer(req *v1.UIRequest, event *v1.ScriptEvent) bool {
+	if event.GetType() != scriptEventSubmit || event.GetSource() != scriptEventSourceUI {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(req.GetScriptView().GetWidgetType())) {
+	case "checklist", "diff":
+		return true
+	}
+	return false
+}
+
+// validateWidgetAnswer checks event.data against the input of a
+// single-widget view and returns a message for the responder, or "" when it
+// is valid.
+func validateWidgetAnswer(view *v1.ScriptView, event *v1.ScriptEvent) string {
+	widgetType := strings.ToLower(strings.TrimSpace(view.GetWidgetType()))
+	return validateSectionAnswer(widgetType, view.GetInput().AsMap(), event.GetData().AsMap())
+}
+
+// validateSectionAnswers checks event.data.sections against the interactive
+// sections of view: one answer per section id, each shaped like that
+// widget's output. It returns nil when every answer is valid.
+func validateSectionAnswers(view *v1.ScriptView, event *v1.ScriptEvent) *v1.ScriptSectionErrors {
+	answers, ok := event.GetData().AsMap()["sections"].(map[string]any)
+	if !ok {
+		return &v1.ScriptSectionErrors{Message: "event.data.sections must be an object keyed by section id"}
+	}
+
+	var errs []*v1.ScriptSectionError
+	known := map[string]bool{}
+	for _, section := range view.GetSections() {
+		widgetType := strings.ToLower(strings.TrimSpace(section.GetWidgetType()))
+		if scriptview.ReadOnly(widgetType) {
+			continue
+		}
+		id := section.GetId()
+		known[id] = true
+		raw, ok := answers[id]
+		if !ok || raw == nil {
+			errs = append(errs, &v1.ScriptSectionError{SectionId: id, Message: "an answer is required"})
+			continue
+		}
+		answer, ok := raw.(map[string]any)
+		if !ok {
+			errs = append(errs, &v1.ScriptSectionError{SectionId: id, Message: "answer must be an object"})
+			continue
+		}
+		if msg := validateSectionAnswer(widgetType, section.GetInput().AsMap(), answer); msg != "" {
+			errs = append(errs, &v1.ScriptSectionError{SectionId: id, Message: msg})
+		}
+	}
+	for _, id := range sortedAnswerIDs(answers) {
+		if !known[id] {
+			errs = append(errs, &v1.ScriptSectionError{SectionId: id, Message: "unknown section"})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &v1.ScriptSectionErrors{
+		Message: fmt.Sprintf("%d section answer(s) failed validation", len(errs)),
+		Errors:  errs,
+	}
+}
+
+// validateSectionAnswer checks one answer against its widget's input and
+// returns a message for the responder, or "" when it is valid. Widgets
+// without specific rules only need an object.
+func validateSectionAnswer(widgetType string, input map[string]any, answer map[string]any) string {
+	switch widgetType {
+	case "confirm":
+		if _, ok := answer["approved"].(bool); !ok {
+			return "approved must be true or false"
+		}
+	case "select":
+		return validateSelectAnswer(input, answer)
+	case "rating":
+		scale := 5
+		if n, ok := scriptview.AsInt(input["scale"]); ok {
+			scale = n
+		}
+		value, ok := scriptview.AsInt(answer["value"])
+		if !ok || value < 1 || value > scale {
+			return fmt.Sprintf("pick a rating between 1 and %d", scale)
+		}
+	case "checklist":
+		return validateChecklistAnswer(input, answer)
+	case "diff":
+		return validateDiffAnswer(input, answer)
+	}
+	return ""
+}
+
+func validateSelectAnswer(input map[string]any, answer map[string]any) string {
+	allowed := map[string]bool{}
+	options, _ := input["options"].([]any)
+	for _, option := range options {
+		switch typed := option.(type) {
+		case string:
+			allowed[typed] = true
+		case map[string]any:
+			if value, ok := typed["value"].(string); ok {
+				allowed[value] = true
+			}
+		}
+	}
+	inOptions := func(v string) bool { return len(options) == 0 || allowed[v] }
+
+	if multi, _ := input["multi"].(bool); multi {
+		selected, _ := answer["selectedMulti"].(map[string]any)
+		values, _ := selected["values"].([]any)
+		if len(values) == 0 {
+			return "select at least one option"
+		}
+		for _, v := range values {
+			if s, ok := v.(string); !ok || !inOptions(s) {
+				return fmt.Sprintf("%v is not one of the options", v)
+			}
+		}
+		return ""
+	}
+	value, _ := answer["selectedSingle"].(string)
+	if value == "" {
+		return "select an option"
+	}
+	if !inOptions(value) {
+		return fmt.Sprintf("%q is not one of the options", value)
+	}
+	return ""
+}
+
+func validateChecklistAnswer(input map[string]any, answer map[string]any) string {
+	checked := map[string]bool{}
+	if raw, ok := answer["checked"]; ok {
+		values, ok := raw.([]any)
+		if !ok {
+			return "checked must be an array of item ids"
+		}
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				checked[s] = true
+			}
+		}
+	}
+	items, _ := input["items"].([]any)
+	var missing []string
+	for _, raw := range items {
+		item, _ := raw.(map[string]any)
+		id, _ := item["id"].(string)
+		if required, ok := item["required"].(bool); ok && !required {
+			continue
+		}
+		if !checked[id] {
+			label, _ := item["label"].(string)
+			missing = append(missing, label)
+		}
+	}
+	if len(missing) > 0 {
+		return "tick the required items: " + strings.Join(missing, ", ")
+	}
+	return ""
+}
+
+func validateDiffAnswer(input map[string]any, answer map[string]any) string {
+	decided := map[string]bool{}
+	if raw, ok := answer["decisions"]; ok {
+		values, ok := raw.([]any)
+		if !ok {
+			return "decisions must be an array"
+		}
+		for _, v := range values {
+			decision, _ := v.(map[string]any)
+			id, _ := decision["hunkId"].(string)
+			switch decision["decision"] {
+			case "approve", "reject":
+				decided[id] = true
+			default:
+				return fmt.Sprintf("decision for hunk %q must be approve or reject", id)
+			}
+		}
+	}
+	if requireAll, _ := input["requireAll"].(bool); !requireAll {
+		return ""
+	}
+	var missing []string
+	files, _ := input["files"].([]any)
+	for _, rawFile := range files {
+		file, _ := rawFile.(map[string]any)
+		hunks, _ := file["hunks"].([]any)
+		for _, rawHunk := range hunks {
+			hunk, _ := rawHunk.(map[string]any)
+			if id, _ := hunk["id"].(string); !decided[id] {
+				missing = append(missing, id)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return "decide every hunk: " + strings.Join(missing, ", ")
+	}
+	return ""
+}
+
+func sortedAnswerIDs(answers map[string]any) []string {
+	ids := make([]string, 0, len(answers))
+	for id := range answers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const scriptMultiSectionFlow = `
+module.exports = {
+  describe: function () { return { name: "release-check", version: "1.0.0" }; },
+  init: function () { return { step: "ask" }; },
+  view: function () {
+    return {
+      stepId: "ask",
+      sections: [
+        { widgetType: "display", input: { content: "Release 1.4" } },
+        { id: "env", widgetType: "select", input: { title: "Environment", options: ["staging", "prod"] } },
+        { id: "confidence", widgetType: "rating", input: { title: "Confidence", scale: 5 } },
+        { id: "checks", widgetType: "checklist", input: { items: [
+          { id: "tests", label: "Tests pass" },
+          { id: "notes", label: "Release notes", required: false }
+        ] } }
+      ]
+    };
+  },
+  update: function (state, event) {
+    var s = event.data.sections;
+    return { done: true, result: { env: s.env.selectedSingle, confidence: s.confidence.value, checked: s.checks.checked } };
+  }
+};
+`
+
+func postScriptEventStatus(t *testing.T, h http.Handler, id string, ev *v1.ScriptEvent, wantStatus int) []byte {
+	t.Helper()
+
+	body, err := protojson.Marshal(ev)
+	if err != nil {
+		t.Fatalf("marshal ScriptEvent: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/requests/"+id+"/event", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != wantStatus {
+		t.Fatalf("expected status %d, got %d body=%s", wantStatus, rr.Code, rr.Body.String())
+	}
+	return rr.Body.Bytes()
+}
+
+func TestScriptMultiSectionSubmit(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Release check", Script: scriptMultiSectionFlow},
+		},
+	})
+	view := created.GetScriptView()
+	if view.GetWidgetType() != "sections" || len(view.GetSections()) != 4 || view.GetSections()[1].GetId() != "env" {
+		t.Fatalf("expected a sections view keyed by id, got %v", view)
+	}
+
+	body := postScriptEventStatus(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"sections": map[string]any{
+			"env":        map[string]any{"selectedSingle": "dev"},
+			"confidence": map[string]any{"value": 9},
+			"extra":      map[string]any{},
+		}}),
+	}, http.StatusUnprocessableEntity)
+	errs := &v1.ScriptSectionErrors{}
+	if err := protojson.Unmarshal(body, errs); err != nil {
+		t.Fatalf("unmarshal section errors: %v body=%s", err, body)
+	}
+	got := map[string]string{}
+	for _, e := range errs.GetErrors() {
+		got[e.GetSectionId()] = e.GetMessage()
+	}
+	want := map[string]string{
+		"env":        `"dev" is not one of the options`,
+		"confidence": "pick a rating between 1 and 5",
+		"checks":     "an answer is required",
+		"extra":      "unknown section",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected errors %v, got %v", want, got)
+	}
+	for id, msg := range want {
+		if got[id] != msg {
+			t.Fatalf("expected %q for %s, got %q", msg, id, got[id])
+		}
+	}
+	if pending := getRequest(t, h, created.Id); pending.Status != v1.RequestStatus_pending {
+		t.Fatalf("expected rejected submit to leave the request pending, got %s", pending.Status)
+	}
+
+	done := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"sections": map[string]any{
+			"env":        map[string]any{"selectedSingle": "prod"},
+			"confidence": map[string]any{"value": 4},
+			"checks":     map[string]any{"checked": []any{"tests"}},
+		}}),
+	})
+	result := done.GetScriptOutput().GetResult().AsMap()
+	if done.Status != v1.RequestStatus_completed || result["env"] != "prod" || result["confidence"] != float64(4) {
+		t.Fatalf("expected bundled answers to reach update(), got status=%s result=%v", done.Status, result)
+	}
+}
+
+const scriptReviewFlow = `
+module.exports = {
+  describe: function () { return { name: "review", version: "1.0.0" }; },
+  init: function () { return { step: "checks" }; },
+  view: function (state) {
+    if (state.step === "checks") {
+      return { widgetType: "checklist", input: { title: "Before merging", items: [
+        { id: "tests", label: "Tests pass" },
+        { id: "notes", label: "Release notes", required: false }
+      ] } };
+    }
+    return { widgetType: "diff", input: { title: "Review", requireAll: true, files: [
+      { path: "main.go", hunks: [
+        { id: "h1", lines: ["+a"] },
+        { id: "h2", lines: ["-b"] }
+      ] }
+    ] } };
+  },
+  update: function (state, event) {
+    if (state.step === "checks") return { step: "review" };
+    return { done: true, result: { approved: event.data.approved } };
+  }
+};
+`
+
+func TestScriptSingleWidgetAnswersAreChecked(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Review", Script: scriptReviewFlow},
+		},
+	})
+
+	body := postScriptEventStatus(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"checked": []any{"notes"}}),
+	}, http.StatusUnprocessableEntity)
+	if got := string(bytes.TrimSpace(body)); got != "tick the required items: Tests pass" {
+		t.Fatalf("unexpected checklist error %q", got)
+	}
+
+	review := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"checked": []any{"tests"}}),
+	})
+	if review.GetScriptView().GetWidgetType() != "diff" {
+		t.Fatalf("expected the diff step, got %v", review.GetScriptView())
+	}
+
+	body = postScriptEventStatus(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{
+			"decisions": []any{map[string]any{"hunkId": "h1", "decision": "approve"}},
+			"approved":  true,
+		}),
+	}, http.StatusUnprocessableEntity)
+	if got := string(bytes.TrimSpace(body)); got != "decide every hunk: h2" {
+		t.Fatalf("unexpected diff error %q", got)
+	}
+
+	done := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{
+			"decisions": []any{
+				map[string]any{"hunkId": "h1", "decision": "approve"},
+				map[string]any{"hunkId": "h2", "decision": "reject"},
+			},
+			"approved": false,
+		}),
+	})
+	if done.Status != v1.RequestStatus_completed {
+		t.Fatalf("expected a complete review to reach update(), got %s", done.Status)
+	}
+}
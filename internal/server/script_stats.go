@@ -0,0 +1,38 @@
+package server
+
+import "net/http"
+
+type scriptStatsResponse struct {
+	ProgramCache programCacheStatsResponse `json:"programCache"`
+	WarmRuntimes int                       `json:"warmRuntimes"`
+}
+
+type programCacheStatsResponse struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+	Capacity  int    `json:"capacity"`
+}
+
+// handleScriptStats reports script engine cache activity.
+//
+// Paths:
+// - GET /api/scripts/stats
+func (s *Server) handleScriptStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	stats := s.scripts.Stats()
+	writeJSON(w, http.StatusOK, scriptStatsResponse{
+		ProgramCache: programCacheStatsResponse{
+			Hits:      stats.Programs.Hits,
+			Misses:    stats.Programs.Misses,
+			Evictions: stats.Programs.Evictions,
+			Entries:   stats.Programs.Entries,
+			Capacity:  stats.Programs.Capacity,
+		},
+		WarmRuntimes: stats.WarmRuntimes,
+	})
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+func TestScriptStatsReportsProgramCache(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	script := `
+module.exports = {
+  describe: function () { return { name: "stats", version: "1.0.0" }; },
+  init: function () { return {}; },
+  view: function () { return { widgetType: "confirm", input: { title: "ok?" } }; },
+  update: function () { return { done: true, result: {} }; }
+};`
+	for i := 0; i < 3; i++ {
+		postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+			Type: v1.WidgetType_script,
+			Input: &v1.UIRequest_ScriptInput{
+				ScriptInput: &v1.ScriptInput{Title: "Stats", Script: script},
+			},
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/scripts/stats", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("stats status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	var got scriptStatsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal stats: %v", err)
+	}
+	if got.ProgramCache.Misses != 1 || got.ProgramCache.Hits != 2 || got.ProgramCache.Entries != 1 {
+		t.Fatalf("unexpected program cache stats: %+v", got.ProgramCache)
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
 	"github.com/go-go-golems/plz-confirm/internal/store"
 	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -319,6 +324,108 @@ module.exports = {
 	}
 }
 
+func TestScriptOutputSchemaRejectsMismatchedResult(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	typedScript := `
+module.exports = {
+  describe: function () {
+    return {
+      name: "typed", version: "1.0.0",
+      outputSchema: {
+        type: "object",
+        required: ["approved"],
+        properties: { approved: { type: "boolean" }, replicas: { type: "integer", minimum: 1 } }
+      }
+    };
+  },
+  init: function () { return { step: "confirm" }; },
+  view: function () { return { widgetType: "confirm", input: { title: "Deploy?" } }; },
+  update: function (state, event) { return { done: true, result: event.data }; }
+};
+`
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_script,
+		SessionId: "global",
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Typed", Script: typedScript},
+		},
+	})
+	schema := created.GetScriptDescribe().GetOutputSchema().AsMap()
+	if schema["type"] != "object" {
+		t.Fatalf("expected describe to record the output schema, got %v", schema)
+	}
+
+	ev := &v1.ScriptEvent{Type: "submit", Data: mustStruct(t, map[string]any{"approved": true, "replicas": 0})}
+	body, err := protojson.Marshal(ev)
+	if err != nil {
+		t.Fatalf("marshal ScriptEvent: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/requests/"+created.Id+"/event", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for schema mismatch, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "result.replicas must be >= 1") {
+		t.Fatalf("expected violation in error body, got %s", rr.Body.String())
+	}
+	if got := getRequest(t, h, created.Id); got.Status != v1.RequestStatus_pending {
+		t.Fatalf("expected request to stay pending, got %v", got.Status)
+	}
+	history := getScriptHistory(t, h, created.Id, http.StatusOK)
+	last := history.GetEntries()[len(history.GetEntries())-1]
+	if !strings.Contains(last.GetError(), "outputSchema") {
+		t.Fatalf("expected history to record the schema error, got %q", last.GetError())
+	}
+
+	done := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": true, "replicas": 2}),
+	})
+	if done.Status != v1.RequestStatus_completed {
+		t.Fatalf("expected matching result to complete the request, got %v", done.Status)
+	}
+}
+
+func TestScriptResourceLimitsMapTo4xx(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New(), WithScriptEngineOptions(scriptengine.WithResourceLimits(0, 64, 1024))).Handler()
+	create := func(initBody string) *httptest.ResponseRecorder {
+		t.Helper()
+		script := `
+module.exports = {
+  describe: function () { return { name: "limits", version: "1.0.0" }; },
+  init: function () { ` + initBody + ` },
+  view: function (state) { return { widgetType: "confirm", input: { title: state.title } }; },
+  update: function (state) { return state; }
+};
+`
+		body, err := protojson.Marshal(&v1.UIRequest{
+			Type:  v1.WidgetType_script,
+			Input: &v1.UIRequest_ScriptInput{ScriptInput: &v1.ScriptInput{Title: "Limits", Script: script}},
+		})
+		if err != nil {
+			t.Fatalf("marshal create req: %v", err)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/requests", bytes.NewReader(body)))
+		return rr
+	}
+
+	if rr := create(`return { title: new Array(4000).join("x") };`); rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for oversized state, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := create(`function deep() { return deep(); } return { title: deep() };`); rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for stack overflow, got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
 func TestScriptLifecycleWithGridWidget(t *testing.T) {
 	t.Parallel()
 
@@ -553,8 +660,8 @@ module.exports = {
 	if rr.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400 for invalid sections, got %d body=%s", rr.Code, rr.Body.String())
 	}
-	if !bytes.Contains(rr.Body.Bytes(), []byte("exactly one interactive")) {
-		t.Fatalf("expected interactive section validation message, got body=%s", rr.Body.String())
+	if !bytes.Contains(rr.Body.Bytes(), []byte("id is required when a view has several interactive sections")) {
+		t.Fatalf("expected missing section id message, got body=%s", rr.Body.String())
 	}
 }
 
@@ -1250,6 +1357,228 @@ module.exports = {
 	}
 }
 
+func TestScriptFSGrantIsRecordedAndReappliedOnEvents(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("ship it"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	s := New(store.New(), WithScriptEngineOptions(scriptengine.WithFSRoots(root)))
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{
+				Title: "Review",
+				Script: `
+module.exports = {
+  describe: function () { return { name: "review", version: "1.0.0", capabilities: ["fs"] }; },
+  init: function () { return {}; },
+  view: function () { return { widgetType: "confirm", input: { title: "Approve?" } }; },
+  update: function (state, event, ctx) {
+    return { done: true, result: { notes: ctx.fs.readFile("notes.txt") } };
+  }
+};`,
+			},
+		},
+	})
+	grants := created.GetScriptDescribe().GetGrants()
+	if len(grants) != 1 || grants[0].GetCapability() != "fs" || len(grants[0].GetScope()) != 1 || grants[0].GetScope()[0] != root {
+		t.Fatalf("expected fs grant for %s, got %v", root, grants)
+	}
+
+	completed := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "submit"})
+	if got := completed.GetScriptOutput().GetResult().AsMap()["notes"]; got != "ship it" {
+		t.Fatalf("expected update to read granted file, got %v", got)
+	}
+}
+
+func TestScriptFetchBudgetIsPersistedAcrossEvents(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"green"}`))
+	}))
+	defer upstream.Close()
+
+	s := New(store.New(), WithScriptEngineOptions(
+		scriptengine.WithFetchAllowlist("127.0.0.1"),
+		scriptengine.WithFetchLimits(2, 0),
+	))
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{
+				Title: "Deploy",
+				Props: mustStruct(t, map[string]any{"url": upstream.URL}),
+				Script: `
+module.exports = {
+  describe: function () { return { name: "deploy", version: "1.0.0", capabilities: ["fetch"] }; },
+  init: function (ctx) { return { checks: [JSON.parse(ctx.fetch(ctx.props.url).body).status] }; },
+  view: function () { return { widgetType: "confirm", input: { title: "Deploy?" } }; },
+  update: function (state, event, ctx) {
+    var check;
+    try { check = JSON.parse(ctx.fetch(ctx.props.url).body).status; } catch (e) { check = "denied"; }
+    return { checks: state.checks.concat([check]) };
+  }
+};`,
+			},
+		},
+	})
+	grants := created.GetScriptDescribe().GetGrants()
+	if len(grants) != 1 || grants[0].GetCapability() != "fetch" || grants[0].GetCallsUsed() != 1 {
+		t.Fatalf("expected fetch grant with one call used, got %v", grants)
+	}
+
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "refresh"})
+	updated := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "refresh"})
+	checks, _ := updated.GetScriptState().AsMap()["checks"].([]any)
+	if len(checks) != 3 || checks[1] != "green" || checks[2] != "denied" {
+		t.Fatalf("expected third fetch to exceed the call budget, got %v", checks)
+	}
+	if got := updated.GetScriptDescribe().GetGrants()[0].GetCallsUsed(); got != 2 {
+		t.Fatalf("expected persisted calls used = 2, got %d", got)
+	}
+	if !slices.ContainsFunc(updated.GetScriptLogs(), func(line string) bool {
+		return strings.HasPrefix(line, "[fetch] GET "+upstream.URL+" denied: call budget exhausted")
+	}) {
+		t.Fatalf("expected budget denial in script logs, got %v", updated.GetScriptLogs())
+	}
+}
+
+func TestScriptFetchBudgetSurvivesFailedEvents(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	s := New(store.New(), WithScriptEngineOptions(
+		scriptengine.WithFetchAllowlist("127.0.0.1"),
+		scriptengine.WithFetchLimits(3, 0),
+	))
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{
+				Title: "Thrower",
+				Props: mustStruct(t, map[string]any{"url": upstream.URL}),
+				Script: `
+module.exports = {
+  describe: function () { return { name: "thrower", version: "1.0.0", capabilities: ["fetch"] }; },
+  init: function () { return {}; },
+  view: function () { return { widgetType: "confirm", input: { title: "Go?" } }; },
+  update: function (state, event, ctx) {
+    var fetched = 0;
+    try { ctx.fetch(ctx.props.url); fetched++; ctx.fetch(ctx.props.url); fetched++; } catch (e) {}
+    throw new Error("fetched " + fetched);
+  }
+};`,
+			},
+		},
+	})
+
+	for _, want := range []string{"fetched 2", "fetched 1", "fetched 0"} {
+		body, err := protojson.Marshal(&v1.ScriptEvent{Type: "submit"})
+		if err != nil {
+			t.Fatalf("marshal ScriptEvent: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/requests/"+created.Id+"/event", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusUnprocessableEntity || !strings.Contains(rr.Body.String(), want) {
+			t.Fatalf("expected 422 with %q, got %d body=%s", want, rr.Code, rr.Body.String())
+		}
+	}
+	if got := getRequest(t, h, created.Id).GetScriptDescribe().GetGrants()[0].GetCallsUsed(); got != 3 {
+		t.Fatalf("expected failed runs to spend the budget, got calls used = %d", got)
+	}
+}
+
+func TestScriptEventsReuseWarmRuntime(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New(), WithScriptEngineOptions(scriptengine.WithWarmRuntimes(4, time.Minute)))
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{
+				Title: "Counter",
+				Script: `
+var updates = 0;
+var initialized = false;
+module.exports = {
+  describe: function () { return { name: "counter", version: "1.0.0" }; },
+  init: function () { initialized = true; return { n: 0 }; },
+  view: function (state) { return { widgetType: "confirm", input: { title: "n=" + state.n } }; },
+  update: function (state, event) {
+    updates++;
+    if (event.type === "finish") return { done: true, result: { updates: updates, initialized: initialized } };
+    return { n: state.n + 1 };
+  }
+};`,
+			},
+		},
+	})
+
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "tick"})
+	postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "tick"})
+	completed := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "finish"})
+	result := completed.GetScriptOutput().GetResult().AsMap()
+	if result["updates"] != float64(3) {
+		t.Fatalf("expected module scope to survive between events, got updates=%v", result["updates"])
+	}
+	if result["initialized"] != true {
+		t.Fatalf("expected the first event to reuse the init runtime, got %v", result)
+	}
+}
+
+func TestScriptDescribeListsLibraryModules(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wizard@2.1.0.js"), []byte(`exports.title = function () { return "From library"; };`), 0o600); err != nil {
+		t.Fatalf("write module: %v", err)
+	}
+	s := New(store.New(), WithScriptEngineOptions(scriptengine.WithLibraryDir(dir)))
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{
+				Title: "Library",
+				Script: `
+var wizard = require("plz/wizard");
+module.exports = {
+  describe: function () { return { name: "lib", version: "1.0.0" }; },
+  init: function () { return {}; },
+  view: function () { return { widgetType: "confirm", input: { title: wizard.title() } }; },
+  update: function () { return { done: true, result: {} }; }
+};`,
+			},
+		},
+	})
+	modules := created.GetScriptDescribe().GetModules()
+	if len(modules) != 1 || modules[0].GetName() != "plz/wizard" || modules[0].GetVersion() != "2.1.0" || modules[0].GetSha256() == "" {
+		t.Fatalf("expected plz/wizard@2.1.0 in describe, got %v", modules)
+	}
+	if got := created.GetScriptView().GetInput().AsMap()["title"]; got != "From library" {
+		t.Fatalf("expected view to use library module, got %v", got)
+	}
+}
+
 func postUIRequest(t *testing.T, h http.Handler, path string, reqProto *v1.UIRequest) *v1.UIRequest {
 	t.Helper()
 
@@ -1321,3 +1650,185 @@ func mustStruct(t *testing.T, m map[string]any) *structpb.Struct {
 func toPtr[T any](v T) *T {
 	return &v
 }
+
+func TestScriptLifecycleWithReviewWidgets(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	reviewScript := `
+module.exports = {
+  describe: function () { return { name: "review-demo", version: "1.0.0" }; },
+  init: function () { return { step: "diff" }; },
+  view: function (state) {
+    if (state.step === "diff") {
+      return {
+        stepId: "diff",
+        sections: [
+          {
+            widgetType: "keyvalue",
+            input: {
+              title: "Release",
+              items: [
+                { key: "Version", value: "1.4.0", monospace: true },
+                { key: "Files", value: 1 },
+                { key: "Breaking", value: false, style: "success" }
+              ]
+            }
+          },
+          {
+            widgetType: "diff",
+            input: {
+              title: "Review changes",
+              layout: "split",
+              requireAll: true,
+              files: [{
+                path: "main.go",
+                language: "go",
+                hunks: [{ id: "h1", header: "@@ -1,2 +1,2 @@", lines: [" package main", "-var x = 1", "+var x = 2"] }]
+              }]
+            }
+          }
+        ]
+      };
+    }
+    return {
+      widgetType: "checklist",
+      stepId: "checks",
+      input: {
+        title: "Before shipping",
+        items: [
+          { id: "tests", label: "Tests pass" },
+          { id: "notes", label: "Release notes", required: false, checked: true }
+        ]
+      }
+    };
+  },
+  update: function (state, event) {
+    if (state.step === "diff") {
+      state.approved = event.data.approved;
+      state.step = "checks";
+      return state;
+    }
+    return { done: true, result: { approved: state.approved, checked: event.data.checked } };
+  }
+};
+`
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_script,
+		SessionId: "global",
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Review", Script: reviewScript},
+		},
+	})
+	view := created.GetScriptView()
+	if view.GetWidgetType() != "diff" {
+		t.Fatalf("expected keyvalue section to be read-only and diff interactive, got %q", view.GetWidgetType())
+	}
+	if len(view.GetSections()) != 2 || view.GetSections()[0].GetWidgetType() != "keyvalue" {
+		t.Fatalf("expected keyvalue and diff sections, got %+v", view.GetSections())
+	}
+
+	atChecks := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type:   "submit",
+		StepId: toPtr("diff"),
+		Data: mustStruct(t, map[string]any{
+			"decisions": []any{map[string]any{"hunkId": "h1", "decision": "approve"}},
+			"approved":  true,
+		}),
+	})
+	if got := atChecks.GetScriptView().GetWidgetType(); got != "checklist" {
+		t.Fatalf("expected checklist widget type, got %q", got)
+	}
+
+	completed := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type:   "submit",
+		StepId: toPtr("checks"),
+		Data:   mustStruct(t, map[string]any{"checked": []any{"tests", "notes"}}),
+	})
+	if completed.GetStatus() != v1.RequestStatus_completed {
+		t.Fatalf("expected completed status, got %v", completed.GetStatus())
+	}
+	result := completed.GetScriptOutput().GetResult().AsMap()
+	if result["approved"] != true || len(result["checked"].([]any)) != 2 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestScriptCreateRejectsInvalidReviewWidgets(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+
+	cases := []struct {
+		name    string
+		view    string
+		message string
+	}{
+		{
+			name:    "diff without files",
+			view:    `{ widgetType: "diff", input: { title: "x", files: [] } }`,
+			message: "files must be a non-empty array",
+		},
+		{
+			name:    "diff duplicate hunk ids",
+			view:    `{ widgetType: "diff", input: { files: [{ path: "a", hunks: [{ id: "h", lines: [] }, { id: "h", lines: [] }] }] } }`,
+			message: "is not unique",
+		},
+		{
+			name:    "diff bad layout",
+			view:    `{ widgetType: "diff", input: { layout: "wide", files: [{ path: "a", hunks: [{ id: "h", lines: [] }] }] } }`,
+			message: "layout must be unified or split",
+		},
+		{
+			name:    "keyvalue object value",
+			view:    `{ widgetType: "keyvalue", input: { items: [{ key: "k", value: { nested: true } }] } }`,
+			message: "value must be string, number, or boolean",
+		},
+		{
+			name:    "checklist missing label",
+			view:    `{ widgetType: "checklist", input: { items: [{ id: "a" }] } }`,
+			message: "label is required",
+		},
+		{
+			name:    "keyvalue is not interactive",
+			view:    `{ sections: [{ widgetType: "keyvalue", input: { items: [{ key: "k", value: "v" }] } }] }`,
+			message: "at least one interactive section",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			script := fmt.Sprintf(`
+module.exports = {
+  describe: function () { return { name: "review-bad", version: "1.0.0" }; },
+  init: function () { return {}; },
+  view: function () { return %s; },
+  update: function (state) { return state; }
+};
+`, tc.view)
+			body, err := protojson.Marshal(&v1.UIRequest{
+				Type:      v1.WidgetType_script,
+				SessionId: "global",
+				Input: &v1.UIRequest_ScriptInput{
+					ScriptInput: &v1.ScriptInput{Title: "Review bad", Script: script},
+				},
+			})
+			if err != nil {
+				t.Fatalf("marshal create req: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/requests", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d body=%s", rr.Code, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tc.message) {
+				t.Fatalf("expected %q in body=%s", tc.message, rr.Body.String())
+			}
+		})
+	}
+}
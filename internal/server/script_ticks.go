@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// minScriptTickDelay keeps a script that schedules afterMs: 0 in a loop from
+// monopolizing the server.
+const minScriptTickDelay = 100 * time.Millisecond
+
+// scriptTicks holds at most one pending tick per request. Each schedule bumps
+// the request's generation so a timer that fires after being replaced or
+// cancelled can tell it is stale.
+type scriptTicks struct {
+	mu      sync.Mutex
+	next    uint64
+	pending map[string]scheduledTick
+	stopped bool
+}
+
+type scheduledTick struct {
+	gen   uint64
+	timer *time.Timer
+}
+
+func newScriptTicks() *scriptTicks {
+	return &scriptTicks{pending: map[string]scheduledTick{}}
+}
+
+// schedule replaces any pending tick for id with fn after delay.
+func (t *scriptTicks) schedule(id string, delay time.Duration, fn func(gen uint64)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	if prev, ok := t.pending[id]; ok {
+		prev.timer.Stop()
+	}
+	t.next++
+	gen := t.next
+	t.pending[id] = scheduledTick{gen: gen, timer: time.AfterFunc(delay, func() { fn(gen) })}
+}
+
+// claim removes the pending tick for id if it is still generation gen.
+func (t *scriptTicks) claim(id string, gen uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cur, ok := t.pending[id]
+	if !ok || cur.gen != gen {
+		return false
+	}
+	delete(t.pending, id)
+	return true
+}
+
+func (t *scriptTicks) cancel(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cur, ok := t.pending[id]; ok {
+		cur.timer.Stop()
+		delete(t.pending, id)
+	}
+}
+
+func (t *scriptTicks) size() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+// stop cancels all pending ticks and rejects new ones.
+func (t *scriptTicks) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	for id, cur := range t.pending {
+		cur.timer.Stop()
+		delete(t.pending, id)
+	}
+}
+
+// scheduleScriptTick arms the tick requested by a script view, replacing any
+// earlier one. A view without a schedule cancels the pending tick.
+func (s *Server) scheduleScriptTick(id string, sched *scriptengine.Schedule) {
+	if sched == nil {
+		s.ticks.cancel(id)
+		return
+	}
+	event, err := scheduledEvent(sched.Event)
+	if err != nil {
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Request %q scheduled an invalid tick event: %v", id, err)
+		s.ticks.cancel(id)
+		return
+	}
+	s.ticks.schedule(id, max(sched.After, minScriptTickDelay), func(gen uint64) {
+		s.runScriptTick(id, gen, event)
+	})
+}
+
+// runScriptTick delivers a scheduled event through the same path as UI
+// events. Ticks that were replaced or cancelled while waiting for the lock
+// are dropped.
+func (s *Server) runScriptTick(id string, gen uint64, event *v1.ScriptEvent) {
+	unlock := s.scriptEventLocks.Lock(id)
+	defer unlock()
+	if !s.ticks.claim(id, gen) {
+		return
+	}
+	if _, err := s.applyScriptEvent(context.Background(), id, event, ""); err != nil {
+		// #nosec G706 -- id is server-generated and quoted for log safety.
+		log.Printf("[SCRIPT] Request %q scheduled %q event failed: %v", id, event.GetType(), err)
+	}
+}
+
+func scheduledEvent(m map[string]any) (*v1.ScriptEvent, error) {
+	ev := &v1.ScriptEvent{Source: scriptEventSourceSchedule}
+	ev.Type, _ = m["type"].(string)
+	if stepID, ok := m["stepId"].(string); ok && stepID != "" {
+		ev.StepId = &stepID
+	}
+	if actionID, ok := m["actionId"].(string); ok && actionID != "" {
+		ev.ActionId = &actionID
+	}
+	if raw, ok := m["data"]; ok && raw != nil {
+		data, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("event.data must be an object")
+		}
+		st, err := structpb.NewStruct(data)
+		if err != nil {
+			return nil, err
+		}
+		ev.Data = st
+	}
+	return ev, nil
+}
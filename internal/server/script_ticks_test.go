@@ -0,0 +1,118 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+const scriptCIWait = `
+module.exports = {
+  describe: function () { return { name: "ci-wait", version: "1.0.0" }; },
+  init: function () { return { step: "waiting", polls: 0 }; },
+  view: function (state) {
+    if (state.step === "waiting") {
+      return {
+        widgetType: "display",
+        input: { title: "CI", content: "Waiting for CI… (" + state.polls + ")" },
+        schedule: { afterMs: 0, event: { type: "tick", data: { source: "ci" } } }
+      };
+    }
+    return { widgetType: "confirm", input: { title: "CI is green. Deploy?" } };
+  },
+  update: function (state, event) {
+    if (event.type === "tick" && event.source === "schedule") {
+      state.polls++;
+      if (state.polls >= 2) state.step = "ready";
+      return state;
+    }
+    if (event.type === "skip") {
+      state.step = "ready";
+      return state;
+    }
+    return { done: true, result: { approved: event.data.approved, polls: state.polls } };
+  }
+};`
+
+func waitForScriptView(t *testing.T, s *Server, id string, cond func(*v1.UIRequest) bool) *v1.UIRequest {
+	t.Helper()
+	h := s.Handler()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		req := getRequest(t, h, id)
+		if cond(req) {
+			return req
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for scheduled ticks; last view=%v state=%v", req.GetScriptView(), req.GetScriptState().AsMap())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestScriptScheduledTicksAdvanceFlow(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "CI wait", Script: scriptCIWait},
+		},
+	})
+	if got := created.GetScriptView().GetWidgetType(); got != "display" {
+		t.Fatalf("expected waiting display view, got %q", got)
+	}
+
+	ready := waitForScriptView(t, s, created.Id, func(req *v1.UIRequest) bool {
+		return req.GetScriptView().GetWidgetType() == "confirm"
+	})
+	if polls := ready.GetScriptState().AsMap()["polls"]; polls != float64(2) {
+		t.Fatalf("expected two ticks before ready, got polls=%v", polls)
+	}
+	if n := s.ticks.size(); n != 0 {
+		t.Fatalf("confirm view has no schedule; expected no pending tick, got %d", n)
+	}
+
+	completed := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": true}),
+	})
+	if completed.Status != v1.RequestStatus_completed {
+		t.Fatalf("expected completed, got %v", completed.Status)
+	}
+}
+
+func TestScriptEventReplacesPendingTick(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "CI wait", Script: scriptCIWait},
+		},
+	})
+	if n := s.ticks.size(); n != 1 {
+		t.Fatalf("expected one pending tick after create, got %d", n)
+	}
+
+	updated := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{Type: "skip"})
+	if got := updated.GetScriptView().GetWidgetType(); got != "confirm" {
+		t.Fatalf("expected confirm view after skip, got %q", got)
+	}
+	if n := s.ticks.size(); n != 0 {
+		t.Fatalf("expected pending tick to be cancelled, got %d", n)
+	}
+
+	time.Sleep(3 * minScriptTickDelay)
+	if polls := getRequest(t, h, created.Id).GetScriptState().AsMap()["polls"]; polls != float64(0) {
+		t.Fatalf("cancelled tick must not run, got polls=%v", polls)
+	}
+}
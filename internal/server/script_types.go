@@ -0,0 +1,23 @@
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+)
+
+// handleScriptTypes serves TypeScript declarations for the script contract,
+// generated from widgets.proto.
+//
+// Paths:
+// - GET /api/scripts/types.d.ts
+func (s *Server) handleScriptTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/typescript; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, scriptengine.TypeDefinitions())
+}
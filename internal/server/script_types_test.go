@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const scriptTyped = `
+interface State { step: string; env?: string }
+
+export function describe() { return { name: "typed", version: "1.0.0" }; }
+export function init(): State { return { step: "pick" }; }
+export function view(state: State): PlzConfirm.ScriptView {
+  return { widgetType: "select", stepId: state.step, input: { title: "Env", options: ["staging", "prod"] } };
+}
+export function update(state: State, event: PlzConfirm.ScriptEvent<PlzConfirm.SelectOutput>) {
+  return { done: true, result: { env: event.data?.selectedSingle ?? "none" } };
+}
+`
+
+func TestScriptTypesEndpoint(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/scripts/types.d.ts", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("types status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/typescript") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(rr.Body.String(), "declare namespace PlzConfirm {") {
+		t.Fatalf("unexpected body:\n%s", rr.Body.String())
+	}
+
+	// The path is served, so a script cannot be registered under it.
+	putScript(t, h, "types.d.ts", scriptWizard, http.StatusMethodNotAllowed)
+}
+
+func TestScriptTypeScriptInlineAndRegistered(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	language := "typescript"
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Typed", Script: scriptTyped, Language: &language},
+		},
+	})
+	if created.GetScriptView().GetWidgetType() != "select" {
+		t.Fatalf("unexpected view: %+v", created.GetScriptView())
+	}
+	done := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"selectedSingle": "prod"}),
+	})
+	if got := done.GetScriptOutput().GetResult().AsMap()["env"]; got != "prod" {
+		t.Fatalf("expected env=prod, got %v", got)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/scripts/typed", strings.NewReader(scriptTyped))
+	req.Header.Set("Content-Type", "application/typescript")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("put typescript status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	rs := &v1.RegisteredScript{}
+	if err := protojson.Unmarshal(rr.Body.Bytes(), rs); err != nil {
+		t.Fatalf("unmarshal RegisteredScript: %v", err)
+	}
+	if rs.GetLanguage() != "typescript" {
+		t.Fatalf("expected typescript language, got %q", rs.GetLanguage())
+	}
+	byRef := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Typed", ScriptRef: &v1.ScriptRef{Name: "typed"}},
+		},
+	})
+	if byRef.GetScriptView().GetStepId() != "pick" {
+		t.Fatalf("unexpected view for registered TypeScript script: %+v", byRef.GetScriptView())
+	}
+
+	// The same source registered as JavaScript fails to compile.
+	putScript(t, h, "typed-js", scriptTyped, http.StatusBadRequest)
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxRegisteredScriptBytes bounds registered script source.
+const maxRegisteredScriptBytes = 1 << 20
+
+var registeredScriptNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// reservedScriptNames are /api/scripts/* paths that are not script names.
+var reservedScriptNames = map[string]bool{
+	"stats":      true,
+	"types.d.ts": true,
+	"validate":   true,
+}
+
+func (s *Server) handleScriptsCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeProtoJSON(w, http.StatusOK, &v1.RegisteredScriptList{Scripts: s.store.ListScripts(r.Context())})
+}
+
+func (s *Server) handleScriptsItem(w http.ResponseWriter, r *http.Request) {
+	// Paths:
+	// - /api/scripts/{name}
+	// - /api/scripts/{name}/versions
+	// - /api/scripts/{name}/versions/{version}
+	path := strings.TrimPrefix(r.URL.Path, "/api/scripts/")
+	parts := strings.Split(path, "/")
+	name := parts[0]
+	if name == "" || len(parts) > 3 {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			rs, err := s.store.GetScript(r.Context(), name, 0)
+			if err != nil {
+				writeScriptStoreError(w, err)
+				return
+			}
+			writeProtoJSON(w, http.StatusOK, rs)
+		case http.MethodPut:
+			s.handlePutScript(w, r, name)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if parts[1] != "versions" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(parts) == 2 {
+		versions, err := s.store.ListScriptVersions(r.Context(), name)
+		if err != nil {
+			writeScriptStoreError(w, err)
+			return
+		}
+		writeProtoJSON(w, http.StatusOK, &v1.RegisteredScriptList{Scripts: versions})
+		return
+	}
+	version, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil || version <= 0 {
+		http.Error(w, "invalid script version", http.StatusBadRequest)
+		return
+	}
+	rs, err := s.store.GetScript(r.Context(), name, int32(version))
+	if err != nil {
+		writeScriptStoreError(w, err)
+		return
+	}
+	writeProtoJSON(w, http.StatusOK, rs)
+}
+
+// handlePutScript registers a new version of a script. The body is either a
+// RegisteredScript (script, description, language) or, with a JavaScript or
+// TypeScript content type, the raw source. With WithRegisteredScriptsOnly the
+// endpoint is off; the operator registers scripts with RegisterScriptsFromDir.
+func (s *Server) handlePutScript(w http.ResponseWriter, r *http.Request, name string) {
+	if s.registeredScriptsOnly {
+		http.Error(w, "script registration is disabled; scripts are registered by the operator at startup", http.StatusForbidden)
+		return
+	}
+	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, maxRegisteredScriptBytes+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(bodyBytes) > maxRegisteredScriptBytes {
+		http.Error(w, "script too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	incoming := &v1.RegisteredScript{}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/javascript", "text/javascript":
+		incoming.Script = string(bodyBytes)
+	case "application/typescript", "text/typescript":
+		incoming.Script = string(bodyBytes)
+		language := scriptengine.LanguageTypeScript
+		incoming.Language = &language
+	default:
+		if err := protojson.Unmarshal(bodyBytes, incoming); err != nil {
+			http.Error(w, "invalid protojson RegisteredScript: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	rs, created, err := s.registerScript(r.Context(), name, incoming.GetScript(), incoming.GetLanguage(), incoming.Description)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeProtoJSON(w, status, rs)
+}
+
+// RegisterScriptsFromDir registers every name.js and name.ts file directly
+// in dir under that name, the way PUT /api/scripts/{name} does. It is how
+// the operator provides vetted scripts when WithRegisteredScriptsOnly turns
+// the endpoint off.
+func (s *Server) RegisterScriptsFromDir(ctx context.Context, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		var language string
+		switch ext {
+		case ".js":
+			language = scriptengine.LanguageJavaScript
+		case ".ts":
+			language = scriptengine.LanguageTypeScript
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if _, _, err := s.registerScript(ctx, strings.TrimSuffix(entry.Name(), ext), string(src), language, nil); err != nil {
+			return fmt.Errorf("register %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// registerScript compiles source and stores it as the next version of name,
+// reporting whether a new version was created.
+func (s *Server) registerScript(ctx context.Context, name, source, language string, description *string) (*v1.RegisteredScript, bool, error) {
+	if !registeredScriptNameRE.MatchString(name) || reservedScriptNames[name] {
+		return nil, false, stderrors.New("invalid script name")
+	}
+	if strings.TrimSpace(source) == "" {
+		return nil, false, stderrors.New("script source is required")
+	}
+	if len(source) > maxRegisteredScriptBytes {
+		return nil, false, stderrors.New("script too large")
+	}
+	language, err := scriptengine.NormalizeLanguage(language)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.scripts.Compile(&v1.ScriptInput{Script: source, Language: &language}); err != nil {
+		return nil, false, err
+	}
+
+	rs, created, err := s.store.PutScript(ctx, name, source, language, description)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		// #nosec G706 -- name is validated against registeredScriptNameRE.
+		log.Printf("[API] Registered script %q version %d (sha256=%s)", rs.Name, rs.Version, rs.Sha256)
+	}
+	return rs, created, nil
+}
+
+func writeScriptStoreError(w http.ResponseWriter, err error) {
+	if stderrors.Is(err, store.ErrScriptNotFound) {
+		http.Error(w, "script not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+// pinScriptRef resolves in.script_ref to a registered version and returns a
+// copy of in with the version and digest recorded. Inline scripts are
+// returned unchanged unless the server only accepts registered scripts.
+func (s *Server) pinScriptRef(ctx context.Context, in *v1.ScriptInput) (*v1.ScriptInput, error) {
+	ref := in.GetScriptRef()
+	if ref == nil {
+		if s.registeredScriptsOnly {
+			return nil, fmt.Errorf("inline scripts are disabled; use scriptRef")
+		}
+		return in, nil
+	}
+	if in.GetScript() != "" {
+		return nil, fmt.Errorf("set either script or scriptRef, not both")
+	}
+	rs, err := s.store.GetScript(ctx, ref.GetName(), ref.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	pinned, ok := proto.Clone(in).(*v1.ScriptInput)
+	if !ok {
+		return nil, fmt.Errorf("failed to clone script input")
+	}
+	pinned.ScriptRef.Version = &rs.Version
+	pinned.ScriptRef.Sha256 = rs.Sha256
+	return pinned, nil
+}
+
+// runnableScriptInput returns in with its source filled in from the registry
+// when it refers to a registered script. The result is only handed to the
+// script engine; stored requests keep the reference.
+func (s *Server) runnableScriptInput(ctx context.Context, in *v1.ScriptInput) (*v1.ScriptInput, error) {
+	ref := in.GetScriptRef()
+	if ref == nil {
+		return in, nil
+	}
+	if ref.GetVersion() <= 0 {
+		return nil, fmt.Errorf("script reference %q is not pinned to a version", ref.GetName())
+	}
+	rs, err := s.store.GetScript(ctx, ref.GetName(), ref.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	runnable, ok := proto.Clone(in).(*v1.ScriptInput)
+	if !ok {
+		return nil, fmt.Errorf("failed to clone script input")
+	}
+	runnable.Script = rs.Script
+	runnable.Language = rs.Language
+	return runnable, nil
+}
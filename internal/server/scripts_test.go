@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func putScript(t *testing.T, h http.Handler, name string, source string, wantStatus int) *v1.RegisteredScript {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/scripts/"+name, strings.NewReader(source))
+	req.Header.Set("Content-Type", "application/javascript")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != wantStatus {
+		t.Fatalf("put script status=%d want=%d body=%s", rr.Code, wantStatus, rr.Body.String())
+	}
+	out := &v1.RegisteredScript{}
+	if wantStatus < 300 {
+		if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+			t.Fatalf("unmarshal RegisteredScript: %v body=%s", err, rr.Body.String())
+		}
+	}
+	return out
+}
+
+func TestScriptRegistryVersionsAndPinsRequests(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	first := putScript(t, h, "deploy-wizard", scriptWizard, http.StatusCreated)
+	if first.GetVersion() != 1 || first.GetSha256() == "" {
+		t.Fatalf("unexpected first version: %+v", first)
+	}
+	same := putScript(t, h, "deploy-wizard", scriptWizard, http.StatusOK)
+	if same.GetVersion() != 1 {
+		t.Fatalf("re-putting identical source should keep version 1, got %d", same.GetVersion())
+	}
+	putScript(t, h, "deploy-wizard", "module.exports = {", http.StatusBadRequest)
+	putScript(t, h, "Bad.Name", scriptWizard, http.StatusBadRequest)
+
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{
+				Title:     "Deploy wizard",
+				ScriptRef: &v1.ScriptRef{Name: "deploy-wizard"},
+			},
+		},
+	})
+	ref := created.GetScriptInput().GetScriptRef()
+	if ref.GetVersion() != 1 || ref.GetSha256() != first.GetSha256() {
+		t.Fatalf("expected request pinned to version 1, got %+v", ref)
+	}
+	if created.GetScriptInput().GetScript() != "" {
+		t.Fatalf("stored script input should reference the registry, not embed source")
+	}
+
+	// Registering a new version must not change the flow of a pending request.
+	second := putScript(t, h, "deploy-wizard", scriptWizard+"\n// v2\n", http.StatusCreated)
+	if second.GetVersion() != 2 {
+		t.Fatalf("expected version 2, got %d", second.GetVersion())
+	}
+
+	updated := postScriptEvent(t, h, created.Id, &v1.ScriptEvent{
+		Type: "submit",
+		Data: mustStruct(t, map[string]any{"approved": false}),
+	})
+	if got := updated.GetScriptView().GetWidgetType(); got != "select" {
+		t.Fatalf("expected select view after first event, got %q", got)
+	}
+	if got := updated.GetScriptInput().GetScriptRef().GetVersion(); got != 1 {
+		t.Fatalf("pending request should stay pinned to version 1, got %d", got)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/scripts/deploy-wizard/versions", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("versions status=%d body=%s", rr.Code, rr.Body.String())
+	}
+	list := &v1.RegisteredScriptList{}
+	if err := protojson.Unmarshal(rr.Body.Bytes(), list); err != nil {
+		t.Fatalf("unmarshal versions: %v", err)
+	}
+	if len(list.Scripts) != 2 || list.Scripts[0].GetScript() != "" {
+		t.Fatalf("expected two versions without source, got %+v", list.Scripts)
+	}
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/scripts/deploy-wizard/versions/1", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "deploy-wizard") {
+		t.Fatalf("get version status=%d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestRegisteredOnlyServerRefusesScriptRegistration(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New(), WithRegisteredScriptsOnly())
+	h := s.Handler()
+	putScript(t, h, "wizard", scriptWizard, http.StatusForbidden)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/scripts/wizard", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected the refused PUT to register nothing, got status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"wizard.js":   scriptWizard,
+		"notes.txt":   "not a script",
+		"Bad.Name.js": scriptWizard,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := s.RegisterScriptsFromDir(t.Context(), dir); err == nil || !strings.Contains(err.Error(), "invalid script name") {
+		t.Fatalf("expected the bad file name to fail the load, got %v", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "Bad.Name.js")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := s.RegisterScriptsFromDir(t.Context(), dir); err != nil {
+		t.Fatalf("RegisterScriptsFromDir: %v", err)
+	}
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type: v1.WidgetType_script,
+		Input: &v1.UIRequest_ScriptInput{
+			ScriptInput: &v1.ScriptInput{Title: "Wizard", ScriptRef: &v1.ScriptRef{Name: "wizard"}},
+		},
+	})
+	if got := created.GetScriptInput().GetScriptRef().GetVersion(); got != 1 {
+		t.Fatalf("expected the operator's script at version 1, got %d", got)
+	}
+}
+
+func TestScriptCreateByRefErrors(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New(), WithRegisteredScriptsOnly())
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wizard.js"), []byte(scriptWizard), 0o600); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	if err := s.RegisterScriptsFromDir(t.Context(), dir); err != nil {
+		t.Fatalf("RegisterScriptsFromDir: %v", err)
+	}
+	h := s.Handler()
+
+	cases := []struct {
+		name  string
+		input *v1.ScriptInput
+		want  int
+	}{
+		{"unknown script", &v1.ScriptInput{ScriptRef: &v1.ScriptRef{Name: "missing"}}, http.StatusNotFound},
+		{"unknown version", &v1.ScriptInput{ScriptRef: &v1.ScriptRef{Name: "wizard", Version: toPtr(int32(9))}}, http.StatusNotFound},
+		{"source and ref", &v1.ScriptInput{Script: scriptWizard, ScriptRef: &v1.ScriptRef{Name: "wizard"}}, http.StatusBadRequest},
+		{"inline disabled", &v1.ScriptInput{Script: scriptWizard}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		body, err := protojson.Marshal(&v1.UIRequest{
+			Type:  v1.WidgetType_script,
+			Input: &v1.UIRequest_ScriptInput{ScriptInput: tc.input},
+		})
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", tc.name, err)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/requests", bytes.NewReader(body)))
+		if rr.Code != tc.want {
+			t.Fatalf("%s: status=%d want=%d body=%s", tc.name, rr.Code, tc.want, rr.Body.String())
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
 	"github.com/go-go-golems/plz-confirm/internal/store"
 	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
 	"golang.org/x/sync/errgroup"
@@ -26,13 +27,52 @@ type Server struct {
 	images           *ImageStore
 	scripts          *scriptengine.Engine
 	scriptEventLocks *keyedLock
+	createLocks      *keyedLock
+	ticks            *scriptTicks
+
+	// registeredScriptsOnly rejects inline script source; script requests
+	// must name a registered script via scriptRef.
+	registeredScriptsOnly bool
 }
 
+// maxIdempotencyKeyLen bounds client-supplied idempotency keys.
+const maxIdempotencyKeyLen = 255
+
 type Options struct {
 	Addr string
 }
 
-func New(s *store.Store) *Server {
+// ServerOption configures a Server.
+type ServerOption func(*serverConfig)
+
+type serverConfig struct {
+	scriptOptions         []scriptengine.Option
+	registeredScriptsOnly bool
+}
+
+// WithScriptEngineOptions configures the script engine, e.g. to enable
+// opt-in capabilities such as scriptengine.WithFSRoots.
+func WithScriptEngineOptions(opts ...scriptengine.Option) ServerOption {
+	return func(c *serverConfig) {
+		c.scriptOptions = append(c.scriptOptions, opts...)
+	}
+}
+
+// WithRegisteredScriptsOnly rejects script requests that carry inline source
+// and turns off PUT /api/scripts/{name}, so only scripts the operator
+// registers with RegisterScriptsFromDir can run.
+func WithRegisteredScriptsOnly() ServerOption {
+	return func(c *serverConfig) {
+		c.registeredScriptsOnly = true
+	}
+}
+
+func New(s *store.Store, opts ...ServerOption) *Server {
+	cfg := &serverConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	imgStore, err := NewImageStore(ImageStoreOptions{})
 	if err != nil {
 		log.Printf("[IMG] failed to initialize image store, uploads disabled: %v", err)
@@ -41,8 +81,12 @@ func New(s *store.Store) *Server {
 		store:            s,
 		ws:               newWSBroadcaster(),
 		images:           imgStore,
-		scripts:          scriptengine.New(),
+		scripts:          scriptengine.New(cfg.scriptOptions...),
 		scriptEventLocks: newKeyedLock(),
+		createLocks:      newKeyedLock(),
+		ticks:            newScriptTicks(),
+
+		registeredScriptsOnly: cfg.registeredScriptsOnly,
 	}
 }
 
@@ -55,6 +99,13 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/api/images/", s.handleImagesItem)
 	mux.HandleFunc("/api/requests", s.handleRequestsCollection)
 	mux.HandleFunc("/api/requests/", s.handleRequestsItem)
+	mux.HandleFunc("/api/sessions", s.handleSessionsCollection)
+	mux.HandleFunc("/api/sessions/", s.handleSessionsItem)
+	mux.HandleFunc("/api/scripts", s.handleScriptsCollection)
+	mux.HandleFunc("/api/scripts/", s.handleScriptsItem)
+	mux.HandleFunc("/api/scripts/stats", s.handleScriptStats)
+	mux.HandleFunc("/api/scripts/validate", s.handleScriptValidate)
+	mux.HandleFunc("/api/scripts/types.d.ts", s.handleScriptTypes)
 
 	// Serve embedded static files (production mode)
 	// In dev, Vite serves UI on :3000 and proxies /api and /ws to backend (typically :3001).
@@ -145,11 +196,28 @@ func (s *Server) ListenAndServe(ctx context.Context, opts Options) error {
 					} else {
 						log.Printf("[WS] marshal request_completed (timeout) failed: %v", err)
 					}
+					s.requestSettled(gctx, req)
 				}
 			}
 		}
 	})
 
+	g.Go(func() error {
+		t := time.NewTicker(30 * time.Second)
+		defer t.Stop()
+		for {
+			select {
+			case <-gctx.Done():
+				s.ticks.stop()
+				s.scripts.Close()
+				return nil
+			case <-t.C:
+				s.scripts.EvictIdle()
+				s.ws.expireActivity(time.Now().Add(-sessionActivityTTL))
+			}
+		}
+	})
+
 	if s.images != nil {
 		g.Go(func() error {
 			t := time.NewTicker(30 * time.Second)
@@ -233,19 +301,80 @@ func (s *Server) handleCreateRequest(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "input widget type does not match request type", http.StatusBadRequest)
 		return
 	}
+	if err := validateWidgetInput(reqProto); err != nil {
+		http.Error(w, "invalid widget input: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
+	// Idempotency-Key header (or the idempotencyKey field) makes retries of
+	// the same create return the original request instead of a duplicate.
+	if key := strings.TrimSpace(r.Header.Get("Idempotency-Key")); key != "" {
+		if reqProto.IdempotencyKey != nil && reqProto.GetIdempotencyKey() != key {
+			http.Error(w, "Idempotency-Key header does not match idempotencyKey field", http.StatusBadRequest)
+			return
+		}
+		reqProto.IdempotencyKey = &key
+	}
+	if key := reqProto.GetIdempotencyKey(); key != "" {
+		if len(key) > maxIdempotencyKeyLen {
+			http.Error(w, "idempotency key too long", http.StatusBadRequest)
+			return
+		}
+		// Serialize creates per key so concurrent retries don't both run
+		// script init and race to the store.
+		unlock := s.createLocks.Lock(key)
+		defer unlock()
+
+		existing, ok, err := s.store.LookupIdempotent(r.Context(), reqProto)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if ok {
+			// #nosec G706 -- existing.Id is server-generated and quoted for log safety.
+			log.Printf("[API] Replayed request %q for idempotency key", existing.Id)
+			s.writeCreatedRequest(w, http.StatusOK, existing)
+			return
+		}
+	}
+
+	var scriptSchedule *scriptengine.Schedule
+	var scriptInitEntry *v1.ScriptHistoryEntry
+	var scriptRuntimeKey string
 	if reqProto.Type == v1.WidgetType_script {
 		seed, err := newScriptSeed()
 		if err != nil {
 			http.Error(w, "failed to allocate script seed", http.StatusInternalServerError)
 			return
 		}
-		seededInput, err := scriptInputWithSeed(reqProto.GetScriptInput(), seed)
+		pinnedInput, err := s.pinScriptRef(r.Context(), reqProto.GetScriptInput())
 		if err != nil {
+			if stderrors.Is(err, store.ErrScriptNotFound) {
+				http.Error(w, "script not found", http.StatusNotFound)
+				return
+			}
 			http.Error(w, "invalid script input: "+err.Error(), http.StatusBadRequest)
 			return
 		}
-		initResult, err := s.scripts.InitAndView(r.Context(), seededInput)
+		seededInput, err := scriptInputWithSeed(pinnedInput, seed)
+		if err != nil {
+			http.Error(w, "invalid script input: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		runnableInput, err := s.runnableScriptInput(r.Context(), seededInput)
+		if err != nil {
+			http.Error(w, "invalid script input: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		// The request id is only known once the store creates the request, so
+		// the init runtime stays warm under a key of its own until then.
+		scriptRuntimeKey = "init-" + strconv.FormatInt(seed, 10)
+		defer s.scripts.Release(scriptRuntimeKey)
+		started := time.Now()
+		initResult, err := s.scripts.InitAndView(r.Context(), runnableInput,
+			scriptengine.WithNow(started),
+			scriptengine.WithRuntimeKey(scriptRuntimeKey),
+		)
 		if err != nil {
 			http.Error(w, "script init failed: "+err.Error(), statusForScriptError(err))
 			return
@@ -262,6 +391,12 @@ func (s *Server) handleCreateRequest(w http.ResponseWriter, r *http.Request) {
 		reqProto.ScriptView = scriptView
 		reqProto.ScriptDescribe = scriptDescribe
 		reqProto.ScriptLogs = append([]string(nil), initResult.Logs...)
+		reqProto.ScriptLocale = &initResult.Locale
+		scriptSchedule = initResult.Schedule
+		scriptInitEntry = newScriptHistoryEntry(nil, started, initResult.Logs)
+		scriptInitEntry.Locale = &initResult.Locale
+		scriptInitEntry.Changes = scriptStateDiff(nil, scriptState.AsMap())
+		setScriptHistoryView(scriptInitEntry, scriptView)
 	}
 	if reqProto.Metadata != nil || r.RemoteAddr != "" || r.UserAgent() != "" {
 		if reqProto.Metadata == nil {
@@ -280,20 +415,67 @@ func (s *Server) handleCreateRequest(w http.ResponseWriter, r *http.Request) {
 	// Create in store
 	req, err := s.store.Create(r.Context(), reqProto)
 	if err != nil {
+		if stderrors.Is(err, store.ErrSessionClosed) {
+			http.Error(w, "session closed", http.StatusConflict)
+			return
+		}
+		if stderrors.Is(err, store.ErrIdempotencyConflict) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Broadcast new_request to WS clients in this session.
-	if msg, err := marshalWSEvent("new_request", req); err == nil {
-		s.ws.BroadcastRawJSON(req.SessionId, msg)
-	} else {
-		log.Printf("[WS] marshal new_request failed: %v", err)
+	// Broadcast new_request to WS clients in this session, unless the request
+	// is waiting on prerequisites (it is announced once they complete) or was
+	// cancelled on arrival because one of them already failed.
+	blocked, err := s.store.Blocked(r.Context(), req.Id)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if req.Status == v1.RequestStatus_pending && !blocked {
+		if msg, err := marshalWSEvent("new_request", req); err == nil {
+			s.ws.BroadcastRawJSON(req.SessionId, msg)
+		} else {
+			log.Printf("[WS] marshal new_request failed: %v", err)
+		}
+	}
+	if scriptInitEntry != nil {
+		s.recordScriptHistory(r.Context(), req.Id, scriptInitEntry)
+	}
+	if req.Status == v1.RequestStatus_pending && scriptRuntimeKey != "" {
+		s.scripts.Rekey(scriptRuntimeKey, req.Id)
+	}
+	if req.Status == v1.RequestStatus_pending && scriptSchedule != nil {
+		s.scheduleScriptTick(req.Id, scriptSchedule)
 	}
 
 	// #nosec G706 -- req.Id is server-generated and quoted for log safety.
 	log.Printf("[API] Created request %q (%s)", req.Id, req.Type.String())
-	writeProtoJSON(w, http.StatusCreated, req)
+	for _, g := range req.GetScriptDescribe().GetGrants() {
+		// #nosec G706 -- req.Id is server-generated; scope comes from operator configuration.
+		log.Printf("[API] Request %q granted script capability %q (scope=%s)", req.Id, g.GetCapability(), strings.Join(g.GetScope(), ","))
+	}
+	for _, m := range req.GetScriptDescribe().GetModules() {
+		// #nosec G706 -- req.Id is server-generated; module refs come from the operator's library.
+		log.Printf("[API] Request %q loaded script module %s@%s (sha256=%s)", req.Id, m.GetName(), m.GetVersion(), m.GetSha256())
+	}
+	s.writeCreatedRequest(w, http.StatusCreated, req)
+}
+
+// writeCreatedRequest writes a create response. Presence is attached to the
+// response only; it is a point-in-time view and must not leak into the
+// stored request or WS broadcasts.
+func (s *Server) writeCreatedRequest(w http.ResponseWriter, status int, req *v1.UIRequest) {
+	resp, ok := proto.Clone(req).(*v1.UIRequest)
+	if !ok {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	resp.Presence = s.ws.presence(req.SessionId)
+	writeProtoJSON(w, status, resp)
 }
 
 func (s *Server) handleRequestsItem(w http.ResponseWriter, r *http.Request) {
@@ -301,6 +483,11 @@ func (s *Server) handleRequestsItem(w http.ResponseWriter, r *http.Request) {
 	// - /api/requests/{id}
 	// - /api/requests/{id}/response
 	// - /api/requests/{id}/wait
+	// - /api/requests/{id}/priority
+	// - /api/requests/{id}/event
+	// - /api/requests/{id}/inject
+	// - /api/requests/{id}/script/history
+	// - /api/requests/{id}/script/replay
 	path := strings.TrimPrefix(r.URL.Path, "/api/requests/")
 	if path == "" {
 		http.Error(w, "not found", http.StatusNotFound)
@@ -347,6 +534,35 @@ func (s *Server) handleRequestsItem(w http.ResponseWriter, r *http.Request) {
 		}
 		s.handleScriptEvent(w, r, id)
 		return
+	case "inject":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleScriptInject(w, r, id)
+		return
+	case "script":
+		if len(parts) != 3 {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		switch parts[2] {
+		case "history":
+			if r.Method != http.MethodGet {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			s.handleScriptHistory(w, r, id)
+		case "replay":
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			s.handleScriptReplay(w, r, id)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+		return
 	case "touch":
 		if r.Method != http.MethodPost {
 			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -361,6 +577,13 @@ func (s *Server) handleRequestsItem(w http.ResponseWriter, r *http.Request) {
 		}
 		s.handleWait(w, r, id)
 		return
+	case "priority":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleSetPriority(w, r, id)
+		return
 	default:
 		http.Error(w, "not found", http.StatusNotFound)
 		return
@@ -381,10 +604,57 @@ func (s *Server) handleTouch(w http.ResponseWriter, r *http.Request, id string)
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
+	s.ws.markActivity(req.SessionId, time.Now().UTC())
+	if req.Type == v1.WidgetType_script {
+		if localized := s.localizeScriptView(r.Context(), id, r.Header.Get("Accept-Language")); localized != nil {
+			req = localized
+		}
+	}
 
 	writeProtoJSON(w, http.StatusOK, req)
 }
 
+func (s *Server) handleSetPriority(w http.ResponseWriter, r *http.Request, id string) {
+	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	patch := &v1.PriorityUpdate{}
+	if err := protojson.Unmarshal(bodyBytes, patch); err != nil {
+		http.Error(w, "invalid protojson PriorityUpdate: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if patch.Priority == nil && patch.DeadlineAware == nil {
+		http.Error(w, "priority or deadlineAware is required", http.StatusBadRequest)
+		return
+	}
+
+	req, err := s.store.SetPriority(r.Context(), id, patch.Priority, patch.DeadlineAware)
+	if err != nil {
+		if stderrors.Is(err, store.ErrNotFound) {
+			http.Error(w, "request not found", http.StatusNotFound)
+			return
+		}
+		if stderrors.Is(err, store.ErrAlreadyCompleted) {
+			http.Error(w, "request already completed", http.StatusConflict)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if msg, err := marshalWSEvent("request_updated", req); err == nil {
+		s.ws.BroadcastRawJSON(req.SessionId, msg)
+	} else {
+		log.Printf("[WS] marshal request_updated failed: %v", err)
+	}
+
+	// #nosec G706 -- req.Id is server-generated and quoted for log safety.
+	log.Printf("[API] Re-prioritized request %q (priority=%d)", req.Id, req.Priority)
+	writeProtoJSON(w, http.StatusOK, req)
+}
+
 func (s *Server) handleSubmitResponse(w http.ResponseWriter, r *http.Request, id string) {
 	// Get the request to determine widget type
 	existingReq, err := s.store.Get(r.Context(), id)
@@ -438,9 +708,14 @@ func (s *Server) handleSubmitResponse(w http.ResponseWriter, r *http.Request, id
 			http.Error(w, "request already completed", http.StatusConflict)
 			return
 		}
+		if stderrors.Is(err, store.ErrBlocked) {
+			http.Error(w, "request blocked by dependencies", http.StatusConflict)
+			return
+		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
+	s.ws.markActivity(req.SessionId, time.Now().UTC())
 
 	// Broadcast completion to WS clients in this session.
 	if msg, err := marshalWSEvent("request_completed", req); err == nil {
@@ -448,6 +723,7 @@ func (s *Server) handleSubmitResponse(w http.ResponseWriter, r *http.Request, id
 	} else {
 		log.Printf("[WS] marshal request_completed failed: %v", err)
 	}
+	s.requestSettled(r.Context(), req)
 
 	// #nosec G706 -- req.Id is server-generated and quoted for log safety.
 	log.Printf("[API] Request %q completed", req.Id)
@@ -474,6 +750,13 @@ func ensureOutputTimestamps(req *v1.UIRequest, now time.Time) {
 		if strings.TrimSpace(output.ImageOutput.Timestamp) == "" {
 			output.ImageOutput.Timestamp = now.Format(time.RFC3339Nano)
 		}
+	case *v1.UIRequest_DisplayOutput:
+		if output.DisplayOutput == nil {
+			return
+		}
+		if strings.TrimSpace(output.DisplayOutput.Timestamp) == "" {
+			output.DisplayOutput.Timestamp = now.Format(time.RFC3339Nano)
+		}
 	}
 }
 
@@ -493,6 +776,12 @@ func widgetTypeFromOutputOneof(req *v1.UIRequest) (v1.WidgetType, bool) {
 		return v1.WidgetType_image, true
 	case *v1.UIRequest_ScriptOutput:
 		return v1.WidgetType_script, true
+	case *v1.UIRequest_GridOutput:
+		return v1.WidgetType_grid, true
+	case *v1.UIRequest_RatingOutput:
+		return v1.WidgetType_rating, true
+	case *v1.UIRequest_DisplayOutput:
+		return v1.WidgetType_display, true
 	default:
 		return v1.WidgetType_widget_type_unspecified, false
 	}
@@ -514,11 +803,44 @@ func widgetTypeFromInputOneof(req *v1.UIRequest) (v1.WidgetType, bool) {
 		return v1.WidgetType_image, true
 	case *v1.UIRequest_ScriptInput:
 		return v1.WidgetType_script, true
+	case *v1.UIRequest_GridInput:
+		return v1.WidgetType_grid, true
+	case *v1.UIRequest_RatingInput:
+		return v1.WidgetType_rating, true
+	case *v1.UIRequest_DisplayInput:
+		return v1.WidgetType_display, true
 	default:
 		return v1.WidgetType_widget_type_unspecified, false
 	}
 }
 
+// validateWidgetInput checks the standalone widgets that share their input
+// shape with script views; other widget inputs are taken as is.
+func validateWidgetInput(req *v1.UIRequest) error {
+	var widgetType string
+	var input proto.Message
+	switch in := req.Input.(type) {
+	case *v1.UIRequest_GridInput:
+		widgetType, input = "grid", in.GridInput
+	case *v1.UIRequest_RatingInput:
+		widgetType, input = "rating", in.RatingInput
+	case *v1.UIRequest_DisplayInput:
+		widgetType, input = "display", in.DisplayInput
+	default:
+		return nil
+	}
+	// The JSON form is the shape script views are checked in.
+	raw, err := protojson.Marshal(input)
+	if err != nil {
+		return err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	return scriptview.ValidateInput(widgetType, fields)
+}
+
 func (s *Server) handleWait(w http.ResponseWriter, r *http.Request, id string) {
 	timeoutS := 60
 	if raw := r.URL.Query().Get("timeout"); raw != "" {
@@ -0,0 +1,201 @@
+package server
+
+import (
+	stderrors "errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func (s *Server) handleSessionsCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleListSessions(w, r)
+		return
+	case http.MethodPost:
+		s.handleCreateSession(w, r)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+func (s *Server) handleSessionsItem(w http.ResponseWriter, r *http.Request) {
+	// Paths:
+	// - /api/sessions/{id}
+	// - /api/sessions/{id}/presence
+	// - /api/sessions/{id}/rename
+	// - /api/sessions/{id}/archive
+	// - /api/sessions/{id}/close
+	path := strings.TrimPrefix(r.URL.Path, "/api/sessions/")
+	parts := strings.Split(path, "/")
+	id := parts[0]
+	if id == "" || len(parts) > 2 {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		sess, err := s.store.GetSession(r.Context(), id)
+		if err != nil {
+			writeSessionStoreError(w, err)
+			return
+		}
+		writeProtoJSON(w, http.StatusOK, sess)
+		return
+	}
+
+	switch parts[1] {
+	case "presence":
+		s.handleSessionPresence(w, r, id)
+		return
+	case "rename":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleRenameSession(w, r, id)
+		return
+	case "archive":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		sess, err := s.store.ArchiveSession(r.Context(), id)
+		if err != nil {
+			writeSessionStoreError(w, err)
+			return
+		}
+		writeProtoJSON(w, http.StatusOK, sess)
+		return
+	case "close":
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleCloseSession(w, r, id)
+		return
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	// Default listing hides archived and closed sessions; ?status=all shows everything.
+	status := v1.SessionStatus_active
+	if raw := strings.TrimSpace(r.URL.Query().Get("status")); raw != "" {
+		if raw == "all" {
+			status = v1.SessionStatus_session_status_unspecified
+		} else {
+			v, ok := v1.SessionStatus_value[raw]
+			if !ok || v == int32(v1.SessionStatus_session_status_unspecified) {
+				http.Error(w, "invalid status filter", http.StatusBadRequest)
+				return
+			}
+			status = v1.SessionStatus(v)
+		}
+	}
+
+	writeProtoJSON(w, http.StatusOK, &v1.SessionList{
+		Sessions: s.store.ListSessions(r.Context(), status),
+	})
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	sessProto, ok := readSessionBody(w, r)
+	if !ok {
+		return
+	}
+
+	sess, err := s.store.CreateSession(r.Context(), sessProto)
+	if err != nil {
+		writeSessionStoreError(w, err)
+		return
+	}
+
+	// #nosec G706 -- session ID is quoted for log safety.
+	log.Printf("[API] Created session %q", sess.Id)
+	writeProtoJSON(w, http.StatusCreated, sess)
+}
+
+func (s *Server) handleRenameSession(w http.ResponseWriter, r *http.Request, id string) {
+	sessProto, ok := readSessionBody(w, r)
+	if !ok {
+		return
+	}
+
+	sess, err := s.store.RenameSession(r.Context(), id, sessProto.GetName())
+	if err != nil {
+		writeSessionStoreError(w, err)
+		return
+	}
+	writeProtoJSON(w, http.StatusOK, sess)
+}
+
+func (s *Server) handleCloseSession(w http.ResponseWriter, r *http.Request, id string) {
+	sess, cancelled, err := s.store.CloseSession(r.Context(), id)
+	if err != nil {
+		writeSessionStoreError(w, err)
+		return
+	}
+
+	for _, req := range cancelled {
+		if msg, err := marshalWSEvent("request_completed", req); err == nil {
+			s.ws.BroadcastRawJSON(req.SessionId, msg)
+		} else {
+			log.Printf("[WS] marshal request_completed (session closed) failed: %v", err)
+		}
+		s.requestSettled(r.Context(), req)
+	}
+	if msg, err := marshalWSSessionEvent("session_closed", sess); err == nil {
+		s.ws.BroadcastRawJSON(sess.Id, msg)
+	} else {
+		log.Printf("[WS] marshal session_closed failed: %v", err)
+	}
+	s.ws.forgetSession(sess.Id)
+
+	// #nosec G706 -- session ID is quoted for log safety.
+	log.Printf("[API] Closed session %q (cancelled %d pending requests)", sess.Id, len(cancelled))
+	writeProtoJSON(w, http.StatusOK, sess)
+}
+
+func readSessionBody(w http.ResponseWriter, r *http.Request) (*v1.Session, bool) {
+	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return nil, false
+	}
+	sessProto := &v1.Session{}
+	if len(bodyBytes) == 0 {
+		return sessProto, true
+	}
+	if err := protojson.Unmarshal(bodyBytes, sessProto); err != nil {
+		http.Error(w, "invalid protojson Session: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return sessProto, true
+}
+
+func writeSessionStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case stderrors.Is(err, store.ErrSessionNotFound):
+		http.Error(w, "session not found", http.StatusNotFound)
+	case stderrors.Is(err, store.ErrSessionExists):
+		http.Error(w, "session already exists", http.StatusConflict)
+	case stderrors.Is(err, store.ErrSessionClosed):
+		http.Error(w, "session closed", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
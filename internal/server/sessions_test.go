@@ -0,0 +1,195 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	t.Parallel()
+
+	s := New(store.New())
+	h := s.Handler()
+
+	created := &v1.Session{}
+	doSessionCall(t, h, http.MethodPost, "/api/sessions", &v1.Session{
+		Id:   "deploy-42",
+		Name: "Deploy 42",
+		Metadata: &v1.SessionMetadata{
+			AgentName: toPtr("deploy-bot"),
+			Project:   toPtr("plz-confirm"),
+		},
+	}, http.StatusCreated, created)
+	if created.GetStatus() != v1.SessionStatus_active {
+		t.Fatalf("expected active session, got %v", created.GetStatus())
+	}
+	if created.GetMetadata().GetAgentName() != "deploy-bot" {
+		t.Fatalf("expected metadata to round-trip, got %+v", created.GetMetadata())
+	}
+	doSessionCall(t, h, http.MethodPost, "/api/sessions", &v1.Session{Id: "deploy-42"}, http.StatusConflict, nil)
+
+	renamed := &v1.Session{}
+	doSessionCall(t, h, http.MethodPost, "/api/sessions/deploy-42/rename", &v1.Session{Name: "Deploy 42 (prod)"}, http.StatusOK, renamed)
+	if renamed.GetName() != "Deploy 42 (prod)" {
+		t.Fatalf("unexpected name after rename: %q", renamed.GetName())
+	}
+
+	// Requests against unknown session IDs register the session implicitly.
+	postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		SessionId: "implicit",
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "implicit"},
+		},
+	})
+	doSessionCall(t, h, http.MethodPost, "/api/sessions/implicit/archive", nil, http.StatusOK, nil)
+
+	list := &v1.SessionList{}
+	doSessionCall(t, h, http.MethodGet, "/api/sessions", nil, http.StatusOK, list)
+	if len(list.GetSessions()) != 1 || list.GetSessions()[0].GetId() != "deploy-42" {
+		t.Fatalf("expected only the active session in default listing, got %v", list.GetSessions())
+	}
+	doSessionCall(t, h, http.MethodGet, "/api/sessions?status=all", nil, http.StatusOK, list)
+	if len(list.GetSessions()) != 2 {
+		t.Fatalf("expected 2 sessions with status=all, got %d", len(list.GetSessions()))
+	}
+
+	pending := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		SessionId: "deploy-42",
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "ship it?"},
+		},
+	})
+	got := &v1.Session{}
+	doSessionCall(t, h, http.MethodGet, "/api/sessions/deploy-42", nil, http.StatusOK, got)
+	if got.GetPendingCount() != 1 {
+		t.Fatalf("expected pending count 1, got %d", got.GetPendingCount())
+	}
+
+	closed := &v1.Session{}
+	doSessionCall(t, h, http.MethodPost, "/api/sessions/deploy-42/close", nil, http.StatusOK, closed)
+	if closed.GetStatus() != v1.SessionStatus_closed || closed.ClosedAt == nil {
+		t.Fatalf("expected closed session with closedAt, got %+v", closed)
+	}
+	if closed.GetPendingCount() != 0 {
+		t.Fatalf("expected no pending requests after close, got %d", closed.GetPendingCount())
+	}
+
+	cancelled := getRequest(t, h, pending.Id)
+	if cancelled.GetStatus() != v1.RequestStatus_cancelled {
+		t.Fatalf("expected cancelled request after session close, got %v", cancelled.GetStatus())
+	}
+	if cancelled.GetError() != store.SessionClosedReason {
+		t.Fatalf("unexpected cancellation reason: %q", cancelled.GetError())
+	}
+
+	body, err := protojson.Marshal(&v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		SessionId: "deploy-42",
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "too late"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal UIRequest: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/requests", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for request in closed session, got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestSessionCloseNotifiesWebSocketClients(t *testing.T) {
+	s := New(store.New())
+	h := s.Handler()
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	pending := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_confirm,
+		SessionId: "ws-close",
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "pending"},
+		},
+	})
+
+	conn := dialWS(t, ts.URL, "ws-close")
+	defer func() {
+		_ = conn.Close()
+	}()
+	if eventType, _ := readWSEvent(t, conn); eventType != "new_request" {
+		t.Fatalf("expected initial new_request, got %q", eventType)
+	}
+
+	doSessionCall(t, h, http.MethodPost, "/api/sessions/ws-close/close", nil, http.StatusOK, nil)
+
+	eventType, eventReq := readWSEvent(t, conn)
+	if eventType != "request_completed" || eventReq.Id != pending.Id {
+		t.Fatalf("expected request_completed for %s, got %q for %s", pending.Id, eventType, eventReq.Id)
+	}
+	if eventReq.Status != v1.RequestStatus_cancelled {
+		t.Fatalf("expected cancelled status in request_completed event, got %v", eventReq.Status)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read websocket message: %v", err)
+	}
+	var ev struct {
+		Type    string          `json:"type"`
+		Session json.RawMessage `json:"session"`
+	}
+	if err := json.Unmarshal(msg, &ev); err != nil {
+		t.Fatalf("unmarshal session event: %v", err)
+	}
+	if ev.Type != "session_closed" {
+		t.Fatalf("expected session_closed event, got %q", ev.Type)
+	}
+	sess := &v1.Session{}
+	if err := protojson.Unmarshal(ev.Session, sess); err != nil {
+		t.Fatalf("unmarshal session payload: %v", err)
+	}
+	if sess.GetId() != "ws-close" || sess.GetStatus() != v1.SessionStatus_closed {
+		t.Fatalf("unexpected session payload: %+v", sess)
+	}
+}
+
+func doSessionCall(t *testing.T, h http.Handler, method string, path string, body proto.Message, wantStatus int, out proto.Message) {
+	t.Helper()
+
+	reader := bytes.NewReader(nil)
+	if body != nil {
+		b, err := protojson.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != wantStatus {
+		t.Fatalf("%s %s: expected status %d, got %d body=%s", method, path, wantStatus, rr.Code, rr.Body.String())
+	}
+	if out == nil {
+		return
+	}
+	if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+		t.Fatalf("unmarshal response: %v body=%s", err, rr.Body.String())
+	}
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/internal/store"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestStandaloneWidgetsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+
+	grid := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_grid,
+		SessionId: "global",
+		Input: &v1.UIRequest_GridInput{
+			GridInput: &v1.GridInput{
+				Title: "Pick a seat",
+				Rows:  1,
+				Cols:  2,
+				Cells: []*v1.GridCell{{Value: "A1"}, {Value: "A2"}},
+			},
+		},
+	})
+	gridDone := postResponse(t, h, grid.Id, &v1.UIRequest{
+		Output: &v1.UIRequest_GridOutput{
+			GridOutput: &v1.GridSelection{Row: 0, Col: 1, CellIndex: 1},
+		},
+	})
+	if gridDone.GetGridOutput().GetCellIndex() != 1 {
+		t.Fatalf("expected grid cell 1, got %+v", gridDone.GetGridOutput())
+	}
+
+	rating := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_rating,
+		SessionId: "global",
+		Input: &v1.UIRequest_RatingInput{
+			RatingInput: &v1.RatingInput{Title: "How did it go?"},
+		},
+	})
+	ratingDone := postResponse(t, h, rating.Id, &v1.UIRequest{
+		Output: &v1.UIRequest_RatingOutput{
+			RatingOutput: &v1.RatingOutput{Value: 4, Comment: toPtr("fine")},
+		},
+	})
+	if ratingDone.GetRatingOutput().GetValue() != 4 {
+		t.Fatalf("expected rating 4, got %+v", ratingDone.GetRatingOutput())
+	}
+
+	display := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:      v1.WidgetType_display,
+		SessionId: "global",
+		Input: &v1.UIRequest_DisplayInput{
+			DisplayInput: &v1.DisplayInput{Content: "# Release notes"},
+		},
+	})
+	displayDone := postResponse(t, h, display.Id, &v1.UIRequest{
+		Output: &v1.UIRequest_DisplayOutput{
+			DisplayOutput: &v1.DisplayOutput{Acknowledged: true},
+		},
+	})
+	if !displayDone.GetDisplayOutput().GetAcknowledged() {
+		t.Fatalf("expected display acknowledgement, got %+v", displayDone.GetDisplayOutput())
+	}
+	if _, err := time.Parse(time.RFC3339Nano, displayDone.GetDisplayOutput().GetTimestamp()); err != nil {
+		t.Fatalf("expected display timestamp to be populated, got %q", displayDone.GetDisplayOutput().GetTimestamp())
+	}
+}
+
+func TestStandaloneWidgetsRejectInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+
+	cases := []struct {
+		name    string
+		req     *v1.UIRequest
+		message string
+	}{
+		{
+			name: "grid cells mismatch",
+			req: &v1.UIRequest{
+				Type: v1.WidgetType_grid,
+				Input: &v1.UIRequest_GridInput{
+					GridInput: &v1.GridInput{Title: "x", Rows: 2, Cols: 2, Cells: []*v1.GridCell{{}}},
+				},
+			},
+			message: "cells length",
+		},
+		{
+			name: "rating scale out of range",
+			req: &v1.UIRequest{
+				Type: v1.WidgetType_rating,
+				Input: &v1.UIRequest_RatingInput{
+					RatingInput: &v1.RatingInput{Title: "x", Scale: toPtr(int32(12))},
+				},
+			},
+			message: "scale must be between 2 and 10",
+		},
+		{
+			name: "display without content",
+			req: &v1.UIRequest{
+				Type: v1.WidgetType_display,
+				Input: &v1.UIRequest_DisplayInput{
+					DisplayInput: &v1.DisplayInput{Content: "  "},
+				},
+			},
+			message: "view.input.content is required for display widget",
+		},
+		{
+			// 65536*65536 wraps to 0 in int32, which once slipped past the cap.
+			name: "grid dimensions overflow",
+			req: &v1.UIRequest{
+				Type: v1.WidgetType_grid,
+				Input: &v1.UIRequest_GridInput{
+					GridInput: &v1.GridInput{Title: "x", Rows: 65536, Cols: 65536},
+				},
+			},
+			message: "grid size exceeds max cells (400)",
+		},
+		{
+			name: "grid cell size",
+			req: &v1.UIRequest{
+				Type: v1.WidgetType_grid,
+				Input: &v1.UIRequest_GridInput{
+					GridInput: &v1.GridInput{Title: "x", Rows: 1, Cols: 1, Cells: []*v1.GridCell{{}}, CellSize: toPtr("huge")},
+				},
+			},
+			message: "cellSize must be small, medium, or large",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := protojson.Marshal(tc.req)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/requests", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d body=%s", rr.Code, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tc.message) {
+				t.Fatalf("expected %q in body=%s", tc.message, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestStandaloneWidgetsAutoTimeoutDefaults(t *testing.T) {
+	t.Parallel()
+
+	st := store.New()
+	h := New(st).Handler()
+	expired := time.Now().UTC().Add(30 * time.Second).Format(time.RFC3339Nano)
+
+	create := func(req *v1.UIRequest) *v1.UIRequest {
+		req.SessionId = "global"
+		req.ExpiresAt = expired
+		return postUIRequest(t, h, "/api/requests", req)
+	}
+	disabled := true
+	grid := create(&v1.UIRequest{
+		Type: v1.WidgetType_grid,
+		Input: &v1.UIRequest_GridInput{
+			GridInput: &v1.GridInput{Title: "x", Rows: 2, Cols: 2, Cells: []*v1.GridCell{
+				{Value: "a", Disabled: &disabled}, {Value: "b", Disabled: &disabled}, {Value: "c"}, {Value: "d"},
+			}},
+		},
+	})
+	blockedGrid := create(&v1.UIRequest{
+		Type: v1.WidgetType_grid,
+		Input: &v1.UIRequest_GridInput{
+			GridInput: &v1.GridInput{Title: "x", Rows: 1, Cols: 1, Cells: []*v1.GridCell{{Disabled: &disabled}}},
+		},
+	})
+	rating := create(&v1.UIRequest{
+		Type: v1.WidgetType_rating,
+		Input: &v1.UIRequest_RatingInput{
+			RatingInput: &v1.RatingInput{Title: "x", Scale: toPtr(int32(7))},
+		},
+	})
+	display := create(&v1.UIRequest{
+		Type: v1.WidgetType_display,
+		Input: &v1.UIRequest_DisplayInput{
+			DisplayInput: &v1.DisplayInput{Content: "notes"},
+		},
+	})
+
+	st.Expire(time.Now().Add(10 * time.Minute))
+
+	gridOut := getRequest(t, h, grid.Id).GetGridOutput()
+	if gridOut.GetCellIndex() != 2 || gridOut.GetRow() != 1 || gridOut.GetCol() != 0 || gridOut.GetComment() != "AUTO_TIMEOUT" {
+		t.Fatalf("expected grid timeout to pick the first enabled cell, got %+v", gridOut)
+	}
+	blockedOut := getRequest(t, h, blockedGrid.Id).GetGridOutput()
+	if blockedOut.GetCellIndex() != -1 || blockedOut.GetRow() != -1 || blockedOut.GetCol() != -1 {
+		t.Fatalf("expected no selection when every cell is disabled, got %+v", blockedOut)
+	}
+	ratingOut := getRequest(t, h, rating.Id).GetRatingOutput()
+	if ratingOut.GetValue() != 4 || ratingOut.GetComment() != "AUTO_TIMEOUT" {
+		t.Fatalf("expected rating midpoint 4 on timeout, got %+v", ratingOut)
+	}
+	displayOut := getRequest(t, h, display.Id).GetDisplayOutput()
+	if displayOut.GetAcknowledged() || displayOut.GetTimestamp() == "" || displayOut.GetComment() != "AUTO_TIMEOUT" {
+		t.Fatalf("expected unacknowledged display on timeout, got %+v", displayOut)
+	}
+}
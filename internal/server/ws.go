@@ -20,24 +20,30 @@ var (
 )
 
 type wsClient struct {
-	conn      *websocket.Conn
-	sessionID string
-	send      chan []byte
-	done      chan struct{}
-	closeOnce sync.Once
-	closed    atomic.Bool
+	conn         *websocket.Conn
+	sessionID    string
+	send         chan []byte
+	done         chan struct{}
+	closeOnce    sync.Once
+	closed       atomic.Bool
+	connectedAt  time.Time
+	lastActivity atomic.Int64 // unix nanos
 }
 
 func newWSClient(conn *websocket.Conn, sessionID string, queueSize int) *wsClient {
 	if queueSize <= 0 {
 		queueSize = defaultWSClientQueueSize
 	}
-	return &wsClient{
-		conn:      conn,
-		sessionID: sessionID,
-		send:      make(chan []byte, queueSize),
-		done:      make(chan struct{}),
+	now := time.Now().UTC()
+	c := &wsClient{
+		conn:        conn,
+		sessionID:   sessionID,
+		send:        make(chan []byte, queueSize),
+		done:        make(chan struct{}),
+		connectedAt: now,
 	}
+	c.lastActivity.Store(now.UnixNano())
+	return c
 }
 
 func (c *wsClient) start(onWriteError func(error)) {
@@ -106,14 +112,18 @@ type wsBroadcaster struct {
 	mu               sync.Mutex
 	clientsBySession map[string]map[*wsClient]struct{}
 	clientByConn     map[*websocket.Conn]*wsClient
-	writeQueueSize   int
+	// lastActivityBySession outlives client connections so agents can tell
+	// "nobody has looked in hours" from "the tab was closed a second ago".
+	lastActivityBySession map[string]time.Time
+	writeQueueSize        int
 }
 
 func newWSBroadcaster() *wsBroadcaster {
 	return &wsBroadcaster{
-		clientsBySession: make(map[string]map[*wsClient]struct{}),
-		clientByConn:     make(map[*websocket.Conn]*wsClient),
-		writeQueueSize:   defaultWSClientQueueSize,
+		clientsBySession:      make(map[string]map[*wsClient]struct{}),
+		clientByConn:          make(map[*websocket.Conn]*wsClient),
+		lastActivityBySession: make(map[string]time.Time),
+		writeQueueSize:        defaultWSClientQueueSize,
 	}
 }
 
@@ -129,6 +139,7 @@ func (b *wsBroadcaster) add(sessionID string, conn *websocket.Conn) *wsClient {
 	}
 	b.clientsBySession[sessionID][client] = struct{}{}
 	b.clientByConn[conn] = client
+	b.lastActivityBySession[sessionID] = client.connectedAt
 	b.mu.Unlock()
 
 	client.start(func(err error) {
@@ -150,12 +161,14 @@ func (b *wsBroadcaster) remove(conn *websocket.Conn) {
 			}
 		}
 		delete(b.clientByConn, conn)
+		b.lastActivityBySession[mapped.sessionID] = time.Now().UTC()
 	} else {
 		for sid, m := range b.clientsBySession {
 			for c := range m {
 				if c.conn == conn {
 					client = c
 					delete(m, c)
+					b.lastActivityBySession[sid] = time.Now().UTC()
 					if len(m) == 0 {
 						delete(b.clientsBySession, sid)
 					}
@@ -245,6 +258,7 @@ func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// We don't expect messages from the client; just read until close.
+	// Anything that does arrive still counts as viewer activity.
 	for {
 		if _, _, err := conn.ReadMessage(); err != nil {
 			_ = conn.Close()
@@ -252,5 +266,8 @@ func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
 			log.Printf("[WS] client disconnected")
 			return
 		}
+		now := time.Now().UTC()
+		client.markActivity(now)
+		s.ws.markActivity(sessionID, now)
 	}
 }
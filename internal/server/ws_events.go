@@ -26,3 +26,23 @@ func marshalWSEvent(eventType string, req proto.Message) ([]byte, error) {
 		Request: reqJSON,
 	})
 }
+
+type wsSessionEvent struct {
+	Type    string          `json:"type"`
+	Session json.RawMessage `json:"session"`
+}
+
+func marshalWSSessionEvent(eventType string, sess proto.Message) ([]byte, error) {
+	sessJSON, err := protojson.MarshalOptions{
+		EmitUnpopulated: true,
+		UseProtoNames:   false, // use json_name (camelCase)
+	}.Marshal(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(wsSessionEvent{
+		Type:    eventType,
+		Session: sessJSON,
+	})
+}
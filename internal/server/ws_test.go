@@ -3,6 +3,7 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -128,6 +129,99 @@ func TestWebSocketInitialPendingEventsFollowCreationOrder(t *testing.T) {
 	}
 }
 
+func TestWebSocketInitialPendingEventsFollowPriority(t *testing.T) {
+	s := New(store.New())
+	h := s.Handler()
+
+	newConfirm := func(title string, priority int32, deadlineAware bool, expiresIn time.Duration) *v1.UIRequest {
+		return postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+			Type:          v1.WidgetType_confirm,
+			SessionId:     "global",
+			Priority:      priority,
+			DeadlineAware: toPtr(deadlineAware),
+			ExpiresAt:     time.Now().UTC().Add(expiresIn).Format(time.RFC3339Nano),
+			Input: &v1.UIRequest_ConfirmInput{
+				ConfirmInput: &v1.ConfirmInput{Title: title},
+			},
+		})
+	}
+
+	color := newConfirm("which color?", 0, false, 10*time.Minute)
+	later := newConfirm("rotate keys?", 5, true, 10*time.Minute)
+	sooner := newConfirm("db migration?", 5, true, 2*time.Minute)
+	plain := newConfirm("restart worker?", 5, false, time.Minute)
+
+	// Re-prioritize the trivial request above everything else.
+	body := strings.NewReader(`{"priority":10}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/requests/"+color.Id+"/priority", body)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("set priority failed status=%d body=%s", rr.Code, rr.Body.String())
+	}
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	conn := dialWS(t, ts.URL, "global")
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	want := []*v1.UIRequest{color, sooner, later, plain}
+	for i, w := range want {
+		eventType, eventReq := readWSEvent(t, conn)
+		if eventType != "new_request" {
+			t.Fatalf("expected new_request event, got %q", eventType)
+		}
+		if eventReq.Id != w.Id {
+			t.Fatalf("pending event %d: expected %q, got %q", i, w.GetConfirmInput().GetTitle(), eventReq.GetConfirmInput().GetTitle())
+		}
+	}
+}
+
+func TestSetPriorityOnlyChangesGivenFields(t *testing.T) {
+	t.Parallel()
+
+	h := New(store.New()).Handler()
+	created := postUIRequest(t, h, "/api/requests", &v1.UIRequest{
+		Type:     v1.WidgetType_confirm,
+		Priority: 7,
+		Input: &v1.UIRequest_ConfirmInput{
+			ConfirmInput: &v1.ConfirmInput{Title: "ship it?"},
+		},
+	})
+
+	setPriority := func(body string, wantStatus int) *v1.UIRequest {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/requests/"+created.Id+"/priority", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != wantStatus {
+			t.Fatalf("%s: expected status %d, got %d body=%s", body, wantStatus, rr.Code, rr.Body.String())
+		}
+		out := &v1.UIRequest{}
+		if wantStatus == http.StatusOK {
+			if err := protojson.Unmarshal(rr.Body.Bytes(), out); err != nil {
+				t.Fatalf("unmarshal UIRequest: %v", err)
+			}
+		}
+		return out
+	}
+
+	if got := setPriority(`{"deadlineAware":true}`, http.StatusOK); got.GetPriority() != 7 || !got.GetDeadlineAware() {
+		t.Fatalf("expected priority 7 to survive a deadlineAware update, got priority=%d deadlineAware=%v", got.GetPriority(), got.GetDeadlineAware())
+	}
+	if got := setPriority(`{"priority":0}`, http.StatusOK); got.GetPriority() != 0 || !got.GetDeadlineAware() {
+		t.Fatalf("expected an explicit priority 0, got priority=%d deadlineAware=%v", got.GetPriority(), got.GetDeadlineAware())
+	}
+	setPriority(`{}`, http.StatusBadRequest)
+	setPriority(`{"priority":3,"status":"completed"}`, http.StatusBadRequest)
+	if got := getRequest(t, h, created.Id); got.GetStatus() != v1.RequestStatus_pending || got.GetPriority() != 0 {
+		t.Fatalf("expected rejected bodies to change nothing, got status=%s priority=%d", got.GetStatus(), got.GetPriority())
+	}
+}
+
 func dialWS(t *testing.T, serverURL, sessionID string) *websocket.Conn {
 	t.Helper()
 
@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// DependencyFailedReason is recorded in UIRequest.Error for requests
+// cancelled because a prerequisite was rejected, cancelled, or timed out.
+const DependencyFailedReason = "DEPENDENCY_FAILED"
+
+// Blocked reports whether the request is pending on unfinished prerequisites.
+// Blocked requests are hidden from Pending and cannot be completed.
+func (s *Store) Blocked(_ context.Context, id string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.requests[id]
+	if !ok {
+		return false, ErrNotFound
+	}
+	return s.blockedLocked(e.req), nil
+}
+
+// SettledDependents returns the requests affected by id reaching a terminal
+// state: dependents that became visible (pending and no longer blocked) and
+// dependents cancelled because id failed, followed transitively through the
+// cancelled ones. Callers use it to notify UIs after Complete, Cancel,
+// Expire, and CloseSession.
+func (s *Store) SettledDependents(_ context.Context, id string) (unblocked []*v1.UIRequest, cancelled []*v1.UIRequest) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := map[string]bool{}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range s.dependentsLocked(cur) {
+			if seen[e.req.Id] {
+				continue
+			}
+			seen[e.req.Id] = true
+			switch {
+			case e.req.Status == v1.RequestStatus_cancelled && e.req.GetError() == DependencyFailedReason:
+				cancelled = append(cancelled, e.req)
+				queue = append(queue, e.req.Id)
+			case e.req.Status == v1.RequestStatus_pending && !s.blockedLocked(e.req):
+				unblocked = append(unblocked, e.req)
+			}
+		}
+	}
+	sortPendingUIRequests(unblocked)
+	sortUIRequestsByCreatedAt(cancelled)
+	return unblocked, cancelled
+}
+
+// resolveDependsOnLocked validates and de-duplicates the prerequisites of a
+// new request. It reports whether any prerequisite has already failed.
+func (s *Store) resolveDependsOnLocked(ids []string) ([]string, bool, error) {
+	if len(ids) == 0 {
+		return nil, false, nil
+	}
+	out := make([]string, 0, len(ids))
+	seen := map[string]bool{}
+	failed := false
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		e, ok := s.requests[id]
+		if !ok {
+			return nil, false, errors.Errorf("unknown dependency %q", id)
+		}
+		if prerequisiteFailed(e.req) {
+			failed = true
+		}
+		out = append(out, id)
+	}
+	return out, failed, nil
+}
+
+func (s *Store) blockedLocked(req *v1.UIRequest) bool {
+	for _, id := range req.DependsOn {
+		if e, ok := s.requests[id]; ok && !isTerminalStatus(e.req.Status) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) dependentsLocked(id string) []*requestEntry {
+	var out []*requestEntry
+	for _, e := range s.requests {
+		for _, dep := range e.req.DependsOn {
+			if dep == id {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// settleDependentsLocked must be called after the request id reaches a
+// terminal state. Dependents of a failed prerequisite are cancelled
+// (cascading); dependents that become unblocked get a fresh expiry window,
+// since their clock should not run while they are hidden.
+func (s *Store) settleDependentsLocked(id string, now time.Time) {
+	prereq, ok := s.requests[id]
+	if !ok {
+		return
+	}
+	failed := prerequisiteFailed(prereq.req)
+	for _, e := range s.dependentsLocked(id) {
+		if e.req.Status != v1.RequestStatus_pending {
+			continue
+		}
+		if failed {
+			cancelEntryLocked(e, now, DependencyFailedReason)
+			s.settleDependentsLocked(e.req.Id, now)
+			continue
+		}
+		if !s.blockedLocked(e.req) {
+			restartExpiry(e.req, now)
+		}
+	}
+}
+
+func restartExpiry(req *v1.UIRequest, now time.Time) {
+	createdAt, err := time.Parse(time.RFC3339Nano, req.CreatedAt)
+	if err != nil {
+		return
+	}
+	expiresAt, err := time.Parse(time.RFC3339Nano, req.ExpiresAt)
+	if err != nil {
+		return
+	}
+	req.ExpiresAt = now.Add(expiresAt.Sub(createdAt)).Format(time.RFC3339Nano)
+}
+
+// prerequisiteFailed reports whether dependents of req should be cancelled:
+// the request did not complete, was answered with a rejection, or was
+// auto-completed on timeout.
+func prerequisiteFailed(req *v1.UIRequest) bool {
+	switch req.Status {
+	case v1.RequestStatus_completed:
+	case v1.RequestStatus_cancelled, v1.RequestStatus_error, v1.RequestStatus_timeout:
+		return true
+	default:
+		return false
+	}
+
+	if out := req.GetConfirmOutput(); out != nil && !out.Approved {
+		return true
+	}
+	if out := req.GetImageOutput(); out != nil {
+		if sel, ok := out.Selected.(*v1.ImageOutput_SelectedBool); ok && !sel.SelectedBool {
+			return true
+		}
+	}
+	return outputComment(req) == autoTimeoutComment
+}
+
+func outputComment(req *v1.UIRequest) string {
+	switch out := req.Output.(type) {
+	case *v1.UIRequest_ConfirmOutput:
+		return out.ConfirmOutput.GetComment()
+	case *v1.UIRequest_SelectOutput:
+		return out.SelectOutput.GetComment()
+	case *v1.UIRequest_FormOutput:
+		return out.FormOutput.GetComment()
+	case *v1.UIRequest_UploadOutput:
+		return out.UploadOutput.GetComment()
+	case *v1.UIRequest_TableOutput:
+		return out.TableOutput.GetComment()
+	case *v1.UIRequest_ImageOutput:
+		return out.ImageOutput.GetComment()
+	case *v1.UIRequest_ScriptOutput:
+		return out.ScriptOutput.GetError()
+	case *v1.UIRequest_GridOutput:
+		return out.GridOutput.GetComment()
+	case *v1.UIRequest_RatingOutput:
+		return out.RatingOutput.GetComment()
+	case *v1.UIRequest_DisplayOutput:
+		return out.DisplayOutput.GetComment()
+	default:
+		return ""
+	}
+}
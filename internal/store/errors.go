@@ -11,4 +11,25 @@ var (
 
 	// ErrWaitTimeout is returned when waiting for a request times out.
 	ErrWaitTimeout = errors.New("timeout waiting for response")
+
+	// ErrBlocked is returned when completing a request whose dependencies are still pending.
+	ErrBlocked = errors.New("request blocked by dependencies")
+
+	// ErrIdempotencyConflict is returned when an idempotency key is reused for a different request.
+	ErrIdempotencyConflict = errors.New("idempotency key reused for a different request")
+
+	// ErrSessionNotFound is returned when a session does not exist.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSessionExists is returned when creating a session whose ID is taken.
+	ErrSessionExists = errors.New("session already exists")
+
+	// ErrSessionClosed is returned when mutating or adding requests to a closed session.
+	ErrSessionClosed = errors.New("session closed")
+
+	// ErrScriptNotFound is returned when a registered script or version does not exist.
+	ErrScriptNotFound = errors.New("script not found")
+
+	// ErrNoScriptSnapshot is returned when a script request has no state snapshot to go back to.
+	ErrNoScriptSnapshot = errors.New("no script state snapshot")
 )
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxScriptHistoryEntries caps the history kept per script request; older
+// entries are dropped first.
+const MaxScriptHistoryEntries = 100
+
+type scriptHistory struct {
+	entries []*v1.ScriptHistoryEntry
+	nextSeq int32
+	dropped int32
+}
+
+// AppendScriptHistory records one script run (init or event) for request id.
+// The store assigns Seq, and At when it is empty.
+func (s *Store) AppendScriptHistory(_ context.Context, id string, entry *v1.ScriptHistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.requests[id]
+	if !ok {
+		return ErrNotFound
+	}
+	h := &e.history
+	h.nextSeq++
+	entry, ok = proto.Clone(entry).(*v1.ScriptHistoryEntry)
+	if !ok {
+		return errors.New("failed to clone script history entry")
+	}
+	entry.Seq = h.nextSeq
+	if entry.At == "" {
+		entry.At = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	if len(h.entries) == MaxScriptHistoryEntries {
+		h.entries[0] = nil
+		h.entries = h.entries[1:]
+		h.dropped++
+	}
+	h.entries = append(h.entries, entry)
+	return nil
+}
+
+// ScriptHistory returns the recorded history of request id, oldest first.
+// Callers must not modify the returned entries.
+func (s *Store) ScriptHistory(_ context.Context, id string) (*v1.ScriptHistory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.requests[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	// Entries are never modified after they are appended, so they can be
+	// shared with the caller.
+	return &v1.ScriptHistory{
+		RequestId: id,
+		Entries:   append([]*v1.ScriptHistoryEntry(nil), e.history.entries...),
+		Dropped:   e.history.dropped,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// IdempotencyWindow is how long an idempotency key keeps mapping to the
+// request it created. Retries after the window create a new request.
+const IdempotencyWindow = 10 * time.Minute
+
+type idempotencyEntry struct {
+	requestID string
+	expiresAt time.Time
+}
+
+// LookupIdempotent returns the request previously created with
+// req.IdempotencyKey, if the key is still within IdempotencyWindow. It returns
+// ErrIdempotencyConflict when the key was used for a different kind of request.
+func (s *Store) LookupIdempotent(_ context.Context, req *v1.UIRequest) (*v1.UIRequest, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.lookupIdempotentLocked(req, time.Now().UTC())
+}
+
+func (s *Store) lookupIdempotentLocked(req *v1.UIRequest, now time.Time) (*v1.UIRequest, bool, error) {
+	key := req.GetIdempotencyKey()
+	if key == "" {
+		return nil, false, nil
+	}
+	ent, ok := s.idempotency[key]
+	if !ok || !now.Before(ent.expiresAt) {
+		return nil, false, nil
+	}
+	e, ok := s.requests[ent.requestID]
+	if !ok {
+		return nil, false, nil
+	}
+
+	sessionID := req.SessionId
+	if sessionID == "" {
+		sessionID = "global"
+	}
+	if e.req.Type != req.Type || e.req.SessionId != sessionID {
+		return nil, false, ErrIdempotencyConflict
+	}
+	return e.req, true, nil
+}
+
+func (s *Store) rememberIdempotencyKeyLocked(req *v1.UIRequest, now time.Time) {
+	key := req.GetIdempotencyKey()
+	if key == "" {
+		return
+	}
+	for k, ent := range s.idempotency {
+		if !now.Before(ent.expiresAt) {
+			delete(s.idempotency, k)
+		}
+	}
+	s.idempotency[key] = idempotencyEntry{
+		requestID: req.Id,
+		expiresAt: now.Add(IdempotencyWindow),
+	}
+}
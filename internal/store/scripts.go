@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// PutScript registers script source in language under name. Each distinct
+// source gets the next version number; putting the same source and language
+// as the latest version returns that version with created=false. Versions
+// are immutable.
+func (s *Store) PutScript(_ context.Context, name string, script string, language string, description *string) (*v1.RegisteredScript, bool, error) {
+	if name == "" {
+		return nil, false, errors.New("script name is required")
+	}
+	if script == "" {
+		return nil, false, errors.New("script source is required")
+	}
+	sum := sha256.Sum256([]byte(script))
+	digest := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := s.scripts[name]
+	if n := len(versions); n > 0 {
+		latest := versions[n-1]
+		if latest.Sha256 == digest && latest.GetLanguage() == language {
+			return cloneRegisteredScript(latest), false, nil
+		}
+		if n >= math.MaxInt32 {
+			return nil, false, errors.New("too many script versions")
+		}
+	}
+	rs := &v1.RegisteredScript{
+		Name:        name,
+		Version:     int32(len(versions) + 1), // #nosec G115 -- bounded by the MaxInt32 check above.
+		Script:      script,
+		Sha256:      digest,
+		Description: description,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339Nano),
+		Language:    &language,
+	}
+	s.scripts[name] = append(versions, rs)
+	return cloneRegisteredScript(rs), true, nil
+}
+
+// GetScript returns a registered script version, or the latest version when
+// version is 0.
+func (s *Store) GetScript(_ context.Context, name string, version int32) (*v1.RegisteredScript, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := s.scripts[name]
+	if len(versions) == 0 {
+		return nil, ErrScriptNotFound
+	}
+	if version == 0 {
+		return cloneRegisteredScript(versions[len(versions)-1]), nil
+	}
+	if version < 0 || int(version) > len(versions) {
+		return nil, ErrScriptNotFound
+	}
+	return cloneRegisteredScript(versions[version-1]), nil
+}
+
+// ListScripts returns the latest version of every registered script, ordered
+// by name, without source.
+func (s *Store) ListScripts(_ context.Context) []*v1.RegisteredScript {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*v1.RegisteredScript, 0, len(s.scripts))
+	for _, versions := range s.scripts {
+		rs := cloneRegisteredScript(versions[len(versions)-1])
+		rs.Script = ""
+		out = append(out, rs)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// ListScriptVersions returns every version of a registered script, oldest
+// first, without source.
+func (s *Store) ListScriptVersions(_ context.Context, name string) ([]*v1.RegisteredScript, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := s.scripts[name]
+	if len(versions) == 0 {
+		return nil, ErrScriptNotFound
+	}
+	out := make([]*v1.RegisteredScript, 0, len(versions))
+	for _, v := range versions {
+		rs := cloneRegisteredScript(v)
+		rs.Script = ""
+		out = append(out, rs)
+	}
+	return out, nil
+}
+
+func cloneRegisteredScript(rs *v1.RegisteredScript) *v1.RegisteredScript {
+	out, _ := proto.Clone(rs).(*v1.RegisteredScript)
+	return out
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// SessionClosedReason is recorded in UIRequest.Error for requests cancelled by CloseSession.
+const SessionClosedReason = "SESSION_CLOSED"
+
+// CreateSession registers a session explicitly. Requests may still use
+// unknown session IDs; those sessions are registered implicitly by Create.
+func (s *Store) CreateSession(_ context.Context, sess *v1.Session) (*v1.Session, error) {
+	if sess == nil {
+		return nil, errors.New("session is required")
+	}
+	id := strings.TrimSpace(sess.Id)
+	if id == "" {
+		id = uuid.NewString()
+	}
+	name := strings.TrimSpace(sess.Name)
+	if name == "" {
+		name = id
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	created := &v1.Session{
+		Id:        id,
+		Name:      name,
+		Status:    v1.SessionStatus_active,
+		Metadata:  sess.Metadata,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[id]; ok {
+		return nil, ErrSessionExists
+	}
+	s.sessions[id] = created
+	return s.sessionSnapshotLocked(created), nil
+}
+
+func (s *Store) GetSession(_ context.Context, id string) (*v1.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return s.sessionSnapshotLocked(sess), nil
+}
+
+// ListSessions returns sessions ordered by creation time. An unspecified
+// status returns every session.
+func (s *Store) ListSessions(_ context.Context, status v1.SessionStatus) []*v1.Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*v1.Session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		if status != v1.SessionStatus_session_status_unspecified && sess.Status != status {
+			continue
+		}
+		out = append(out, s.sessionSnapshotLocked(sess))
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].CreatedAt != out[j].CreatedAt {
+			return out[i].CreatedAt < out[j].CreatedAt
+		}
+		return out[i].Id < out[j].Id
+	})
+	return out
+}
+
+func (s *Store) RenameSession(_ context.Context, id string, name string) (*v1.Session, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if sess.Status == v1.SessionStatus_closed {
+		return nil, ErrSessionClosed
+	}
+	sess.Name = name
+	sess.UpdatedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	return s.sessionSnapshotLocked(sess), nil
+}
+
+func (s *Store) ArchiveSession(_ context.Context, id string) (*v1.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if sess.Status == v1.SessionStatus_closed {
+		return nil, ErrSessionClosed
+	}
+	if sess.Status != v1.SessionStatus_archived {
+		now := time.Now().UTC().Format(time.RFC3339Nano)
+		sess.Status = v1.SessionStatus_archived
+		sess.ArchivedAt = &now
+		sess.UpdatedAt = now
+	}
+	return s.sessionSnapshotLocked(sess), nil
+}
+
+// CloseSession marks the session closed and cancels all of its pending
+// requests. It returns the closed session and the requests it cancelled.
+func (s *Store) CloseSession(_ context.Context, id string) (*v1.Session, []*v1.UIRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, nil, ErrSessionNotFound
+	}
+	if sess.Status == v1.SessionStatus_closed {
+		return nil, nil, ErrSessionClosed
+	}
+
+	now := time.Now().UTC()
+	var cancelled []*v1.UIRequest
+	for _, e := range s.requests {
+		if e.req.SessionId != id || e.req.Status != v1.RequestStatus_pending {
+			continue
+		}
+		cancelEntryLocked(e, now, SessionClosedReason)
+		cancelled = append(cancelled, e.req)
+	}
+	for _, req := range cancelled {
+		s.settleDependentsLocked(req.Id, now)
+	}
+	sortUIRequestsByCreatedAt(cancelled)
+
+	closedAt := now.Format(time.RFC3339Nano)
+	sess.Status = v1.SessionStatus_closed
+	sess.ClosedAt = &closedAt
+	sess.UpdatedAt = closedAt
+	return s.sessionSnapshotLocked(sess), cancelled, nil
+}
+
+// ensureSessionLocked registers unknown sessions on first use and rejects
+// requests for closed sessions.
+func (s *Store) ensureSessionLocked(id string, now time.Time) error {
+	sess, ok := s.sessions[id]
+	if !ok {
+		ts := now.Format(time.RFC3339Nano)
+		s.sessions[id] = &v1.Session{
+			Id:        id,
+			Name:      id,
+			Status:    v1.SessionStatus_active,
+			CreatedAt: ts,
+			UpdatedAt: ts,
+		}
+		return nil
+	}
+	if sess.Status == v1.SessionStatus_closed {
+		return ErrSessionClosed
+	}
+	return nil
+}
+
+// sessionSnapshotLocked returns a copy of sess with computed fields filled in,
+// so callers never observe later mutations of the stored session.
+func (s *Store) sessionSnapshotLocked(sess *v1.Session) *v1.Session {
+	out, ok := proto.Clone(sess).(*v1.Session)
+	if !ok {
+		return sess
+	}
+	var pending int32
+	for _, e := range s.requests {
+		if e.req.SessionId == sess.Id && e.req.Status == v1.RequestStatus_pending {
+			pending++
+		}
+	}
+	out.PendingCount = pending
+	return out
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// MaxScriptSnapshots caps the state snapshots kept per script request for
+// back navigation; the oldest are dropped first.
+const MaxScriptSnapshots = 50
+
+// PushScriptSnapshot saves state as the newest back-navigation snapshot of
+// request id.
+func (s *Store) PushScriptSnapshot(_ context.Context, id string, state *structpb.Struct) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.requests[id]
+	if !ok {
+		return ErrNotFound
+	}
+	state, ok = proto.Clone(state).(*structpb.Struct)
+	if !ok {
+		return errors.New("failed to clone script state snapshot")
+	}
+	if len(e.snapshots) == MaxScriptSnapshots {
+		e.snapshots[0] = nil
+		e.snapshots = e.snapshots[1:]
+	}
+	e.snapshots = append(e.snapshots, state)
+	return nil
+}
+
+// LatestScriptSnapshot returns the newest snapshot of request id without
+// removing it, or ErrNoScriptSnapshot. Callers must not modify the result.
+func (s *Store) LatestScriptSnapshot(_ context.Context, id string) (*structpb.Struct, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.requests[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if len(e.snapshots) == 0 {
+		return nil, ErrNoScriptSnapshot
+	}
+	return e.snapshots[len(e.snapshots)-1], nil
+}
+
+// PopScriptSnapshot removes the newest snapshot of request id.
+func (s *Store) PopScriptSnapshot(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.requests[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if len(e.snapshots) == 0 {
+		return ErrNoScriptSnapshot
+	}
+	e.snapshots[len(e.snapshots)-1] = nil
+	e.snapshots = e.snapshots[:len(e.snapshots)-1]
+	return nil
+}
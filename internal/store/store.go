@@ -10,31 +10,46 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
+// autoTimeoutComment marks outputs filled in by Expire.
+const autoTimeoutComment = "AUTO_TIMEOUT"
+
 type requestEntry struct {
 	req      *v1.UIRequest
 	done     chan struct{}
 	doneOnce sync.Once
+	history  scriptHistory
+	// snapshots holds prior script states for back navigation, newest last.
+	snapshots []*structpb.Struct
 }
 
 // Store is an in-memory store for UIRequests (E1).
 // It also provides an event-driven wait mechanism (F2) via per-request done channels.
 type Store struct {
-	mu       sync.RWMutex
-	requests map[string]*requestEntry
+	mu          sync.RWMutex
+	requests    map[string]*requestEntry
+	sessions    map[string]*v1.Session
+	idempotency map[string]idempotencyEntry
+	scripts     map[string][]*v1.RegisteredScript
 }
 
 func New() *Store {
 	return &Store{
-		requests: make(map[string]*requestEntry),
+		requests:    make(map[string]*requestEntry),
+		sessions:    make(map[string]*v1.Session),
+		idempotency: make(map[string]idempotencyEntry),
+		scripts:     make(map[string][]*v1.RegisteredScript),
 	}
 }
 
 // Create creates a new UIRequest from a protobuf UIRequest.
 // The request should have Input oneof populated, Type set, and SessionId set.
 // ID, Status, CreatedAt, and ExpiresAt will be set automatically.
+// If IdempotencyKey matches a request created within IdempotencyWindow, that
+// request is returned instead of creating a duplicate.
 func (s *Store) Create(_ context.Context, req *v1.UIRequest) (*v1.UIRequest, error) {
 	if req.Type == v1.WidgetType_widget_type_unspecified {
 		return nil, errors.New("type is required")
@@ -61,6 +76,10 @@ func (s *Store) Create(_ context.Context, req *v1.UIRequest) (*v1.UIRequest, err
 		ScriptView:     req.ScriptView,
 		ScriptDescribe: req.ScriptDescribe,
 		ScriptLogs:     append([]string(nil), req.ScriptLogs...),
+		ScriptLocale:   req.ScriptLocale,
+		Priority:       req.Priority,
+		DeadlineAware:  req.DeadlineAware,
+		IdempotencyKey: req.IdempotencyKey,
 		Status:         v1.RequestStatus_pending,
 		CreatedAt:      now.Format(time.RFC3339Nano),
 		ExpiresAt:      now.Format(time.RFC3339Nano), // Will be set below
@@ -81,10 +100,29 @@ func (s *Store) Create(_ context.Context, req *v1.UIRequest) (*v1.UIRequest, err
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.requests[id] = &requestEntry{
+	if existing, ok, err := s.lookupIdempotentLocked(reqCopy, now); err != nil || ok {
+		return existing, err
+	}
+
+	dependsOn, prereqFailed, err := s.resolveDependsOnLocked(req.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+	reqCopy.DependsOn = dependsOn
+
+	if err := s.ensureSessionLocked(reqCopy.SessionId, now); err != nil {
+		return nil, err
+	}
+
+	e := &requestEntry{
 		req:  reqCopy,
 		done: make(chan struct{}),
 	}
+	s.requests[id] = e
+	s.rememberIdempotencyKeyLocked(reqCopy, now)
+	if prereqFailed {
+		cancelEntryLocked(e, now, DependencyFailedReason)
+	}
 
 	return reqCopy, nil
 }
@@ -106,11 +144,11 @@ func (s *Store) Pending(_ context.Context) []*v1.UIRequest {
 
 	out := make([]*v1.UIRequest, 0, len(s.requests))
 	for _, e := range s.requests {
-		if e.req.Status == v1.RequestStatus_pending {
+		if e.req.Status == v1.RequestStatus_pending && !s.blockedLocked(e.req) {
 			out = append(out, e.req)
 		}
 	}
-	sortUIRequestsByCreatedAt(out)
+	sortPendingUIRequests(out)
 	return out
 }
 
@@ -124,14 +162,79 @@ func (s *Store) PendingForSession(_ context.Context, sessionID string) []*v1.UIR
 
 	out := make([]*v1.UIRequest, 0, len(s.requests))
 	for _, e := range s.requests {
-		if e.req.Status == v1.RequestStatus_pending && e.req.SessionId == sessionID {
+		if e.req.Status == v1.RequestStatus_pending && e.req.SessionId == sessionID && !s.blockedLocked(e.req) {
 			out = append(out, e.req)
 		}
 	}
-	sortUIRequestsByCreatedAt(out)
+	sortPendingUIRequests(out)
 	return out
 }
 
+// SetPriority re-prioritizes a pending request. priority and deadlineAware
+// are left unchanged when nil.
+func (s *Store) SetPriority(_ context.Context, id string, priority *int32, deadlineAware *bool) (*v1.UIRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.requests[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if e.req.Status != v1.RequestStatus_pending {
+		return nil, ErrAlreadyCompleted
+	}
+
+	if priority != nil {
+		e.req.Priority = *priority
+	}
+	if deadlineAware != nil {
+		v := *deadlineAware
+		e.req.DeadlineAware = &v
+	}
+	return e.req, nil
+}
+
+// sortPendingUIRequests orders the queue shown to the user: higher priority
+// first, then (within a priority) deadline-aware requests by soonest
+// expiry, then oldest first.
+func sortPendingUIRequests(requests []*v1.UIRequest) {
+	sort.SliceStable(requests, func(i, j int) bool {
+		a, b := requests[i], requests[j]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		aDeadline, aOK := urgencyDeadline(a)
+		bDeadline, bOK := urgencyDeadline(b)
+		switch {
+		case aOK && bOK:
+			if !aDeadline.Equal(bDeadline) {
+				return aDeadline.Before(bDeadline)
+			}
+		case aOK:
+			return true
+		case bOK:
+			return false
+		}
+		return uiRequestCreatedAtLess(a, b)
+	})
+}
+
+// urgencyDeadline returns the expiry a deadline-aware request is racing
+// against. Requests whose expiry was disabled (touched) no longer have one.
+func urgencyDeadline(req *v1.UIRequest) (time.Time, bool) {
+	if !req.GetDeadlineAware() || req.GetExpiryDisabled() {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, req.ExpiresAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 func sortUIRequestsByCreatedAt(requests []*v1.UIRequest) {
 	sort.SliceStable(requests, func(i, j int) bool {
 		return uiRequestCreatedAtLess(requests[i], requests[j])
@@ -176,6 +279,9 @@ func (s *Store) Expire(now time.Time) []*v1.UIRequest {
 		if e.req.ExpiryDisabled != nil && *e.req.ExpiryDisabled {
 			continue
 		}
+		if s.blockedLocked(e.req) {
+			continue
+		}
 		expAt, err := time.Parse(time.RFC3339Nano, e.req.ExpiresAt)
 		if err != nil {
 			continue
@@ -184,7 +290,7 @@ func (s *Store) Expire(now time.Time) []*v1.UIRequest {
 			continue
 		}
 
-		autoComment := "AUTO_TIMEOUT"
+		autoComment := autoTimeoutComment
 		setDefaultOutputFor(e.req, now, &autoComment)
 		e.req.Status = v1.RequestStatus_completed
 		completedAt := now.Format(time.RFC3339Nano)
@@ -192,6 +298,7 @@ func (s *Store) Expire(now time.Time) []*v1.UIRequest {
 		e.req.Error = nil
 
 		e.doneOnce.Do(func() { close(e.done) })
+		s.settleDependentsLocked(e.req.Id, now)
 		expired = append(expired, e.req)
 	}
 
@@ -362,6 +469,50 @@ func setDefaultOutputFor(req *v1.UIRequest, now time.Time, comment *string) {
 			},
 		}
 		return
+	case v1.WidgetType_grid:
+		// The first cell the user could have picked, or -1 everywhere when
+		// every cell is disabled.
+		out := &v1.GridSelection{Row: -1, Col: -1, CellIndex: -1, Comment: comment}
+		if in := req.GetGridInput(); in != nil && in.GetCols() > 0 {
+			for i, cell := range in.GetCells() {
+				if cell.GetDisabled() {
+					continue
+				}
+				idx := int32(i) // #nosec G115 -- grids are capped at 400 cells.
+				out.CellIndex = idx
+				out.Row = idx / in.GetCols()
+				out.Col = idx % in.GetCols()
+				break
+			}
+		}
+		req.Output = &v1.UIRequest_GridOutput{GridOutput: out}
+		return
+	case v1.WidgetType_rating:
+		in := req.GetRatingInput()
+		scale := int32(5)
+		if in != nil && in.Scale != nil {
+			scale = in.GetScale()
+		}
+		value := (scale + 1) / 2
+		if in != nil && in.DefaultValue != nil {
+			value = in.GetDefaultValue()
+		}
+		req.Output = &v1.UIRequest_RatingOutput{
+			RatingOutput: &v1.RatingOutput{
+				Value:   value,
+				Comment: comment,
+			},
+		}
+		return
+	case v1.WidgetType_display:
+		req.Output = &v1.UIRequest_DisplayOutput{
+			DisplayOutput: &v1.DisplayOutput{
+				Acknowledged: false,
+				Timestamp:    now.Format(time.RFC3339Nano),
+				Comment:      comment,
+			},
+		}
+		return
 	case v1.WidgetType_script:
 		st, _ := structpb.NewStruct(map[string]any{})
 		req.Output = &v1.UIRequest_ScriptOutput{
@@ -389,25 +540,55 @@ func (s *Store) Complete(_ context.Context, id string, output *v1.UIRequest) (*v
 	if e.req.Status != v1.RequestStatus_pending {
 		return nil, ErrAlreadyCompleted
 	}
+	if s.blockedLocked(e.req) {
+		return nil, ErrBlocked
+	}
 
-	now := time.Now().UTC().Format(time.RFC3339Nano)
-	e.req.Output = output.Output // Copy the output oneof field
-	e.req.ScriptLogs = append([]string(nil), output.ScriptLogs...)
-	e.req.Status = v1.RequestStatus_completed
-	completedAt := now
-	e.req.CompletedAt = &completedAt
+	now := time.Now().UTC()
+	next := cloneRequest(e.req)
+	next.Output = output.Output // Copy the output oneof field
+	next.ScriptLogs = append([]string(nil), output.ScriptLogs...)
+	next.Status = v1.RequestStatus_completed
+	completedAt := now.Format(time.RFC3339Nano)
+	next.CompletedAt = &completedAt
+	e.req = next
 
 	e.doneOnce.Do(func() { close(e.done) })
+	s.settleDependentsLocked(id, now)
 
 	return e.req, nil
 }
 
+func cancelEntryLocked(e *requestEntry, now time.Time, reason string) {
+	e.req.Status = v1.RequestStatus_cancelled
+	completedAt := now.Format(time.RFC3339Nano)
+	e.req.CompletedAt = &completedAt
+	if reason != "" {
+		e.req.Error = &reason
+	}
+	e.doneOnce.Do(func() { close(e.done) })
+}
+
+func isTerminalStatus(status v1.RequestStatus) bool {
+	switch status {
+	case v1.RequestStatus_completed,
+		v1.RequestStatus_timeout,
+		v1.RequestStatus_error,
+		v1.RequestStatus_cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *Store) PatchScript(
 	_ context.Context,
 	id string,
 	state *structpb.Struct,
 	view *v1.ScriptView,
 	logs []string,
+	grants []*v1.ScriptGrant,
+	locale string,
 ) (*v1.UIRequest, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -423,29 +604,49 @@ func (s *Store) PatchScript(
 		return nil, errors.New("request is not a script widget")
 	}
 
+	next := cloneRequest(e.req)
 	if state != nil {
-		e.req.ScriptState = state
+		next.ScriptState = state
 	}
 	if view != nil {
-		e.req.ScriptView = view
+		next.ScriptView = view
 	}
 	if logs != nil {
-		e.req.ScriptLogs = append([]string(nil), logs...)
+		next.ScriptLogs = append([]string(nil), logs...)
+	}
+	if grants != nil && next.ScriptDescribe != nil {
+		next.ScriptDescribe.Grants = grants
 	}
+	if locale != "" {
+		next.ScriptLocale = &locale
+	}
+	e.req = next
 
 	return e.req, nil
 }
 
+// cloneRequest copies req before an update. Script events and completions
+// replace the stored request rather than mutating it, since the previous value
+// may still be marshaled by a concurrent reader that got it from Get.
+func cloneRequest(req *v1.UIRequest) *v1.UIRequest {
+	out, _ := proto.Clone(req).(*v1.UIRequest)
+	return out
+}
+
 func (s *Store) Wait(ctx context.Context, id string) (*v1.UIRequest, error) {
 	s.mu.RLock()
 	e, ok := s.requests[id]
+	var req *v1.UIRequest
+	if ok {
+		req = e.req
+	}
 	s.mu.RUnlock()
 
 	if !ok {
 		return nil, ErrNotFound
 	}
-	if e.req.Status == v1.RequestStatus_completed || e.req.Status == v1.RequestStatus_timeout || e.req.Status == v1.RequestStatus_error {
-		return e.req, nil
+	if isTerminalStatus(req.Status) {
+		return req, nil
 	}
 
 	select {
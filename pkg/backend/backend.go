@@ -4,7 +4,9 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
 	internalserver "github.com/go-go-golems/plz-confirm/internal/server"
 	"github.com/go-go-golems/plz-confirm/internal/store"
 )
@@ -18,8 +20,146 @@ type ListenOptions struct {
 	Addr string
 }
 
-func NewServer() *Server {
-	return &Server{server: internalserver.New(store.New())}
+// Option configures a Server.
+type Option func(*options)
+
+type options struct {
+	scriptOptions         []scriptengine.Option
+	registeredScriptsOnly bool
+}
+
+// WithScriptFSRoots enables the opt-in script "fs" capability, giving scripts
+// that declare it read-only access to the given directories.
+func WithScriptFSRoots(roots ...string) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithFSRoots(roots...))
+	}
+}
+
+// WithScriptFSLimits bounds ctx.fs file sizes and directory listings.
+func WithScriptFSLimits(maxFileBytes int64, maxDirEntries int) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithFSLimits(maxFileBytes, maxDirEntries))
+	}
+}
+
+// WithScriptFetchAllowlist enables the opt-in script "fetch" capability for
+// the given hosts ("api.example.com" or "*.example.com").
+func WithScriptFetchAllowlist(hosts ...string) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithFetchAllowlist(hosts...))
+	}
+}
+
+// WithScriptFetchLimits bounds ctx.fetch calls and response bytes per request.
+func WithScriptFetchLimits(maxCalls int, maxBytes int64) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithFetchLimits(maxCalls, maxBytes))
+	}
+}
+
+// WithScriptResourceLimits bounds heap growth, call-stack depth, and returned
+// state/view size for each script run. Non-positive values keep the
+// defaults; the heap check is off unless set.
+func WithScriptResourceLimits(maxHeapGrowth int64, maxCallStackSize int, maxOutputBytes int) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithResourceLimits(maxHeapGrowth, maxCallStackSize, maxOutputBytes))
+	}
+}
+
+// WithScriptWarmRuntimes keeps up to maxRuntimes script runtimes alive
+// between events of pending script requests, closing those idle for longer
+// than idleTTL.
+func WithScriptWarmRuntimes(maxRuntimes int, idleTTL time.Duration) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithWarmRuntimes(maxRuntimes, idleTTL))
+	}
+}
+
+// WithScriptProgramCacheSize bounds how many compiled scripts are cached.
+// size <= 0 disables the cache.
+func WithScriptProgramCacheSize(size int) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithProgramCacheSize(size))
+	}
+}
+
+// WithScriptLibraryDir makes the modules in dir available to scripts via
+// require("plz/<name>").
+func WithScriptLibraryDir(dir string) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithLibraryDir(dir))
+	}
+}
+
+// ScriptLibrary is a directory of shared script modules, loaded once by
+// LoadScriptLibrary and served with WithScriptLibrary.
+type ScriptLibrary struct {
+	lib *scriptengine.Library
+}
+
+// ScriptModule identifies one module of a ScriptLibrary.
+type ScriptModule struct {
+	Name    string // require name without version, e.g. "plz/wizard"
+	Version string // empty for unversioned modules
+	SHA256  string
+}
+
+// LoadScriptLibrary reads and compiles every module in dir, so a broken
+// library fails at startup instead of on the first script run.
+func LoadScriptLibrary(dir string) (*ScriptLibrary, error) {
+	lib, err := scriptengine.LoadLibrary(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &ScriptLibrary{lib: lib}, nil
+}
+
+// Modules lists the library's modules.
+func (l *ScriptLibrary) Modules() []ScriptModule {
+	refs := l.lib.Modules()
+	out := make([]ScriptModule, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, ScriptModule{Name: ref.Name, Version: ref.Version, SHA256: ref.SHA256})
+	}
+	return out
+}
+
+// WithScriptLibrary makes the modules of lib available to scripts via
+// require("plz/<name>").
+func WithScriptLibrary(lib *ScriptLibrary) Option {
+	return func(o *options) {
+		o.scriptOptions = append(o.scriptOptions, scriptengine.WithLibrary(lib.lib))
+	}
+}
+
+// WithRegisteredScriptsOnly rejects script requests with inline source and
+// turns off PUT /api/scripts/{name}; agents must reference a script the
+// operator registered with RegisterScriptsFromDir.
+func WithRegisteredScriptsOnly() Option {
+	return func(o *options) {
+		o.registeredScriptsOnly = true
+	}
+}
+
+func NewServer(opts ...Option) *Server {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	serverOpts := []internalserver.ServerOption{
+		internalserver.WithScriptEngineOptions(o.scriptOptions...),
+	}
+	if o.registeredScriptsOnly {
+		serverOpts = append(serverOpts, internalserver.WithRegisteredScriptsOnly())
+	}
+	return &Server{server: internalserver.New(store.New(), serverOpts...)}
+}
+
+// RegisterScriptsFromDir registers every name.js and name.ts file in dir as
+// the script name, the same as PUT /api/scripts/{name}.
+func (s *Server) RegisterScriptsFromDir(ctx context.Context, dir string) error {
+	return s.server.RegisterScriptsFromDir(ctx, dir)
 }
 
 func (s *Server) Handler() http.Handler {
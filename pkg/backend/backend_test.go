@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
@@ -37,6 +39,29 @@ func TestMount_WithPrefix_CreatesConfirmRequest(t *testing.T) {
 	}
 }
 
+func TestLoadScriptLibrary_ListsModules(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wizard@2.1.0.js"), []byte(`exports.title = "x";`), 0o600); err != nil {
+		t.Fatalf("write module: %v", err)
+	}
+	lib, err := LoadScriptLibrary(dir)
+	if err != nil {
+		t.Fatalf("LoadScriptLibrary: %v", err)
+	}
+	mods := lib.Modules()
+	if len(mods) != 1 || mods[0].Name != "plz/wizard" || mods[0].Version != "2.1.0" || mods[0].SHA256 == "" {
+		t.Fatalf("unexpected modules: %+v", mods)
+	}
+	NewServer(WithScriptLibrary(lib))
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.js"), []byte(`exports.x = (;`), 0o600); err != nil {
+		t.Fatalf("write module: %v", err)
+	}
+	if _, err := LoadScriptLibrary(dir); err == nil {
+		t.Fatalf("expected a syntax error to fail the load")
+	}
+}
+
 func createConfirmRequest(t *testing.T, handler http.Handler, path string) *v1.UIRequest {
 	t.Helper()
 
@@ -0,0 +1,144 @@
+// Package scripttest runs plz-confirm script flows headlessly. A fixture
+// names a script, its props, and a list of events to replay; the harness
+// checks the expected view or result after each step and records a
+// transcript that can be compared against a golden file.
+package scripttest
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+)
+
+// Fixture describes one scripted run of a flow. Fixtures are YAML or JSON:
+//
+//	name: approve staging
+//	script: ../deploy.js
+//	props: { env: staging }
+//	expect: { widgetType: confirm, stepId: confirm }
+//	steps:
+//	  - event: { type: submit, data: { approved: false } }
+//	    expect: { widgetType: select }
+//	  - event: { type: submit, data: { selectedSingle: staging } }
+//	    expect: { done: true, result: { env: staging } }
+type Fixture struct {
+	Name string `yaml:"name" json:"name"`
+	// Script is the script path, relative to the fixture file.
+	Script string `yaml:"script" json:"script"`
+	// Source is inline script source, used when Script is empty.
+	Source string `yaml:"source" json:"source"`
+	// Language is "javascript" or "typescript". When empty it is taken from
+	// the Script extension (.ts is TypeScript).
+	Language  string         `yaml:"language" json:"language"`
+	Props     map[string]any `yaml:"props" json:"props"`
+	Seed      int64          `yaml:"seed" json:"seed"`
+	TimeoutMs int64          `yaml:"timeoutMs" json:"timeoutMs"`
+	// Locale is the responder's Accept-Language, e.g. "de-AT,de;q=0.9",
+	// from which ctx.locale is negotiated.
+	Locale string `yaml:"locale" json:"locale"`
+	// Now is ctx.now for every step, as an RFC 3339 time. It defaults to
+	// DefaultNow so transcripts do not depend on the wall clock.
+	Now string `yaml:"now" json:"now"`
+	// Expect is checked against the view returned by init.
+	Expect Expect `yaml:"expect" json:"expect"`
+	Steps  []Step `yaml:"steps" json:"steps"`
+
+	// path is the file the fixture was loaded from, if any.
+	path string
+}
+
+// Step sends one event and checks what the script does with it.
+type Step struct {
+	Event  Event  `yaml:"event" json:"event"`
+	Expect Expect `yaml:"expect" json:"expect"`
+}
+
+// Event mirrors ScriptEvent. Source defaults to "ui".
+type Event struct {
+	Type     string         `yaml:"type" json:"type"`
+	StepID   string         `yaml:"stepId" json:"stepId,omitempty"`
+	ActionID string         `yaml:"actionId" json:"actionId,omitempty"`
+	Source   string         `yaml:"source" json:"source,omitempty"`
+	Data     map[string]any `yaml:"data" json:"data,omitempty"`
+}
+
+// Expect lists assertions on a step. Empty fields are not checked.
+type Expect struct {
+	WidgetType string `yaml:"widgetType" json:"widgetType"`
+	StepID     string `yaml:"stepId" json:"stepId"`
+	// Done asserts whether the flow completed on this step.
+	Done *bool `yaml:"done" json:"done"`
+	// Result must equal the completed flow's result.
+	Result map[string]any `yaml:"result" json:"result"`
+}
+
+// DefaultNow is ctx.now for fixtures that do not set now.
+var DefaultNow = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// NowTime returns the frozen ctx.now of the fixture.
+func (f *Fixture) NowTime() (time.Time, error) {
+	if f.Now == "" {
+		return DefaultNow, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, f.Now)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "fixture %s: now must be an RFC 3339 time", f.Name)
+	}
+	return t, nil
+}
+
+// LoadFixture reads a YAML or JSON fixture and the script it refers to.
+func LoadFixture(path string) (*Fixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read fixture")
+	}
+	f := &Fixture{}
+	if err := yaml.Unmarshal(b, f); err != nil {
+		return nil, errors.Wrapf(err, "parse fixture %s", path)
+	}
+	f.path = path
+	if f.Name == "" {
+		f.Name = filepath.Base(path)
+	}
+	if f.Script != "" {
+		scriptPath := f.Script
+		if !filepath.IsAbs(scriptPath) {
+			scriptPath = filepath.Join(filepath.Dir(path), scriptPath)
+		}
+		src, err := os.ReadFile(scriptPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read script for fixture %s", path)
+		}
+		f.Source = string(src)
+		if f.Language == "" {
+			f.Language = scriptengine.LanguageForPath(f.Script)
+		}
+	}
+	if f.Source == "" {
+		return nil, errors.Errorf("fixture %s: script or source is required", path)
+	}
+	if _, err := f.NowTime(); err != nil {
+		return nil, err
+	}
+	for i, step := range f.Steps {
+		if step.Event.Type == "" {
+			return nil, errors.Errorf("fixture %s: step %d: event.type is required", path, i+1)
+		}
+	}
+	return f, nil
+}
+
+// GoldenPath is where a fixture's transcript is kept: the fixture path with
+// its extension replaced by ".golden.json".
+func (f *Fixture) GoldenPath() string {
+	if f.path == "" {
+		return ""
+	}
+	return f.path[:len(f.path)-len(filepath.Ext(f.path))] + ".golden.json"
+}
@@ -0,0 +1,380 @@
+package scripttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/go-go-golems/plz-confirm/internal/scriptengine"
+	"github.com/go-go-golems/plz-confirm/internal/scriptview"
+	"github.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1"
+)
+
+// seedPropKey is the prop the server uses to seed ctx.random; fixtures set it
+// so runs are reproducible.
+const seedPropKey = "__pc_seed"
+
+// Frame records one step of a run: the event sent (nil for init) and what the
+// script returned.
+type Frame struct {
+	Step   int            `json:"step"`
+	Event  *Event         `json:"event,omitempty"`
+	View   map[string]any `json:"view,omitempty"`
+	State  map[string]any `json:"state,omitempty"`
+	Done   bool           `json:"done,omitempty"`
+	Result map[string]any `json:"result,omitempty"`
+	// Schedule is the tick the view asked for. The harness does not fire it;
+	// replay it as a step with source "schedule".
+	Schedule *Schedule `json:"schedule,omitempty"`
+	Logs     []string  `json:"logs,omitempty"`
+}
+
+// Schedule is a tick requested by view.schedule.
+type Schedule struct {
+	AfterMs int64          `json:"afterMs"`
+	Event   map[string]any `json:"event"`
+}
+
+func frameSchedule(s *scriptengine.Schedule) *Schedule {
+	if s == nil {
+		return nil
+	}
+	return &Schedule{AfterMs: s.After.Milliseconds(), Event: normalizeMap(s.Event)}
+}
+
+// Result is the outcome of running a fixture.
+type Result struct {
+	Name       string
+	Transcript []Frame
+	Failures   []string
+}
+
+// Passed reports whether every expectation (and the golden file, if checked)
+// matched.
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Golden returns the transcript as indented JSON, the golden file format.
+func (r *Result) Golden() ([]byte, error) {
+	b, err := json.MarshalIndent(r.Transcript, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal transcript")
+	}
+	return append(b, '\n'), nil
+}
+
+func (r *Result) failf(format string, args ...any) {
+	r.Failures = append(r.Failures, fmt.Sprintf(format, args...))
+}
+
+// Option configures Run.
+type Option func(*runConfig)
+
+type runConfig struct {
+	libraryDir   string
+	updateGolden bool
+}
+
+// WithLibraryDir makes the library modules in dir available to the script,
+// as plz-confirm serve --script-library does.
+func WithLibraryDir(dir string) Option {
+	return func(c *runConfig) {
+		c.libraryDir = dir
+	}
+}
+
+// WithUpdateGolden rewrites the fixture's golden file instead of comparing
+// against it.
+func WithUpdateGolden(update bool) Option {
+	return func(c *runConfig) {
+		c.updateGolden = update
+	}
+}
+
+// Run replays f against a fresh engine with ctx.now frozen at the fixture's
+// now. Expectation mismatches are collected in Result.Failures; a script
+// error, or a view the server would reject, ends the run at that step. When the
+// fixture was loaded from a file and its golden file exists (or
+// WithUpdateGolden is set), the transcript is compared to (or written to) it.
+func Run(ctx context.Context, f *Fixture, opts ...Option) (*Result, error) {
+	cfg := &runConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	var engineOpts []scriptengine.Option
+	if cfg.libraryDir != "" {
+		engineOpts = append(engineOpts, scriptengine.WithLibraryDir(cfg.libraryDir))
+	}
+	engine := scriptengine.New(engineOpts...)
+	defer engine.Close()
+
+	in, err := scriptInput(f)
+	if err != nil {
+		return nil, err
+	}
+	now, err := f.NowTime()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Name: f.Name}
+	initRes, err := engine.InitAndView(ctx, in, scriptengine.WithLocale(f.Locale), scriptengine.WithNow(now))
+	if err != nil {
+		res.failf("init: %v", err)
+		return res, nil
+	}
+	if _, err := scriptview.FromMap(initRes.View); err != nil {
+		res.failf("init: invalid view: %v", err)
+		return res, nil
+	}
+	res.Transcript = append(res.Transcript, Frame{
+		View:     normalizeMap(initRes.View),
+		State:    normalizeMap(initRes.State),
+		Schedule: frameSchedule(initRes.Schedule),
+		Logs:     initRes.Logs,
+	})
+	checkExpect(res, "init", f.Expect, false, initRes.View, nil)
+
+	state := initRes.State
+	view := initRes.View
+	outputSchema := scriptengine.OutputSchema(initRes.Describe)
+	runOpts := []scriptengine.RunOption{
+		scriptengine.WithNow(now),
+		scriptengine.WithLocale(f.Locale),
+		scriptengine.WithMessages(scriptengine.MessagesFromDescribe(initRes.Describe)),
+	}
+	// snapshots mirrors the server's back-navigation stack.
+	var snapshots []map[string]any
+	for i, step := range f.Steps {
+		label := fmt.Sprintf("step %d (%s)", i+1, step.Event.Type)
+		if state == nil {
+			res.failf("%s: flow already completed", label)
+			break
+		}
+		ev := step.Event
+		if ev.Source == "" {
+			ev.Source = "ui"
+		}
+		if ev.Type == "back" && ev.Source == "ui" && viewAllowsBack(view) && len(snapshots) > 0 {
+			state = snapshots[len(snapshots)-1]
+			snapshots = snapshots[:len(snapshots)-1]
+			vr, err := engine.View(ctx, in, normalizeMap(state), runOpts...)
+			if err != nil {
+				res.failf("%s: %v", label, err)
+				break
+			}
+			if _, err := scriptview.FromMap(vr.View); err != nil {
+				res.failf("%s: invalid view: %v", label, err)
+				break
+			}
+			view = vr.View
+			res.Transcript = append(res.Transcript, Frame{
+				Step:     i + 1,
+				Event:    &ev,
+				View:     normalizeMap(vr.View),
+				State:    normalizeMap(state),
+				Schedule: frameSchedule(vr.Schedule),
+				Logs:     vr.Logs,
+			})
+			checkExpect(res, label, step.Expect, false, vr.View, nil)
+			continue
+		}
+		// A cold runtime mutates state in place, so keep a copy to go back to.
+		prev := normalizeMap(state)
+		upd, err := engine.UpdateAndView(ctx, in, state, eventMap(ev),
+			append([]scriptengine.RunOption{scriptengine.WithOutputSchema(outputSchema)}, runOpts...)...)
+		if err != nil {
+			res.failf("%s: %v", label, err)
+			break
+		}
+		if !upd.Done {
+			if _, err := scriptview.FromMap(upd.View); err != nil {
+				res.failf("%s: invalid view: %v", label, err)
+				break
+			}
+		}
+		frame := Frame{Step: i + 1, Event: &ev, Logs: upd.Logs}
+		if upd.Done {
+			frame.Done = true
+			frame.Result = normalizeMap(upd.Result)
+			state = nil
+		} else {
+			frame.View = normalizeMap(upd.View)
+			frame.State = normalizeMap(upd.State)
+			frame.Schedule = frameSchedule(upd.Schedule)
+			if ev.Source == "ui" && ev.Type != "back" && !reflect.DeepEqual(prev, frame.State) {
+				snapshots = append(snapshots, prev)
+			}
+			state = upd.State
+			view = upd.View
+		}
+		res.Transcript = append(res.Transcript, frame)
+		checkExpect(res, label, step.Expect, upd.Done, upd.View, upd.Result)
+	}
+
+	if golden := f.GoldenPath(); golden != "" {
+		if err := checkGolden(res, golden, cfg.updateGolden); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// viewAllowsBack reports whether view offers back navigation, accepting
+// showBack as the server does.
+func viewAllowsBack(view map[string]any) bool {
+	if allowBack, ok := view["allowBack"].(bool); ok {
+		return allowBack
+	}
+	showBack, _ := view["showBack"].(bool)
+	return showBack
+}
+
+func scriptInput(f *Fixture) (*v1.ScriptInput, error) {
+	props := map[string]any{}
+	for k, v := range f.Props {
+		props[k] = v
+	}
+	seed := f.Seed
+	if seed == 0 {
+		seed = 1
+	}
+	props[seedPropKey] = float64(seed)
+	propsStruct, err := structpb.NewStruct(normalizeMap(props))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fixture %s: props", f.Name)
+	}
+	in := &v1.ScriptInput{
+		Title:  f.Name,
+		Script: f.Source,
+		Props:  propsStruct,
+	}
+	if f.Language != "" {
+		in.Language = &f.Language
+	}
+	if f.TimeoutMs > 0 {
+		in.TimeoutMs = &f.TimeoutMs
+	}
+	return in, nil
+}
+
+// eventMap builds the event update() receives, in the same shape the server
+// passes for a ScriptEvent.
+func eventMap(ev Event) map[string]any {
+	m := map[string]any{"type": ev.Type, "source": ev.Source}
+	if ev.StepID != "" {
+		m["stepId"] = ev.StepID
+	}
+	if ev.ActionID != "" {
+		m["actionId"] = ev.ActionID
+	}
+	if ev.Data != nil {
+		m["data"] = normalizeMap(ev.Data)
+	}
+	return m
+}
+
+func checkExpect(res *Result, label string, want Expect, done bool, view map[string]any, result map[string]any) {
+	if want.Done != nil && *want.Done != done {
+		res.failf("%s: done = %v, want %v", label, done, *want.Done)
+	}
+	if want.Result != nil {
+		if !done {
+			res.failf("%s: expected a result, but the flow is still running", label)
+		} else if got, exp := normalizeMap(result), normalizeMap(want.Result); !reflect.DeepEqual(got, exp) {
+			res.failf("%s: result = %s, want %s", label, compactJSON(got), compactJSON(exp))
+		}
+	}
+	if want.WidgetType == "" && want.StepID == "" {
+		return
+	}
+	if done {
+		res.failf("%s: expected a view, but the flow completed", label)
+		return
+	}
+	if got, _ := view["widgetType"].(string); want.WidgetType != "" && got != want.WidgetType {
+		res.failf("%s: widgetType = %q, want %q", label, got, want.WidgetType)
+	}
+	if got, _ := view["stepId"].(string); want.StepID != "" && got != want.StepID {
+		res.failf("%s: stepId = %q, want %q", label, got, want.StepID)
+	}
+}
+
+func checkGolden(res *Result, path string, update bool) error {
+	got, err := res.Golden()
+	if err != nil {
+		return err
+	}
+	if update {
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			return errors.Wrap(err, "write golden file")
+		}
+		return nil
+	}
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "read golden file")
+	}
+	if !bytes.Equal(got, want) {
+		res.failf("transcript differs from %s (rerun with update to accept)", path)
+	}
+	return nil
+}
+
+// normalizeMap round-trips m through JSON so values compare the way they
+// would after crossing the API (all numbers become float64).
+func normalizeMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return m
+	}
+	out := map[string]any{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return m
+	}
+	return out
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// Test loads and runs the fixture at path, reporting failures on t. It is
+// meant for script authors' own Go tests:
+//
+//	func TestDeployWizard(t *testing.T) {
+//		scripttest.Test(t, "testdata/deploy-approve.yaml")
+//	}
+func Test(t testing.TB, path string, opts ...Option) {
+	t.Helper()
+
+	f, err := LoadFixture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Run(context.Background(), f, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, failure := range res.Failures {
+		t.Errorf("%s: %s", res.Name, failure)
+	}
+}
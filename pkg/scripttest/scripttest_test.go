@@ -0,0 +1,157 @@
+package scripttest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeployFixtureMatchesGolden(t *testing.T) {
+	Test(t, "testdata/deploy-pick.yaml")
+}
+
+func TestRunReportsExpectationFailures(t *testing.T) {
+	f, err := LoadFixture("testdata/deploy-pick.yaml")
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	f.path = "" // skip the golden comparison
+	f.Expect.WidgetType = "form"
+	f.Steps[1].Expect.Result = map[string]any{"env": "staging"}
+	f.Steps = append(f.Steps, Step{Event: Event{Type: "submit"}})
+
+	res, err := Run(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []string{
+		`init: widgetType = "confirm", want "form"`,
+		`step 2 (submit): result = {"env":"production"}, want {"env":"staging"}`,
+		`step 3 (submit): flow already completed`,
+	}
+	if strings.Join(res.Failures, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected failures:\n%s", strings.Join(res.Failures, "\n"))
+	}
+}
+
+func TestRunUpdatesAndComparesGolden(t *testing.T) {
+	dir := t.TempDir()
+	src, err := os.ReadFile("testdata/deploy.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture := "script: deploy.js\nsteps:\n  - event: { type: submit, data: { approved: true } }\n"
+	for name, content := range map[string]string{"deploy.js": string(src), "approve.yaml": fixture} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, "approve.yaml")
+
+	f, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	if _, err := Run(context.Background(), f, WithUpdateGolden(true)); err != nil {
+		t.Fatalf("Run with update: %v", err)
+	}
+	golden, err := os.ReadFile(filepath.Join(dir, "approve.golden.json"))
+	if err != nil || !strings.Contains(string(golden), `"env": "staging"`) {
+		t.Fatalf("expected golden transcript, got %q (err=%v)", golden, err)
+	}
+
+	// Changing the script changes the transcript.
+	changed := strings.Replace(string(src), `ctx.props.defaultEnv || "staging"`, `"qa"`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "deploy.js"), []byte(changed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err = LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	res, err := Run(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Passed() || !strings.Contains(res.Failures[0], "transcript differs") {
+		t.Fatalf("expected golden mismatch, got %v", res.Failures)
+	}
+}
+
+func TestRunRestoresStateOnBack(t *testing.T) {
+	f := &Fixture{
+		Name: "back",
+		Source: `module.exports = {
+  describe: function () { return { name: "back", version: "1.0.0" }; },
+  init: function () { return { step: "a" }; },
+  view: function (state) {
+    return { widgetType: "confirm", stepId: state.step, allowBack: state.step !== "a", input: { title: state.step } };
+  },
+  update: function (state, event) {
+    if (event.type === "back") throw new Error("update() should not see back");
+    state.step = "b";
+    return state;
+  }
+};`,
+		Steps: []Step{
+			{Event: Event{Type: "submit"}, Expect: Expect{StepID: "b"}},
+			{Event: Event{Type: "back"}, Expect: Expect{StepID: "a"}},
+		},
+	}
+
+	res, err := Run(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.Failures) != 0 {
+		t.Fatalf("unexpected failures:\n%s", strings.Join(res.Failures, "\n"))
+	}
+	if got := res.Transcript[2].State["step"]; got != "a" {
+		t.Fatalf("expected restored state, got %v", res.Transcript[2].State)
+	}
+}
+
+func TestRunFreezesNowAndChecksViews(t *testing.T) {
+	f := &Fixture{
+		Name: "clock",
+		Now:  "2025-03-04T05:06:07Z",
+		Source: `module.exports = {
+  describe: function () { return { name: "clock", version: "1.0.0" }; },
+  init: function (ctx) { return { started: ctx.now }; },
+  view: function (state, ctx) {
+    if (state.broken) return { widgetType: "rating", input: { title: "x", scale: 20 } };
+    return { widgetType: "confirm", input: { title: "at " + ctx.now } };
+  },
+  update: function (state, event, ctx) {
+    state.broken = true;
+    state.updated = ctx.now;
+    return state;
+  }
+};`,
+		Steps: []Step{{Event: Event{Type: "submit"}}},
+	}
+
+	res, err := Run(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := res.Transcript[0].State["started"]; got != "2025-03-04T05:06:07Z" {
+		t.Fatalf("expected the fixture's now in init, got %v", got)
+	}
+	want := "step 1 (submit): invalid view: view.input.scale must be between 2 and 10 for rating widget"
+	if len(res.Failures) != 1 || res.Failures[0] != want {
+		t.Fatalf("expected the server's view check to fail the step, got %v", res.Failures)
+	}
+
+	f.Now = ""
+	f.Steps = nil
+	res, err = Run(context.Background(), f)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := res.Transcript[0].State["started"]; got != "2024-01-01T00:00:00Z" {
+		t.Fatalf("expected DefaultNow, got %v", got)
+	}
+}
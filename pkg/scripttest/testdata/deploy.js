@@ -0,0 +1,29 @@
+module.exports = {
+  describe: function () {
+    return { name: "deploy", version: "1.0.0" };
+  },
+  init: function (ctx) {
+    return { step: "confirm", env: ctx.props.defaultEnv || "staging" };
+  },
+  view: function (state) {
+    if (state.step === "confirm") {
+      return { widgetType: "confirm", stepId: "confirm", input: { title: "Deploy to " + state.env + "?" } };
+    }
+    return {
+      widgetType: "select",
+      stepId: "pick-env",
+      input: { title: "Where to?", options: ["staging", "production"] }
+    };
+  },
+  update: function (state, event) {
+    if (state.step === "confirm") {
+      if (event.data && event.data.approved) {
+        return { done: true, result: { env: state.env } };
+      }
+      console.log("user rejected", state.env);
+      state.step = "pick";
+      return state;
+    }
+    return { done: true, result: { env: event.data.selectedSingle } };
+  }
+};
@@ -33,6 +33,7 @@ const (
 	RequestStatus_completed                  RequestStatus = 2
 	RequestStatus_timeout                    RequestStatus = 3
 	RequestStatus_error                      RequestStatus = 4
+	RequestStatus_cancelled                  RequestStatus = 5
 )
 
 // Enum value maps for RequestStatus.
@@ -43,6 +44,7 @@ var (
 		2: "completed",
 		3: "timeout",
 		4: "error",
+		5: "cancelled",
 	}
 	RequestStatus_value = map[string]int32{
 		"request_status_unspecified": 0,
@@ -50,6 +52,7 @@ var (
 		"completed":                  2,
 		"timeout":                    3,
 		"error":                      4,
+		"cancelled":                  5,
 	}
 )
 
@@ -94,19 +97,25 @@ const (
 	WidgetType_table                   WidgetType = 5
 	WidgetType_image                   WidgetType = 6
 	WidgetType_script                  WidgetType = 7
+	WidgetType_grid                    WidgetType = 8
+	WidgetType_rating                  WidgetType = 9
+	WidgetType_display                 WidgetType = 10
 )
 
 // Enum value maps for WidgetType.
 var (
 	WidgetType_name = map[int32]string{
-		0: "widget_type_unspecified",
-		1: "confirm",
-		2: "select",
-		3: "form",
-		4: "upload",
-		5: "table",
-		6: "image",
-		7: "script",
+		0:  "widget_type_unspecified",
+		1:  "confirm",
+		2:  "select",
+		3:  "form",
+		4:  "upload",
+		5:  "table",
+		6:  "image",
+		7:  "script",
+		8:  "grid",
+		9:  "rating",
+		10: "display",
 	}
 	WidgetType_value = map[string]int32{
 		"widget_type_unspecified": 0,
@@ -117,6 +126,9 @@ var (
 		"table":                   5,
 		"image":                   6,
 		"script":                  7,
+		"grid":                    8,
+		"rating":                  9,
+		"display":                 10,
 	}
 )
 
@@ -147,6 +159,59 @@ func (WidgetType) EnumDescriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{1}
 }
 
+// SessionStatus enum
+type SessionStatus int32
+
+const (
+	SessionStatus_session_status_unspecified SessionStatus = 0
+	SessionStatus_active                     SessionStatus = 1
+	SessionStatus_archived                   SessionStatus = 2 // Hidden from default listings, still accepts requests
+	SessionStatus_closed                     SessionStatus = 3 // Pending requests cancelled, new requests rejected
+)
+
+// Enum value maps for SessionStatus.
+var (
+	SessionStatus_name = map[int32]string{
+		0: "session_status_unspecified",
+		1: "active",
+		2: "archived",
+		3: "closed",
+	}
+	SessionStatus_value = map[string]int32{
+		"session_status_unspecified": 0,
+		"active":                     1,
+		"archived":                   2,
+		"closed":                     3,
+	}
+)
+
+func (x SessionStatus) Enum() *SessionStatus {
+	p := new(SessionStatus)
+	*p = x
+	return p
+}
+
+func (x SessionStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SessionStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_plz_confirm_v1_request_proto_enumTypes[2].Descriptor()
+}
+
+func (SessionStatus) Type() protoreflect.EnumType {
+	return &file_plz_confirm_v1_request_proto_enumTypes[2]
+}
+
+func (x SessionStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SessionStatus.Descriptor instead.
+func (SessionStatus) EnumDescriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{2}
+}
+
 type ProcessInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pid           int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
@@ -291,6 +356,349 @@ func (x *RequestMetadata) GetUserAgent() string {
 	return ""
 }
 
+// SessionViewer describes one connected WebSocket client of a session.
+type SessionViewer struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConnectedAt    string                 `protobuf:"bytes,1,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`            // RFC3339Nano timestamp
+	LastActivityAt string                 `protobuf:"bytes,2,opt,name=last_activity_at,json=lastActivityAt,proto3" json:"last_activity_at,omitempty"` // RFC3339Nano timestamp
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionViewer) Reset() {
+	*x = SessionViewer{}
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionViewer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionViewer) ProtoMessage() {}
+
+func (x *SessionViewer) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionViewer.ProtoReflect.Descriptor instead.
+func (*SessionViewer) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SessionViewer) GetConnectedAt() string {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return ""
+}
+
+func (x *SessionViewer) GetLastActivityAt() string {
+	if x != nil {
+		return x.LastActivityAt
+	}
+	return ""
+}
+
+// SessionPresence reports whether anyone is watching a session.
+type SessionPresence struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionId      string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Connected      bool                   `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"`
+	ViewerCount    int32                  `protobuf:"varint,3,opt,name=viewer_count,json=viewerCount,proto3" json:"viewer_count,omitempty"`
+	Viewers        []*SessionViewer       `protobuf:"bytes,4,rep,name=viewers,proto3" json:"viewers,omitempty"`
+	LastActivityAt *string                `protobuf:"bytes,5,opt,name=last_activity_at,json=lastActivityAt,proto3,oneof" json:"last_activity_at,omitempty"` // RFC3339Nano timestamp (latest viewer activity, survives disconnects)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionPresence) Reset() {
+	*x = SessionPresence{}
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionPresence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionPresence) ProtoMessage() {}
+
+func (x *SessionPresence) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionPresence.ProtoReflect.Descriptor instead.
+func (*SessionPresence) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SessionPresence) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionPresence) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *SessionPresence) GetViewerCount() int32 {
+	if x != nil {
+		return x.ViewerCount
+	}
+	return 0
+}
+
+func (x *SessionPresence) GetViewers() []*SessionViewer {
+	if x != nil {
+		return x.Viewers
+	}
+	return nil
+}
+
+func (x *SessionPresence) GetLastActivityAt() string {
+	if x != nil && x.LastActivityAt != nil {
+		return *x.LastActivityAt
+	}
+	return ""
+}
+
+type SessionMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentName     *string                `protobuf:"bytes,1,opt,name=agent_name,json=agentName,proto3,oneof" json:"agent_name,omitempty"`
+	Project       *string                `protobuf:"bytes,2,opt,name=project,proto3,oneof" json:"project,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionMetadata) Reset() {
+	*x = SessionMetadata{}
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMetadata) ProtoMessage() {}
+
+func (x *SessionMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMetadata.ProtoReflect.Descriptor instead.
+func (*SessionMetadata) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SessionMetadata) GetAgentName() string {
+	if x != nil && x.AgentName != nil {
+		return *x.AgentName
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetProject() string {
+	if x != nil && x.Project != nil {
+		return *x.Project
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+// Session - first-class grouping for requests sharing a session_id
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status        SessionStatus          `protobuf:"varint,3,opt,name=status,proto3,enum=plz_confirm.v1.SessionStatus" json:"status,omitempty"`
+	Metadata      *SessionMetadata       `protobuf:"bytes,4,opt,name=metadata,proto3,oneof" json:"metadata,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339Nano timestamp
+	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // RFC3339Nano timestamp
+	ArchivedAt    *string                `protobuf:"bytes,7,opt,name=archived_at,json=archivedAt,proto3,oneof" json:"archived_at,omitempty"`
+	ClosedAt      *string                `protobuf:"bytes,8,opt,name=closed_at,json=closedAt,proto3,oneof" json:"closed_at,omitempty"`
+	PendingCount  int32                  `protobuf:"varint,9,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"` // Computed on read
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Session) GetStatus() SessionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SessionStatus_session_status_unspecified
+}
+
+func (x *Session) GetMetadata() *SessionMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Session) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetArchivedAt() string {
+	if x != nil && x.ArchivedAt != nil {
+		return *x.ArchivedAt
+	}
+	return ""
+}
+
+func (x *Session) GetClosedAt() string {
+	if x != nil && x.ClosedAt != nil {
+		return *x.ClosedAt
+	}
+	return ""
+}
+
+func (x *Session) GetPendingCount() int32 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+type SessionList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionList) Reset() {
+	*x = SessionList{}
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionList) ProtoMessage() {}
+
+func (x *SessionList) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionList.ProtoReflect.Descriptor instead.
+func (*SessionList) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SessionList) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
 // UIRequest - main request/response envelope
 type UIRequest struct {
 	state     protoimpl.MessageState `protogen:"open.v1"`
@@ -308,6 +716,9 @@ type UIRequest struct {
 	//	*UIRequest_TableInput
 	//	*UIRequest_ImageInput
 	//	*UIRequest_ScriptInput
+	//	*UIRequest_GridInput
+	//	*UIRequest_RatingInput
+	//	*UIRequest_DisplayInput
 	Input isUIRequest_Input `protobuf_oneof:"input"`
 	// Widget-specific output (oneof for type safety)
 	//
@@ -320,6 +731,9 @@ type UIRequest struct {
 	//	*UIRequest_TableOutput
 	//	*UIRequest_ImageOutput
 	//	*UIRequest_ScriptOutput
+	//	*UIRequest_GridOutput
+	//	*UIRequest_RatingOutput
+	//	*UIRequest_DisplayOutput
 	Output         isUIRequest_Output `protobuf_oneof:"output"`
 	Status         RequestStatus      `protobuf:"varint,16,opt,name=status,proto3,enum=plz_confirm.v1.RequestStatus" json:"status,omitempty"`
 	CreatedAt      string             `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339Nano timestamp
@@ -333,13 +747,19 @@ type UIRequest struct {
 	ScriptView     *ScriptView        `protobuf:"bytes,27,opt,name=script_view,json=scriptView,proto3,oneof" json:"script_view,omitempty"`
 	ScriptDescribe *ScriptDescribe    `protobuf:"bytes,28,opt,name=script_describe,json=scriptDescribe,proto3,oneof" json:"script_describe,omitempty"`
 	ScriptLogs     []string           `protobuf:"bytes,29,rep,name=script_logs,json=scriptLogs,proto3" json:"script_logs,omitempty"`
+	Presence       *SessionPresence   `protobuf:"bytes,30,opt,name=presence,proto3,oneof" json:"presence,omitempty"`                                   // Only populated on create responses
+	Priority       int32              `protobuf:"varint,31,opt,name=priority,proto3" json:"priority,omitempty"`                                        // Higher values are shown first (default 0)
+	DeadlineAware  *bool              `protobuf:"varint,32,opt,name=deadline_aware,json=deadlineAware,proto3,oneof" json:"deadline_aware,omitempty"`   // If true, sooner expires_at sorts first within a priority
+	DependsOn      []string           `protobuf:"bytes,33,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`                      // Request IDs that must complete (and not be rejected) first
+	IdempotencyKey *string            `protobuf:"bytes,34,opt,name=idempotency_key,json=idempotencyKey,proto3,oneof" json:"idempotency_key,omitempty"` // Retries with the same key return the original request
+	ScriptLocale   *string            `protobuf:"bytes,41,opt,name=script_locale,json=scriptLocale,proto3,oneof" json:"script_locale,omitempty"`       // ctx.locale the current script view was rendered for
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *UIRequest) Reset() {
 	*x = UIRequest{}
-	mi := &file_plz_confirm_v1_request_proto_msgTypes[2]
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -351,7 +771,7 @@ func (x *UIRequest) String() string {
 func (*UIRequest) ProtoMessage() {}
 
 func (x *UIRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plz_confirm_v1_request_proto_msgTypes[2]
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -364,7 +784,7 @@ func (x *UIRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UIRequest.ProtoReflect.Descriptor instead.
 func (*UIRequest) Descriptor() ([]byte, []int) {
-	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{2}
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UIRequest) GetId() string {
@@ -458,6 +878,33 @@ func (x *UIRequest) GetScriptInput() *ScriptInput {
 	return nil
 }
 
+func (x *UIRequest) GetGridInput() *GridInput {
+	if x != nil {
+		if x, ok := x.Input.(*UIRequest_GridInput); ok {
+			return x.GridInput
+		}
+	}
+	return nil
+}
+
+func (x *UIRequest) GetRatingInput() *RatingInput {
+	if x != nil {
+		if x, ok := x.Input.(*UIRequest_RatingInput); ok {
+			return x.RatingInput
+		}
+	}
+	return nil
+}
+
+func (x *UIRequest) GetDisplayInput() *DisplayInput {
+	if x != nil {
+		if x, ok := x.Input.(*UIRequest_DisplayInput); ok {
+			return x.DisplayInput
+		}
+	}
+	return nil
+}
+
 func (x *UIRequest) GetOutput() isUIRequest_Output {
 	if x != nil {
 		return x.Output
@@ -528,6 +975,33 @@ func (x *UIRequest) GetScriptOutput() *ScriptOutput {
 	return nil
 }
 
+func (x *UIRequest) GetGridOutput() *GridSelection {
+	if x != nil {
+		if x, ok := x.Output.(*UIRequest_GridOutput); ok {
+			return x.GridOutput
+		}
+	}
+	return nil
+}
+
+func (x *UIRequest) GetRatingOutput() *RatingOutput {
+	if x != nil {
+		if x, ok := x.Output.(*UIRequest_RatingOutput); ok {
+			return x.RatingOutput
+		}
+	}
+	return nil
+}
+
+func (x *UIRequest) GetDisplayOutput() *DisplayOutput {
+	if x != nil {
+		if x, ok := x.Output.(*UIRequest_DisplayOutput); ok {
+			return x.DisplayOutput
+		}
+	}
+	return nil
+}
+
 func (x *UIRequest) GetStatus() RequestStatus {
 	if x != nil {
 		return x.Status
@@ -612,6 +1086,48 @@ func (x *UIRequest) GetScriptLogs() []string {
 	return nil
 }
 
+func (x *UIRequest) GetPresence() *SessionPresence {
+	if x != nil {
+		return x.Presence
+	}
+	return nil
+}
+
+func (x *UIRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *UIRequest) GetDeadlineAware() bool {
+	if x != nil && x.DeadlineAware != nil {
+		return *x.DeadlineAware
+	}
+	return false
+}
+
+func (x *UIRequest) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *UIRequest) GetIdempotencyKey() string {
+	if x != nil && x.IdempotencyKey != nil {
+		return *x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *UIRequest) GetScriptLocale() string {
+	if x != nil && x.ScriptLocale != nil {
+		return *x.ScriptLocale
+	}
+	return ""
+}
+
 type isUIRequest_Input interface {
 	isUIRequest_Input()
 }
@@ -644,6 +1160,18 @@ type UIRequest_ScriptInput struct {
 	ScriptInput *ScriptInput `protobuf:"bytes,24,opt,name=script_input,json=scriptInput,proto3,oneof"`
 }
 
+type UIRequest_GridInput struct {
+	GridInput *GridInput `protobuf:"bytes,35,opt,name=grid_input,json=gridInput,proto3,oneof"`
+}
+
+type UIRequest_RatingInput struct {
+	RatingInput *RatingInput `protobuf:"bytes,36,opt,name=rating_input,json=ratingInput,proto3,oneof"`
+}
+
+type UIRequest_DisplayInput struct {
+	DisplayInput *DisplayInput `protobuf:"bytes,37,opt,name=display_input,json=displayInput,proto3,oneof"`
+}
+
 func (*UIRequest_ConfirmInput) isUIRequest_Input() {}
 
 func (*UIRequest_SelectInput) isUIRequest_Input() {}
@@ -658,6 +1186,12 @@ func (*UIRequest_ImageInput) isUIRequest_Input() {}
 
 func (*UIRequest_ScriptInput) isUIRequest_Input() {}
 
+func (*UIRequest_GridInput) isUIRequest_Input() {}
+
+func (*UIRequest_RatingInput) isUIRequest_Input() {}
+
+func (*UIRequest_DisplayInput) isUIRequest_Input() {}
+
 type isUIRequest_Output interface {
 	isUIRequest_Output()
 }
@@ -690,6 +1224,18 @@ type UIRequest_ScriptOutput struct {
 	ScriptOutput *ScriptOutput `protobuf:"bytes,25,opt,name=script_output,json=scriptOutput,proto3,oneof"`
 }
 
+type UIRequest_GridOutput struct {
+	GridOutput *GridSelection `protobuf:"bytes,38,opt,name=grid_output,json=gridOutput,proto3,oneof"`
+}
+
+type UIRequest_RatingOutput struct {
+	RatingOutput *RatingOutput `protobuf:"bytes,39,opt,name=rating_output,json=ratingOutput,proto3,oneof"`
+}
+
+type UIRequest_DisplayOutput struct {
+	DisplayOutput *DisplayOutput `protobuf:"bytes,40,opt,name=display_output,json=displayOutput,proto3,oneof"`
+}
+
 func (*UIRequest_ConfirmOutput) isUIRequest_Output() {}
 
 func (*UIRequest_SelectOutput) isUIRequest_Output() {}
@@ -704,6 +1250,65 @@ func (*UIRequest_ImageOutput) isUIRequest_Output() {}
 
 func (*UIRequest_ScriptOutput) isUIRequest_Output() {}
 
+func (*UIRequest_GridOutput) isUIRequest_Output() {}
+
+func (*UIRequest_RatingOutput) isUIRequest_Output() {}
+
+func (*UIRequest_DisplayOutput) isUIRequest_Output() {}
+
+// PriorityUpdate - body of POST /api/requests/{id}/priority; unset fields stay unchanged
+type PriorityUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Priority      *int32                 `protobuf:"varint,1,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	DeadlineAware *bool                  `protobuf:"varint,2,opt,name=deadline_aware,json=deadlineAware,proto3,oneof" json:"deadline_aware,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriorityUpdate) Reset() {
+	*x = PriorityUpdate{}
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriorityUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriorityUpdate) ProtoMessage() {}
+
+func (x *PriorityUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_request_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriorityUpdate.ProtoReflect.Descriptor instead.
+func (*PriorityUpdate) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_request_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PriorityUpdate) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *PriorityUpdate) GetDeadlineAware() bool {
+	if x != nil && x.DeadlineAware != nil {
+		return *x.DeadlineAware
+	}
+	return false
+}
+
 var File_plz_confirm_v1_request_proto protoreflect.FileDescriptor
 
 const file_plz_confirm_v1_request_proto_rawDesc = "" +
@@ -727,7 +1332,48 @@ const file_plz_confirm_v1_request_proto_rawDesc = "" +
 	"\x04_cwdB\a\n" +
 	"\x05_selfB\x0e\n" +
 	"\f_remote_addrB\r\n" +
-	"\v_user_agent\"\xda\r\n" +
+	"\v_user_agent\"\\\n" +
+	"\rSessionViewer\x12!\n" +
+	"\fconnected_at\x18\x01 \x01(\tR\vconnectedAt\x12(\n" +
+	"\x10last_activity_at\x18\x02 \x01(\tR\x0elastActivityAt\"\xee\x01\n" +
+	"\x0fSessionPresence\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1c\n" +
+	"\tconnected\x18\x02 \x01(\bR\tconnected\x12!\n" +
+	"\fviewer_count\x18\x03 \x01(\x05R\vviewerCount\x127\n" +
+	"\aviewers\x18\x04 \x03(\v2\x1d.plz_confirm.v1.SessionViewerR\aviewers\x12-\n" +
+	"\x10last_activity_at\x18\x05 \x01(\tH\x00R\x0elastActivityAt\x88\x01\x01B\x13\n" +
+	"\x11_last_activity_at\"\xef\x01\n" +
+	"\x0fSessionMetadata\x12\"\n" +
+	"\n" +
+	"agent_name\x18\x01 \x01(\tH\x00R\tagentName\x88\x01\x01\x12\x1d\n" +
+	"\aproject\x18\x02 \x01(\tH\x01R\aproject\x88\x01\x01\x12C\n" +
+	"\x06labels\x18\x03 \x03(\v2+.plz_confirm.v1.SessionMetadata.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\r\n" +
+	"\v_agent_nameB\n" +
+	"\n" +
+	"\b_project\"\xfc\x02\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x125\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1d.plz_confirm.v1.SessionStatusR\x06status\x12@\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x1f.plz_confirm.v1.SessionMetadataH\x00R\bmetadata\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\x12$\n" +
+	"\varchived_at\x18\a \x01(\tH\x01R\n" +
+	"archivedAt\x88\x01\x01\x12 \n" +
+	"\tclosed_at\x18\b \x01(\tH\x02R\bclosedAt\x88\x01\x01\x12#\n" +
+	"\rpending_count\x18\t \x01(\x05R\fpendingCountB\v\n" +
+	"\t_metadataB\x0e\n" +
+	"\f_archived_atB\f\n" +
+	"\n" +
+	"_closed_at\"B\n" +
+	"\vSessionList\x123\n" +
+	"\bsessions\x18\x01 \x03(\v2\x17.plz_confirm.v1.SessionR\bsessions\"\xb3\x13\n" +
 	"\tUIRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x1a.plz_confirm.v1.WidgetTypeR\x04type\x12\x1d\n" +
@@ -742,7 +1388,11 @@ const file_plz_confirm_v1_request_proto_rawDesc = "" +
 	"tableInput\x12=\n" +
 	"\vimage_input\x18\t \x01(\v2\x1a.plz_confirm.v1.ImageInputH\x00R\n" +
 	"imageInput\x12@\n" +
-	"\fscript_input\x18\x18 \x01(\v2\x1b.plz_confirm.v1.ScriptInputH\x00R\vscriptInput\x12F\n" +
+	"\fscript_input\x18\x18 \x01(\v2\x1b.plz_confirm.v1.ScriptInputH\x00R\vscriptInput\x12:\n" +
+	"\n" +
+	"grid_input\x18# \x01(\v2\x19.plz_confirm.v1.GridInputH\x00R\tgridInput\x12@\n" +
+	"\frating_input\x18$ \x01(\v2\x1b.plz_confirm.v1.RatingInputH\x00R\vratingInput\x12C\n" +
+	"\rdisplay_input\x18% \x01(\v2\x1c.plz_confirm.v1.DisplayInputH\x00R\fdisplayInput\x12F\n" +
 	"\x0econfirm_output\x18\n" +
 	" \x01(\v2\x1d.plz_confirm.v1.ConfirmOutputH\x01R\rconfirmOutput\x12C\n" +
 	"\rselect_output\x18\v \x01(\v2\x1c.plz_confirm.v1.SelectOutputH\x01R\fselectOutput\x12=\n" +
@@ -751,7 +1401,11 @@ const file_plz_confirm_v1_request_proto_rawDesc = "" +
 	"\rupload_output\x18\r \x01(\v2\x1c.plz_confirm.v1.UploadOutputH\x01R\fuploadOutput\x12@\n" +
 	"\ftable_output\x18\x0e \x01(\v2\x1b.plz_confirm.v1.TableOutputH\x01R\vtableOutput\x12@\n" +
 	"\fimage_output\x18\x0f \x01(\v2\x1b.plz_confirm.v1.ImageOutputH\x01R\vimageOutput\x12C\n" +
-	"\rscript_output\x18\x19 \x01(\v2\x1c.plz_confirm.v1.ScriptOutputH\x01R\fscriptOutput\x125\n" +
+	"\rscript_output\x18\x19 \x01(\v2\x1c.plz_confirm.v1.ScriptOutputH\x01R\fscriptOutput\x12@\n" +
+	"\vgrid_output\x18& \x01(\v2\x1d.plz_confirm.v1.GridSelectionH\x01R\n" +
+	"gridOutput\x12C\n" +
+	"\rrating_output\x18' \x01(\v2\x1c.plz_confirm.v1.RatingOutputH\x01R\fratingOutput\x12F\n" +
+	"\x0edisplay_output\x18( \x01(\v2\x1d.plz_confirm.v1.DisplayOutputH\x01R\rdisplayOutput\x125\n" +
 	"\x06status\x18\x10 \x01(\x0e2\x1d.plz_confirm.v1.RequestStatusR\x06status\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\x11 \x01(\tR\tcreatedAt\x12&\n" +
@@ -768,7 +1422,15 @@ const file_plz_confirm_v1_request_proto_rawDesc = "" +
 	"scriptView\x88\x01\x01\x12L\n" +
 	"\x0fscript_describe\x18\x1c \x01(\v2\x1e.plz_confirm.v1.ScriptDescribeH\tR\x0escriptDescribe\x88\x01\x01\x12\x1f\n" +
 	"\vscript_logs\x18\x1d \x03(\tR\n" +
-	"scriptLogsB\a\n" +
+	"scriptLogs\x12@\n" +
+	"\bpresence\x18\x1e \x01(\v2\x1f.plz_confirm.v1.SessionPresenceH\n" +
+	"R\bpresence\x88\x01\x01\x12\x1a\n" +
+	"\bpriority\x18\x1f \x01(\x05R\bpriority\x12*\n" +
+	"\x0edeadline_aware\x18  \x01(\bH\vR\rdeadlineAware\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18! \x03(\tR\tdependsOn\x12,\n" +
+	"\x0fidempotency_key\x18\" \x01(\tH\fR\x0eidempotencyKey\x88\x01\x01\x12(\n" +
+	"\rscript_locale\x18) \x01(\tH\rR\fscriptLocale\x88\x01\x01B\a\n" +
 	"\x05inputB\b\n" +
 	"\x06outputB\x0f\n" +
 	"\r_completed_atB\b\n" +
@@ -778,13 +1440,23 @@ const file_plz_confirm_v1_request_proto_rawDesc = "" +
 	"\x10_expiry_disabledB\x0f\n" +
 	"\r_script_stateB\x0e\n" +
 	"\f_script_viewB\x12\n" +
-	"\x10_script_describe*c\n" +
+	"\x10_script_describeB\v\n" +
+	"\t_presenceB\x11\n" +
+	"\x0f_deadline_awareB\x12\n" +
+	"\x10_idempotency_keyB\x10\n" +
+	"\x0e_script_locale\"}\n" +
+	"\x0ePriorityUpdate\x12\x1f\n" +
+	"\bpriority\x18\x01 \x01(\x05H\x00R\bpriority\x88\x01\x01\x12*\n" +
+	"\x0edeadline_aware\x18\x02 \x01(\bH\x01R\rdeadlineAware\x88\x01\x01B\v\n" +
+	"\t_priorityB\x11\n" +
+	"\x0f_deadline_aware*r\n" +
 	"\rRequestStatus\x12\x1e\n" +
 	"\x1arequest_status_unspecified\x10\x00\x12\v\n" +
 	"\apending\x10\x01\x12\r\n" +
 	"\tcompleted\x10\x02\x12\v\n" +
 	"\atimeout\x10\x03\x12\t\n" +
-	"\x05error\x10\x04*z\n" +
+	"\x05error\x10\x04\x12\r\n" +
+	"\tcancelled\x10\x05*\x9d\x01\n" +
 	"\n" +
 	"WidgetType\x12\x1b\n" +
 	"\x17widget_type_unspecified\x10\x00\x12\v\n" +
@@ -797,7 +1469,19 @@ const file_plz_confirm_v1_request_proto_rawDesc = "" +
 	"\x05table\x10\x05\x12\t\n" +
 	"\x05image\x10\x06\x12\n" +
 	"\n" +
-	"\x06script\x10\aBGZEgithub.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1b\x06proto3"
+	"\x06script\x10\a\x12\b\n" +
+	"\x04grid\x10\b\x12\n" +
+	"\n" +
+	"\x06rating\x10\t\x12\v\n" +
+	"\adisplay\x10\n" +
+	"*U\n" +
+	"\rSessionStatus\x12\x1e\n" +
+	"\x1asession_status_unspecified\x10\x00\x12\n" +
+	"\n" +
+	"\x06active\x10\x01\x12\f\n" +
+	"\barchived\x10\x02\x12\n" +
+	"\n" +
+	"\x06closed\x10\x03BGZEgithub.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1b\x06proto3"
 
 var (
 	file_plz_confirm_v1_request_proto_rawDescOnce sync.Once
@@ -811,60 +1495,86 @@ func file_plz_confirm_v1_request_proto_rawDescGZIP() []byte {
 	return file_plz_confirm_v1_request_proto_rawDescData
 }
 
-var file_plz_confirm_v1_request_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_plz_confirm_v1_request_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_plz_confirm_v1_request_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_plz_confirm_v1_request_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_plz_confirm_v1_request_proto_goTypes = []any{
 	(RequestStatus)(0),      // 0: plz_confirm.v1.RequestStatus
 	(WidgetType)(0),         // 1: plz_confirm.v1.WidgetType
-	(*ProcessInfo)(nil),     // 2: plz_confirm.v1.ProcessInfo
-	(*RequestMetadata)(nil), // 3: plz_confirm.v1.RequestMetadata
-	(*UIRequest)(nil),       // 4: plz_confirm.v1.UIRequest
-	(*ConfirmInput)(nil),    // 5: plz_confirm.v1.ConfirmInput
-	(*SelectInput)(nil),     // 6: plz_confirm.v1.SelectInput
-	(*FormInput)(nil),       // 7: plz_confirm.v1.FormInput
-	(*UploadInput)(nil),     // 8: plz_confirm.v1.UploadInput
-	(*TableInput)(nil),      // 9: plz_confirm.v1.TableInput
-	(*ImageInput)(nil),      // 10: plz_confirm.v1.ImageInput
-	(*ScriptInput)(nil),     // 11: plz_confirm.v1.ScriptInput
-	(*ConfirmOutput)(nil),   // 12: plz_confirm.v1.ConfirmOutput
-	(*SelectOutput)(nil),    // 13: plz_confirm.v1.SelectOutput
-	(*FormOutput)(nil),      // 14: plz_confirm.v1.FormOutput
-	(*UploadOutput)(nil),    // 15: plz_confirm.v1.UploadOutput
-	(*TableOutput)(nil),     // 16: plz_confirm.v1.TableOutput
-	(*ImageOutput)(nil),     // 17: plz_confirm.v1.ImageOutput
-	(*ScriptOutput)(nil),    // 18: plz_confirm.v1.ScriptOutput
-	(*structpb.Struct)(nil), // 19: google.protobuf.Struct
-	(*ScriptView)(nil),      // 20: plz_confirm.v1.ScriptView
-	(*ScriptDescribe)(nil),  // 21: plz_confirm.v1.ScriptDescribe
+	(SessionStatus)(0),      // 2: plz_confirm.v1.SessionStatus
+	(*ProcessInfo)(nil),     // 3: plz_confirm.v1.ProcessInfo
+	(*RequestMetadata)(nil), // 4: plz_confirm.v1.RequestMetadata
+	(*SessionViewer)(nil),   // 5: plz_confirm.v1.SessionViewer
+	(*SessionPresence)(nil), // 6: plz_confirm.v1.SessionPresence
+	(*SessionMetadata)(nil), // 7: plz_confirm.v1.SessionMetadata
+	(*Session)(nil),         // 8: plz_confirm.v1.Session
+	(*SessionList)(nil),     // 9: plz_confirm.v1.SessionList
+	(*UIRequest)(nil),       // 10: plz_confirm.v1.UIRequest
+	(*PriorityUpdate)(nil),  // 11: plz_confirm.v1.PriorityUpdate
+	nil,                     // 12: plz_confirm.v1.SessionMetadata.LabelsEntry
+	(*ConfirmInput)(nil),    // 13: plz_confirm.v1.ConfirmInput
+	(*SelectInput)(nil),     // 14: plz_confirm.v1.SelectInput
+	(*FormInput)(nil),       // 15: plz_confirm.v1.FormInput
+	(*UploadInput)(nil),     // 16: plz_confirm.v1.UploadInput
+	(*TableInput)(nil),      // 17: plz_confirm.v1.TableInput
+	(*ImageInput)(nil),      // 18: plz_confirm.v1.ImageInput
+	(*ScriptInput)(nil),     // 19: plz_confirm.v1.ScriptInput
+	(*GridInput)(nil),       // 20: plz_confirm.v1.GridInput
+	(*RatingInput)(nil),     // 21: plz_confirm.v1.RatingInput
+	(*DisplayInput)(nil),    // 22: plz_confirm.v1.DisplayInput
+	(*ConfirmOutput)(nil),   // 23: plz_confirm.v1.ConfirmOutput
+	(*SelectOutput)(nil),    // 24: plz_confirm.v1.SelectOutput
+	(*FormOutput)(nil),      // 25: plz_confirm.v1.FormOutput
+	(*UploadOutput)(nil),    // 26: plz_confirm.v1.UploadOutput
+	(*TableOutput)(nil),     // 27: plz_confirm.v1.TableOutput
+	(*ImageOutput)(nil),     // 28: plz_confirm.v1.ImageOutput
+	(*ScriptOutput)(nil),    // 29: plz_confirm.v1.ScriptOutput
+	(*GridSelection)(nil),   // 30: plz_confirm.v1.GridSelection
+	(*RatingOutput)(nil),    // 31: plz_confirm.v1.RatingOutput
+	(*DisplayOutput)(nil),   // 32: plz_confirm.v1.DisplayOutput
+	(*structpb.Struct)(nil), // 33: google.protobuf.Struct
+	(*ScriptView)(nil),      // 34: plz_confirm.v1.ScriptView
+	(*ScriptDescribe)(nil),  // 35: plz_confirm.v1.ScriptDescribe
 }
 var file_plz_confirm_v1_request_proto_depIdxs = []int32{
-	2,  // 0: plz_confirm.v1.RequestMetadata.self:type_name -> plz_confirm.v1.ProcessInfo
-	2,  // 1: plz_confirm.v1.RequestMetadata.parents:type_name -> plz_confirm.v1.ProcessInfo
-	1,  // 2: plz_confirm.v1.UIRequest.type:type_name -> plz_confirm.v1.WidgetType
-	5,  // 3: plz_confirm.v1.UIRequest.confirm_input:type_name -> plz_confirm.v1.ConfirmInput
-	6,  // 4: plz_confirm.v1.UIRequest.select_input:type_name -> plz_confirm.v1.SelectInput
-	7,  // 5: plz_confirm.v1.UIRequest.form_input:type_name -> plz_confirm.v1.FormInput
-	8,  // 6: plz_confirm.v1.UIRequest.upload_input:type_name -> plz_confirm.v1.UploadInput
-	9,  // 7: plz_confirm.v1.UIRequest.table_input:type_name -> plz_confirm.v1.TableInput
-	10, // 8: plz_confirm.v1.UIRequest.image_input:type_name -> plz_confirm.v1.ImageInput
-	11, // 9: plz_confirm.v1.UIRequest.script_input:type_name -> plz_confirm.v1.ScriptInput
-	12, // 10: plz_confirm.v1.UIRequest.confirm_output:type_name -> plz_confirm.v1.ConfirmOutput
-	13, // 11: plz_confirm.v1.UIRequest.select_output:type_name -> plz_confirm.v1.SelectOutput
-	14, // 12: plz_confirm.v1.UIRequest.form_output:type_name -> plz_confirm.v1.FormOutput
-	15, // 13: plz_confirm.v1.UIRequest.upload_output:type_name -> plz_confirm.v1.UploadOutput
-	16, // 14: plz_confirm.v1.UIRequest.table_output:type_name -> plz_confirm.v1.TableOutput
-	17, // 15: plz_confirm.v1.UIRequest.image_output:type_name -> plz_confirm.v1.ImageOutput
-	18, // 16: plz_confirm.v1.UIRequest.script_output:type_name -> plz_confirm.v1.ScriptOutput
-	0,  // 17: plz_confirm.v1.UIRequest.status:type_name -> plz_confirm.v1.RequestStatus
-	3,  // 18: plz_confirm.v1.UIRequest.metadata:type_name -> plz_confirm.v1.RequestMetadata
-	19, // 19: plz_confirm.v1.UIRequest.script_state:type_name -> google.protobuf.Struct
-	20, // 20: plz_confirm.v1.UIRequest.script_view:type_name -> plz_confirm.v1.ScriptView
-	21, // 21: plz_confirm.v1.UIRequest.script_describe:type_name -> plz_confirm.v1.ScriptDescribe
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	3,  // 0: plz_confirm.v1.RequestMetadata.self:type_name -> plz_confirm.v1.ProcessInfo
+	3,  // 1: plz_confirm.v1.RequestMetadata.parents:type_name -> plz_confirm.v1.ProcessInfo
+	5,  // 2: plz_confirm.v1.SessionPresence.viewers:type_name -> plz_confirm.v1.SessionViewer
+	12, // 3: plz_confirm.v1.SessionMetadata.labels:type_name -> plz_confirm.v1.SessionMetadata.LabelsEntry
+	2,  // 4: plz_confirm.v1.Session.status:type_name -> plz_confirm.v1.SessionStatus
+	7,  // 5: plz_confirm.v1.Session.metadata:type_name -> plz_confirm.v1.SessionMetadata
+	8,  // 6: plz_confirm.v1.SessionList.sessions:type_name -> plz_confirm.v1.Session
+	1,  // 7: plz_confirm.v1.UIRequest.type:type_name -> plz_confirm.v1.WidgetType
+	13, // 8: plz_confirm.v1.UIRequest.confirm_input:type_name -> plz_confirm.v1.ConfirmInput
+	14, // 9: plz_confirm.v1.UIRequest.select_input:type_name -> plz_confirm.v1.SelectInput
+	15, // 10: plz_confirm.v1.UIRequest.form_input:type_name -> plz_confirm.v1.FormInput
+	16, // 11: plz_confirm.v1.UIRequest.upload_input:type_name -> plz_confirm.v1.UploadInput
+	17, // 12: plz_confirm.v1.UIRequest.table_input:type_name -> plz_confirm.v1.TableInput
+	18, // 13: plz_confirm.v1.UIRequest.image_input:type_name -> plz_confirm.v1.ImageInput
+	19, // 14: plz_confirm.v1.UIRequest.script_input:type_name -> plz_confirm.v1.ScriptInput
+	20, // 15: plz_confirm.v1.UIRequest.grid_input:type_name -> plz_confirm.v1.GridInput
+	21, // 16: plz_confirm.v1.UIRequest.rating_input:type_name -> plz_confirm.v1.RatingInput
+	22, // 17: plz_confirm.v1.UIRequest.display_input:type_name -> plz_confirm.v1.DisplayInput
+	23, // 18: plz_confirm.v1.UIRequest.confirm_output:type_name -> plz_confirm.v1.ConfirmOutput
+	24, // 19: plz_confirm.v1.UIRequest.select_output:type_name -> plz_confirm.v1.SelectOutput
+	25, // 20: plz_confirm.v1.UIRequest.form_output:type_name -> plz_confirm.v1.FormOutput
+	26, // 21: plz_confirm.v1.UIRequest.upload_output:type_name -> plz_confirm.v1.UploadOutput
+	27, // 22: plz_confirm.v1.UIRequest.table_output:type_name -> plz_confirm.v1.TableOutput
+	28, // 23: plz_confirm.v1.UIRequest.image_output:type_name -> plz_confirm.v1.ImageOutput
+	29, // 24: plz_confirm.v1.UIRequest.script_output:type_name -> plz_confirm.v1.ScriptOutput
+	30, // 25: plz_confirm.v1.UIRequest.grid_output:type_name -> plz_confirm.v1.GridSelection
+	31, // 26: plz_confirm.v1.UIRequest.rating_output:type_name -> plz_confirm.v1.RatingOutput
+	32, // 27: plz_confirm.v1.UIRequest.display_output:type_name -> plz_confirm.v1.DisplayOutput
+	0,  // 28: plz_confirm.v1.UIRequest.status:type_name -> plz_confirm.v1.RequestStatus
+	4,  // 29: plz_confirm.v1.UIRequest.metadata:type_name -> plz_confirm.v1.RequestMetadata
+	33, // 30: plz_confirm.v1.UIRequest.script_state:type_name -> google.protobuf.Struct
+	34, // 31: plz_confirm.v1.UIRequest.script_view:type_name -> plz_confirm.v1.ScriptView
+	35, // 32: plz_confirm.v1.UIRequest.script_describe:type_name -> plz_confirm.v1.ScriptDescribe
+	6,  // 33: plz_confirm.v1.UIRequest.presence:type_name -> plz_confirm.v1.SessionPresence
+	34, // [34:34] is the sub-list for method output_type
+	34, // [34:34] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_plz_confirm_v1_request_proto_init() }
@@ -875,7 +1585,10 @@ func file_plz_confirm_v1_request_proto_init() {
 	file_plz_confirm_v1_widgets_proto_init()
 	file_plz_confirm_v1_request_proto_msgTypes[0].OneofWrappers = []any{}
 	file_plz_confirm_v1_request_proto_msgTypes[1].OneofWrappers = []any{}
-	file_plz_confirm_v1_request_proto_msgTypes[2].OneofWrappers = []any{
+	file_plz_confirm_v1_request_proto_msgTypes[3].OneofWrappers = []any{}
+	file_plz_confirm_v1_request_proto_msgTypes[4].OneofWrappers = []any{}
+	file_plz_confirm_v1_request_proto_msgTypes[5].OneofWrappers = []any{}
+	file_plz_confirm_v1_request_proto_msgTypes[7].OneofWrappers = []any{
 		(*UIRequest_ConfirmInput)(nil),
 		(*UIRequest_SelectInput)(nil),
 		(*UIRequest_FormInput)(nil),
@@ -883,6 +1596,9 @@ func file_plz_confirm_v1_request_proto_init() {
 		(*UIRequest_TableInput)(nil),
 		(*UIRequest_ImageInput)(nil),
 		(*UIRequest_ScriptInput)(nil),
+		(*UIRequest_GridInput)(nil),
+		(*UIRequest_RatingInput)(nil),
+		(*UIRequest_DisplayInput)(nil),
 		(*UIRequest_ConfirmOutput)(nil),
 		(*UIRequest_SelectOutput)(nil),
 		(*UIRequest_FormOutput)(nil),
@@ -890,14 +1606,18 @@ func file_plz_confirm_v1_request_proto_init() {
 		(*UIRequest_TableOutput)(nil),
 		(*UIRequest_ImageOutput)(nil),
 		(*UIRequest_ScriptOutput)(nil),
+		(*UIRequest_GridOutput)(nil),
+		(*UIRequest_RatingOutput)(nil),
+		(*UIRequest_DisplayOutput)(nil),
 	}
+	file_plz_confirm_v1_request_proto_msgTypes[8].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plz_confirm_v1_request_proto_rawDesc), len(file_plz_confirm_v1_request_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   3,
+			NumEnums:      3,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -356,7 +356,7 @@ func (x *SelectOutputMulti) GetValues() []string {
 	return nil
 }
 
-// Grid Widget (script view extension)
+// Grid Widget
 type GridCell struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
@@ -514,6 +514,7 @@ type GridSelection struct {
 	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
 	Col           int32                  `protobuf:"varint,2,opt,name=col,proto3" json:"col,omitempty"`
 	CellIndex     int32                  `protobuf:"varint,3,opt,name=cell_index,json=cellIndex,proto3" json:"cell_index,omitempty"`
+	Comment       *string                `protobuf:"bytes,4,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -569,7 +570,14 @@ func (x *GridSelection) GetCellIndex() int32 {
 	return 0
 }
 
-// Rating Widget (script view extension)
+func (x *GridSelection) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+// Rating Widget
 type RatingLabels struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Low           *string                `protobuf:"bytes,1,opt,name=low,proto3,oneof" json:"low,omitempty"`
@@ -1658,30 +1666,30 @@ func (x *ImageOutputStrings) GetValues() []string {
 }
 
 // Script Widget
-type ScriptInput struct {
+// Diff Widget (script view extension)
+type DiffHunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Script        string                 `protobuf:"bytes,2,opt,name=script,proto3" json:"script,omitempty"`
-	Props         *structpb.Struct       `protobuf:"bytes,3,opt,name=props,proto3" json:"props,omitempty"`
-	TimeoutMs     *int64                 `protobuf:"varint,4,opt,name=timeout_ms,json=timeoutMs,proto3,oneof" json:"timeout_ms,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`               // Unique within the view; echoed in DiffHunkDecision
+	Header        *string                `protobuf:"bytes,2,opt,name=header,proto3,oneof" json:"header,omitempty"` // e.g. "@@ -10,4 +10,6 @@"
+	Lines         []string               `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`         // Unified diff lines starting with " ", "+", or "-"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptInput) Reset() {
-	*x = ScriptInput{}
+func (x *DiffHunk) Reset() {
+	*x = DiffHunk{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptInput) String() string {
+func (x *DiffHunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptInput) ProtoMessage() {}
+func (*DiffHunk) ProtoMessage() {}
 
-func (x *ScriptInput) ProtoReflect() protoreflect.Message {
+func (x *DiffHunk) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1693,62 +1701,56 @@ func (x *ScriptInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptInput.ProtoReflect.Descriptor instead.
-func (*ScriptInput) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffHunk.ProtoReflect.Descriptor instead.
+func (*DiffHunk) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *ScriptInput) GetTitle() string {
+func (x *DiffHunk) GetId() string {
 	if x != nil {
-		return x.Title
+		return x.Id
 	}
 	return ""
 }
 
-func (x *ScriptInput) GetScript() string {
-	if x != nil {
-		return x.Script
+func (x *DiffHunk) GetHeader() string {
+	if x != nil && x.Header != nil {
+		return *x.Header
 	}
 	return ""
 }
 
-func (x *ScriptInput) GetProps() *structpb.Struct {
+func (x *DiffHunk) GetLines() []string {
 	if x != nil {
-		return x.Props
+		return x.Lines
 	}
 	return nil
 }
 
-func (x *ScriptInput) GetTimeoutMs() int64 {
-	if x != nil && x.TimeoutMs != nil {
-		return *x.TimeoutMs
-	}
-	return 0
-}
-
-type ScriptOutput struct {
+type DiffFile struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        *structpb.Struct       `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
-	Logs          []string               `protobuf:"bytes,2,rep,name=logs,proto3" json:"logs,omitempty"`
-	Error         *string                `protobuf:"bytes,3,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	OldPath       *string                `protobuf:"bytes,2,opt,name=old_path,json=oldPath,proto3,oneof" json:"old_path,omitempty"` // Set for renames
+	Language      *string                `protobuf:"bytes,3,opt,name=language,proto3,oneof" json:"language,omitempty"`              // Syntax hint, e.g. "go"
+	Hunks         []*DiffHunk            `protobuf:"bytes,4,rep,name=hunks,proto3" json:"hunks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptOutput) Reset() {
-	*x = ScriptOutput{}
+func (x *DiffFile) Reset() {
+	*x = DiffFile{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptOutput) String() string {
+func (x *DiffFile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptOutput) ProtoMessage() {}
+func (*DiffFile) ProtoMessage() {}
 
-func (x *ScriptOutput) ProtoReflect() protoreflect.Message {
+func (x *DiffFile) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1760,56 +1762,64 @@ func (x *ScriptOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptOutput.ProtoReflect.Descriptor instead.
-func (*ScriptOutput) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffFile.ProtoReflect.Descriptor instead.
+func (*DiffFile) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *ScriptOutput) GetResult() *structpb.Struct {
+func (x *DiffFile) GetPath() string {
 	if x != nil {
-		return x.Result
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-func (x *ScriptOutput) GetLogs() []string {
-	if x != nil {
-		return x.Logs
+func (x *DiffFile) GetOldPath() string {
+	if x != nil && x.OldPath != nil {
+		return *x.OldPath
 	}
-	return nil
+	return ""
 }
 
-func (x *ScriptOutput) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+func (x *DiffFile) GetLanguage() string {
+	if x != nil && x.Language != nil {
+		return *x.Language
 	}
 	return ""
 }
 
-type ScriptEvent struct {
+func (x *DiffFile) GetHunks() []*DiffHunk {
+	if x != nil {
+		return x.Hunks
+	}
+	return nil
+}
+
+type DiffInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	StepId        *string                `protobuf:"bytes,2,opt,name=step_id,json=stepId,proto3,oneof" json:"step_id,omitempty"`
-	ActionId      *string                `protobuf:"bytes,3,opt,name=action_id,json=actionId,proto3,oneof" json:"action_id,omitempty"`
-	Data          *structpb.Struct       `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Message       *string                `protobuf:"bytes,2,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	Files         []*DiffFile            `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	Layout        *string                `protobuf:"bytes,4,opt,name=layout,proto3,oneof" json:"layout,omitempty"`                            // "unified" (default) | "split"
+	RequireAll    *bool                  `protobuf:"varint,5,opt,name=require_all,json=requireAll,proto3,oneof" json:"require_all,omitempty"` // Every hunk needs a decision before submit
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptEvent) Reset() {
-	*x = ScriptEvent{}
+func (x *DiffInput) Reset() {
+	*x = DiffInput{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptEvent) String() string {
+func (x *DiffInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptEvent) ProtoMessage() {}
+func (*DiffInput) ProtoMessage() {}
 
-func (x *ScriptEvent) ProtoReflect() protoreflect.Message {
+func (x *DiffInput) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1821,61 +1831,69 @@ func (x *ScriptEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptEvent.ProtoReflect.Descriptor instead.
-func (*ScriptEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffInput.ProtoReflect.Descriptor instead.
+func (*DiffInput) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *ScriptEvent) GetType() string {
+func (x *DiffInput) GetTitle() string {
 	if x != nil {
-		return x.Type
+		return x.Title
 	}
 	return ""
 }
 
-func (x *ScriptEvent) GetStepId() string {
-	if x != nil && x.StepId != nil {
-		return *x.StepId
+func (x *DiffInput) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
 	}
 	return ""
 }
 
-func (x *ScriptEvent) GetActionId() string {
-	if x != nil && x.ActionId != nil {
-		return *x.ActionId
+func (x *DiffInput) GetFiles() []*DiffFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *DiffInput) GetLayout() string {
+	if x != nil && x.Layout != nil {
+		return *x.Layout
 	}
 	return ""
 }
 
-func (x *ScriptEvent) GetData() *structpb.Struct {
-	if x != nil {
-		return x.Data
+func (x *DiffInput) GetRequireAll() bool {
+	if x != nil && x.RequireAll != nil {
+		return *x.RequireAll
 	}
-	return nil
+	return false
 }
 
-type ScriptViewSection struct {
+type DiffHunkDecision struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WidgetType    string                 `protobuf:"bytes,1,opt,name=widget_type,json=widgetType,proto3" json:"widget_type,omitempty"`
-	Input         *structpb.Struct       `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	HunkId        string                 `protobuf:"bytes,1,opt,name=hunk_id,json=hunkId,proto3" json:"hunk_id,omitempty"`
+	Decision      string                 `protobuf:"bytes,2,opt,name=decision,proto3" json:"decision,omitempty"` // "approve" | "reject"
+	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptViewSection) Reset() {
-	*x = ScriptViewSection{}
+func (x *DiffHunkDecision) Reset() {
+	*x = DiffHunkDecision{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptViewSection) String() string {
+func (x *DiffHunkDecision) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptViewSection) ProtoMessage() {}
+func (*DiffHunkDecision) ProtoMessage() {}
 
-func (x *ScriptViewSection) ProtoReflect() protoreflect.Message {
+func (x *DiffHunkDecision) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1887,47 +1905,55 @@ func (x *ScriptViewSection) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptViewSection.ProtoReflect.Descriptor instead.
-func (*ScriptViewSection) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffHunkDecision.ProtoReflect.Descriptor instead.
+func (*DiffHunkDecision) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *ScriptViewSection) GetWidgetType() string {
+func (x *DiffHunkDecision) GetHunkId() string {
 	if x != nil {
-		return x.WidgetType
+		return x.HunkId
 	}
 	return ""
 }
 
-func (x *ScriptViewSection) GetInput() *structpb.Struct {
+func (x *DiffHunkDecision) GetDecision() string {
 	if x != nil {
-		return x.Input
+		return x.Decision
 	}
-	return nil
+	return ""
 }
 
-type DisplayInput struct {
+func (x *DiffHunkDecision) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+type DiffOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	Format        *string                `protobuf:"bytes,2,opt,name=format,proto3,oneof" json:"format,omitempty"` // "markdown" | "text" | "html"
+	Decisions     []*DiffHunkDecision    `protobuf:"bytes,1,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	Approved      bool                   `protobuf:"varint,2,opt,name=approved,proto3" json:"approved,omitempty"` // No hunk was rejected
+	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DisplayInput) Reset() {
-	*x = DisplayInput{}
+func (x *DiffOutput) Reset() {
+	*x = DiffOutput{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DisplayInput) String() string {
+func (x *DiffOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisplayInput) ProtoMessage() {}
+func (*DiffOutput) ProtoMessage() {}
 
-func (x *DisplayInput) ProtoReflect() protoreflect.Message {
+func (x *DiffOutput) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1939,48 +1965,57 @@ func (x *DisplayInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisplayInput.ProtoReflect.Descriptor instead.
-func (*DisplayInput) Descriptor() ([]byte, []int) {
+// Deprecated: Use DiffOutput.ProtoReflect.Descriptor instead.
+func (*DiffOutput) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *DisplayInput) GetContent() string {
+func (x *DiffOutput) GetDecisions() []*DiffHunkDecision {
 	if x != nil {
-		return x.Content
+		return x.Decisions
 	}
-	return ""
+	return nil
 }
 
-func (x *DisplayInput) GetFormat() string {
-	if x != nil && x.Format != nil {
-		return *x.Format
+func (x *DiffOutput) GetApproved() bool {
+	if x != nil {
+		return x.Approved
+	}
+	return false
+}
+
+func (x *DiffOutput) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
 	}
 	return ""
 }
 
-type ScriptProgress struct {
+// Key-Value Widget (script view extension)
+type KeyValueItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Current       int32                  `protobuf:"varint,1,opt,name=current,proto3" json:"current,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Label         *string                `protobuf:"bytes,3,opt,name=label,proto3,oneof" json:"label,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Style         *string                `protobuf:"bytes,3,opt,name=style,proto3,oneof" json:"style,omitempty"` // "default" | "muted" | "success" | "warning" | "danger"
+	Monospace     *bool                  `protobuf:"varint,4,opt,name=monospace,proto3,oneof" json:"monospace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptProgress) Reset() {
-	*x = ScriptProgress{}
+func (x *KeyValueItem) Reset() {
+	*x = KeyValueItem{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptProgress) String() string {
+func (x *KeyValueItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptProgress) ProtoMessage() {}
+func (*KeyValueItem) ProtoMessage() {}
 
-func (x *ScriptProgress) ProtoReflect() protoreflect.Message {
+func (x *KeyValueItem) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1992,55 +2027,63 @@ func (x *ScriptProgress) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptProgress.ProtoReflect.Descriptor instead.
-func (*ScriptProgress) Descriptor() ([]byte, []int) {
+// Deprecated: Use KeyValueItem.ProtoReflect.Descriptor instead.
+func (*KeyValueItem) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *ScriptProgress) GetCurrent() int32 {
+func (x *KeyValueItem) GetKey() string {
 	if x != nil {
-		return x.Current
+		return x.Key
 	}
-	return 0
+	return ""
 }
 
-func (x *ScriptProgress) GetTotal() int32 {
+func (x *KeyValueItem) GetValue() string {
 	if x != nil {
-		return x.Total
+		return x.Value
 	}
-	return 0
+	return ""
 }
 
-func (x *ScriptProgress) GetLabel() string {
-	if x != nil && x.Label != nil {
-		return *x.Label
+func (x *KeyValueItem) GetStyle() string {
+	if x != nil && x.Style != nil {
+		return *x.Style
 	}
 	return ""
 }
 
-type ScriptToast struct {
+func (x *KeyValueItem) GetMonospace() bool {
+	if x != nil && x.Monospace != nil {
+		return *x.Monospace
+	}
+	return false
+}
+
+type KeyValueInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	DurationMs    *int32                 `protobuf:"varint,2,opt,name=duration_ms,json=durationMs,proto3,oneof" json:"duration_ms,omitempty"`
-	Style         *string                `protobuf:"bytes,3,opt,name=style,proto3,oneof" json:"style,omitempty"` // "info" | "success" | "warning" | "error"
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Message       *string                `protobuf:"bytes,2,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	Items         []*KeyValueItem        `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	ConfirmText   *string                `protobuf:"bytes,4,opt,name=confirm_text,json=confirmText,proto3,oneof" json:"confirm_text,omitempty"` // Continue button label when shown on its own
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptToast) Reset() {
-	*x = ScriptToast{}
+func (x *KeyValueInput) Reset() {
+	*x = KeyValueInput{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptToast) String() string {
+func (x *KeyValueInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptToast) ProtoMessage() {}
+func (*KeyValueInput) ProtoMessage() {}
 
-func (x *ScriptToast) ProtoReflect() protoreflect.Message {
+func (x *KeyValueInput) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2052,62 +2095,61 @@ func (x *ScriptToast) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptToast.ProtoReflect.Descriptor instead.
-func (*ScriptToast) Descriptor() ([]byte, []int) {
+// Deprecated: Use KeyValueInput.ProtoReflect.Descriptor instead.
+func (*KeyValueInput) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *ScriptToast) GetMessage() string {
+func (x *KeyValueInput) GetTitle() string {
 	if x != nil {
-		return x.Message
+		return x.Title
 	}
 	return ""
 }
 
-func (x *ScriptToast) GetDurationMs() int32 {
-	if x != nil && x.DurationMs != nil {
-		return *x.DurationMs
+func (x *KeyValueInput) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *ScriptToast) GetStyle() string {
-	if x != nil && x.Style != nil {
-		return *x.Style
+func (x *KeyValueInput) GetItems() []*KeyValueItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *KeyValueInput) GetConfirmText() string {
+	if x != nil && x.ConfirmText != nil {
+		return *x.ConfirmText
 	}
 	return ""
 }
 
-type ScriptView struct {
+type KeyValueOutput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WidgetType    string                 `protobuf:"bytes,1,opt,name=widget_type,json=widgetType,proto3" json:"widget_type,omitempty"`
-	Input         *structpb.Struct       `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
-	StepId        *string                `protobuf:"bytes,3,opt,name=step_id,json=stepId,proto3,oneof" json:"step_id,omitempty"`
-	Title         *string                `protobuf:"bytes,4,opt,name=title,proto3,oneof" json:"title,omitempty"`
-	Description   *string                `protobuf:"bytes,5,opt,name=description,proto3,oneof" json:"description,omitempty"`
-	Sections      []*ScriptViewSection   `protobuf:"bytes,6,rep,name=sections,proto3" json:"sections,omitempty"`
-	Progress      *ScriptProgress        `protobuf:"bytes,7,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
-	AllowBack     *bool                  `protobuf:"varint,8,opt,name=allow_back,json=allowBack,proto3,oneof" json:"allow_back,omitempty"`
-	BackLabel     *string                `protobuf:"bytes,9,opt,name=back_label,json=backLabel,proto3,oneof" json:"back_label,omitempty"`
-	Toast         *ScriptToast           `protobuf:"bytes,10,opt,name=toast,proto3,oneof" json:"toast,omitempty"`
+	Acknowledged  bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	Comment       *string                `protobuf:"bytes,2,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptView) Reset() {
-	*x = ScriptView{}
+func (x *KeyValueOutput) Reset() {
+	*x = KeyValueOutput{}
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptView) String() string {
+func (x *KeyValueOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptView) ProtoMessage() {}
+func (*KeyValueOutput) ProtoMessage() {}
 
-func (x *ScriptView) ProtoReflect() protoreflect.Message {
+func (x *KeyValueOutput) ProtoReflect() protoreflect.Message {
 	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2119,49 +2161,1737 @@ func (x *ScriptView) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptView.ProtoReflect.Descriptor instead.
-func (*ScriptView) Descriptor() ([]byte, []int) {
+// Deprecated: Use KeyValueOutput.ProtoReflect.Descriptor instead.
+func (*KeyValueOutput) Descriptor() ([]byte, []int) {
 	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *ScriptView) GetWidgetType() string {
+func (x *KeyValueOutput) GetAcknowledged() bool {
 	if x != nil {
-		return x.WidgetType
+		return x.Acknowledged
 	}
-	return ""
+	return false
 }
 
-func (x *ScriptView) GetInput() *structpb.Struct {
-	if x != nil {
-		return x.Input
+func (x *KeyValueOutput) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
 	}
-	return nil
+	return ""
 }
 
-func (x *ScriptView) GetStepId() string {
-	if x != nil && x.StepId != nil {
-		return *x.StepId
-	}
-	return ""
+// Checklist Widget (script view extension)
+type ChecklistItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Description   *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Required      *bool                  `protobuf:"varint,4,opt,name=required,proto3,oneof" json:"required,omitempty"` // Defaults to true: must be ticked before submit
+	Checked       *bool                  `protobuf:"varint,5,opt,name=checked,proto3,oneof" json:"checked,omitempty"`   // Initially ticked
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ScriptView) GetTitle() string {
-	if x != nil && x.Title != nil {
-		return *x.Title
-	}
-	return ""
+func (x *ChecklistItem) Reset() {
+	*x = ChecklistItem{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ScriptView) GetDescription() string {
-	if x != nil && x.Description != nil {
-		return *x.Description
-	}
-	return ""
+func (x *ChecklistItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ScriptView) GetSections() []*ScriptViewSection {
+func (*ChecklistItem) ProtoMessage() {}
+
+func (x *ChecklistItem) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[32]
 	if x != nil {
-		return x.Sections
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistItem.ProtoReflect.Descriptor instead.
+func (*ChecklistItem) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ChecklistItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetRequired() bool {
+	if x != nil && x.Required != nil {
+		return *x.Required
+	}
+	return false
+}
+
+func (x *ChecklistItem) GetChecked() bool {
+	if x != nil && x.Checked != nil {
+		return *x.Checked
+	}
+	return false
+}
+
+type ChecklistInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Message       *string                `protobuf:"bytes,2,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	Items         []*ChecklistItem       `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	SubmitText    *string                `protobuf:"bytes,4,opt,name=submit_text,json=submitText,proto3,oneof" json:"submit_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecklistInput) Reset() {
+	*x = ChecklistInput{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecklistInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecklistInput) ProtoMessage() {}
+
+func (x *ChecklistInput) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistInput.ProtoReflect.Descriptor instead.
+func (*ChecklistInput) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ChecklistInput) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChecklistInput) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+func (x *ChecklistInput) GetItems() []*ChecklistItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ChecklistInput) GetSubmitText() string {
+	if x != nil && x.SubmitText != nil {
+		return *x.SubmitText
+	}
+	return ""
+}
+
+type ChecklistOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checked       []string               `protobuf:"bytes,1,rep,name=checked,proto3" json:"checked,omitempty"` // IDs of ticked items
+	Comment       *string                `protobuf:"bytes,2,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecklistOutput) Reset() {
+	*x = ChecklistOutput{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecklistOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecklistOutput) ProtoMessage() {}
+
+func (x *ChecklistOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistOutput.ProtoReflect.Descriptor instead.
+func (*ChecklistOutput) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ChecklistOutput) GetChecked() []string {
+	if x != nil {
+		return x.Checked
+	}
+	return nil
+}
+
+func (x *ChecklistOutput) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+type ScriptInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Script        string                 `protobuf:"bytes,2,opt,name=script,proto3" json:"script,omitempty"`
+	Props         *structpb.Struct       `protobuf:"bytes,3,opt,name=props,proto3" json:"props,omitempty"`
+	TimeoutMs     *int64                 `protobuf:"varint,4,opt,name=timeout_ms,json=timeoutMs,proto3,oneof" json:"timeout_ms,omitempty"`
+	ScriptRef     *ScriptRef             `protobuf:"bytes,5,opt,name=script_ref,json=scriptRef,proto3,oneof" json:"script_ref,omitempty"` // Registered script to run instead of inline script
+	Language      *string                `protobuf:"bytes,6,opt,name=language,proto3,oneof" json:"language,omitempty"`                    // "javascript" (default) | "typescript"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptInput) Reset() {
+	*x = ScriptInput{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptInput) ProtoMessage() {}
+
+func (x *ScriptInput) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptInput.ProtoReflect.Descriptor instead.
+func (*ScriptInput) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ScriptInput) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ScriptInput) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *ScriptInput) GetProps() *structpb.Struct {
+	if x != nil {
+		return x.Props
+	}
+	return nil
+}
+
+func (x *ScriptInput) GetTimeoutMs() int64 {
+	if x != nil && x.TimeoutMs != nil {
+		return *x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *ScriptInput) GetScriptRef() *ScriptRef {
+	if x != nil {
+		return x.ScriptRef
+	}
+	return nil
+}
+
+func (x *ScriptInput) GetLanguage() string {
+	if x != nil && x.Language != nil {
+		return *x.Language
+	}
+	return ""
+}
+
+type ScriptRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       *int32                 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"` // Latest when unset; the server records the resolved version
+	Sha256        string                 `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`          // Set by the server to the digest of the resolved source
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptRef) Reset() {
+	*x = ScriptRef{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptRef) ProtoMessage() {}
+
+func (x *ScriptRef) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptRef.ProtoReflect.Descriptor instead.
+func (*ScriptRef) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ScriptRef) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScriptRef) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+func (x *ScriptRef) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type RegisteredScript struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Script        string                 `protobuf:"bytes,3,opt,name=script,proto3" json:"script,omitempty"`
+	Sha256        string                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Description   *string                `protobuf:"bytes,5,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Language      *string                `protobuf:"bytes,7,opt,name=language,proto3,oneof" json:"language,omitempty"` // "javascript" (default) | "typescript"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredScript) Reset() {
+	*x = RegisteredScript{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredScript) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredScript) ProtoMessage() {}
+
+func (x *RegisteredScript) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredScript.ProtoReflect.Descriptor instead.
+func (*RegisteredScript) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RegisteredScript) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisteredScript) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RegisteredScript) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *RegisteredScript) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *RegisteredScript) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *RegisteredScript) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *RegisteredScript) GetLanguage() string {
+	if x != nil && x.Language != nil {
+		return *x.Language
+	}
+	return ""
+}
+
+type RegisteredScriptList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scripts       []*RegisteredScript    `protobuf:"bytes,1,rep,name=scripts,proto3" json:"scripts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredScriptList) Reset() {
+	*x = RegisteredScriptList{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredScriptList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredScriptList) ProtoMessage() {}
+
+func (x *RegisteredScriptList) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredScriptList.ProtoReflect.Descriptor instead.
+func (*RegisteredScriptList) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RegisteredScriptList) GetScripts() []*RegisteredScript {
+	if x != nil {
+		return x.Scripts
+	}
+	return nil
+}
+
+// Result of POST /api/scripts/validate.
+type ScriptValidation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"` // No issue has severity "error"
+	Issues        []*ScriptLintIssue     `protobuf:"bytes,2,rep,name=issues,proto3" json:"issues,omitempty"`
+	Steps         []*ScriptLintStep      `protobuf:"bytes,3,rep,name=steps,proto3" json:"steps,omitempty"` // Initial step plus steps reached through ctx.branch
+	Describe      *ScriptDescribe        `protobuf:"bytes,4,opt,name=describe,proto3" json:"describe,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptValidation) Reset() {
+	*x = ScriptValidation{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptValidation) ProtoMessage() {}
+
+func (x *ScriptValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptValidation.ProtoReflect.Descriptor instead.
+func (*ScriptValidation) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ScriptValidation) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ScriptValidation) GetIssues() []*ScriptLintIssue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+func (x *ScriptValidation) GetSteps() []*ScriptLintStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *ScriptValidation) GetDescribe() *ScriptDescribe {
+	if x != nil {
+		return x.Describe
+	}
+	return nil
+}
+
+type ScriptLintIssue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Severity      string                 `protobuf:"bytes,1,opt,name=severity,proto3" json:"severity,omitempty"` // "error" | "warning"
+	Phase         string                 `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`       // "compile" | "init" | "view" | "walk"
+	Step          *string                `protobuf:"bytes,3,opt,name=step,proto3,oneof" json:"step,omitempty"`   // state.step the issue was found at
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptLintIssue) Reset() {
+	*x = ScriptLintIssue{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptLintIssue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptLintIssue) ProtoMessage() {}
+
+func (x *ScriptLintIssue) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptLintIssue.ProtoReflect.Descriptor instead.
+func (*ScriptLintIssue) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ScriptLintIssue) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *ScriptLintIssue) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *ScriptLintIssue) GetStep() string {
+	if x != nil && x.Step != nil {
+		return *x.Step
+	}
+	return ""
+}
+
+func (x *ScriptLintIssue) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ScriptLintStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          string                 `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`
+	WidgetType    string                 `protobuf:"bytes,2,opt,name=widget_type,json=widgetType,proto3" json:"widget_type,omitempty"`
+	StepId        *string                `protobuf:"bytes,3,opt,name=step_id,json=stepId,proto3,oneof" json:"step_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptLintStep) Reset() {
+	*x = ScriptLintStep{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptLintStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptLintStep) ProtoMessage() {}
+
+func (x *ScriptLintStep) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptLintStep.ProtoReflect.Descriptor instead.
+func (*ScriptLintStep) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ScriptLintStep) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *ScriptLintStep) GetWidgetType() string {
+	if x != nil {
+		return x.WidgetType
+	}
+	return ""
+}
+
+func (x *ScriptLintStep) GetStepId() string {
+	if x != nil && x.StepId != nil {
+		return *x.StepId
+	}
+	return ""
+}
+
+type ScriptOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *structpb.Struct       `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Logs          []string               `protobuf:"bytes,2,rep,name=logs,proto3" json:"logs,omitempty"`
+	Error         *string                `protobuf:"bytes,3,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptOutput) Reset() {
+	*x = ScriptOutput{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptOutput) ProtoMessage() {}
+
+func (x *ScriptOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptOutput.ProtoReflect.Descriptor instead.
+func (*ScriptOutput) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ScriptOutput) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ScriptOutput) GetLogs() []string {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *ScriptOutput) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+type ScriptEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Type     string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	StepId   *string                `protobuf:"bytes,2,opt,name=step_id,json=stepId,proto3,oneof" json:"step_id,omitempty"`
+	ActionId *string                `protobuf:"bytes,3,opt,name=action_id,json=actionId,proto3,oneof" json:"action_id,omitempty"`
+	Data     *structpb.Struct       `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// Who sent the event: "ui" (browser), "agent" (inject endpoint), or
+	// "schedule" (scheduled tick). Set by the server; client values are ignored.
+	Source        string `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptEvent) Reset() {
+	*x = ScriptEvent{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptEvent) ProtoMessage() {}
+
+func (x *ScriptEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptEvent.ProtoReflect.Descriptor instead.
+func (*ScriptEvent) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ScriptEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ScriptEvent) GetStepId() string {
+	if x != nil && x.StepId != nil {
+		return *x.StepId
+	}
+	return ""
+}
+
+func (x *ScriptEvent) GetActionId() string {
+	if x != nil && x.ActionId != nil {
+		return *x.ActionId
+	}
+	return ""
+}
+
+func (x *ScriptEvent) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ScriptEvent) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// One change between a script's state before and after an event.
+type ScriptStateChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Dotted path into state, e.g. "form.env"
+	Op            string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`     // "add" | "remove" | "replace"
+	Before        *structpb.Value        `protobuf:"bytes,3,opt,name=before,proto3" json:"before,omitempty"`
+	After         *structpb.Value        `protobuf:"bytes,4,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptStateChange) Reset() {
+	*x = ScriptStateChange{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptStateChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptStateChange) ProtoMessage() {}
+
+func (x *ScriptStateChange) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptStateChange.ProtoReflect.Descriptor instead.
+func (*ScriptStateChange) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ScriptStateChange) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ScriptStateChange) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *ScriptStateChange) GetBefore() *structpb.Value {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *ScriptStateChange) GetAfter() *structpb.Value {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+type ScriptHistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           int32                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	At            string                 `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`       // When the event was handled (RFC3339)
+	Event         *ScriptEvent           `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"` // Unset for the init entry
+	Changes       []*ScriptStateChange   `protobuf:"bytes,4,rep,name=changes,proto3" json:"changes,omitempty"`
+	StepId        *string                `protobuf:"bytes,5,opt,name=step_id,json=stepId,proto3,oneof" json:"step_id,omitempty"` // View step shown after the event
+	WidgetType    *string                `protobuf:"bytes,6,opt,name=widget_type,json=widgetType,proto3,oneof" json:"widget_type,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,7,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Logs          []string               `protobuf:"bytes,8,rep,name=logs,proto3" json:"logs,omitempty"`
+	Done          bool                   `protobuf:"varint,9,opt,name=done,proto3" json:"done,omitempty"`
+	Error         *string                `protobuf:"bytes,10,opt,name=error,proto3,oneof" json:"error,omitempty"`   // Set when the script failed on this event
+	Locale        *string                `protobuf:"bytes,11,opt,name=locale,proto3,oneof" json:"locale,omitempty"` // ctx.locale for the run
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptHistoryEntry) Reset() {
+	*x = ScriptHistoryEntry{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptHistoryEntry) ProtoMessage() {}
+
+func (x *ScriptHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptHistoryEntry.ProtoReflect.Descriptor instead.
+func (*ScriptHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ScriptHistoryEntry) GetSeq() int32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ScriptHistoryEntry) GetAt() string {
+	if x != nil {
+		return x.At
+	}
+	return ""
+}
+
+func (x *ScriptHistoryEntry) GetEvent() *ScriptEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *ScriptHistoryEntry) GetChanges() []*ScriptStateChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ScriptHistoryEntry) GetStepId() string {
+	if x != nil && x.StepId != nil {
+		return *x.StepId
+	}
+	return ""
+}
+
+func (x *ScriptHistoryEntry) GetWidgetType() string {
+	if x != nil && x.WidgetType != nil {
+		return *x.WidgetType
+	}
+	return ""
+}
+
+func (x *ScriptHistoryEntry) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ScriptHistoryEntry) GetLogs() []string {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *ScriptHistoryEntry) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *ScriptHistoryEntry) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *ScriptHistoryEntry) GetLocale() string {
+	if x != nil && x.Locale != nil {
+		return *x.Locale
+	}
+	return ""
+}
+
+// Result of GET /api/requests/{id}/script/history.
+type ScriptHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Entries       []*ScriptHistoryEntry  `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	Dropped       int32                  `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"` // Oldest entries discarded to stay within the cap
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptHistory) Reset() {
+	*x = ScriptHistory{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptHistory) ProtoMessage() {}
+
+func (x *ScriptHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptHistory.ProtoReflect.Descriptor instead.
+func (*ScriptHistory) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ScriptHistory) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ScriptHistory) GetEntries() []*ScriptHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ScriptHistory) GetDropped() int32 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+// Body of POST /api/requests/{id}/script/replay. Leave it empty to replay the
+// recorded script; set script or script_ref to check another version against
+// the recorded events.
+type ScriptReplayRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Script        *string                `protobuf:"bytes,1,opt,name=script,proto3,oneof" json:"script,omitempty"`
+	ScriptRef     *ScriptRef             `protobuf:"bytes,2,opt,name=script_ref,json=scriptRef,proto3,oneof" json:"script_ref,omitempty"`
+	Language      *string                `protobuf:"bytes,3,opt,name=language,proto3,oneof" json:"language,omitempty"` // "javascript" (default) | "typescript"; applies to script
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptReplayRequest) Reset() {
+	*x = ScriptReplayRequest{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptReplayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptReplayRequest) ProtoMessage() {}
+
+func (x *ScriptReplayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptReplayRequest.ProtoReflect.Descriptor instead.
+func (*ScriptReplayRequest) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ScriptReplayRequest) GetScript() string {
+	if x != nil && x.Script != nil {
+		return *x.Script
+	}
+	return ""
+}
+
+func (x *ScriptReplayRequest) GetScriptRef() *ScriptRef {
+	if x != nil {
+		return x.ScriptRef
+	}
+	return nil
+}
+
+func (x *ScriptReplayRequest) GetLanguage() string {
+	if x != nil && x.Language != nil {
+		return *x.Language
+	}
+	return ""
+}
+
+// Result of POST /api/requests/{id}/script/replay.
+type ScriptReplay struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Reproduced    bool                   `protobuf:"varint,2,opt,name=reproduced,proto3" json:"reproduced,omitempty"`                     // Every replayed run and the final result matched the recording
+	Entries       []*ScriptHistoryEntry  `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`                            // Replayed runs; at is the frozen clock of each run
+	Mismatch      *string                `protobuf:"bytes,4,opt,name=mismatch,proto3,oneof" json:"mismatch,omitempty"`                    // First difference found; the replay stops there
+	Result        *structpb.Struct       `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`                              // Result of the replayed flow, if it finished
+	ScriptRef     *ScriptRef             `protobuf:"bytes,6,opt,name=script_ref,json=scriptRef,proto3,oneof" json:"script_ref,omitempty"` // Registered script version that was replayed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptReplay) Reset() {
+	*x = ScriptReplay{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptReplay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptReplay) ProtoMessage() {}
+
+func (x *ScriptReplay) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptReplay.ProtoReflect.Descriptor instead.
+func (*ScriptReplay) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ScriptReplay) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ScriptReplay) GetReproduced() bool {
+	if x != nil {
+		return x.Reproduced
+	}
+	return false
+}
+
+func (x *ScriptReplay) GetEntries() []*ScriptHistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ScriptReplay) GetMismatch() string {
+	if x != nil && x.Mismatch != nil {
+		return *x.Mismatch
+	}
+	return ""
+}
+
+func (x *ScriptReplay) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ScriptReplay) GetScriptRef() *ScriptRef {
+	if x != nil {
+		return x.ScriptRef
+	}
+	return nil
+}
+
+type ScriptViewSection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WidgetType    string                 `protobuf:"bytes,1,opt,name=widget_type,json=widgetType,proto3" json:"widget_type,omitempty"`
+	Input         *structpb.Struct       `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	Id            *string                `protobuf:"bytes,3,opt,name=id,proto3,oneof" json:"id,omitempty"` // Keys the answer in event.data.sections; required with several interactive sections
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptViewSection) Reset() {
+	*x = ScriptViewSection{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptViewSection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptViewSection) ProtoMessage() {}
+
+func (x *ScriptViewSection) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptViewSection.ProtoReflect.Descriptor instead.
+func (*ScriptViewSection) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ScriptViewSection) GetWidgetType() string {
+	if x != nil {
+		return x.WidgetType
+	}
+	return ""
+}
+
+func (x *ScriptViewSection) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *ScriptViewSection) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+// Body of the 422 response to a browser submit whose section answers failed
+// validation. Nothing is passed to update().
+type ScriptSectionErrors struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Errors        []*ScriptSectionError  `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptSectionErrors) Reset() {
+	*x = ScriptSectionErrors{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptSectionErrors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptSectionErrors) ProtoMessage() {}
+
+func (x *ScriptSectionErrors) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptSectionErrors.ProtoReflect.Descriptor instead.
+func (*ScriptSectionErrors) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ScriptSectionErrors) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ScriptSectionErrors) GetErrors() []*ScriptSectionError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ScriptSectionError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SectionId     string                 `protobuf:"bytes,1,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptSectionError) Reset() {
+	*x = ScriptSectionError{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptSectionError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptSectionError) ProtoMessage() {}
+
+func (x *ScriptSectionError) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptSectionError.ProtoReflect.Descriptor instead.
+func (*ScriptSectionError) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ScriptSectionError) GetSectionId() string {
+	if x != nil {
+		return x.SectionId
+	}
+	return ""
+}
+
+func (x *ScriptSectionError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DisplayInput struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Content         string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Format          *string                `protobuf:"bytes,2,opt,name=format,proto3,oneof" json:"format,omitempty"` // "markdown" | "text" | "html"
+	Title           *string                `protobuf:"bytes,3,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	AcknowledgeText *string                `protobuf:"bytes,4,opt,name=acknowledge_text,json=acknowledgeText,proto3,oneof" json:"acknowledge_text,omitempty"` // Button label for standalone display requests
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DisplayInput) Reset() {
+	*x = DisplayInput{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisplayInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisplayInput) ProtoMessage() {}
+
+func (x *DisplayInput) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisplayInput.ProtoReflect.Descriptor instead.
+func (*DisplayInput) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *DisplayInput) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *DisplayInput) GetFormat() string {
+	if x != nil && x.Format != nil {
+		return *x.Format
+	}
+	return ""
+}
+
+func (x *DisplayInput) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *DisplayInput) GetAcknowledgeText() string {
+	if x != nil && x.AcknowledgeText != nil {
+		return *x.AcknowledgeText
+	}
+	return ""
+}
+
+type DisplayOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Acknowledged  bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // ISO 8601
+	Comment       *string                `protobuf:"bytes,3,opt,name=comment,proto3,oneof" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisplayOutput) Reset() {
+	*x = DisplayOutput{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisplayOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisplayOutput) ProtoMessage() {}
+
+func (x *DisplayOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisplayOutput.ProtoReflect.Descriptor instead.
+func (*DisplayOutput) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *DisplayOutput) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+func (x *DisplayOutput) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *DisplayOutput) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+type ScriptProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Current       int32                  `protobuf:"varint,1,opt,name=current,proto3" json:"current,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Label         *string                `protobuf:"bytes,3,opt,name=label,proto3,oneof" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptProgress) Reset() {
+	*x = ScriptProgress{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptProgress) ProtoMessage() {}
+
+func (x *ScriptProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptProgress.ProtoReflect.Descriptor instead.
+func (*ScriptProgress) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ScriptProgress) GetCurrent() int32 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *ScriptProgress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ScriptProgress) GetLabel() string {
+	if x != nil && x.Label != nil {
+		return *x.Label
+	}
+	return ""
+}
+
+type ScriptToast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	DurationMs    *int32                 `protobuf:"varint,2,opt,name=duration_ms,json=durationMs,proto3,oneof" json:"duration_ms,omitempty"`
+	Style         *string                `protobuf:"bytes,3,opt,name=style,proto3,oneof" json:"style,omitempty"` // "info" | "success" | "warning" | "error"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptToast) Reset() {
+	*x = ScriptToast{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptToast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptToast) ProtoMessage() {}
+
+func (x *ScriptToast) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptToast.ProtoReflect.Descriptor instead.
+func (*ScriptToast) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ScriptToast) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ScriptToast) GetDurationMs() int32 {
+	if x != nil && x.DurationMs != nil {
+		return *x.DurationMs
+	}
+	return 0
+}
+
+func (x *ScriptToast) GetStyle() string {
+	if x != nil && x.Style != nil {
+		return *x.Style
+	}
+	return ""
+}
+
+type ScriptView struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WidgetType    string                 `protobuf:"bytes,1,opt,name=widget_type,json=widgetType,proto3" json:"widget_type,omitempty"`
+	Input         *structpb.Struct       `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`
+	StepId        *string                `protobuf:"bytes,3,opt,name=step_id,json=stepId,proto3,oneof" json:"step_id,omitempty"`
+	Title         *string                `protobuf:"bytes,4,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Description   *string                `protobuf:"bytes,5,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Sections      []*ScriptViewSection   `protobuf:"bytes,6,rep,name=sections,proto3" json:"sections,omitempty"`
+	Progress      *ScriptProgress        `protobuf:"bytes,7,opt,name=progress,proto3,oneof" json:"progress,omitempty"`
+	AllowBack     *bool                  `protobuf:"varint,8,opt,name=allow_back,json=allowBack,proto3,oneof" json:"allow_back,omitempty"`
+	BackLabel     *string                `protobuf:"bytes,9,opt,name=back_label,json=backLabel,proto3,oneof" json:"back_label,omitempty"`
+	Toast         *ScriptToast           `protobuf:"bytes,10,opt,name=toast,proto3,oneof" json:"toast,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptView) Reset() {
+	*x = ScriptView{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptView) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptView) ProtoMessage() {}
+
+func (x *ScriptView) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptView.ProtoReflect.Descriptor instead.
+func (*ScriptView) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ScriptView) GetWidgetType() string {
+	if x != nil {
+		return x.WidgetType
+	}
+	return ""
+}
+
+func (x *ScriptView) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *ScriptView) GetStepId() string {
+	if x != nil && x.StepId != nil {
+		return *x.StepId
+	}
+	return ""
+}
+
+func (x *ScriptView) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *ScriptView) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *ScriptView) GetSections() []*ScriptViewSection {
+	if x != nil {
+		return x.Sections
 	}
 	return nil
 }
@@ -2200,13 +3930,18 @@ type ScriptDescribe struct {
 	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
 	ApiVersion    *string                `protobuf:"bytes,3,opt,name=api_version,json=apiVersion,proto3,oneof" json:"api_version,omitempty"`
 	Capabilities  []string               `protobuf:"bytes,4,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Grants        []*ScriptGrant         `protobuf:"bytes,5,rep,name=grants,proto3" json:"grants,omitempty"`                                          // Capabilities the server actually granted
+	Modules       []*ScriptModule        `protobuf:"bytes,6,rep,name=modules,proto3" json:"modules,omitempty"`                                        // Library modules the script loaded
+	OutputSchema  *structpb.Struct       `protobuf:"bytes,7,opt,name=output_schema,json=outputSchema,proto3" json:"output_schema,omitempty"`          // JSON Schema the final result must match
+	Messages      *structpb.Struct       `protobuf:"bytes,8,opt,name=messages,proto3" json:"messages,omitempty"`                                      // Message catalogs for ctx.t, keyed by locale
+	DefaultLocale *string                `protobuf:"bytes,9,opt,name=default_locale,json=defaultLocale,proto3,oneof" json:"default_locale,omitempty"` // Locale used when no catalog matches the responder
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ScriptDescribe) Reset() {
 	*x = ScriptDescribe{}
-	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[32]
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2218,7 +3953,7 @@ func (x *ScriptDescribe) String() string {
 func (*ScriptDescribe) ProtoMessage() {}
 
 func (x *ScriptDescribe) ProtoReflect() protoreflect.Message {
-	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[32]
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2231,7 +3966,7 @@ func (x *ScriptDescribe) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScriptDescribe.ProtoReflect.Descriptor instead.
 func (*ScriptDescribe) Descriptor() ([]byte, []int) {
-	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{32}
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *ScriptDescribe) GetName() string {
@@ -2262,6 +3997,169 @@ func (x *ScriptDescribe) GetCapabilities() []string {
 	return nil
 }
 
+func (x *ScriptDescribe) GetGrants() []*ScriptGrant {
+	if x != nil {
+		return x.Grants
+	}
+	return nil
+}
+
+func (x *ScriptDescribe) GetModules() []*ScriptModule {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
+func (x *ScriptDescribe) GetOutputSchema() *structpb.Struct {
+	if x != nil {
+		return x.OutputSchema
+	}
+	return nil
+}
+
+func (x *ScriptDescribe) GetMessages() *structpb.Struct {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ScriptDescribe) GetDefaultLocale() string {
+	if x != nil && x.DefaultLocale != nil {
+		return *x.DefaultLocale
+	}
+	return ""
+}
+
+type ScriptModule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // e.g. "plz/wizard"
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Sha256        string                 `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"` // Hex digest of the module source
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptModule) Reset() {
+	*x = ScriptModule{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptModule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptModule) ProtoMessage() {}
+
+func (x *ScriptModule) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptModule.ProtoReflect.Descriptor instead.
+func (*ScriptModule) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ScriptModule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScriptModule) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ScriptModule) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type ScriptGrant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Capability    string                 `protobuf:"bytes,1,opt,name=capability,proto3" json:"capability,omitempty"`
+	Scope         []string               `protobuf:"bytes,2,rep,name=scope,proto3" json:"scope,omitempty"`                           // fs: allowed roots; fetch: allowed hosts
+	CallsUsed     int32                  `protobuf:"varint,3,opt,name=calls_used,json=callsUsed,proto3" json:"calls_used,omitempty"` // fetch: calls made so far for this request
+	BytesUsed     int64                  `protobuf:"varint,4,opt,name=bytes_used,json=bytesUsed,proto3" json:"bytes_used,omitempty"` // fetch: response bytes read so far for this request
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScriptGrant) Reset() {
+	*x = ScriptGrant{}
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScriptGrant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScriptGrant) ProtoMessage() {}
+
+func (x *ScriptGrant) ProtoReflect() protoreflect.Message {
+	mi := &file_plz_confirm_v1_widgets_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScriptGrant.ProtoReflect.Descriptor instead.
+func (*ScriptGrant) Descriptor() ([]byte, []int) {
+	return file_plz_confirm_v1_widgets_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ScriptGrant) GetCapability() string {
+	if x != nil {
+		return x.Capability
+	}
+	return ""
+}
+
+func (x *ScriptGrant) GetScope() []string {
+	if x != nil {
+		return x.Scope
+	}
+	return nil
+}
+
+func (x *ScriptGrant) GetCallsUsed() int32 {
+	if x != nil {
+		return x.CallsUsed
+	}
+	return 0
+}
+
+func (x *ScriptGrant) GetBytesUsed() int64 {
+	if x != nil {
+		return x.BytesUsed
+	}
+	return 0
+}
+
 var File_plz_confirm_v1_widgets_proto protoreflect.FileDescriptor
 
 const file_plz_confirm_v1_widgets_proto_rawDesc = "" +
@@ -2319,12 +4217,15 @@ const file_plz_confirm_v1_widgets_proto_rawDesc = "" +
 	"\x05cells\x18\x04 \x03(\v2\x18.plz_confirm.v1.GridCellR\x05cells\x12 \n" +
 	"\tcell_size\x18\x05 \x01(\tH\x00R\bcellSize\x88\x01\x01B\f\n" +
 	"\n" +
-	"_cell_size\"R\n" +
+	"_cell_size\"}\n" +
 	"\rGridSelection\x12\x10\n" +
 	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x10\n" +
 	"\x03col\x18\x02 \x01(\x05R\x03col\x12\x1d\n" +
 	"\n" +
-	"cell_index\x18\x03 \x01(\x05R\tcellIndex\"O\n" +
+	"cell_index\x18\x03 \x01(\x05R\tcellIndex\x12\x1d\n" +
+	"\acomment\x18\x04 \x01(\tH\x00R\acomment\x88\x01\x01B\n" +
+	"\n" +
+	"\b_comment\"O\n" +
 	"\fRatingLabels\x12\x15\n" +
 	"\x03low\x18\x01 \x01(\tH\x00R\x03low\x88\x01\x01\x12\x17\n" +
 	"\x04high\x18\x02 \x01(\tH\x01R\x04high\x88\x01\x01B\x06\n" +
@@ -2429,36 +4330,230 @@ const file_plz_confirm_v1_widgets_proto_rawDesc = "" +
 	"\x12ImageOutputNumbers\x12\x16\n" +
 	"\x06values\x18\x01 \x03(\x03R\x06values\",\n" +
 	"\x12ImageOutputStrings\x12\x16\n" +
-	"\x06values\x18\x01 \x03(\tR\x06values\"\x9d\x01\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"X\n" +
+	"\bDiffHunk\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\x06header\x18\x02 \x01(\tH\x00R\x06header\x88\x01\x01\x12\x14\n" +
+	"\x05lines\x18\x03 \x03(\tR\x05linesB\t\n" +
+	"\a_header\"\xa9\x01\n" +
+	"\bDiffFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1e\n" +
+	"\bold_path\x18\x02 \x01(\tH\x00R\aoldPath\x88\x01\x01\x12\x1f\n" +
+	"\blanguage\x18\x03 \x01(\tH\x01R\blanguage\x88\x01\x01\x12.\n" +
+	"\x05hunks\x18\x04 \x03(\v2\x18.plz_confirm.v1.DiffHunkR\x05hunksB\v\n" +
+	"\t_old_pathB\v\n" +
+	"\t_language\"\xda\x01\n" +
+	"\tDiffInput\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1d\n" +
+	"\amessage\x18\x02 \x01(\tH\x00R\amessage\x88\x01\x01\x12.\n" +
+	"\x05files\x18\x03 \x03(\v2\x18.plz_confirm.v1.DiffFileR\x05files\x12\x1b\n" +
+	"\x06layout\x18\x04 \x01(\tH\x01R\x06layout\x88\x01\x01\x12$\n" +
+	"\vrequire_all\x18\x05 \x01(\bH\x02R\n" +
+	"requireAll\x88\x01\x01B\n" +
+	"\n" +
+	"\b_messageB\t\n" +
+	"\a_layoutB\x0e\n" +
+	"\f_require_all\"r\n" +
+	"\x10DiffHunkDecision\x12\x17\n" +
+	"\ahunk_id\x18\x01 \x01(\tR\x06hunkId\x12\x1a\n" +
+	"\bdecision\x18\x02 \x01(\tR\bdecision\x12\x1d\n" +
+	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01B\n" +
+	"\n" +
+	"\b_comment\"\x93\x01\n" +
+	"\n" +
+	"DiffOutput\x12>\n" +
+	"\tdecisions\x18\x01 \x03(\v2 .plz_confirm.v1.DiffHunkDecisionR\tdecisions\x12\x1a\n" +
+	"\bapproved\x18\x02 \x01(\bR\bapproved\x12\x1d\n" +
+	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01B\n" +
+	"\n" +
+	"\b_comment\"\x8c\x01\n" +
+	"\fKeyValueItem\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x19\n" +
+	"\x05style\x18\x03 \x01(\tH\x00R\x05style\x88\x01\x01\x12!\n" +
+	"\tmonospace\x18\x04 \x01(\bH\x01R\tmonospace\x88\x01\x01B\b\n" +
+	"\x06_styleB\f\n" +
+	"\n" +
+	"_monospace\"\xbd\x01\n" +
+	"\rKeyValueInput\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1d\n" +
+	"\amessage\x18\x02 \x01(\tH\x00R\amessage\x88\x01\x01\x122\n" +
+	"\x05items\x18\x03 \x03(\v2\x1c.plz_confirm.v1.KeyValueItemR\x05items\x12&\n" +
+	"\fconfirm_text\x18\x04 \x01(\tH\x01R\vconfirmText\x88\x01\x01B\n" +
+	"\n" +
+	"\b_messageB\x0f\n" +
+	"\r_confirm_text\"_\n" +
+	"\x0eKeyValueOutput\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12\x1d\n" +
+	"\acomment\x18\x02 \x01(\tH\x00R\acomment\x88\x01\x01B\n" +
+	"\n" +
+	"\b_comment\"\xc5\x01\n" +
+	"\rChecklistItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x1f\n" +
+	"\brequired\x18\x04 \x01(\bH\x01R\brequired\x88\x01\x01\x12\x1d\n" +
+	"\achecked\x18\x05 \x01(\bH\x02R\achecked\x88\x01\x01B\x0e\n" +
+	"\f_descriptionB\v\n" +
+	"\t_requiredB\n" +
+	"\n" +
+	"\b_checked\"\xbc\x01\n" +
+	"\x0eChecklistInput\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1d\n" +
+	"\amessage\x18\x02 \x01(\tH\x00R\amessage\x88\x01\x01\x123\n" +
+	"\x05items\x18\x03 \x03(\v2\x1d.plz_confirm.v1.ChecklistItemR\x05items\x12$\n" +
+	"\vsubmit_text\x18\x04 \x01(\tH\x01R\n" +
+	"submitText\x88\x01\x01B\n" +
+	"\n" +
+	"\b_messageB\x0e\n" +
+	"\f_submit_text\"V\n" +
+	"\x0fChecklistOutput\x12\x18\n" +
+	"\achecked\x18\x01 \x03(\tR\achecked\x12\x1d\n" +
+	"\acomment\x18\x02 \x01(\tH\x00R\acomment\x88\x01\x01B\n" +
+	"\n" +
+	"\b_comment\"\x99\x02\n" +
 	"\vScriptInput\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x16\n" +
 	"\x06script\x18\x02 \x01(\tR\x06script\x12-\n" +
 	"\x05props\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x05props\x12\"\n" +
 	"\n" +
-	"timeout_ms\x18\x04 \x01(\x03H\x00R\ttimeoutMs\x88\x01\x01B\r\n" +
-	"\v_timeout_ms\"x\n" +
+	"timeout_ms\x18\x04 \x01(\x03H\x00R\ttimeoutMs\x88\x01\x01\x12=\n" +
+	"\n" +
+	"script_ref\x18\x05 \x01(\v2\x19.plz_confirm.v1.ScriptRefH\x01R\tscriptRef\x88\x01\x01\x12\x1f\n" +
+	"\blanguage\x18\x06 \x01(\tH\x02R\blanguage\x88\x01\x01B\r\n" +
+	"\v_timeout_msB\r\n" +
+	"\v_script_refB\v\n" +
+	"\t_language\"b\n" +
+	"\tScriptRef\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x05H\x00R\aversion\x88\x01\x01\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\tR\x06sha256B\n" +
+	"\n" +
+	"\b_version\"\xf4\x01\n" +
+	"\x10RegisteredScript\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x16\n" +
+	"\x06script\x18\x03 \x01(\tR\x06script\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\tR\x06sha256\x12%\n" +
+	"\vdescription\x18\x05 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1f\n" +
+	"\blanguage\x18\a \x01(\tH\x01R\blanguage\x88\x01\x01B\x0e\n" +
+	"\f_descriptionB\v\n" +
+	"\t_language\"R\n" +
+	"\x14RegisteredScriptList\x12:\n" +
+	"\ascripts\x18\x01 \x03(\v2 .plz_confirm.v1.RegisteredScriptR\ascripts\"\xcd\x01\n" +
+	"\x10ScriptValidation\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x127\n" +
+	"\x06issues\x18\x02 \x03(\v2\x1f.plz_confirm.v1.ScriptLintIssueR\x06issues\x124\n" +
+	"\x05steps\x18\x03 \x03(\v2\x1e.plz_confirm.v1.ScriptLintStepR\x05steps\x12:\n" +
+	"\bdescribe\x18\x04 \x01(\v2\x1e.plz_confirm.v1.ScriptDescribeR\bdescribe\"\x7f\n" +
+	"\x0fScriptLintIssue\x12\x1a\n" +
+	"\bseverity\x18\x01 \x01(\tR\bseverity\x12\x14\n" +
+	"\x05phase\x18\x02 \x01(\tR\x05phase\x12\x17\n" +
+	"\x04step\x18\x03 \x01(\tH\x00R\x04step\x88\x01\x01\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessageB\a\n" +
+	"\x05_step\"o\n" +
+	"\x0eScriptLintStep\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\tR\x04step\x12\x1f\n" +
+	"\vwidget_type\x18\x02 \x01(\tR\n" +
+	"widgetType\x12\x1c\n" +
+	"\astep_id\x18\x03 \x01(\tH\x00R\x06stepId\x88\x01\x01B\n" +
+	"\n" +
+	"\b_step_id\"x\n" +
 	"\fScriptOutput\x12/\n" +
 	"\x06result\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x06result\x12\x12\n" +
 	"\x04logs\x18\x02 \x03(\tR\x04logs\x12\x19\n" +
 	"\x05error\x18\x03 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
-	"\x06_error\"\xa8\x01\n" +
+	"\x06_error\"\xc0\x01\n" +
 	"\vScriptEvent\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1c\n" +
 	"\astep_id\x18\x02 \x01(\tH\x00R\x06stepId\x88\x01\x01\x12 \n" +
 	"\taction_id\x18\x03 \x01(\tH\x01R\bactionId\x88\x01\x01\x12+\n" +
-	"\x04data\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04dataB\n" +
+	"\x04data\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x04data\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06sourceB\n" +
 	"\n" +
 	"\b_step_idB\f\n" +
 	"\n" +
-	"_action_id\"c\n" +
+	"_action_id\"\x95\x01\n" +
+	"\x11ScriptStateChange\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12.\n" +
+	"\x06before\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x06before\x12,\n" +
+	"\x05after\x18\x04 \x01(\v2\x16.google.protobuf.ValueR\x05after\"\x9c\x03\n" +
+	"\x12ScriptHistoryEntry\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x05R\x03seq\x12\x0e\n" +
+	"\x02at\x18\x02 \x01(\tR\x02at\x121\n" +
+	"\x05event\x18\x03 \x01(\v2\x1b.plz_confirm.v1.ScriptEventR\x05event\x12;\n" +
+	"\achanges\x18\x04 \x03(\v2!.plz_confirm.v1.ScriptStateChangeR\achanges\x12\x1c\n" +
+	"\astep_id\x18\x05 \x01(\tH\x00R\x06stepId\x88\x01\x01\x12$\n" +
+	"\vwidget_type\x18\x06 \x01(\tH\x01R\n" +
+	"widgetType\x88\x01\x01\x12\x1f\n" +
+	"\vduration_ms\x18\a \x01(\x03R\n" +
+	"durationMs\x12\x12\n" +
+	"\x04logs\x18\b \x03(\tR\x04logs\x12\x12\n" +
+	"\x04done\x18\t \x01(\bR\x04done\x12\x19\n" +
+	"\x05error\x18\n" +
+	" \x01(\tH\x02R\x05error\x88\x01\x01\x12\x1b\n" +
+	"\x06locale\x18\v \x01(\tH\x03R\x06locale\x88\x01\x01B\n" +
+	"\n" +
+	"\b_step_idB\x0e\n" +
+	"\f_widget_typeB\b\n" +
+	"\x06_errorB\t\n" +
+	"\a_locale\"\x86\x01\n" +
+	"\rScriptHistory\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12<\n" +
+	"\aentries\x18\x02 \x03(\v2\".plz_confirm.v1.ScriptHistoryEntryR\aentries\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\x05R\adropped\"\xb9\x01\n" +
+	"\x13ScriptReplayRequest\x12\x1b\n" +
+	"\x06script\x18\x01 \x01(\tH\x00R\x06script\x88\x01\x01\x12=\n" +
+	"\n" +
+	"script_ref\x18\x02 \x01(\v2\x19.plz_confirm.v1.ScriptRefH\x01R\tscriptRef\x88\x01\x01\x12\x1f\n" +
+	"\blanguage\x18\x03 \x01(\tH\x02R\blanguage\x88\x01\x01B\t\n" +
+	"\a_scriptB\r\n" +
+	"\v_script_refB\v\n" +
+	"\t_language\"\xb8\x02\n" +
+	"\fScriptReplay\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x1e\n" +
+	"\n" +
+	"reproduced\x18\x02 \x01(\bR\n" +
+	"reproduced\x12<\n" +
+	"\aentries\x18\x03 \x03(\v2\".plz_confirm.v1.ScriptHistoryEntryR\aentries\x12\x1f\n" +
+	"\bmismatch\x18\x04 \x01(\tH\x00R\bmismatch\x88\x01\x01\x12/\n" +
+	"\x06result\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x06result\x12=\n" +
+	"\n" +
+	"script_ref\x18\x06 \x01(\v2\x19.plz_confirm.v1.ScriptRefH\x01R\tscriptRef\x88\x01\x01B\v\n" +
+	"\t_mismatchB\r\n" +
+	"\v_script_ref\"\x7f\n" +
 	"\x11ScriptViewSection\x12\x1f\n" +
 	"\vwidget_type\x18\x01 \x01(\tR\n" +
 	"widgetType\x12-\n" +
-	"\x05input\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x05input\"P\n" +
+	"\x05input\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x05input\x12\x13\n" +
+	"\x02id\x18\x03 \x01(\tH\x00R\x02id\x88\x01\x01B\x05\n" +
+	"\x03_id\"k\n" +
+	"\x13ScriptSectionErrors\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12:\n" +
+	"\x06errors\x18\x02 \x03(\v2\".plz_confirm.v1.ScriptSectionErrorR\x06errors\"M\n" +
+	"\x12ScriptSectionError\x12\x1d\n" +
+	"\n" +
+	"section_id\x18\x01 \x01(\tR\tsectionId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xba\x01\n" +
 	"\fDisplayInput\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12\x1b\n" +
-	"\x06format\x18\x02 \x01(\tH\x00R\x06format\x88\x01\x01B\t\n" +
-	"\a_format\"e\n" +
+	"\x06format\x18\x02 \x01(\tH\x00R\x06format\x88\x01\x01\x12\x19\n" +
+	"\x05title\x18\x03 \x01(\tH\x01R\x05title\x88\x01\x01\x12.\n" +
+	"\x10acknowledge_text\x18\x04 \x01(\tH\x02R\x0facknowledgeText\x88\x01\x01B\t\n" +
+	"\a_formatB\b\n" +
+	"\x06_titleB\x13\n" +
+	"\x11_acknowledge_text\"|\n" +
+	"\rDisplayOutput\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\x12\x1d\n" +
+	"\acomment\x18\x03 \x01(\tH\x00R\acomment\x88\x01\x01B\n" +
+	"\n" +
+	"\b_comment\"e\n" +
 	"\x0eScriptProgress\x12\x18\n" +
 	"\acurrent\x18\x01 \x01(\x05R\acurrent\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x19\n" +
@@ -2494,14 +4589,33 @@ const file_plz_confirm_v1_widgets_proto_rawDesc = "" +
 	"\t_progressB\r\n" +
 	"\v_allow_backB\r\n" +
 	"\v_back_labelB\b\n" +
-	"\x06_toast\"\x98\x01\n" +
+	"\x06_toast\"\xb7\x03\n" +
 	"\x0eScriptDescribe\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12$\n" +
 	"\vapi_version\x18\x03 \x01(\tH\x00R\n" +
 	"apiVersion\x88\x01\x01\x12\"\n" +
-	"\fcapabilities\x18\x04 \x03(\tR\fcapabilitiesB\x0e\n" +
-	"\f_api_versionBGZEgithub.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1b\x06proto3"
+	"\fcapabilities\x18\x04 \x03(\tR\fcapabilities\x123\n" +
+	"\x06grants\x18\x05 \x03(\v2\x1b.plz_confirm.v1.ScriptGrantR\x06grants\x126\n" +
+	"\amodules\x18\x06 \x03(\v2\x1c.plz_confirm.v1.ScriptModuleR\amodules\x12<\n" +
+	"\routput_schema\x18\a \x01(\v2\x17.google.protobuf.StructR\foutputSchema\x123\n" +
+	"\bmessages\x18\b \x01(\v2\x17.google.protobuf.StructR\bmessages\x12*\n" +
+	"\x0edefault_locale\x18\t \x01(\tH\x01R\rdefaultLocale\x88\x01\x01B\x0e\n" +
+	"\f_api_versionB\x11\n" +
+	"\x0f_default_locale\"T\n" +
+	"\fScriptModule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\tR\x06sha256\"\x81\x01\n" +
+	"\vScriptGrant\x12\x1e\n" +
+	"\n" +
+	"capability\x18\x01 \x01(\tR\n" +
+	"capability\x12\x14\n" +
+	"\x05scope\x18\x02 \x03(\tR\x05scope\x12\x1d\n" +
+	"\n" +
+	"calls_used\x18\x03 \x01(\x05R\tcallsUsed\x12\x1d\n" +
+	"\n" +
+	"bytes_used\x18\x04 \x01(\x03R\tbytesUsedBGZEgithub.com/go-go-golems/plz-confirm/proto/generated/go/plz_confirm/v1b\x06proto3"
 
 var (
 	file_plz_confirm_v1_widgets_proto_rawDescOnce sync.Once
@@ -2515,70 +4629,122 @@ func file_plz_confirm_v1_widgets_proto_rawDescGZIP() []byte {
 	return file_plz_confirm_v1_widgets_proto_rawDescData
 }
 
-var file_plz_confirm_v1_widgets_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
+var file_plz_confirm_v1_widgets_proto_msgTypes = make([]protoimpl.MessageInfo, 60)
 var file_plz_confirm_v1_widgets_proto_goTypes = []any{
-	(*ConfirmInput)(nil),       // 0: plz_confirm.v1.ConfirmInput
-	(*ConfirmOutput)(nil),      // 1: plz_confirm.v1.ConfirmOutput
-	(*SelectInput)(nil),        // 2: plz_confirm.v1.SelectInput
-	(*SelectOutput)(nil),       // 3: plz_confirm.v1.SelectOutput
-	(*SelectOutputMulti)(nil),  // 4: plz_confirm.v1.SelectOutputMulti
-	(*GridCell)(nil),           // 5: plz_confirm.v1.GridCell
-	(*GridInput)(nil),          // 6: plz_confirm.v1.GridInput
-	(*GridSelection)(nil),      // 7: plz_confirm.v1.GridSelection
-	(*RatingLabels)(nil),       // 8: plz_confirm.v1.RatingLabels
-	(*RatingInput)(nil),        // 9: plz_confirm.v1.RatingInput
-	(*RatingOutput)(nil),       // 10: plz_confirm.v1.RatingOutput
-	(*FormInput)(nil),          // 11: plz_confirm.v1.FormInput
-	(*FormOutput)(nil),         // 12: plz_confirm.v1.FormOutput
-	(*UploadInput)(nil),        // 13: plz_confirm.v1.UploadInput
-	(*UploadOutput)(nil),       // 14: plz_confirm.v1.UploadOutput
-	(*UploadedFile)(nil),       // 15: plz_confirm.v1.UploadedFile
-	(*TableInput)(nil),         // 16: plz_confirm.v1.TableInput
-	(*TableOutput)(nil),        // 17: plz_confirm.v1.TableOutput
-	(*TableOutputMulti)(nil),   // 18: plz_confirm.v1.TableOutputMulti
-	(*ImageItem)(nil),          // 19: plz_confirm.v1.ImageItem
-	(*ImageInput)(nil),         // 20: plz_confirm.v1.ImageInput
-	(*ImageOutput)(nil),        // 21: plz_confirm.v1.ImageOutput
-	(*ImageOutputNumbers)(nil), // 22: plz_confirm.v1.ImageOutputNumbers
-	(*ImageOutputStrings)(nil), // 23: plz_confirm.v1.ImageOutputStrings
-	(*ScriptInput)(nil),        // 24: plz_confirm.v1.ScriptInput
-	(*ScriptOutput)(nil),       // 25: plz_confirm.v1.ScriptOutput
-	(*ScriptEvent)(nil),        // 26: plz_confirm.v1.ScriptEvent
-	(*ScriptViewSection)(nil),  // 27: plz_confirm.v1.ScriptViewSection
-	(*DisplayInput)(nil),       // 28: plz_confirm.v1.DisplayInput
-	(*ScriptProgress)(nil),     // 29: plz_confirm.v1.ScriptProgress
-	(*ScriptToast)(nil),        // 30: plz_confirm.v1.ScriptToast
-	(*ScriptView)(nil),         // 31: plz_confirm.v1.ScriptView
-	(*ScriptDescribe)(nil),     // 32: plz_confirm.v1.ScriptDescribe
-	(*structpb.Struct)(nil),    // 33: google.protobuf.Struct
+	(*ConfirmInput)(nil),         // 0: plz_confirm.v1.ConfirmInput
+	(*ConfirmOutput)(nil),        // 1: plz_confirm.v1.ConfirmOutput
+	(*SelectInput)(nil),          // 2: plz_confirm.v1.SelectInput
+	(*SelectOutput)(nil),         // 3: plz_confirm.v1.SelectOutput
+	(*SelectOutputMulti)(nil),    // 4: plz_confirm.v1.SelectOutputMulti
+	(*GridCell)(nil),             // 5: plz_confirm.v1.GridCell
+	(*GridInput)(nil),            // 6: plz_confirm.v1.GridInput
+	(*GridSelection)(nil),        // 7: plz_confirm.v1.GridSelection
+	(*RatingLabels)(nil),         // 8: plz_confirm.v1.RatingLabels
+	(*RatingInput)(nil),          // 9: plz_confirm.v1.RatingInput
+	(*RatingOutput)(nil),         // 10: plz_confirm.v1.RatingOutput
+	(*FormInput)(nil),            // 11: plz_confirm.v1.FormInput
+	(*FormOutput)(nil),           // 12: plz_confirm.v1.FormOutput
+	(*UploadInput)(nil),          // 13: plz_confirm.v1.UploadInput
+	(*UploadOutput)(nil),         // 14: plz_confirm.v1.UploadOutput
+	(*UploadedFile)(nil),         // 15: plz_confirm.v1.UploadedFile
+	(*TableInput)(nil),           // 16: plz_confirm.v1.TableInput
+	(*TableOutput)(nil),          // 17: plz_confirm.v1.TableOutput
+	(*TableOutputMulti)(nil),     // 18: plz_confirm.v1.TableOutputMulti
+	(*ImageItem)(nil),            // 19: plz_confirm.v1.ImageItem
+	(*ImageInput)(nil),           // 20: plz_confirm.v1.ImageInput
+	(*ImageOutput)(nil),          // 21: plz_confirm.v1.ImageOutput
+	(*ImageOutputNumbers)(nil),   // 22: plz_confirm.v1.ImageOutputNumbers
+	(*ImageOutputStrings)(nil),   // 23: plz_confirm.v1.ImageOutputStrings
+	(*DiffHunk)(nil),             // 24: plz_confirm.v1.DiffHunk
+	(*DiffFile)(nil),             // 25: plz_confirm.v1.DiffFile
+	(*DiffInput)(nil),            // 26: plz_confirm.v1.DiffInput
+	(*DiffHunkDecision)(nil),     // 27: plz_confirm.v1.DiffHunkDecision
+	(*DiffOutput)(nil),           // 28: plz_confirm.v1.DiffOutput
+	(*KeyValueItem)(nil),         // 29: plz_confirm.v1.KeyValueItem
+	(*KeyValueInput)(nil),        // 30: plz_confirm.v1.KeyValueInput
+	(*KeyValueOutput)(nil),       // 31: plz_confirm.v1.KeyValueOutput
+	(*ChecklistItem)(nil),        // 32: plz_confirm.v1.ChecklistItem
+	(*ChecklistInput)(nil),       // 33: plz_confirm.v1.ChecklistInput
+	(*ChecklistOutput)(nil),      // 34: plz_confirm.v1.ChecklistOutput
+	(*ScriptInput)(nil),          // 35: plz_confirm.v1.ScriptInput
+	(*ScriptRef)(nil),            // 36: plz_confirm.v1.ScriptRef
+	(*RegisteredScript)(nil),     // 37: plz_confirm.v1.RegisteredScript
+	(*RegisteredScriptList)(nil), // 38: plz_confirm.v1.RegisteredScriptList
+	(*ScriptValidation)(nil),     // 39: plz_confirm.v1.ScriptValidation
+	(*ScriptLintIssue)(nil),      // 40: plz_confirm.v1.ScriptLintIssue
+	(*ScriptLintStep)(nil),       // 41: plz_confirm.v1.ScriptLintStep
+	(*ScriptOutput)(nil),         // 42: plz_confirm.v1.ScriptOutput
+	(*ScriptEvent)(nil),          // 43: plz_confirm.v1.ScriptEvent
+	(*ScriptStateChange)(nil),    // 44: plz_confirm.v1.ScriptStateChange
+	(*ScriptHistoryEntry)(nil),   // 45: plz_confirm.v1.ScriptHistoryEntry
+	(*ScriptHistory)(nil),        // 46: plz_confirm.v1.ScriptHistory
+	(*ScriptReplayRequest)(nil),  // 47: plz_confirm.v1.ScriptReplayRequest
+	(*ScriptReplay)(nil),         // 48: plz_confirm.v1.ScriptReplay
+	(*ScriptViewSection)(nil),    // 49: plz_confirm.v1.ScriptViewSection
+	(*ScriptSectionErrors)(nil),  // 50: plz_confirm.v1.ScriptSectionErrors
+	(*ScriptSectionError)(nil),   // 51: plz_confirm.v1.ScriptSectionError
+	(*DisplayInput)(nil),         // 52: plz_confirm.v1.DisplayInput
+	(*DisplayOutput)(nil),        // 53: plz_confirm.v1.DisplayOutput
+	(*ScriptProgress)(nil),       // 54: plz_confirm.v1.ScriptProgress
+	(*ScriptToast)(nil),          // 55: plz_confirm.v1.ScriptToast
+	(*ScriptView)(nil),           // 56: plz_confirm.v1.ScriptView
+	(*ScriptDescribe)(nil),       // 57: plz_confirm.v1.ScriptDescribe
+	(*ScriptModule)(nil),         // 58: plz_confirm.v1.ScriptModule
+	(*ScriptGrant)(nil),          // 59: plz_confirm.v1.ScriptGrant
+	(*structpb.Struct)(nil),      // 60: google.protobuf.Struct
+	(*structpb.Value)(nil),       // 61: google.protobuf.Value
 }
 var file_plz_confirm_v1_widgets_proto_depIdxs = []int32{
 	4,  // 0: plz_confirm.v1.SelectOutput.selected_multi:type_name -> plz_confirm.v1.SelectOutputMulti
 	5,  // 1: plz_confirm.v1.GridInput.cells:type_name -> plz_confirm.v1.GridCell
 	8,  // 2: plz_confirm.v1.RatingInput.labels:type_name -> plz_confirm.v1.RatingLabels
-	33, // 3: plz_confirm.v1.FormInput.schema:type_name -> google.protobuf.Struct
-	33, // 4: plz_confirm.v1.FormOutput.data:type_name -> google.protobuf.Struct
+	60, // 3: plz_confirm.v1.FormInput.schema:type_name -> google.protobuf.Struct
+	60, // 4: plz_confirm.v1.FormOutput.data:type_name -> google.protobuf.Struct
 	15, // 5: plz_confirm.v1.UploadOutput.files:type_name -> plz_confirm.v1.UploadedFile
-	33, // 6: plz_confirm.v1.TableInput.data:type_name -> google.protobuf.Struct
-	33, // 7: plz_confirm.v1.TableOutput.selected_single:type_name -> google.protobuf.Struct
+	60, // 6: plz_confirm.v1.TableInput.data:type_name -> google.protobuf.Struct
+	60, // 7: plz_confirm.v1.TableOutput.selected_single:type_name -> google.protobuf.Struct
 	18, // 8: plz_confirm.v1.TableOutput.selected_multi:type_name -> plz_confirm.v1.TableOutputMulti
-	33, // 9: plz_confirm.v1.TableOutputMulti.values:type_name -> google.protobuf.Struct
+	60, // 9: plz_confirm.v1.TableOutputMulti.values:type_name -> google.protobuf.Struct
 	19, // 10: plz_confirm.v1.ImageInput.images:type_name -> plz_confirm.v1.ImageItem
 	22, // 11: plz_confirm.v1.ImageOutput.selected_numbers:type_name -> plz_confirm.v1.ImageOutputNumbers
 	23, // 12: plz_confirm.v1.ImageOutput.selected_strings:type_name -> plz_confirm.v1.ImageOutputStrings
-	33, // 13: plz_confirm.v1.ScriptInput.props:type_name -> google.protobuf.Struct
-	33, // 14: plz_confirm.v1.ScriptOutput.result:type_name -> google.protobuf.Struct
-	33, // 15: plz_confirm.v1.ScriptEvent.data:type_name -> google.protobuf.Struct
-	33, // 16: plz_confirm.v1.ScriptViewSection.input:type_name -> google.protobuf.Struct
-	33, // 17: plz_confirm.v1.ScriptView.input:type_name -> google.protobuf.Struct
-	27, // 18: plz_confirm.v1.ScriptView.sections:type_name -> plz_confirm.v1.ScriptViewSection
-	29, // 19: plz_confirm.v1.ScriptView.progress:type_name -> plz_confirm.v1.ScriptProgress
-	30, // 20: plz_confirm.v1.ScriptView.toast:type_name -> plz_confirm.v1.ScriptToast
-	21, // [21:21] is the sub-list for method output_type
-	21, // [21:21] is the sub-list for method input_type
-	21, // [21:21] is the sub-list for extension type_name
-	21, // [21:21] is the sub-list for extension extendee
-	0,  // [0:21] is the sub-list for field type_name
+	24, // 13: plz_confirm.v1.DiffFile.hunks:type_name -> plz_confirm.v1.DiffHunk
+	25, // 14: plz_confirm.v1.DiffInput.files:type_name -> plz_confirm.v1.DiffFile
+	27, // 15: plz_confirm.v1.DiffOutput.decisions:type_name -> plz_confirm.v1.DiffHunkDecision
+	29, // 16: plz_confirm.v1.KeyValueInput.items:type_name -> plz_confirm.v1.KeyValueItem
+	32, // 17: plz_confirm.v1.ChecklistInput.items:type_name -> plz_confirm.v1.ChecklistItem
+	60, // 18: plz_confirm.v1.ScriptInput.props:type_name -> google.protobuf.Struct
+	36, // 19: plz_confirm.v1.ScriptInput.script_ref:type_name -> plz_confirm.v1.ScriptRef
+	37, // 20: plz_confirm.v1.RegisteredScriptList.scripts:type_name -> plz_confirm.v1.RegisteredScript
+	40, // 21: plz_confirm.v1.ScriptValidation.issues:type_name -> plz_confirm.v1.ScriptLintIssue
+	41, // 22: plz_confirm.v1.ScriptValidation.steps:type_name -> plz_confirm.v1.ScriptLintStep
+	57, // 23: plz_confirm.v1.ScriptValidation.describe:type_name -> plz_confirm.v1.ScriptDescribe
+	60, // 24: plz_confirm.v1.ScriptOutput.result:type_name -> google.protobuf.Struct
+	60, // 25: plz_confirm.v1.ScriptEvent.data:type_name -> google.protobuf.Struct
+	61, // 26: plz_confirm.v1.ScriptStateChange.before:type_name -> google.protobuf.Value
+	61, // 27: plz_confirm.v1.ScriptStateChange.after:type_name -> google.protobuf.Value
+	43, // 28: plz_confirm.v1.ScriptHistoryEntry.event:type_name -> plz_confirm.v1.ScriptEvent
+	44, // 29: plz_confirm.v1.ScriptHistoryEntry.changes:type_name -> plz_confirm.v1.ScriptStateChange
+	45, // 30: plz_confirm.v1.ScriptHistory.entries:type_name -> plz_confirm.v1.ScriptHistoryEntry
+	36, // 31: plz_confirm.v1.ScriptReplayRequest.script_ref:type_name -> plz_confirm.v1.ScriptRef
+	45, // 32: plz_confirm.v1.ScriptReplay.entries:type_name -> plz_confirm.v1.ScriptHistoryEntry
+	60, // 33: plz_confirm.v1.ScriptReplay.result:type_name -> google.protobuf.Struct
+	36, // 34: plz_confirm.v1.ScriptReplay.script_ref:type_name -> plz_confirm.v1.ScriptRef
+	60, // 35: plz_confirm.v1.ScriptViewSection.input:type_name -> google.protobuf.Struct
+	51, // 36: plz_confirm.v1.ScriptSectionErrors.errors:type_name -> plz_confirm.v1.ScriptSectionError
+	60, // 37: plz_confirm.v1.ScriptView.input:type_name -> google.protobuf.Struct
+	49, // 38: plz_confirm.v1.ScriptView.sections:type_name -> plz_confirm.v1.ScriptViewSection
+	54, // 39: plz_confirm.v1.ScriptView.progress:type_name -> plz_confirm.v1.ScriptProgress
+	55, // 40: plz_confirm.v1.ScriptView.toast:type_name -> plz_confirm.v1.ScriptToast
+	59, // 41: plz_confirm.v1.ScriptDescribe.grants:type_name -> plz_confirm.v1.ScriptGrant
+	58, // 42: plz_confirm.v1.ScriptDescribe.modules:type_name -> plz_confirm.v1.ScriptModule
+	60, // 43: plz_confirm.v1.ScriptDescribe.output_schema:type_name -> google.protobuf.Struct
+	60, // 44: plz_confirm.v1.ScriptDescribe.messages:type_name -> google.protobuf.Struct
+	45, // [45:45] is the sub-list for method output_type
+	45, // [45:45] is the sub-list for method input_type
+	45, // [45:45] is the sub-list for extension type_name
+	45, // [45:45] is the sub-list for extension extendee
+	0,  // [0:45] is the sub-list for field type_name
 }
 
 func init() { file_plz_confirm_v1_widgets_proto_init() }
@@ -2595,6 +4761,7 @@ func file_plz_confirm_v1_widgets_proto_init() {
 	}
 	file_plz_confirm_v1_widgets_proto_msgTypes[5].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[6].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[7].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[8].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[9].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[10].OneofWrappers = []any{}
@@ -2618,18 +4785,38 @@ func file_plz_confirm_v1_widgets_proto_init() {
 	file_plz_confirm_v1_widgets_proto_msgTypes[24].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[25].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[26].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[27].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[28].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[29].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[30].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[31].OneofWrappers = []any{}
 	file_plz_confirm_v1_widgets_proto_msgTypes[32].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[33].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[34].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[35].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[36].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[37].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[40].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[41].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[42].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[43].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[45].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[47].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[48].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[49].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[52].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[53].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[54].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[55].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[56].OneofWrappers = []any{}
+	file_plz_confirm_v1_widgets_proto_msgTypes[57].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plz_confirm_v1_widgets_proto_rawDesc), len(file_plz_confirm_v1_widgets_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   33,
+			NumMessages:   60,
 			NumExtensions: 0,
 			NumServices:   0,
 		},